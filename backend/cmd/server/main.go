@@ -2,14 +2,23 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"rythmitbackend/configs"
+	"rythmitbackend/internal/handlers"
+	"rythmitbackend/internal/repositories"
 	"rythmitbackend/internal/router"
+	"rythmitbackend/internal/services"
 	"rythmitbackend/pkg/database"
 	"rythmitbackend/pkg/migrations"
+	"rythmitbackend/pkg/musicmeta"
 )
 
 func main() {
@@ -32,6 +41,9 @@ func main() {
 	}
 	log.Println("✅ Migrations terminées")
 
+	// Tâches de fond
+	startBackgroundJobs(cfg)
+
 	// Configuration du router avec support des templates
 	handler := router.Init(cfg)
 
@@ -50,9 +62,69 @@ func main() {
 	log.Printf("🌐 Templates: Chargés depuis ../frontend/\n")
 	log.Printf("📁 Fichiers statiques: /styles/ → ../frontend/styles/\n")
 
-	if err := srv.ListenAndServe(); err != nil {
-		log.Fatalf("❌ Erreur démarrage serveur: %v", err)
+	// Arrêt propre (SIGINT, SIGTERM) : les requêtes en cours se terminent,
+	// puis les vues des threads encore en mémoire sont écrites
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("❌ Erreur démarrage serveur: %v", err)
+		}
+	case <-ctx.Done():
+		log.Println("🛑 Arrêt du serveur...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.WriteTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("⚠️ Arrêt forcé du serveur: %v", err)
+		}
+	}
+
+	services.StopThreadViewCounter()
+}
+
+// startBackgroundJobs - Démarre les tâches périodiques (archivage, publication programmée)
+func startBackgroundJobs(cfg *configs.Config) {
+	db := database.DB
+	threadService := services.NewThreadService(
+		repositories.NewThreadRepository(db),
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+	services.StartThreadAutoArchiver(threadService, cfg.Threads.AutoArchiveAfter, cfg.Threads.AutoArchiveInterval)
+	services.StartScheduledThreadPublisher(threadService, cfg.Threads.PublishInterval, handlers.NotifyThreadPublished)
+	services.StartThreadViewCounter(repositories.NewThreadRepository(db), cfg.Threads.ViewDedupWindow, cfg.Threads.ViewFlushInterval)
+
+	// Métadonnées des liens musicaux, récupérées hors des requêtes
+	provider, err := musicmeta.New(cfg.Music.MetadataProvider, cfg.Music.MetadataFixtures, &http.Client{Timeout: cfg.Music.MetadataTimeout})
+	if err != nil {
+		log.Printf("⚠️ Fournisseur de métadonnées indisponible: %v", err)
+	}
+	services.StartMusicMetadataFetcher(repositories.NewMetadataRepository(db), provider,
+		cfg.Music.MetadataInterval, cfg.Music.MetadataBatchSize, cfg.Music.MetadataTimeout)
+
+	// Limites des fichiers audio envoyés, puis forme d'onde et durée exacte calculées hors des requêtes
+	services.SetAudioLimits(cfg.Audio.MaxUploadBytes, cfg.Audio.MaxDuration)
+	audioSecret := cfg.Audio.SigningSecret
+	if audioSecret == "" {
+		audioSecret = cfg.JWT.Secret
 	}
+	services.SetAudioStreamSigning(audioSecret, cfg.Audio.StreamTTL)
+	services.StartAudioAnalyzer(repositories.NewAudioRepository(db), cfg.Audio.AnalysisInterval, cfg.Audio.WaveformPoints)
+
+	// Notifier en temps réel les utilisateurs mentionnés
+	services.SetMentionNotifier(handlers.NotifyMention)
+
+	// Réactions proposées et diffusion en temps réel de leurs changements
+	services.SetAllowedReactions(cfg.Reactions.Set)
+	services.SetReactionNotifier(handlers.NotifyReactionChange)
 }
 
 // displayBanner - Affiche la bannière ASCII au démarrage
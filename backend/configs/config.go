@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -11,11 +12,15 @@ import (
 
 // Config structure principale contenant toute la configuration
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Server   ServerConfig
-	Security SecurityConfig
+	App       AppConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Server    ServerConfig
+	Security  SecurityConfig
+	Threads   ThreadsConfig
+	Reactions ReactionsConfig
+	Music     MusicConfig
+	Audio     AudioConfig
 }
 
 // AppConfig configuration de l'application
@@ -59,6 +64,39 @@ type SecurityConfig struct {
 	LockoutDuration   time.Duration
 }
 
+// ThreadsConfig configuration du cycle de vie des threads
+type ThreadsConfig struct {
+	AutoArchiveAfter    time.Duration // 0 désactive l'archivage automatique
+	AutoArchiveInterval time.Duration
+	PublishInterval     time.Duration // fréquence de publication des threads programmés
+	ViewDedupWindow     time.Duration // un visiteur n'est compté qu'une fois par fenêtre
+	ViewFlushInterval   time.Duration // fréquence d'écriture des vues accumulées (0 désactive le comptage)
+}
+
+// ReactionsConfig configuration des réactions emoji
+type ReactionsConfig struct {
+	Set []string // réactions proposées (vide = ensemble par défaut)
+}
+
+// MusicConfig configuration des métadonnées des liens musicaux
+type MusicConfig struct {
+	MetadataProvider  string        // fixture (développement, tests), oembed ou none
+	MetadataFixtures  string        // fichier JSON du fournisseur fixture
+	MetadataInterval  time.Duration // fréquence de récupération des métadonnées en attente (0 désactive)
+	MetadataBatchSize int
+	MetadataTimeout   time.Duration // délai maximal d'un appel au fournisseur
+}
+
+// AudioConfig configuration des fichiers audio envoyés
+type AudioConfig struct {
+	MaxUploadBytes   int64         // taille maximale d'un fichier
+	MaxDuration      time.Duration // durée maximale d'un morceau
+	WaveformPoints   int           // nombre de crêtes de chaque forme d'onde
+	AnalysisInterval time.Duration // fréquence d'analyse des fichiers en attente (0 désactive)
+	SigningSecret    string        // clé des URL de lecture signées (JWT_SECRET si vide)
+	StreamTTL        time.Duration // validité des URL de lecture, en plus de la durée du morceau
+}
+
 // instance unique de configuration (singleton)
 var instance *Config
 
@@ -105,6 +143,31 @@ func Load() *Config {
 			MaxLoginAttempts:  getEnvAsInt("MAX_LOGIN_ATTEMPTS", 5),
 			LockoutDuration:   time.Duration(getEnvAsInt("LOCKOUT_DURATION_MINUTES", 15)) * time.Minute,
 		},
+		Threads: ThreadsConfig{
+			AutoArchiveAfter:    time.Duration(getEnvAsInt("THREAD_AUTO_ARCHIVE_DAYS", 90)) * 24 * time.Hour,
+			AutoArchiveInterval: time.Duration(getEnvAsInt("THREAD_AUTO_ARCHIVE_INTERVAL_MINUTES", 60)) * time.Minute,
+			PublishInterval:     time.Duration(getEnvAsInt("THREAD_PUBLISH_INTERVAL_SECONDS", 30)) * time.Second,
+			ViewDedupWindow:     time.Duration(getEnvAsInt("THREAD_VIEW_DEDUP_MINUTES", 30)) * time.Minute,
+			ViewFlushInterval:   time.Duration(getEnvAsInt("THREAD_VIEW_FLUSH_SECONDS", 30)) * time.Second,
+		},
+		Reactions: ReactionsConfig{
+			Set: getEnvAsList("REACTION_SET"),
+		},
+		Music: MusicConfig{
+			MetadataProvider:  getEnv("MUSIC_METADATA_PROVIDER", "fixture"),
+			MetadataFixtures:  getEnv("MUSIC_METADATA_FIXTURES", "fixtures/music_metadata.json"),
+			MetadataInterval:  time.Duration(getEnvAsInt("MUSIC_METADATA_INTERVAL_SECONDS", 60)) * time.Second,
+			MetadataBatchSize: getEnvAsInt("MUSIC_METADATA_BATCH_SIZE", 20),
+			MetadataTimeout:   time.Duration(getEnvAsInt("MUSIC_METADATA_TIMEOUT_SECONDS", 10)) * time.Second,
+		},
+		Audio: AudioConfig{
+			MaxUploadBytes:   int64(getEnvAsInt("AUDIO_UPLOAD_MAX_MB", 20)) << 20,
+			MaxDuration:      time.Duration(getEnvAsInt("AUDIO_UPLOAD_MAX_MINUTES", 10)) * time.Minute,
+			WaveformPoints:   getEnvAsInt("AUDIO_WAVEFORM_POINTS", 200),
+			AnalysisInterval: time.Duration(getEnvAsInt("AUDIO_ANALYSIS_INTERVAL_SECONDS", 60)) * time.Second,
+			SigningSecret:    getEnv("AUDIO_SIGNING_SECRET", ""),
+			StreamTTL:        time.Duration(getEnvAsInt("AUDIO_STREAM_TTL_MINUTES", 15)) * time.Minute,
+		},
 	}
 
 	// Log de la configuration chargée (sans les secrets)
@@ -150,6 +213,17 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsList récupère une variable d'environnement comme liste séparée par des virgules
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // IsDevelopment vérifie si on est en environnement de développement
 func (c *Config) IsDevelopment() bool {
 	return c.App.Environment == "development"
@@ -125,3 +125,23 @@ func TestGetEnvAsInt(t *testing.T) {
 		})
 	}
 }
+
+func TestGetEnvAsList(t *testing.T) {
+	os.Setenv("TEST_LIST", " 🔥, ⏭️ ,,💯 ")
+	defer os.Unsetenv("TEST_LIST")
+
+	got := getEnvAsList("TEST_LIST")
+	want := []string{"🔥", "⏭️", "💯"}
+	if len(got) != len(want) {
+		t.Fatalf("getEnvAsList() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("getEnvAsList()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if got := getEnvAsList("TEST_LIST_UNSET"); len(got) != 0 {
+		t.Errorf("getEnvAsList() sans variable = %v, want vide", got)
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// AdminThreadHandler gère l'épinglage et les annonces (routes protégées par AdminMiddleware)
+type AdminThreadHandler struct {
+	threadService services.ThreadService
+}
+
+// NewAdminThreadHandler crée une nouvelle instance du handler
+func NewAdminThreadHandler(threadService services.ThreadService) *AdminThreadHandler {
+	return &AdminThreadHandler{
+		threadService: threadService,
+	}
+}
+
+// PinThreadRequest représente un épinglage ; Tag vide = fil global
+type PinThreadRequest struct {
+	Tag       string     `json:"tag"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// AnnouncementRequest active ou désactive le statut d'annonce
+type AnnouncementRequest struct {
+	Announcement bool `json:"announcement"`
+}
+
+// PinThread épingle un thread sur le fil global ou sur un tag
+func (h *AdminThreadHandler) PinThread(w http.ResponseWriter, r *http.Request) {
+	adminID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	var req PinThreadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	pin, err := h.threadService.PinThread(uint(threadID), req.Tag, req.ExpiresAt, adminID)
+	if err != nil {
+		sendAdminThreadError(w, err)
+		return
+	}
+
+	log.Printf("📌 Thread %d épinglé par l'admin %d (tag: %q)", threadID, adminID, req.Tag)
+	sendAPISuccess(w, "Thread épinglé", map[string]interface{}{
+		"pin": pin,
+	})
+}
+
+// UnpinThread retire l'épinglage d'un thread (paramètre ?tag= pour un épinglage de tag)
+func (h *AdminThreadHandler) UnpinThread(w http.ResponseWriter, r *http.Request) {
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	tag := r.URL.Query().Get("tag")
+	if err := h.threadService.UnpinThread(uint(threadID), tag); err != nil {
+		if errors.Is(err, utils.ErrThreadNotFound) {
+			sendAPIError(w, "Ce thread n'est pas épinglé", http.StatusNotFound)
+			return
+		}
+		sendAdminThreadError(w, err)
+		return
+	}
+
+	log.Printf("📍 Thread %d désépinglé (tag: %q)", threadID, tag)
+	sendAPISuccess(w, "Thread désépinglé", nil)
+}
+
+// SetAnnouncement marque ou démarque un thread comme annonce
+func (h *AdminThreadHandler) SetAnnouncement(w http.ResponseWriter, r *http.Request) {
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	var req AnnouncementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.threadService.SetAnnouncement(uint(threadID), req.Announcement); err != nil {
+		sendAdminThreadError(w, err)
+		return
+	}
+
+	log.Printf("📢 Annonce du thread %d: %v", threadID, req.Announcement)
+	sendAPISuccess(w, "Statut d'annonce mis à jour", map[string]interface{}{
+		"thread_id":    threadID,
+		"announcement": req.Announcement,
+	})
+}
+
+// sendAdminThreadError traduit les erreurs du service en réponses API
+func sendAdminThreadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrThreadNotFound):
+		sendAPIError(w, "Thread non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrTagNotFound):
+		sendAPIError(w, "Tag non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrThreadArchived):
+		sendAPIError(w, "Un thread archivé ne peut pas être épinglé", http.StatusForbidden)
+	default:
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// AnnotationHandler gère les annotations de passages de la description des threads
+type AnnotationHandler struct {
+	annotationService services.AnnotationService
+}
+
+// NewAnnotationHandler crée une nouvelle instance du handler
+func NewAnnotationHandler(annotationService services.AnnotationService) *AnnotationHandler {
+	return &AnnotationHandler{
+		annotationService: annotationService,
+	}
+}
+
+// AnnotationVoteRequest vote sur une annotation (fire, skip ou neutral)
+type AnnotationVoteRequest struct {
+	Vote string `json:"vote"`
+}
+
+// GetThreadAnnotations retourne les annotations d'un thread et la révision courante de son texte
+func (h *AnnotationHandler) GetThreadAnnotations(w http.ResponseWriter, r *http.Request) {
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	annotations, err := h.annotationService.GetThreadAnnotations(uint(threadID), optionalViewerID(r))
+	if err != nil {
+		sendAnnotationError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Annotations récupérées", annotations)
+}
+
+// CreateAnnotation annote un passage de la description d'un thread
+func (h *AnnotationHandler) CreateAnnotation(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	var req services.CreateAnnotationDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	annotation, err := h.annotationService.CreateAnnotation(uint(threadID), userID, req)
+	if err != nil {
+		sendAnnotationError(w, err)
+		return
+	}
+
+	log.Printf("📝 Annotation %d ajoutée au thread %d par %d", annotation.ID, threadID, userID)
+	sendAPISuccess(w, "Annotation ajoutée", map[string]interface{}{
+		"annotation": annotation,
+	})
+}
+
+// DeleteAnnotation supprime une annotation
+func (h *AnnotationHandler) DeleteAnnotation(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	annotationID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID annotation invalide", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.annotationService.DeleteAnnotation(uint(annotationID), userID, controllers.IsAdminFromContext(r)); err != nil {
+		sendAnnotationError(w, err)
+		return
+	}
+
+	log.Printf("🗑️ Annotation %d supprimée par %d", annotationID, userID)
+	sendAPISuccess(w, "Annotation supprimée", nil)
+}
+
+// VoteAnnotation enregistre le vote de l'utilisateur sur une annotation
+func (h *AnnotationHandler) VoteAnnotation(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	annotationID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID annotation invalide", http.StatusBadRequest)
+		return
+	}
+
+	var req AnnotationVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	annotation, err := h.annotationService.VoteAnnotation(uint(annotationID), userID, req.Vote)
+	if err != nil {
+		sendAnnotationError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Vote enregistré", map[string]interface{}{
+		"annotation": annotation,
+	})
+}
+
+// sendAnnotationError traduit les erreurs du service d'annotations en réponses API
+func sendAnnotationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrThreadNotFound):
+		sendAPIError(w, "Thread non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrAnnotationNotFound):
+		sendAPIError(w, "Annotation non trouvée", http.StatusNotFound)
+	case errors.Is(err, utils.ErrAnnotationOutdated):
+		sendAPIError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, utils.ErrUnauthorized):
+		sendAPIError(w, "Action non autorisée sur cette annotation", http.StatusForbidden)
+	case errors.Is(err, utils.ErrThreadClosed), errors.Is(err, utils.ErrThreadArchived):
+		sendAPIError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, utils.ErrInvalidInput):
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("❌ Erreur annotation: %v", err)
+		sendAPIError(w, "Erreur lors du traitement de l'annotation", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/audiofile"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// audioFormMemory part du formulaire gardée en mémoire (le reste passe par un fichier temporaire)
+const audioFormMemory = 8 << 20
+
+// AudioHandler gère l'envoi de fichiers audio pour les options de battle
+type AudioHandler struct {
+	audioService services.AudioService
+}
+
+// NewAudioHandler crée une nouvelle instance du handler
+func NewAudioHandler(audioService services.AudioService) *AudioHandler {
+	return &AudioHandler{
+		audioService: audioService,
+	}
+}
+
+// Upload reçoit un fichier audio (champ multipart "audio") et retourne son URL, sa durée
+// et les tags lus pour préremplir le titre et l'artiste de l'option
+func (h *AudioHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	// Marge pour les autres champs et l'enveloppe multipart
+	r.Body = http.MaxBytesReader(w, r.Body, services.AudioMaxBytes()+1<<20)
+	if err := r.ParseMultipartForm(audioFormMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			sendAudioError(w, utils.ErrAudioTooLarge)
+			return
+		}
+		sendAPIError(w, "Formulaire invalide", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("audio")
+	if err != nil {
+		sendAPIError(w, "Fichier audio manquant", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	audio, err := h.audioService.Upload(userID, file, header.Filename)
+	if err != nil {
+		sendAudioError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Fichier audio envoyé", map[string]interface{}{
+		"audio": audio,
+	})
+}
+
+// GetAudio récupère un fichier audio (URL de lecture, format, durée, tags) si le visiteur peut l'écouter
+func (h *AudioHandler) GetAudio(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID de fichier audio invalide", http.StatusBadRequest)
+		return
+	}
+
+	audio, err := h.audioService.GetAudio(uint(audioID), optionalViewerID(r), controllers.IsAdminFromContext(r))
+	if err != nil {
+		sendAudioError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Fichier audio récupéré", map[string]interface{}{
+		"audio": audio,
+	})
+}
+
+// GetStreamURL émet une URL de lecture signée, utilisable sans session jusqu'à son expiration
+func (h *AudioHandler) GetStreamURL(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID de fichier audio invalide", http.StatusBadRequest)
+		return
+	}
+
+	streamURL, err := h.audioService.SignStreamURL(uint(audioID), optionalViewerID(r), controllers.IsAdminFromContext(r))
+	if err != nil {
+		sendAudioError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "URL de lecture générée", streamURL)
+}
+
+// Stream sert le fichier audio avec prise en charge des requêtes Range (lecture et déplacement
+// dans le morceau) ; accès par session ou par URL signée (paramètres expires et sig)
+func (h *AudioHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	audioID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID de fichier audio invalide", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	audio, file, err := h.audioService.OpenStream(uint(audioID), optionalViewerID(r), controllers.IsAdminFromContext(r),
+		query.Get("expires"), query.Get("sig"))
+	if err != nil {
+		sendAudioError(w, err)
+		return
+	}
+	defer file.Close()
+
+	// Remplace le Content-Type JSON posé par le middleware de l'API
+	w.Header().Set("Content-Type", audiofile.MimeType(audio.Format))
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", audio.CreatedAt, file)
+}
+
+// sendAudioError traduit les erreurs du service en réponses API
+func sendAudioError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrAudioNotFound):
+		sendAPIError(w, "Fichier audio non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrAudioTooLarge):
+		sendAPIError(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, utils.ErrAudioUnsupported):
+		sendAPIError(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, utils.ErrTokenExpired):
+		sendAPIError(w, "Lien de lecture expiré", http.StatusForbidden)
+	case errors.Is(err, utils.ErrTokenInvalid):
+		sendAPIError(w, "Lien de lecture invalide", http.StatusForbidden)
+	case errors.Is(err, utils.ErrInvalidInput):
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("❌ Erreur fichier audio: %v", err)
+		sendAPIError(w, "Erreur lors du traitement du fichier audio", http.StatusInternalServerError)
+	}
+}
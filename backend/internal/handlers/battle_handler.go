@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// BattleHandler gère la composition des battles de musique
+type BattleHandler struct {
+	battleService services.BattleService
+}
+
+// NewBattleHandler crée une nouvelle instance du handler
+func NewBattleHandler(battleService services.BattleService) *BattleHandler {
+	return &BattleHandler{
+		battleService: battleService,
+	}
+}
+
+// AddOption ajoute une musique à une battle : lien musical ou fichier audio envoyé via POST /audio
+func (h *BattleHandler) AddOption(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	battleID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID de battle invalide", http.StatusBadRequest)
+		return
+	}
+
+	var req services.AddBattleOptionDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données JSON invalides", http.StatusBadRequest)
+		return
+	}
+
+	option, err := h.battleService.AddOption(uint(battleID), userID, req)
+	if err != nil {
+		sendBattleError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Musique ajoutée à la battle", map[string]interface{}{
+		"option": option,
+	})
+}
+
+// sendBattleError traduit les erreurs du service en réponses API
+func sendBattleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrBattleNotFound):
+		sendAPIError(w, "Battle non trouvée", http.StatusNotFound)
+	case errors.Is(err, utils.ErrAudioNotFound):
+		sendAPIError(w, "Fichier audio non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrUnauthorized):
+		sendAPIError(w, "Seul le créateur de la battle peut y ajouter des musiques", http.StatusForbidden)
+	case errors.Is(err, utils.ErrInvalidInput):
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("❌ Erreur battle: %v", err)
+		sendAPIError(w, "Erreur lors de l'ajout de la musique", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// BookmarkHandler gère les threads mis en favoris
+type BookmarkHandler struct {
+	bookmarkService services.BookmarkService
+}
+
+// NewBookmarkHandler crée une nouvelle instance du handler
+func NewBookmarkHandler(bookmarkService services.BookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{
+		bookmarkService: bookmarkService,
+	}
+}
+
+// GetBookmarks liste les favoris de l'utilisateur connecté
+func (h *BookmarkHandler) GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	bookmarks, err := h.bookmarkService.GetBookmarks(userID)
+	if err != nil {
+		sendSubscriptionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Favoris récupérés", map[string]interface{}{
+		"threads": bookmarks,
+		"total":   len(bookmarks),
+	})
+}
+
+// AddBookmark met un thread en favori
+func (h *BookmarkHandler) AddBookmark(w http.ResponseWriter, r *http.Request) {
+	userID, threadID, ok := bookmarkRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.bookmarkService.AddBookmark(threadID, userID); err != nil {
+		sendSubscriptionError(w, err)
+		return
+	}
+
+	log.Printf("🔖 Thread %d ajouté aux favoris de l'utilisateur %d", threadID, userID)
+	sendAPISuccess(w, "Thread ajouté aux favoris", map[string]interface{}{
+		"bookmarked": true,
+	})
+}
+
+// RemoveBookmark retire un thread des favoris
+func (h *BookmarkHandler) RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	userID, threadID, ok := bookmarkRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.bookmarkService.RemoveBookmark(threadID, userID); err != nil {
+		sendSubscriptionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Thread retiré des favoris", map[string]interface{}{
+		"bookmarked": false,
+	})
+}
+
+// bookmarkRequestIDs extrait l'utilisateur authentifié et l'ID du thread
+func bookmarkRequestIDs(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return userID, uint(threadID), true
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/musiclink"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// CatalogHandler gère le catalogue musical commun aux plateformes
+type CatalogHandler struct {
+	catalogService services.CatalogService
+}
+
+// NewCatalogHandler crée une nouvelle instance du handler
+func NewCatalogHandler(catalogService services.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+// RegisterLink rattache un lien au catalogue à partir de ses métadonnées (titre, artiste, ISRC...)
+func (h *CatalogHandler) RegisterLink(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	var req services.RegisterCatalogLinkDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.catalogService.RegisterLink(userID, req)
+	if err != nil {
+		sendCatalogError(w, err)
+		return
+	}
+
+	log.Printf("🎼 Lien %s rattaché au catalogue (%s %d) par %d", req.URL, entry.Type, entry.ID, userID)
+	sendAPISuccess(w, "Lien rattaché au catalogue", map[string]interface{}{
+		"entry": entry,
+	})
+}
+
+// UnlinkLink détache un lien de son entrée (?url=, admin uniquement)
+func (h *CatalogHandler) UnlinkLink(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	rawURL := r.URL.Query().Get("url")
+	if err := h.catalogService.UnlinkLink(rawURL, controllers.IsAdminFromContext(r)); err != nil {
+		sendCatalogError(w, err)
+		return
+	}
+
+	log.Printf("🗑️ Lien %s détaché du catalogue par %d", rawURL, userID)
+	sendAPISuccess(w, "Lien détaché du catalogue", nil)
+}
+
+// LookupLink retrouve l'entrée du catalogue d'un lien (?url=)
+func (h *CatalogHandler) LookupLink(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.catalogService.LookupLink(r.URL.Query().Get("url"), optionalViewerID(r))
+	if err != nil {
+		sendCatalogError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Entrée du catalogue récupérée", map[string]interface{}{
+		"entry": entry,
+	})
+}
+
+// GetEntry récupère un morceau, un album ou un artiste avec ses liens, ses threads et ses battles
+func (h *CatalogHandler) GetEntry(w http.ResponseWriter, r *http.Request) {
+	catalogType, catalogID, ok := catalogEntryFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := h.catalogService.GetEntry(catalogType, catalogID, optionalViewerID(r))
+	if err != nil {
+		sendCatalogError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Entrée du catalogue récupérée", map[string]interface{}{
+		"entry": entry,
+	})
+}
+
+// LinkToEntry rattache à la main un lien ({"url": ...}) à une entrée existante
+func (h *CatalogHandler) LinkToEntry(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	catalogType, catalogID, ok := catalogEntryFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.catalogService.LinkToEntry(catalogType, catalogID, userID, controllers.IsAdminFromContext(r), req.URL)
+	if err != nil {
+		sendCatalogError(w, err)
+		return
+	}
+
+	log.Printf("🔗 Lien %s rattaché à la main (%s %d) par %d", req.URL, catalogType, catalogID, userID)
+	sendAPISuccess(w, "Lien rattaché à l'entrée", map[string]interface{}{
+		"entry": entry,
+	})
+}
+
+// GetCharts classe les entrées les plus citées, toutes plateformes confondues
+// (?type=track|album|artist, ?days=, ?limit=)
+func (h *CatalogHandler) GetCharts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	catalogType := query.Get("type")
+	if catalogType == "" {
+		catalogType = musiclink.TypeTrack
+	}
+	days, _ := strconv.Atoi(query.Get("days"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	entries, err := h.catalogService.GetCharts(catalogType, days, limit)
+	if err != nil {
+		sendCatalogError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Classement récupéré", map[string]interface{}{
+		"entries": entries,
+	})
+}
+
+// GetPreferences récupère la plateforme préférée de l'utilisateur
+func (h *CatalogHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	platform, err := h.catalogService.GetPreferredPlatform(userID)
+	if err != nil {
+		sendCatalogError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Préférences récupérées", map[string]interface{}{
+		"platform": platform,
+	})
+}
+
+// SetPreferences choisit la plateforme sur laquelle ouvrir les liens ({"platform": ""} pour l'oublier)
+func (h *CatalogHandler) SetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Platform string `json:"platform"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.catalogService.SetPreferredPlatform(userID, req.Platform); err != nil {
+		sendCatalogError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Plateforme préférée enregistrée", map[string]interface{}{
+		"platform": req.Platform,
+	})
+}
+
+// catalogEntryFromRequest extrait le type (tracks, albums, artists) et l'ID d'entrée de l'URL
+func catalogEntryFromRequest(w http.ResponseWriter, r *http.Request) (string, uint, bool) {
+	vars := mux.Vars(r)
+	catalogID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID d'entrée invalide", http.StatusBadRequest)
+		return "", 0, false
+	}
+	return strings.TrimSuffix(vars["type"], "s"), uint(catalogID), true
+}
+
+// sendCatalogError traduit les erreurs du service en réponses API
+func sendCatalogError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrCatalogEntryNotFound):
+		sendAPIError(w, "Entrée du catalogue non trouvée", http.StatusNotFound)
+	case errors.Is(err, utils.ErrCatalogLinkTaken):
+		sendAPIError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, utils.ErrUnauthorized):
+		sendAPIError(w, "Action réservée aux administrateurs", http.StatusForbidden)
+	case errors.Is(err, utils.ErrInvalidInput):
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("❌ Erreur catalogue: %v", err)
+		sendAPIError(w, "Erreur lors du traitement du catalogue", http.StatusInternalServerError)
+	}
+}
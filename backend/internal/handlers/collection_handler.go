@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// CollectionHandler gère les collections de threads des utilisateurs
+type CollectionHandler struct {
+	collectionService services.CollectionService
+}
+
+// NewCollectionHandler crée une nouvelle instance du handler
+func NewCollectionHandler(collectionService services.CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// UpdateCollectionItemRequest nouvelle note d'un élément de collection
+type UpdateCollectionItemRequest struct {
+	Note *string `json:"note"`
+}
+
+// ReorderCollectionRequest nouvel ordre des threads d'une collection
+type ReorderCollectionRequest struct {
+	ThreadIDs []uint `json:"thread_ids"`
+}
+
+// GetMyCollections liste toutes les collections de l'utilisateur connecté
+func (h *CollectionHandler) GetMyCollections(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	collections, err := h.collectionService.GetUserCollections(userID, &userID)
+	if err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Collections récupérées", map[string]interface{}{
+		"collections": collections,
+	})
+}
+
+// GetUserCollections liste les collections visibles d'un utilisateur
+func (h *CollectionHandler) GetUserCollections(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := strconv.ParseUint(mux.Vars(r)["userId"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID utilisateur invalide", http.StatusBadRequest)
+		return
+	}
+
+	collections, err := h.collectionService.GetUserCollections(uint(ownerID), optionalViewerID(r))
+	if err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Collections récupérées", map[string]interface{}{
+		"collections": collections,
+	})
+}
+
+// GetCollection récupère une collection avec ses threads
+func (h *CollectionHandler) GetCollection(w http.ResponseWriter, r *http.Request) {
+	collectionID, ok := collectionIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	collection, err := h.collectionService.GetCollection(collectionID, optionalViewerID(r))
+	if err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Collection récupérée", map[string]interface{}{
+		"collection": collection,
+	})
+}
+
+// CreateCollection crée une collection pour l'utilisateur connecté
+func (h *CollectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	var dto services.CollectionDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	collection, err := h.collectionService.CreateCollection(userID, dto)
+	if err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	log.Printf("📚 Collection %d « %s » créée par l'utilisateur %d", collection.ID, collection.Name, userID)
+	sendAPISuccess(w, "Collection créée", map[string]interface{}{
+		"collection": collection,
+	})
+}
+
+// UpdateCollection modifie le nom, la description ou la visibilité d'une collection
+func (h *CollectionHandler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := collectionOwnerRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var dto services.CollectionDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	collection, err := h.collectionService.UpdateCollection(collectionID, userID, dto)
+	if err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Collection mise à jour", map[string]interface{}{
+		"collection": collection,
+	})
+}
+
+// DeleteCollection supprime une collection
+func (h *CollectionHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := collectionOwnerRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.collectionService.DeleteCollection(collectionID, userID); err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	log.Printf("🗑️ Collection %d supprimée par l'utilisateur %d", collectionID, userID)
+	sendAPISuccess(w, "Collection supprimée", nil)
+}
+
+// AddItem ajoute un thread à une collection
+func (h *CollectionHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := collectionOwnerRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var dto services.CollectionItemDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.collectionService.AddItem(collectionID, userID, dto); err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Thread ajouté à la collection", nil)
+}
+
+// UpdateItem modifie la note d'un thread de la collection
+func (h *CollectionHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := collectionOwnerRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["threadId"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateCollectionItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.collectionService.UpdateItemNote(collectionID, uint(threadID), userID, req.Note); err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Note mise à jour", nil)
+}
+
+// RemoveItem retire un thread de la collection
+func (h *CollectionHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := collectionOwnerRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["threadId"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.collectionService.RemoveItem(collectionID, uint(threadID), userID); err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Thread retiré de la collection", nil)
+}
+
+// ReorderItems change l'ordre des threads d'une collection
+func (h *CollectionHandler) ReorderItems(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, ok := collectionOwnerRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReorderCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.collectionService.ReorderItems(collectionID, userID, req.ThreadIDs); err != nil {
+		sendCollectionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Ordre de la collection mis à jour", nil)
+}
+
+// optionalViewerID retourne l'utilisateur connecté s'il y en a un
+func optionalViewerID(r *http.Request) *uint {
+	if userID, exists := controllers.GetUserIDFromContext(r); exists {
+		return &userID
+	}
+	return nil
+}
+
+// collectionIDFromRequest extrait l'ID de collection de l'URL
+func collectionIDFromRequest(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	collectionID, err := strconv.ParseUint(mux.Vars(r)["collectionId"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID collection invalide", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(collectionID), true
+}
+
+// collectionOwnerRequestIDs extrait l'utilisateur authentifié et l'ID de collection
+func collectionOwnerRequestIDs(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+
+	collectionID, ok := collectionIDFromRequest(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+
+	return userID, collectionID, true
+}
+
+// sendCollectionError traduit les erreurs du service en réponses API
+func sendCollectionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrCollectionNotFound):
+		sendAPIError(w, "Collection non trouvée", http.StatusNotFound)
+	case errors.Is(err, utils.ErrCollectionItemNotFound):
+		sendAPIError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, utils.ErrThreadNotFound):
+		sendAPIError(w, "Thread non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrUnauthorized):
+		sendAPIError(w, "Action non autorisée sur cette collection ou ce thread", http.StatusForbidden)
+	case errors.Is(err, utils.ErrCollectionNameTaken), errors.Is(err, utils.ErrCollectionItemExists):
+		sendAPIError(w, err.Error(), http.StatusConflict)
+	default:
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	}
+}
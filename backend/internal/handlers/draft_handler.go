@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// DraftHandler gère les brouillons de threads (autosave)
+type DraftHandler struct {
+	draftService services.DraftService
+}
+
+// NewDraftHandler crée une nouvelle instance du handler
+func NewDraftHandler(draftService services.DraftService) *DraftHandler {
+	return &DraftHandler{
+		draftService: draftService,
+	}
+}
+
+// GetDrafts liste les brouillons de l'utilisateur
+func (h *DraftHandler) GetDrafts(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	drafts, err := h.draftService.GetUserDrafts(userID)
+	if err != nil {
+		sendAPIError(w, "Erreur récupération brouillons", http.StatusInternalServerError)
+		return
+	}
+
+	sendAPISuccess(w, "Brouillons récupérés", map[string]interface{}{
+		"drafts": drafts,
+	})
+}
+
+// GetDraft récupère un brouillon
+func (h *DraftHandler) GetDraft(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	draftID, err := strconv.ParseUint(mux.Vars(r)["draftId"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID brouillon invalide", http.StatusBadRequest)
+		return
+	}
+
+	draft, err := h.draftService.GetDraft(uint(draftID), userID)
+	if err != nil {
+		sendDraftError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Brouillon récupéré", map[string]interface{}{
+		"draft": draft,
+	})
+}
+
+// CreateDraft crée un brouillon (première sauvegarde)
+func (h *DraftHandler) CreateDraft(w http.ResponseWriter, r *http.Request) {
+	h.saveDraft(w, r, 0)
+}
+
+// AutosaveDraft écrase le contenu d'un brouillon existant
+func (h *DraftHandler) AutosaveDraft(w http.ResponseWriter, r *http.Request) {
+	draftID, err := strconv.ParseUint(mux.Vars(r)["draftId"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID brouillon invalide", http.StatusBadRequest)
+		return
+	}
+
+	h.saveDraft(w, r, uint(draftID))
+}
+
+// saveDraft logique commune de création / autosave
+func (h *DraftHandler) saveDraft(w http.ResponseWriter, r *http.Request, draftID uint) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	var req services.SaveDraftDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	draft, err := h.draftService.SaveDraft(userID, draftID, req)
+	if err != nil {
+		sendDraftError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Brouillon sauvegardé", map[string]interface{}{
+		"draft": draft,
+	})
+}
+
+// DeleteDraft supprime un brouillon
+func (h *DraftHandler) DeleteDraft(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	draftID, err := strconv.ParseUint(mux.Vars(r)["draftId"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID brouillon invalide", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.draftService.DeleteDraft(uint(draftID), userID); err != nil {
+		sendDraftError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Brouillon supprimé", nil)
+}
+
+// PublishDraft publie (ou programme) le thread correspondant au brouillon
+func (h *DraftHandler) PublishDraft(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	draftID, err := strconv.ParseUint(mux.Vars(r)["draftId"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID brouillon invalide", http.StatusBadRequest)
+		return
+	}
+
+	thread, err := h.draftService.PublishDraft(uint(draftID), userID)
+	if err != nil && thread == nil {
+		sendDraftError(w, err)
+		return
+	}
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+
+	message := "Thread publié"
+	if thread.PublishAt != nil {
+		message = "Thread programmé"
+	}
+
+	sendAPISuccess(w, message, map[string]interface{}{
+		"thread": thread,
+	})
+}
+
+// sendDraftError traduit les erreurs du service en réponses API
+func sendDraftError(w http.ResponseWriter, err error) {
+	if errors.Is(err, utils.ErrDraftNotFound) {
+		sendAPIError(w, "Brouillon non trouvé", http.StatusNotFound)
+		return
+	}
+	sendAPIError(w, err.Error(), http.StatusBadRequest)
+}
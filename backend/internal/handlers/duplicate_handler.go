@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+)
+
+// DuplicateHandler gère la détection des threads en double avant publication
+type DuplicateHandler struct {
+	threadService services.ThreadService
+}
+
+// NewDuplicateHandler crée une nouvelle instance du handler
+func NewDuplicateHandler(threadService services.ThreadService) *DuplicateHandler {
+	return &DuplicateHandler{
+		threadService: threadService,
+	}
+}
+
+// CheckDuplicates retourne les threads récents probablement identiques au thread en cours de rédaction
+func (h *DuplicateHandler) CheckDuplicates(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	var req services.DuplicateCheckDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	duplicates, err := h.threadService.FindDuplicates(req, userID, 0)
+	if err != nil {
+		log.Printf("❌ Erreur détection de doublons: %v", err)
+		sendAPIError(w, "Erreur lors de la recherche de doublons", http.StatusInternalServerError)
+		return
+	}
+
+	sendAPISuccess(w, "Recherche de doublons effectuée", map[string]interface{}{
+		"duplicates": duplicates,
+	})
+}
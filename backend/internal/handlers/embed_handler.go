@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+	"rythmitbackend/pkg/musiclink"
+)
+
+// ResolveMusicLinkHandler reconnaît un lien musical (?url=) pour l'aperçu de l'éditeur :
+// plateforme, type d'entité, identifiant et URL canonique
+func ResolveMusicLinkHandler(w http.ResponseWriter, r *http.Request) {
+	rawURL := r.URL.Query().Get("url")
+	if rawURL == "" {
+		sendAPIError(w, "Paramètre url requis", http.StatusBadRequest)
+		return
+	}
+
+	link, err := musiclink.Parse(rawURL)
+	if err != nil {
+		sendAPIError(w, "Lien musical non reconnu", http.StatusUnprocessableEntity)
+		return
+	}
+
+	sendAPISuccess(w, "Lien musical reconnu", map[string]interface{}{
+		"link": link,
+	})
+}
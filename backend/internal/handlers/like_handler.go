@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
 
+	"rythmitbackend/internal/models"
 	"rythmitbackend/internal/repositories"
 	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
 	"rythmitbackend/pkg/database"
 
 	"github.com/gorilla/mux"
@@ -19,6 +22,7 @@ type LikeResponse struct {
 	Liked      bool   `json:"liked"`
 	LikesCount int    `json:"likes_count"`
 	Message    string `json:"message,omitempty"`
+	Reason     string `json:"reason,omitempty"` // état du thread qui bloque l'action
 }
 
 // ToggleLikeHandler gère le like/unlike d'un thread
@@ -63,6 +67,33 @@ func ToggleLikeHandler(w http.ResponseWriter, r *http.Request) {
 	db := database.DB
 	likeRepo := repositories.NewLikeRepository(db)
 
+	// Le thread doit être visible par l'utilisateur ; archivé, il n'accepte plus aucune interaction, likes compris
+	threadService := services.NewThreadService(
+		repositories.NewThreadRepository(db),
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+	if err := threadService.CheckThreadAction(uint(threadID), services.ThreadActionLike, userID); err != nil {
+		status := http.StatusForbidden
+		message := "Ce thread est archivé : les likes sont désactivés"
+		reason := models.ThreadStateArchived
+		if errors.Is(err, utils.ErrThreadNotFound) {
+			status = http.StatusNotFound
+			message = "Thread non trouvé"
+			reason = ""
+		}
+		log.Printf("🔒 Like refusé sur thread %d: %v", threadID, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(LikeResponse{
+			Success: false,
+			Message: message,
+			Reason:  reason,
+		})
+		return
+	}
+
 	// Vérifier si l'utilisateur a déjà liké ce thread
 	currentlyLiked, err := likeRepo.IsThreadLikedByUser(userID, uint(threadID))
 	if err != nil {
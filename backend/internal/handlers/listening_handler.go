@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ListeningHandler gère l'historique d'écoute des utilisateurs
+type ListeningHandler struct {
+	listeningService services.ListeningService
+}
+
+// NewListeningHandler crée une nouvelle instance du handler
+func NewListeningHandler(listeningService services.ListeningService) *ListeningHandler {
+	return &ListeningHandler{
+		listeningService: listeningService,
+	}
+}
+
+// GetUserListens récupère le morceau en cours d'écoute et les écoutes récentes d'un utilisateur (?limit=),
+// réservés à l'utilisateur lui-même et à ses amis
+func (h *ListeningHandler) GetUserListens(w http.ResponseWriter, r *http.Request) {
+	viewerID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := strconv.ParseUint(mux.Vars(r)["userId"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID utilisateur invalide", http.StatusBadRequest)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	playing, listens, err := h.listeningService.GetUserListens(viewerID, uint(userID), limit)
+	if err != nil {
+		sendListeningError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Écoutes récupérées", map[string]interface{}{
+		"now_playing": playing,
+		"listens":     listens,
+	})
+}
+
+// sendListeningError traduit les erreurs du service en réponses API
+func sendListeningError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidInput):
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, utils.ErrUnauthorized):
+		sendAPIError(w, "Ces écoutes sont réservées aux amis de l'utilisateur", http.StatusForbidden)
+	default:
+		log.Printf("❌ Erreur historique d'écoute: %v", err)
+		sendAPIError(w, "Erreur lors de la récupération des écoutes", http.StatusInternalServerError)
+	}
+}
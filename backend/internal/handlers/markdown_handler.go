@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/pkg/markdown"
+	"unicode/utf8"
+)
+
+// maxPreviewLength taille maximale d'un contenu à prévisualiser
+const maxPreviewLength = 20000
+
+// MarkdownPreviewRequest contenu Markdown à prévisualiser
+type MarkdownPreviewRequest struct {
+	Content string `json:"content"`
+}
+
+// MarkdownPreviewHandler rend un contenu Markdown en HTML assaini pour l'aperçu de l'éditeur
+func MarkdownPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	if _, exists := controllers.GetUserIDFromContext(r); !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	var req MarkdownPreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	if utf8.RuneCountInString(req.Content) > maxPreviewLength {
+		sendAPIError(w, "Contenu trop long pour l'aperçu", http.StatusBadRequest)
+		return
+	}
+
+	sendAPISuccess(w, "Aperçu généré", map[string]interface{}{
+		"html": markdown.Render(req.Content),
+	})
+}
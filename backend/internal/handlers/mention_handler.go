@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/pkg/markdown"
+	"strings"
+)
+
+// MentionHandler gère l'autocomplétion des mentions @utilisateur
+type MentionHandler struct {
+	mentionService services.MentionService
+}
+
+// NewMentionHandler crée une nouvelle instance du handler
+func NewMentionHandler(mentionService services.MentionService) *MentionHandler {
+	return &MentionHandler{
+		mentionService: mentionService,
+	}
+}
+
+// SuggestUsers propose des utilisateurs à mentionner (?q=préfixe)
+func (h *MentionHandler) SuggestUsers(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	suggestions, err := h.mentionService.SuggestUsers(r.URL.Query().Get("q"), userID)
+	if err != nil {
+		log.Printf("❌ Erreur suggestions de mention: %v", err)
+		sendAPIError(w, "Erreur lors de la recherche d'utilisateurs", http.StatusInternalServerError)
+		return
+	}
+
+	sendAPISuccess(w, "Suggestions récupérées", map[string]interface{}{
+		"users": suggestions,
+	})
+}
+
+// NotifyMention prévient en temps réel un utilisateur qu'il a été mentionné
+func NotifyMention(mention *models.Mention, author *models.User) {
+	var message, url string
+	switch mention.SourceType {
+	case models.MentionSourceComment:
+		message = fmt.Sprintf("%s vous a mentionné dans un commentaire", author.Username)
+		if mention.ThreadID != nil {
+			url = fmt.Sprintf("/thread/%d#comment-%d", *mention.ThreadID, mention.SourceID)
+		}
+	case models.MentionSourceMessage:
+		message = fmt.Sprintf("%s vous a mentionné dans un message privé", author.Username)
+		url = fmt.Sprintf("/messages?user=%d", author.ID)
+	default:
+		message = fmt.Sprintf("%s vous a mentionné dans un thread", author.Username)
+		url = fmt.Sprintf("/thread/%d", mention.SourceID)
+	}
+
+	GetNotificationManager().SendNotification(
+		mention.MentionedUserID,
+		"mention",
+		"Nouvelle mention",
+		message,
+		map[string]interface{}{
+			"author_id":   author.ID,
+			"source_type": mention.SourceType,
+			"source_id":   mention.SourceID,
+			"url":         url,
+		},
+	)
+}
+
+// linkMentions transforme les @username mentionnés d'un contenu rendu en liens de profil
+func linkMentions(rendered template.HTML, mentions []*models.Mention) template.HTML {
+	if len(mentions) == 0 {
+		return rendered
+	}
+
+	userIDs := make(map[string]uint, len(mentions))
+	for _, mention := range mentions {
+		userIDs[strings.ToLower(mention.MentionedUsername)] = mention.MentionedUserID
+	}
+
+	// Les mentions dans les liens et le code restent du texte
+	return template.HTML(markdown.MapText(string(rendered), func(text string) string {
+		return models.ReplaceMentions(text, func(username string) (string, bool) {
+			userID, ok := userIDs[strings.ToLower(username)]
+			if !ok {
+				return "", false
+			}
+			return fmt.Sprintf(`<a href="/profile?user=%d" class="mention">@%s</a>`, userID, username), true
+		})
+	}))
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ModerationHandler gère la scission, le déplacement, le retag et la fusion des threads
+// (routes protégées par AdminMiddleware)
+type ModerationHandler struct {
+	moderationService services.ModerationService
+}
+
+// NewModerationHandler crée une nouvelle instance du handler
+func NewModerationHandler(moderationService services.ModerationService) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+	}
+}
+
+// MergeThreadRequest thread dans lequel fusionner le doublon
+type MergeThreadRequest struct {
+	TargetID uint `json:"target_id"`
+}
+
+// SplitThread extrait une sélection de commentaires dans un nouveau thread
+func (h *ModerationHandler) SplitThread(w http.ResponseWriter, r *http.Request) {
+	moderatorID, threadID, ok := moderationRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var dto services.SplitThreadDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	thread, err := h.moderationService.SplitThread(threadID, dto, moderatorID)
+	if err != nil {
+		sendModerationError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Commentaires scindés dans un nouveau thread", map[string]interface{}{
+		"source_thread_id": threadID,
+		"thread":           thread,
+	})
+}
+
+// MoveComments déplace une sélection de commentaires vers un autre thread
+func (h *ModerationHandler) MoveComments(w http.ResponseWriter, r *http.Request) {
+	moderatorID, threadID, ok := moderationRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var dto services.MoveMessagesDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	moved, err := h.moderationService.MoveMessages(threadID, dto, moderatorID)
+	if err != nil {
+		sendModerationError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Commentaires déplacés", map[string]interface{}{
+		"source_thread_id": threadID,
+		"target_thread_id": dto.TargetID,
+		"moved":            moved,
+	})
+}
+
+// RetagThread remplace les tags d'un thread
+func (h *ModerationHandler) RetagThread(w http.ResponseWriter, r *http.Request) {
+	moderatorID, threadID, ok := moderationRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var dto services.RetagThreadDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	thread, err := h.moderationService.RetagThread(threadID, dto, moderatorID)
+	if err != nil {
+		sendModerationError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Tags mis à jour", map[string]interface{}{
+		"thread": thread,
+	})
+}
+
+// MergeThread fusionne un thread en double dans un autre thread
+func (h *ModerationHandler) MergeThread(w http.ResponseWriter, r *http.Request) {
+	moderatorID, threadID, ok := moderationRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req MergeThreadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TargetID == 0 {
+		sendAPIError(w, "ID du thread cible requis", http.StatusBadRequest)
+		return
+	}
+
+	target, err := h.moderationService.MergeThreads(threadID, req.TargetID, moderatorID)
+	if err != nil {
+		sendModerationError(w, err)
+		return
+	}
+
+	log.Printf("🔀 Thread %d fusionné dans le thread %d par l'admin %d", threadID, req.TargetID, moderatorID)
+	sendAPISuccess(w, "Threads fusionnés", map[string]interface{}{
+		"merged_thread_id": threadID,
+		"thread":           target,
+	})
+}
+
+// GetModerationLog retourne le journal des actions de modération d'un thread
+func (h *ModerationHandler) GetModerationLog(w http.ResponseWriter, r *http.Request) {
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	actions, err := h.moderationService.GetThreadLog(uint(threadID))
+	if err != nil {
+		log.Printf("❌ Erreur journal de modération du thread %d: %v", threadID, err)
+		sendAPIError(w, "Erreur lors de la récupération du journal", http.StatusInternalServerError)
+		return
+	}
+
+	sendAPISuccess(w, "Journal de modération récupéré", map[string]interface{}{
+		"actions": actions,
+	})
+}
+
+// moderationRequestIDs extrait l'ID du modérateur et du thread ; écrit l'erreur si besoin
+func moderationRequestIDs(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	moderatorID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return moderatorID, uint(threadID), true
+}
+
+// sendModerationError traduit les erreurs du service de modération en réponses API
+func sendModerationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrThreadNotFound):
+		sendAPIError(w, "Thread non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrThreadArchived):
+		sendAPIError(w, "Cette action est impossible sur un thread archivé", http.StatusForbidden)
+	case errors.Is(err, utils.ErrInvalidInput):
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("❌ Erreur modération: %v", err)
+		sendAPIError(w, "Erreur lors de l'action de modération", http.StatusInternalServerError)
+	}
+}
@@ -3,13 +3,19 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/database"
 
 	"github.com/gorilla/websocket"
 )
@@ -18,6 +24,7 @@ import (
 type NotificationManager struct {
 	clients    map[uint]*websocket.Conn // UserID -> Connection
 	clientsMux sync.RWMutex
+	watching   map[uint]uint // thread affiché par la connexion de chaque utilisateur (voir ThreadWatchers)
 	broadcast  chan NotificationMessage
 	register   chan ClientConnection
 	unregister chan ClientConnection
@@ -25,8 +32,9 @@ type NotificationManager struct {
 
 // ClientConnection représente une connexion client
 type ClientConnection struct {
-	UserID uint
-	Conn   *websocket.Conn
+	UserID   uint
+	ThreadID uint // thread suivi en direct par la page (/ws?thread=<id>), 0 sinon
+	Conn     *websocket.Conn
 }
 
 // NotificationMessage représente un message de notification
@@ -41,12 +49,13 @@ type NotificationMessage struct {
 
 // ActivityData structure pour les données d'activité
 type ActivityData struct {
-	Type       string  `json:"type"`
-	UserName   string  `json:"user_name"`
-	ThreadID   *uint   `json:"thread_id,omitempty"`
-	ThreadName *string `json:"thread_name,omitempty"`
-	TrackName  *string `json:"track_name,omitempty"`
-	Artist     *string `json:"artist,omitempty"`
+	Type       string     `json:"type"`
+	UserName   string     `json:"user_name"`
+	ThreadID   *uint      `json:"thread_id,omitempty"`
+	ThreadName *string    `json:"thread_name,omitempty"`
+	TrackName  *string    `json:"track_name,omitempty"`
+	Artist     *string    `json:"artist,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // fin du morceau en cours d'écoute
 }
 
 var (
@@ -68,6 +77,7 @@ func GetNotificationManager() *NotificationManager {
 	once.Do(func() {
 		notificationManager = &NotificationManager{
 			clients:    make(map[uint]*websocket.Conn),
+			watching:   make(map[uint]uint),
 			broadcast:  make(chan NotificationMessage, 256),
 			register:   make(chan ClientConnection),
 			unregister: make(chan ClientConnection),
@@ -86,6 +96,27 @@ func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Page d'un thread : l'accès est vérifié une fois, à la connexion
+	var threadID uint
+	if value := r.URL.Query().Get("thread"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			http.Error(w, "ID de thread invalide", http.StatusBadRequest)
+			return
+		}
+		threadService := services.NewThreadService(
+			repositories.NewThreadRepository(database.DB),
+			repositories.NewTagRepository(database.DB),
+			repositories.NewMessageRepository(database.DB),
+			database.DB,
+		)
+		if err := threadService.CanViewThread(uint(id), &user.ID); err != nil {
+			http.Error(w, "Thread non trouvé", http.StatusNotFound)
+			return
+		}
+		threadID = uint(id)
+	}
+
 	// Upgrader la connexion vers WebSocket
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -95,8 +126,9 @@ func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Enregistrer le client
 	client := ClientConnection{
-		UserID: user.ID,
-		Conn:   conn,
+		UserID:   user.ID,
+		ThreadID: threadID,
+		Conn:     conn,
 	}
 
 	notificationManager := GetNotificationManager()
@@ -115,6 +147,11 @@ func (nm *NotificationManager) run() {
 		case client := <-nm.register:
 			nm.clientsMux.Lock()
 			nm.clients[client.UserID] = client.Conn
+			if client.ThreadID != 0 {
+				nm.watching[client.UserID] = client.ThreadID
+			} else {
+				delete(nm.watching, client.UserID)
+			}
 			nm.clientsMux.Unlock()
 			log.Printf("🔌 Client WebSocket enregistré: UserID %d", client.UserID)
 
@@ -122,6 +159,7 @@ func (nm *NotificationManager) run() {
 			nm.clientsMux.Lock()
 			if _, ok := nm.clients[client.UserID]; ok {
 				delete(nm.clients, client.UserID)
+				delete(nm.watching, client.UserID)
 				client.Conn.Close()
 			}
 			nm.clientsMux.Unlock()
@@ -184,11 +222,67 @@ func (nm *NotificationManager) SendNotification(userID uint, notType, title, mes
 	}
 }
 
-// BroadcastActivity diffuse une activité utilisateur
-func (nm *NotificationManager) BroadcastActivity(userID uint, userName string, activityType string, data interface{}) {
-	// Récupérer la liste des amis pour diffuser l'activité
-	friends := nm.getFriendsOfUser(userID)
+// ConnectedUsers retourne les utilisateurs connectés au WebSocket de notifications
+func (nm *NotificationManager) ConnectedUsers() []uint {
+	nm.clientsMux.RLock()
+	defer nm.clientsMux.RUnlock()
+
+	userIDs := make([]uint, 0, len(nm.clients))
+	for userID := range nm.clients {
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs
+}
+
+// ThreadWatchers liste les utilisateurs connectés dont la page affiche le thread
+func (nm *NotificationManager) ThreadWatchers(threadID uint) []uint {
+	nm.clientsMux.RLock()
+	defer nm.clientsMux.RUnlock()
+
+	var userIDs []uint
+	for userID, watched := range nm.watching {
+		if watched == threadID {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs
+}
+
+// NotifyThreadPublished prévient l'auteur et ses amis qu'un thread programmé vient d'être publié
+func NotifyThreadPublished(thread *services.ThreadResponseDTO) {
+	nm := GetNotificationManager()
+	data := map[string]interface{}{
+		"thread_id": thread.ID,
+		"url":       fmt.Sprintf("/thread/%d", thread.ID),
+	}
+
+	nm.SendNotification(thread.Author.ID, "thread_published", "Thread publié",
+		fmt.Sprintf("Votre thread programmé « %s » est maintenant en ligne", thread.Title), data)
 
+	db := database.DB
+	friends, err := repositories.NewFriendshipRepository(db).GetFriends(thread.Author.ID)
+	if err != nil {
+		log.Printf("❌ Erreur récupération amis pour notification: %v", err)
+		return
+	}
+
+	threadRepo := repositories.NewThreadRepository(db)
+	for _, friend := range friends {
+		// Ne pas notifier les amis qui n'ont pas accès à un thread privé
+		if thread.Visibility == models.VisibilityPrivate {
+			if allowed, err := threadRepo.CanView(thread.ID, friend.ID); err != nil || !allowed {
+				continue
+			}
+		}
+
+		nm.SendNotification(friend.ID, "thread_published", "Nouveau thread",
+			fmt.Sprintf("%s a publié « %s »", thread.Author.Username, thread.Title), data)
+	}
+}
+
+// BroadcastActivity diffuse une activité utilisateur ; une écoute (« listening ») est d'abord
+// enregistrée dans l'historique et devient le « en écoute » de l'utilisateur jusqu'à la fin du morceau
+func (nm *NotificationManager) BroadcastActivity(userID uint, userName string, activityType string, data interface{}) error {
 	activityData := ActivityData{
 		Type:     activityType,
 		UserName: userName,
@@ -197,14 +291,17 @@ func (nm *NotificationManager) BroadcastActivity(userID uint, userName string, a
 	// Enrichir les données selon le type d'activité
 	switch activityType {
 	case "listening":
-		if musicData, ok := data.(map[string]string); ok {
-			if track, exists := musicData["track"]; exists {
-				activityData.TrackName = &track
-			}
-			if artist, exists := musicData["artist"]; exists {
-				activityData.Artist = &artist
-			}
+		var listening services.ListeningDTO
+		if raw, err := json.Marshal(data); err == nil {
+			json.Unmarshal(raw, &listening)
+		}
+		event, err := services.NewListeningServiceWithDB(database.DB).RecordListening(userID, listening)
+		if err != nil {
+			return err
 		}
+		activityData.TrackName = &event.TrackName
+		activityData.Artist = &event.Artist
+		activityData.ExpiresAt = &event.ExpiresAt
 	case "thread_created", "thread_liked":
 		if threadData, ok := data.(map[string]interface{}); ok {
 			if threadID, ok := threadData["thread_id"].(uint); ok {
@@ -217,19 +314,28 @@ func (nm *NotificationManager) BroadcastActivity(userID uint, userName string, a
 	}
 
 	// Envoyer la notification à tous les amis
-	for _, friendID := range friends {
+	for _, friendID := range nm.getFriendsOfUser(userID) {
 		nm.SendNotification(friendID, "activity",
 			fmt.Sprintf("Activité de %s", userName),
 			nm.formatActivityMessage(activityType, userName, activityData),
 			activityData)
 	}
+	return nil
 }
 
-// getFriendsOfUser récupère les amis d'un utilisateur (simulation pour l'instant)
+// getFriendsOfUser récupère les amis d'un utilisateur
 func (nm *NotificationManager) getFriendsOfUser(userID uint) []uint {
-	// TODO: Implémenter la logique réelle pour récupérer les amis
-	// Pour l'instant, retourner une liste vide
-	return []uint{}
+	friends, err := repositories.NewFriendshipRepository(database.DB).GetFriends(userID)
+	if err != nil {
+		log.Printf("❌ Erreur récupération amis pour diffusion d'activité: %v", err)
+		return nil
+	}
+
+	friendIDs := make([]uint, len(friends))
+	for i, friend := range friends {
+		friendIDs[i] = friend.ID
+	}
+	return friendIDs
 }
 
 // formatActivityMessage formate le message d'activité
@@ -348,9 +454,17 @@ func ActivityAPIHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Diffuser l'activité
+	// Diffuser l'activité (et enregistrer les écoutes)
 	nm := GetNotificationManager()
-	nm.BroadcastActivity(user.ID, user.Username, requestData.Type, requestData.Data)
+	if err := nm.BroadcastActivity(user.ID, user.Username, requestData.Type, requestData.Data); err != nil {
+		if errors.Is(err, utils.ErrInvalidInput) {
+			sendAPIError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("❌ Erreur enregistrement de l'activité: %v", err)
+		sendAPIError(w, "Erreur lors de l'enregistrement de l'activité", http.StatusInternalServerError)
+		return
+	}
 
 	sendAPISuccess(w, "Activité diffusée", map[string]interface{}{
 		"activity_type": requestData.Type,
@@ -4,6 +4,7 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
@@ -13,6 +14,7 @@ import (
 	"rythmitbackend/internal/models"
 	"rythmitbackend/internal/repositories"
 	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
 	"rythmitbackend/pkg/database"
 	"strconv"
 	"strings"
@@ -58,18 +60,28 @@ func InitTemplates() error {
 
 // PageData structure de données pour les templates
 type PageData struct {
-	Title       string
-	User        *User
-	IsLoggedIn  bool
-	Friends     []Friend
-	Threads     []Thread
-	Messages    []Message
-	Trends      []Trend
-	CurrentPage string
-	Profile     *ProfileData // Données du profil personnalisé
+	Title         string
+	User          *User
+	IsLoggedIn    bool
+	Friends       []Friend
+	Threads       []Thread
+	Announcements []Announcement // Annonces affichées en bannière sur l'accueil
+	Messages      []Message
+	Trends        []Trend
+	CurrentPage   string
+	Profile       *ProfileData // Données du profil personnalisé
 	// Données pour la page thread
 	Thread   *Thread   `json:"thread,omitempty"`
 	Comments []Comment `json:"comments,omitempty"`
+	// Suivi de lecture de la page thread
+	IsSubscribed         bool
+	UnreadCount          int
+	FirstUnreadCommentID uint
+	IsBookmarked         bool
+	ReactionSet          []string      // Réactions proposées sur les commentaires
+	Review               *ThreadReview // Critique notée du thread (nil pour une discussion)
+	// Données pour la page collection
+	Collection *CollectionPage
 	// Données pour l'authentification
 	IsSignupMode   bool
 	ErrorMessage   string
@@ -82,6 +94,12 @@ type PageData struct {
 	IsOwnProfile     bool
 	CurrentUser      *User   // Utilisateur connecté (différent de User si on visite un autre profil)
 	FriendshipStatus *string // Statut d'amitié avec l'utilisateur affiché
+	// Threads republiés affichés sur le profil
+	Reposts []Repost
+	// Morceau en cours d'écoute et écoutes récentes du profil
+	NowPlaying    *Listen
+	RecentListens []Listen
+	ListensHidden bool // écoutes réservées aux amis du profil
 }
 
 // ProfileData structure pour les données de profil personnalisé
@@ -111,22 +129,68 @@ type Friend struct {
 
 // Thread structure pour les discussions
 type Thread struct {
-	ID           uint        `json:"id"`
-	Title        string      `json:"title"`
-	Content      string      `json:"content"`
-	ImageURL     *string     `json:"image_url,omitempty"`
-	Author       string      `json:"author"`
-	AuthorAvatar string      `json:"author_avatar"`
-	TimeAgo      string      `json:"time_ago"`
-	Genre        string      `json:"genre"`
-	Tags         []string    `json:"tags"`
-	Likes        int         `json:"likes"`
-	IsLiked      bool        `json:"is_liked"`
-	Comments     int         `json:"comments"`
-	Shares       int         `json:"shares"`
-	Visibility   string      `json:"visibility"`
-	State        string      `json:"state"`
-	MusicTrack   *MusicTrack `json:"music_track,omitempty"`
+	ID           uint                 `json:"id"`
+	Title        string               `json:"title"`
+	Content      string               `json:"content"`
+	ContentHTML  template.HTML        `json:"-"` // Contenu échappé avec les mentions en liens
+	ImageURL     *string              `json:"image_url,omitempty"`
+	Author       string               `json:"author"`
+	AuthorAvatar string               `json:"author_avatar"`
+	TimeAgo      string               `json:"time_ago"`
+	Genre        string               `json:"genre"`
+	Tags         []string             `json:"tags"`
+	Likes        int                  `json:"likes"`
+	IsLiked      bool                 `json:"is_liked"`
+	Comments     int                  `json:"comments"`
+	Shares       int                  `json:"shares"`
+	Views        int                  `json:"views"`
+	Visibility   string               `json:"visibility"`
+	Access       string               `json:"access"`
+	State        string               `json:"state"`
+	IsPinned     bool                 `json:"is_pinned"`
+	UnreadCount  int                  `json:"unread_count"`
+	MusicTrack   *MusicTrack          `json:"music_track,omitempty"`
+	MusicEmbeds  []*models.MusicEmbed `json:"music_embeds,omitempty"` // liens musicaux de la description
+}
+
+// Announcement structure pour les bannières d'annonce
+type Announcement struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+// Repost structure pour un thread republié sur un profil
+type Repost struct {
+	Thread  *Thread
+	Comment string
+	TimeAgo string
+}
+
+// Listen structure pour une écoute affichée sur un profil
+type Listen struct {
+	Track    string
+	Artist   string
+	Album    string
+	MusicURL string
+	TimeAgo  string
+}
+
+// CollectionPage structure pour l'affichage d'une collection de threads
+type CollectionPage struct {
+	ID          uint
+	Name        string
+	Description string
+	Visibility  string
+	Owner       string
+	IsOwner     bool
+	Items       []CollectionPageItem
+}
+
+// CollectionPageItem thread d'une collection avec la note de son propriétaire
+type CollectionPageItem struct {
+	Position int
+	Note     string
+	Thread   *Thread
 }
 
 // MusicTrack structure pour les pistes musicales
@@ -148,16 +212,29 @@ type Message struct {
 
 // Comment structure pour les commentaires de threads
 type Comment struct {
-	ID           uint      `json:"id"`
-	Content      string    `json:"content"`
-	ImageURL     *string   `json:"image_url,omitempty"`
-	Author       string    `json:"author"`
-	AuthorAvatar string    `json:"author_avatar"`
-	TimeAgo      string    `json:"time_ago"`
-	Likes        int       `json:"likes"`    // Nombre de likes
-	IsLiked      bool      `json:"is_liked"` // Utilisateur a liké
-	IsOP         bool      `json:"is_op"`    // Original Poster
-	Replies      []Comment `json:"replies,omitempty"`
+	ID           uint                      `json:"id"`
+	Content      string                    `json:"content"`
+	ContentHTML  template.HTML             `json:"-"` // Contenu échappé avec les mentions en liens
+	ImageURL     *string                   `json:"image_url,omitempty"`
+	Author       string                    `json:"author"`
+	AuthorAvatar string                    `json:"author_avatar"`
+	TimeAgo      string                    `json:"time_ago"`
+	Likes        int                       `json:"likes"`    // Nombre de likes
+	IsLiked      bool                      `json:"is_liked"` // Utilisateur a liké
+	IsOP         bool                      `json:"is_op"`    // Original Poster
+	IsUnread     bool                      `json:"is_unread"`
+	Replies      []Comment                 `json:"replies,omitempty"`
+	Reactions    []*models.ReactionSummary `json:"reactions,omitempty"`
+	MusicEmbeds  []*models.MusicEmbed      `json:"music_embeds,omitempty"`
+}
+
+// ThreadReview critique notée affichée sur la page thread
+type ThreadReview struct {
+	SubjectName  string                         `json:"subject_name"`
+	SubjectType  string                         `json:"subject_type"` // artist, album ou track
+	Score        float64                        `json:"score"`
+	Tracks       []services.ReviewTrackScoreDTO `json:"tracks,omitempty"`
+	TrackAverage float64                        `json:"track_average,omitempty"`
 }
 
 // Trend structure pour les tendances
@@ -354,7 +431,29 @@ func handleComment(w http.ResponseWriter, r *http.Request, threadID string, user
 
 func handleShare(w http.ResponseWriter, r *http.Request, threadID string, user *User) {
 	log.Printf("🔄 Share - Thread: %s, User: %s", threadID, user.Username)
-	// TODO: Implémenter le partage
+
+	id, err := strconv.ParseUint(threadID, 10, 32)
+	if err != nil {
+		log.Printf("❌ ID de thread invalide pour le partage: %s", threadID)
+		return
+	}
+
+	thread, err := newPageShareService().Repost(uint(id), user.ID, r.FormValue("comment"))
+	if err != nil {
+		log.Printf("❌ Erreur repost du thread %d: %v", id, err)
+		return
+	}
+
+	NotifyThreadShared(thread, user.ID, models.ShareKindRepost)
+}
+
+// newPageShareService construit le service de partage utilisé par les pages
+func newPageShareService() services.ShareService {
+	db := database.DB
+	threadRepo := repositories.NewThreadRepository(db)
+	threadService := services.NewThreadService(threadRepo, repositories.NewTagRepository(db), repositories.NewMessageRepository(db), db)
+	messageService := services.NewMessageService(repositories.NewDirectMessageRepository(db), repositories.NewFriendshipRepository(db), services.NewMentionServiceWithDB(db), repositories.NewReactionRepository(db))
+	return services.NewShareService(repositories.NewShareRepository(db), threadRepo, threadService, messageService)
 }
 
 func handleCreatePost(w http.ResponseWriter, r *http.Request, user *User) {
@@ -399,7 +498,11 @@ func IndexHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Créer le service pour récupérer les threads de la DB
-	threadsFromDB, err := getThreadsFromDatabase()
+	var viewerID *uint
+	if user != nil {
+		viewerID = &user.ID
+	}
+	threadsFromDB, err := getThreadsFromDatabase(viewerID)
 	var threads []Thread
 
 	if err != nil {
@@ -411,12 +514,18 @@ func IndexHandler(w http.ResponseWriter, r *http.Request) {
 		log.Printf("✅ %d threads récupérés de la DB", len(threads))
 	}
 
+	announcements, err := getAnnouncementsFromDatabase()
+	if err != nil {
+		log.Printf("❌ Erreur récupération annonces: %v", err)
+	}
+
 	data := PageData{
 		Title:          "Accueil - Rythm'it",
 		CurrentPage:    "index",
 		IsLoggedIn:     isLoggedIn,
 		User:           user,
 		Threads:        threads, // Utiliser les threads de la DB
+		Announcements:  announcements,
 		ErrorMessage:   errorMessage,
 		SuccessMessage: successMessage,
 		Trends: []Trend{
@@ -573,6 +682,17 @@ func ThreadHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Un doublon fusionné redirige vers le thread conservé
+	if threadDetails.MergedIntoID != nil {
+		http.Redirect(w, r, fmt.Sprintf("/thread/%d", *threadDetails.MergedIntoID), http.StatusMovedPermanently)
+		return
+	}
+
+	// Compter la vue (hors robots et auteur) : dédupliquée et écrite en base par lots
+	if user == nil || user.ID != threadDetails.Author.ID {
+		services.RecordThreadView(threadDetails.ID, threadViewerKey(r, user), r.UserAgent())
+	}
+
 	// Convertir le thread
 	thread := convertDBThreadToPageThread(*threadDetails, user, likeRepo)
 
@@ -589,7 +709,7 @@ func ThreadHandler(w http.ResponseWriter, r *http.Request) {
 		userID = &user.ID
 	}
 
-	messages, _, err := messageRepo.GetMessagesWithVotes(uint(threadID), userID, params, "newest")
+	messages, _, err := messageRepo.GetMessagesWithVotes(uint(threadID), userID, params, "date")
 	if err != nil {
 		log.Printf("❌ Erreur récupération commentaires: %v", err)
 		// Continuer avec des commentaires vides plutôt que d'échouer
@@ -599,6 +719,74 @@ func ThreadHandler(w http.ResponseWriter, r *http.Request) {
 	// Convertir les messages en commentaires
 	comments := convertMessagesToComments(messages, threadDetails.Author.Username, userIDPtr)
 
+	// Transformer les @mentions en liens de profil
+	renderPageMentions(&thread, comments)
+	attachPageReactions(comments, userIDPtr)
+	attachPageEmbeds(comments, userIDPtr)
+
+	// Critique notée (artiste, album ou morceau) affichée sous le thread
+	var threadReview *ThreadReview
+	review, err := services.NewReviewServiceWithDB(db).GetReview(uint(threadID), userIDPtr)
+	if err == nil {
+		threadReview = &ThreadReview{
+			SubjectName: review.Subject.Name,
+			SubjectType: review.Subject.Type,
+			Score:       review.Score,
+			Tracks:      review.Tracks,
+		}
+		if review.TrackAverage != nil {
+			threadReview.TrackAverage = *review.TrackAverage
+		}
+	} else if !errors.Is(err, utils.ErrReviewNotFound) {
+		log.Printf("❌ Erreur récupération critique du thread %d: %v", threadID, err)
+	}
+
+	// Suivi de lecture : repérer les commentaires non lus puis avancer la position
+	var isSubscribed bool
+	var unreadCount int
+	var firstUnreadID uint
+	var isBookmarked bool
+	if user != nil {
+		subscriptionService := services.NewSubscriptionService(repositories.NewSubscriptionRepository(db), threadRepo, threadService)
+
+		lastRead, err := subscriptionService.GetLastRead(uint(threadID), user.ID)
+		if err != nil {
+			log.Printf("❌ Erreur récupération position de lecture: %v", err)
+		}
+
+		var lastMessageID uint
+		for i, msg := range messages {
+			if msg.ID > lastMessageID {
+				lastMessageID = msg.ID
+			}
+			// Une première visite ne marque rien comme non lu
+			if lastRead > 0 && msg.ID > lastRead && msg.UserID != user.ID {
+				comments[i].IsUnread = true
+				unreadCount++
+				if firstUnreadID == 0 {
+					firstUnreadID = msg.ID
+				}
+			}
+		}
+
+		if lastMessageID > 0 {
+			if err := subscriptionService.MarkRead(uint(threadID), user.ID, lastMessageID); err != nil {
+				log.Printf("❌ Erreur mise à jour position de lecture: %v", err)
+			}
+		}
+
+		isSubscribed, err = subscriptionService.IsSubscribed(uint(threadID), user.ID)
+		if err != nil {
+			log.Printf("❌ Erreur vérification abonnement: %v", err)
+		}
+
+		bookmarkService := services.NewBookmarkService(repositories.NewBookmarkRepository(db), threadService)
+		isBookmarked, err = bookmarkService.IsBookmarked(uint(threadID), user.ID)
+		if err != nil {
+			log.Printf("❌ Erreur vérification favori: %v", err)
+		}
+	}
+
 	// Récupérer les messages d'erreur/succès
 	errorParam := r.URL.Query().Get("error")
 	successParam := r.URL.Query().Get("success")
@@ -609,6 +797,10 @@ func ThreadHandler(w http.ResponseWriter, r *http.Request) {
 		errorMessage = "Erreur lors de l'ajout du commentaire"
 	case "empty_comment":
 		errorMessage = "Le commentaire ne peut pas être vide"
+	case "thread_closed":
+		errorMessage = "Ce thread est fermé : les nouveaux commentaires et votes ne sont plus acceptés"
+	case "thread_archived":
+		errorMessage = "Ce thread est archivé : il est consultable en lecture seule"
 	}
 
 	switch successParam {
@@ -617,20 +809,91 @@ func ThreadHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := PageData{
-		Title:          thread.Title + " - Rythm'it",
-		CurrentPage:    "thread",
-		IsLoggedIn:     isLoggedIn,
-		User:           user,
-		Thread:         &thread,
-		Comments:       comments,
-		ErrorMessage:   errorMessage,
-		SuccessMessage: successMessage,
+		Title:                thread.Title + " - Rythm'it",
+		CurrentPage:          "thread",
+		IsLoggedIn:           isLoggedIn,
+		User:                 user,
+		Thread:               &thread,
+		Comments:             comments,
+		ErrorMessage:         errorMessage,
+		SuccessMessage:       successMessage,
+		IsSubscribed:         isSubscribed,
+		UnreadCount:          unreadCount,
+		FirstUnreadCommentID: firstUnreadID,
+		IsBookmarked:         isBookmarked,
+		ReactionSet:          services.NewReactionServiceWithDB(db).AllowedReactions(),
+		Review:               threadReview,
 	}
 
 	log.Printf("✅ Thread %d chargé: %s avec %d commentaires", threadID, thread.Title, len(comments))
 	renderTemplate(w, "thread.html", data)
 }
 
+// CollectionPageHandler affiche une collection de threads (les collections privées uniquement à leur propriétaire)
+func CollectionPageHandler(w http.ResponseWriter, r *http.Request) {
+	collectionID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		http.Error(w, "ID de collection invalide", http.StatusBadRequest)
+		return
+	}
+
+	user, isLoggedIn := getUserFromCookie(r)
+	var userIDPtr *uint
+	if user != nil {
+		userIDPtr = &user.ID
+	}
+
+	db := database.DB
+	threadService := services.NewThreadService(
+		repositories.NewThreadRepository(db),
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+	collectionService := services.NewCollectionService(repositories.NewCollectionRepository(db), threadService)
+
+	collection, err := collectionService.GetCollection(uint(collectionID), userIDPtr)
+	if err != nil {
+		log.Printf("❌ Collection %d non trouvée: %v", collectionID, err)
+		http.Error(w, "Collection non trouvée", http.StatusNotFound)
+		return
+	}
+
+	page := &CollectionPage{
+		ID:         collection.ID,
+		Name:       collection.Name,
+		Visibility: collection.Visibility,
+		Owner:      collection.Owner.Username,
+		IsOwner:    user != nil && user.ID == collection.Owner.ID,
+		Items:      make([]CollectionPageItem, 0, len(collection.Items)),
+	}
+	if collection.Description != nil {
+		page.Description = *collection.Description
+	}
+
+	for i, item := range collection.Items {
+		pageItem := CollectionPageItem{
+			Position: i + 1,
+			Thread:   convertThreadResponseToPageThread(item.Thread, user),
+		}
+		if item.Note != nil {
+			pageItem.Note = *item.Note
+		}
+		page.Items = append(page.Items, pageItem)
+	}
+
+	data := PageData{
+		Title:       collection.Name + " - Rythm'it",
+		CurrentPage: "collection",
+		IsLoggedIn:  isLoggedIn,
+		User:        user,
+		Collection:  page,
+	}
+
+	log.Printf("✅ Collection %d chargée: %s avec %d threads", collectionID, collection.Name, len(page.Items))
+	renderTemplate(w, "collection.html", data)
+}
+
 // ProfileHandler gère la page de profil (GET) et la mise à jour (POST)
 func ProfileHandler(w http.ResponseWriter, r *http.Request) {
 	log.Printf("👤 ProfileHandler appelé - Method: %s", r.Method)
@@ -851,6 +1114,28 @@ func ProfileHandler(w http.ResponseWriter, r *http.Request) {
 		log.Printf("👥 Statut d'amitié: %v", friendshipStatus)
 	}
 
+	// Récupérer les threads republiés visibles par l'utilisateur connecté
+	var reposts []Repost
+	repostDTOs, err := newPageShareService().GetUserReposts(targetUser.ID, &currentUser.ID)
+	if err != nil {
+		log.Printf("⚠️ Erreur récupération reposts: %v", err)
+	}
+	for _, repostDTO := range repostDTOs {
+		repost := Repost{
+			Thread: convertThreadResponseToPageThread(repostDTO.Thread, currentUser),
+		}
+		if repostDTO.Comment != nil {
+			repost.Comment = *repostDTO.Comment
+		}
+		if createdAt, err := time.Parse("2006-01-02T15:04:05Z", repostDTO.CreatedAt); err == nil {
+			repost.TimeAgo = formatTimeAgo(createdAt)
+		}
+		reposts = append(reposts, repost)
+	}
+
+	// Récupérer le morceau en cours d'écoute et l'historique d'écoute
+	nowPlaying, recentListens, listensHidden := getProfileListens(currentUser.ID, targetUser.ID)
+
 	// Récupérer les messages d'erreur/succès depuis les query parameters
 	errorParam := r.URL.Query().Get("error")
 	successParam := r.URL.Query().Get("success")
@@ -905,6 +1190,10 @@ func ProfileHandler(w http.ResponseWriter, r *http.Request) {
 		FriendshipStatus: friendshipStatus,
 		ErrorMessage:     errorMessage,
 		SuccessMessage:   successMessage,
+		Reposts:          reposts,
+		NowPlaying:       nowPlaying,
+		RecentListens:    recentListens,
+		ListensHidden:    listensHidden,
 	}
 
 	renderTemplate(w, "profile.html", data)
@@ -1200,6 +1489,40 @@ func LogoutHandler(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/signin", http.StatusSeeOther)
 }
 
+// getProfileListens récupère le morceau en cours d'écoute (nil si aucun) et les écoutes récentes d'un utilisateur ;
+// hidden indique que viewerID n'est ni l'utilisateur ni l'un de ses amis
+func getProfileListens(viewerID, userID uint) (nowPlaying *Listen, listens []Listen, hidden bool) {
+	playing, events, err := services.NewListeningServiceWithDB(database.DB).GetUserListens(viewerID, userID, services.RecentListensLimit)
+	if errors.Is(err, utils.ErrUnauthorized) {
+		return nil, nil, true
+	}
+	if err != nil {
+		log.Printf("⚠️ Erreur récupération des écoutes: %v", err)
+	}
+
+	if playing != nil {
+		listen := convertListeningEvent(playing)
+		nowPlaying = &listen
+	}
+	listens = make([]Listen, 0, len(events))
+	for _, event := range events {
+		listens = append(listens, convertListeningEvent(event))
+	}
+
+	return nowPlaying, listens, false
+}
+
+// convertListeningEvent convertit une écoute pour les templates
+func convertListeningEvent(event *models.ListeningEvent) Listen {
+	return Listen{
+		Track:    event.TrackName,
+		Artist:   event.Artist,
+		Album:    event.Album,
+		MusicURL: event.MusicURL,
+		TimeAgo:  formatTimeAgo(event.StartedAt),
+	}
+}
+
 // ProfileAPIHandler gère l'API du profil (pour le JavaScript)
 func ProfileAPIHandler(w http.ResponseWriter, r *http.Request) {
 	log.Printf("🔌 ProfileAPIHandler appelé")
@@ -1254,14 +1577,14 @@ func renderTemplate(w http.ResponseWriter, templateName string, data PageData) {
 	log.Printf("✅ Template %s rendu avec succès", templateName)
 }
 
-// getThreadsFromDatabase récupère les threads depuis la base de données
-func getThreadsFromDatabase() ([]services.ThreadDTO, error) {
+// getThreadsFromDatabase récupère les threads visibles par viewerID depuis la base de données
+func getThreadsFromDatabase(viewerID *uint) ([]services.ThreadDTO, error) {
 	// Créer les dépendances
 	db := database.DB
 	threadRepo := repositories.NewThreadRepository(db)
 	tagRepo := repositories.NewTagRepository(db)
 	messageRepo := repositories.NewMessageRepository(db)
-	threadService := services.NewThreadService(threadRepo, tagRepo, messageRepo, db)
+	threadService := services.NewThreadService(threadRepo, tagRepo, messageRepo, db).ForViewer(viewerID)
 
 	// Récupérer les threads avec pagination (5 premiers threads)
 	params := models.PaginationParams{
@@ -1304,6 +1627,10 @@ func getThreadsFromDatabase() ([]services.ThreadDTO, error) {
 			CreatedAt:    createdAt,
 			UpdatedAt:    updatedAt,
 			Tags:         make([]string, len(threadResp.Tags)),
+			IsPinned:     threadResp.IsPinned,
+			UnreadCount:  threadResp.UnreadCount,
+			ShareCount:   threadResp.ShareCount,
+			ViewCount:    threadResp.ViewCount,
 		}
 
 		// Convertir les tags
@@ -1317,6 +1644,29 @@ func getThreadsFromDatabase() ([]services.ThreadDTO, error) {
 	return threads, nil
 }
 
+// getAnnouncementsFromDatabase récupère les annonces à afficher en bannière sur l'accueil
+func getAnnouncementsFromDatabase() ([]Announcement, error) {
+	db := database.DB
+	threadService := services.NewThreadService(
+		repositories.NewThreadRepository(db),
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+
+	dbAnnouncements, err := threadService.GetAnnouncements()
+	if err != nil {
+		return nil, err
+	}
+
+	announcements := make([]Announcement, 0, len(dbAnnouncements))
+	for _, a := range dbAnnouncements {
+		announcements = append(announcements, Announcement{ID: a.ID, Title: a.Title})
+	}
+
+	return announcements, nil
+}
+
 // getAvailableTagsFromDatabase récupère les tags disponibles depuis la base de données
 func getAvailableTagsFromDatabase() ([]Tag, error) {
 	// Créer les dépendances
@@ -1396,8 +1746,11 @@ func convertDBThreadsToPageThreads(dbThreads []services.ThreadDTO, currentUser *
 			Tags:         dbThread.Tags,
 			Likes:        likesCount,
 			IsLiked:      isLiked,
+			IsPinned:     dbThread.IsPinned,
+			UnreadCount:  dbThread.UnreadCount,
 			Comments:     dbThread.MessageCount,
-			Shares:       0,        // TODO: implémenter les partages
+			Shares:       dbThread.ShareCount,
+			Views:        dbThread.ViewCount,
 			Visibility:   "public", // Valeur par défaut
 			State:        "ouvert", // Valeur par défaut
 			MusicTrack:   nil,      // Pas de piste musicale pour l'instant
@@ -1480,6 +1833,17 @@ func getUserFromCookie(r *http.Request) (*User, bool) {
 	return user, true
 }
 
+// viewerIDFromRequest retourne l'ID de l'utilisateur connecté (nil si anonyme)
+func viewerIDFromRequest(r *http.Request) *uint {
+	if userID, ok := r.Context().Value("user_id").(uint); ok && userID > 0 {
+		return &userID
+	}
+	if user, ok := getUserFromCookie(r); ok {
+		return &user.ID
+	}
+	return nil
+}
+
 // TagsAPIHandler retourne la liste des tags disponibles en JSON
 func TagsAPIHandler(w http.ResponseWriter, r *http.Request) {
 	log.Printf("🏷️ TagsAPIHandler appelé")
@@ -1536,13 +1900,19 @@ func ThreadsAPIHandler(w http.ResponseWriter, r *http.Request) {
 	threadRepo := repositories.NewThreadRepository(db)
 	tagRepo := repositories.NewTagRepository(db)
 	messageRepo := repositories.NewMessageRepository(db)
-	threadService := services.NewThreadService(threadRepo, tagRepo, messageRepo, db)
+	threadService := services.NewThreadService(threadRepo, tagRepo, messageRepo, db).ForViewer(viewerIDFromRequest(r))
+
+	// Tri : les plus récents par défaut, ou les plus vus (?sort=views)
+	sort := models.ThreadSortRecent
+	if r.URL.Query().Get("sort") == models.ThreadSortViews {
+		sort = models.ThreadSortViews
+	}
 
 	// Paramètres de pagination
 	params := models.PaginationParams{
 		Page:    page,
 		PerPage: perPage,
-		Sort:    "created_at",
+		Sort:    sort,
 		Order:   "DESC",
 	}
 
@@ -1611,7 +1981,8 @@ func ThreadsAPIHandler(w http.ResponseWriter, r *http.Request) {
 			Likes:        likesCount,
 			IsLiked:      isLiked,
 			Comments:     threadResp.MessageCount,
-			Shares:       0,
+			Shares:       threadResp.ShareCount,
+			Views:        threadResp.ViewCount,
 			MusicTrack:   nil,
 		}
 
@@ -1868,7 +2239,17 @@ func handleAddComment(w http.ResponseWriter, r *http.Request, threadID uint, use
 
 	// Créer les services
 	db := database.DB
+	threadRepo := repositories.NewThreadRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
 	messageRepo := repositories.NewMessageRepository(db)
+	threadService := services.NewThreadService(threadRepo, tagRepo, messageRepo, db)
+
+	// Vérifier que l'utilisateur voit le thread et que son état accepte encore des commentaires
+	if err := threadService.CheckThreadAction(threadID, services.ThreadActionComment, user.ID); err != nil {
+		log.Printf("🔒 Commentaire refusé sur thread %d: %v", threadID, err)
+		http.Redirect(w, r, fmt.Sprintf("/thread/%d?error=%s", threadID, threadStateErrorParam(err)), http.StatusSeeOther)
+		return
+	}
 
 	// Créer le message/commentaire
 	message := &models.Message{
@@ -1889,10 +2270,45 @@ func handleAddComment(w http.ResponseWriter, r *http.Request, threadID uint, use
 		return
 	}
 
+	// Enregistrer les mentions (@username) et notifier les utilisateurs mentionnés
+	mentionService := services.NewMentionServiceWithDB(db)
+	if _, err := mentionService.RecordMentions(services.MentionSource{
+		Type:     models.MentionSourceComment,
+		ID:       message.ID,
+		ThreadID: &threadID,
+		AuthorID: user.ID,
+		Content:  content,
+	}); err != nil {
+		log.Printf("❌ Erreur mentions du commentaire %d: %v", message.ID, err)
+	}
+
+	// Enregistrer les liens musicaux reconnus dans le commentaire
+	if _, err := services.NewEmbedServiceWithDB(db).SyncEmbeds(models.EmbedTargetComment, message.ID, content); err != nil {
+		log.Printf("❌ Erreur liens musicaux du commentaire %d: %v", message.ID, err)
+	}
+
+	// Abonner le commentateur et notifier les abonnés du thread
+	if thread, err := threadRepo.FindByID(threadID); err == nil {
+		subscriptionService := services.NewSubscriptionService(repositories.NewSubscriptionRepository(db), threadRepo, threadService)
+		NotifyThreadSubscribers(subscriptionService, message, thread.Title, user.Username)
+	}
+
 	log.Printf("✅ Commentaire ajouté par %s sur thread %d", user.Username, threadID)
 	http.Redirect(w, r, fmt.Sprintf("/thread/%d?success=comment_added", threadID), http.StatusSeeOther)
 }
 
+// threadStateErrorParam traduit une erreur de cycle de vie en paramètre ?error=
+func threadStateErrorParam(err error) string {
+	switch {
+	case errors.Is(err, utils.ErrThreadClosed):
+		return "thread_closed"
+	case errors.Is(err, utils.ErrThreadArchived):
+		return "thread_archived"
+	default:
+		return "comment_failed"
+	}
+}
+
 // convertDBThreadToPageThread convertit un thread de la DB au format de la page
 func convertDBThreadToPageThread(threadResp services.ThreadResponseDTO, user *User, likeRepo repositories.LikeRepository) Thread {
 	// Générer les initiales
@@ -1935,6 +2351,7 @@ func convertDBThreadToPageThread(threadResp services.ThreadResponseDTO, user *Us
 		ID:           threadResp.ID,
 		Title:        threadResp.Title,
 		Content:      threadResp.Description,
+		ContentHTML:  template.HTML(threadResp.DescriptionHTML),
 			ImageURL:     threadResp.ImageURL,
 		Author:       authorDisplay,
 		AuthorAvatar: initials,
@@ -1944,8 +2361,13 @@ func convertDBThreadToPageThread(threadResp services.ThreadResponseDTO, user *Us
 		Likes:        likesCount,
 		IsLiked:      isLiked,
 		Comments:     threadResp.MessageCount,
-		Shares:       0,
+		Shares:       threadResp.ShareCount,
+		Views:        threadResp.ViewCount,
+		Visibility:   threadResp.Visibility,
+		Access:       threadResp.Access,
+		State:        threadResp.State,
 		MusicTrack:   nil,
+		MusicEmbeds:  threadResp.MusicEmbeds,
 	}
 }
 
@@ -1990,6 +2412,7 @@ func convertMessagesToComments(messages []*models.Message, threadAuthor string,
 		comment := Comment{
 			ID:           msg.ID,
 			Content:      msg.Content,
+			ContentHTML:  template.HTML(msg.ContentHTML),
 			ImageURL:     msg.ImageURL,
 			Author:       msg.Author.Username,
 			AuthorAvatar: initials,
@@ -2006,6 +2429,64 @@ func convertMessagesToComments(messages []*models.Message, threadAuthor string,
 	return comments
 }
 
+// renderPageMentions ajoute au rendu du thread et des commentaires les liens des mentions enregistrées
+func renderPageMentions(thread *Thread, comments []Comment) {
+	mentionService := services.NewMentionServiceWithDB(database.DB)
+
+	threadMentions, err := mentionService.GetMentions(models.MentionSourceThread, []uint{thread.ID})
+	if err != nil {
+		log.Printf("❌ Erreur récupération mentions du thread %d: %v", thread.ID, err)
+	}
+	thread.ContentHTML = linkMentions(thread.ContentHTML, threadMentions[thread.ID])
+
+	commentIDs := make([]uint, len(comments))
+	for i, comment := range comments {
+		commentIDs[i] = comment.ID
+	}
+	commentMentions, err := mentionService.GetMentions(models.MentionSourceComment, commentIDs)
+	if err != nil {
+		log.Printf("❌ Erreur récupération mentions des commentaires: %v", err)
+	}
+	for i := range comments {
+		comments[i].ContentHTML = linkMentions(comments[i].ContentHTML, commentMentions[comments[i].ID])
+	}
+}
+
+// attachPageReactions ajoute aux commentaires les compteurs de réactions emoji
+func attachPageReactions(comments []Comment, userID *uint) {
+	commentIDs := make([]uint, len(comments))
+	for i, comment := range comments {
+		commentIDs[i] = comment.ID
+	}
+
+	reactions, err := repositories.NewReactionRepository(database.DB).SummariesByTargets(models.ReactionTargetComment, commentIDs, userID)
+	if err != nil {
+		log.Printf("❌ Erreur récupération réactions des commentaires: %v", err)
+		return
+	}
+	for i := range comments {
+		comments[i].Reactions = reactions[comments[i].ID]
+	}
+}
+
+// attachPageEmbeds ajoute aux commentaires les liens musicaux reconnus dans leur contenu,
+// ouverts sur la plateforme préférée du lecteur quand l'entrée du catalogue y a un lien
+func attachPageEmbeds(comments []Comment, viewerID *uint) {
+	commentIDs := make([]uint, len(comments))
+	for i, comment := range comments {
+		commentIDs[i] = comment.ID
+	}
+
+	embeds, err := services.NewEmbedServiceWithDB(database.DB).GetEmbeds(models.EmbedTargetComment, commentIDs, viewerID)
+	if err != nil {
+		log.Printf("❌ Erreur récupération liens musicaux des commentaires: %v", err)
+		return
+	}
+	for i := range comments {
+		comments[i].MusicEmbeds = embeds[comments[i].ID]
+	}
+}
+
 // DeleteThreadHandler gère la suppression d'un thread
 func DeleteThreadHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
@@ -2151,6 +2632,8 @@ func handleShowEditForm(w http.ResponseWriter, r *http.Request, threadID uint, u
 		errorMessage = "Erreur lors de la mise à jour du thread"
 	case "validation_failed":
 		errorMessage = "Données invalides. Vérifiez que le titre fait au moins 5 caractères et la description au moins 10 caractères."
+	case "thread_archived":
+		errorMessage = "Ce thread est archivé : seul un administrateur peut le désarchiver"
 	}
 
 	switch successParam {
@@ -2214,6 +2697,7 @@ func handleUpdateThread(w http.ResponseWriter, r *http.Request, threadID uint, u
 	description := strings.TrimSpace(r.FormValue("description"))
 	imageURL := strings.TrimSpace(r.FormValue("image_url"))
 	visibility := strings.TrimSpace(r.FormValue("visibility"))
+	access := strings.TrimSpace(r.FormValue("access"))
 	state := strings.TrimSpace(r.FormValue("state"))
 	tagsStr := strings.TrimSpace(r.FormValue("tags"))
 
@@ -2273,10 +2757,16 @@ func handleUpdateThread(w http.ResponseWriter, r *http.Request, threadID uint, u
 		Tags:        tags,
 		State:       state,
 		Visibility:  visibility,
+		Access:      access,
 	}
 
 	// Mettre à jour le thread
 	err = threadService.UpdateThread(threadID, updateDTO, user.ID, user.IsAdmin)
+	if errors.Is(err, utils.ErrThreadArchived) {
+		log.Printf("🔒 Modification refusée, thread %d archivé", threadID)
+		http.Redirect(w, r, fmt.Sprintf("/thread/%d/edit?error=thread_archived", threadID), http.StatusSeeOther)
+		return
+	}
 	if err != nil {
 		log.Printf("❌ Erreur mise à jour thread %d: %v", threadID, err)
 		http.Redirect(w, r, fmt.Sprintf("/thread/%d/edit?error=update_failed", threadID), http.StatusSeeOther)
@@ -2324,8 +2814,9 @@ func convertThreadResponseToPageThread(threadResp services.ThreadResponseDTO, us
 		Likes:        0, // Les likes seront récupérés si nécessaire
 		IsLiked:      false,
 		Comments:     threadResp.MessageCount,
-		Shares:       0,
+		Shares:       threadResp.ShareCount,
 		Visibility:   threadResp.Visibility,
+		Access:       threadResp.Access,
 		State:        threadResp.State,
 		MusicTrack:   nil,
 	}
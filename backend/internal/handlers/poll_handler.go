@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// PollHandler gère les sondages intégrés aux threads
+type PollHandler struct {
+	pollService services.PollService
+}
+
+// NewPollHandler crée une nouvelle instance du handler
+func NewPollHandler(pollService services.PollService) *PollHandler {
+	return &PollHandler{
+		pollService: pollService,
+	}
+}
+
+// PollVoteRequest bulletin de vote : une option (choix unique) ou plusieurs (choix multiple)
+type PollVoteRequest struct {
+	OptionIDs []uint `json:"option_ids"`
+}
+
+// GetPoll récupère le sondage d'un thread
+func (h *PollHandler) GetPoll(w http.ResponseWriter, r *http.Request) {
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	var viewerID *uint
+	if userID, exists := controllers.GetUserIDFromContext(r); exists {
+		viewerID = &userID
+	}
+
+	poll, err := h.pollService.GetPoll(uint(threadID), viewerID)
+	if err != nil {
+		sendPollError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Sondage récupéré", map[string]interface{}{
+		"poll": poll,
+	})
+}
+
+// CreatePoll ajoute un sondage à un thread
+func (h *PollHandler) CreatePoll(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	var req services.CreatePollDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	poll, err := h.pollService.CreatePoll(uint(threadID), userID, req)
+	if err != nil {
+		sendPollError(w, err)
+		return
+	}
+
+	log.Printf("📊 Sondage créé sur le thread %d par %d", threadID, userID)
+	sendAPISuccess(w, "Sondage créé", map[string]interface{}{
+		"poll": poll,
+	})
+}
+
+// Vote enregistre le vote de l'utilisateur
+func (h *PollHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	var req PollVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	poll, err := h.pollService.Vote(uint(threadID), userID, req.OptionIDs)
+	if err != nil {
+		sendPollError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Vote enregistré", map[string]interface{}{
+		"poll": poll,
+	})
+}
+
+// DeletePoll supprime le sondage d'un thread
+func (h *PollHandler) DeletePoll(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.pollService.DeletePoll(uint(threadID), userID, controllers.IsAdminFromContext(r)); err != nil {
+		sendPollError(w, err)
+		return
+	}
+
+	log.Printf("🗑️ Sondage du thread %d supprimé par %d", threadID, userID)
+	sendAPISuccess(w, "Sondage supprimé", nil)
+}
+
+// sendPollError traduit les erreurs du service en réponses API
+func sendPollError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrThreadNotFound):
+		sendAPIError(w, "Thread non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrPollNotFound):
+		sendAPIError(w, "Sondage non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrUnauthorized):
+		sendAPIError(w, "Action non autorisée sur ce sondage", http.StatusForbidden)
+	case errors.Is(err, utils.ErrThreadClosed), errors.Is(err, utils.ErrThreadArchived), errors.Is(err, utils.ErrPollClosed):
+		sendAPIError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, utils.ErrAlreadyVotedPoll):
+		sendAPIError(w, "Vous avez déjà voté à ce sondage", http.StatusConflict)
+	default:
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	}
+}
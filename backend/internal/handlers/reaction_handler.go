@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ReactionHandler gère les réactions emoji sur les commentaires et les messages privés
+type ReactionHandler struct {
+	reactionService services.ReactionService
+}
+
+// NewReactionHandler crée une nouvelle instance du handler
+func NewReactionHandler(reactionService services.ReactionService) *ReactionHandler {
+	return &ReactionHandler{
+		reactionService: reactionService,
+	}
+}
+
+// ReactionRequest réaction à ajouter ou retirer
+type ReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+// GetAllowedReactions retourne l'ensemble des réactions proposées
+func (h *ReactionHandler) GetAllowedReactions(w http.ResponseWriter, r *http.Request) {
+	sendAPISuccess(w, "Réactions disponibles", map[string]interface{}{
+		"reactions": h.reactionService.AllowedReactions(),
+	})
+}
+
+// ToggleCommentReaction ajoute ou retire une réaction sur un commentaire
+func (h *ReactionHandler) ToggleCommentReaction(w http.ResponseWriter, r *http.Request) {
+	h.toggleReaction(w, r, models.ReactionTargetComment)
+}
+
+// GetCommentReactions liste les réactions d'un commentaire avec les utilisateurs
+func (h *ReactionHandler) GetCommentReactions(w http.ResponseWriter, r *http.Request) {
+	h.getReactions(w, r, models.ReactionTargetComment)
+}
+
+// ToggleMessageReaction ajoute ou retire une réaction sur un message privé
+func (h *ReactionHandler) ToggleMessageReaction(w http.ResponseWriter, r *http.Request) {
+	h.toggleReaction(w, r, models.ReactionTargetMessage)
+}
+
+// GetMessageReactions liste les réactions d'un message privé avec les utilisateurs
+func (h *ReactionHandler) GetMessageReactions(w http.ResponseWriter, r *http.Request) {
+	h.getReactions(w, r, models.ReactionTargetMessage)
+}
+
+// toggleReaction traite l'ajout ou le retrait d'une réaction de l'utilisateur connecté
+func (h *ReactionHandler) toggleReaction(w http.ResponseWriter, r *http.Request, targetType string) {
+	userID, exists := optionalUserID(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	targetID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID message invalide", http.StatusBadRequest)
+		return
+	}
+
+	var req ReactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.reactionService.ToggleReaction(targetType, uint(targetID), req.Emoji, userID)
+	if err != nil {
+		sendReactionError(w, err)
+		return
+	}
+
+	message := "Réaction ajoutée"
+	if !change.Added {
+		message = "Réaction retirée"
+	}
+	sendAPISuccess(w, message, change)
+}
+
+// getReactions retourne le détail des réactions d'une cible (qui a réagi avec quel emoji)
+func (h *ReactionHandler) getReactions(w http.ResponseWriter, r *http.Request, targetType string) {
+	targetID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID message invalide", http.StatusBadRequest)
+		return
+	}
+
+	userID, _ := optionalUserID(r)
+	reactions, err := h.reactionService.GetReactions(targetType, uint(targetID), userID)
+	if err != nil {
+		sendReactionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Réactions récupérées", map[string]interface{}{
+		"reactions": reactions,
+	})
+}
+
+// optionalUserID retourne l'ID de l'utilisateur connecté (0 pour un visiteur)
+func optionalUserID(r *http.Request) (uint, bool) {
+	if viewerID := optionalViewerID(r); viewerID != nil {
+		return *viewerID, true
+	}
+	return 0, false
+}
+
+// sendReactionError traduit les erreurs du service de réactions en réponses HTTP
+func sendReactionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrReactionInvalid), errors.Is(err, utils.ErrInvalidInput):
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, utils.ErrMessageNotFound):
+		sendAPIError(w, "Message non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrThreadNotFound):
+		sendAPIError(w, "Thread non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrUnauthorized):
+		sendAPIError(w, "Vous n'avez pas accès à ce message", http.StatusForbidden)
+	case errors.Is(err, utils.ErrThreadArchived):
+		sendAPIError(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("❌ Erreur réaction: %v", err)
+		sendAPIError(w, "Erreur lors du traitement de la réaction", http.StatusInternalServerError)
+	}
+}
+
+// NotifyReactionChange diffuse en temps réel un changement de réaction :
+// aux participants d'une conversation privée, ou aux utilisateurs dont la page
+// affiche le thread du commentaire (accès vérifié à leur connexion)
+func NotifyReactionChange(change *services.ReactionChange) {
+	// Reacted dépend de l'utilisateur : chaque client le déduit de user_id et added
+	broadcast := *change
+	broadcast.Reactions = make([]*models.ReactionSummary, len(change.Reactions))
+	for i, summary := range change.Reactions {
+		broadcast.Reactions[i] = &models.ReactionSummary{Emoji: summary.Emoji, Count: summary.Count}
+	}
+
+	switch change.TargetType {
+	case models.ReactionTargetMessage:
+		hub := GetMessageHub()
+		for _, userID := range change.Participants {
+			hub.SendToUser(userID, &models.WebSocketMessage{
+				Type:           "reaction",
+				ConversationID: change.ConversationID,
+				Data: map[string]interface{}{
+					"reaction": broadcast,
+				},
+				Timestamp: time.Now(),
+			})
+		}
+
+	case models.ReactionTargetComment:
+		nm := GetNotificationManager()
+		for _, userID := range nm.ThreadWatchers(change.ThreadID) {
+			nm.SendNotification(userID, "reaction", "Réaction", "", broadcast)
+		}
+	}
+}
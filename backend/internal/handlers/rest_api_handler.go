@@ -330,6 +330,9 @@ func SearchAPIHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Les threads privés accessibles à l'utilisateur connecté sont inclus
+	viewerID := viewerIDFromRequest(r)
+
 	// Simulation de résultats de recherche (à remplacer par la vraie logique)
 	var results []map[string]interface{}
 
@@ -339,9 +342,9 @@ func SearchAPIHandler(w http.ResponseWriter, r *http.Request) {
 	case "users":
 		results = simulateUserSearch(query, limit)
 	case "threads":
-		results = simulateThreadSearch(query, cleanTags, limit)
+		results = simulateThreadSearch(query, cleanTags, limit, viewerID)
 	default:
-		results = simulateGlobalSearch(query, cleanTags, limit)
+		results = simulateGlobalSearch(query, cleanTags, limit, viewerID)
 	}
 
 	sendAPISuccess(w, "Recherche effectuée", map[string]interface{}{
@@ -387,13 +390,13 @@ func simulateUserSearch(query string, limit int) []map[string]interface{} {
 	}
 }
 
-func simulateThreadSearch(query string, tags []string, limit int) []map[string]interface{} {
+func simulateThreadSearch(query string, tags []string, limit int, viewerID *uint) []map[string]interface{} {
 	// Utiliser le vrai service de recherche
 	db := database.DB
 	threadRepo := repositories.NewThreadRepository(db)
 	tagRepo := repositories.NewTagRepository(db)
 	messageRepo := repositories.NewMessageRepository(db)
-	threadService := services.NewThreadService(threadRepo, tagRepo, messageRepo, db)
+	threadService := services.NewThreadService(threadRepo, tagRepo, messageRepo, db).ForViewer(viewerID)
 
 	params := models.PaginationParams{
 		Page:    1,
@@ -437,13 +440,13 @@ func simulateThreadSearch(query string, tags []string, limit int) []map[string]i
 	return results
 }
 
-func simulateGlobalSearch(query string, tags []string, limit int) []map[string]interface{} {
+func simulateGlobalSearch(query string, tags []string, limit int, viewerID *uint) []map[string]interface{} {
 	results := make([]map[string]interface{}, 0)
 
 	// Combiner les résultats de différents types
 	tagResults := simulateTagSearch(query, 3)
 	users := simulateUserSearch(query, 3)
-	threads := simulateThreadSearch(query, tags, 4)
+	threads := simulateThreadSearch(query, tags, 4, viewerID)
 
 	results = append(results, tagResults...)
 	results = append(results, users...)
@@ -510,7 +513,7 @@ func ThreadSearchAPIHandler(w http.ResponseWriter, r *http.Request) {
 	threadRepo := repositories.NewThreadRepository(db)
 	tagRepo := repositories.NewTagRepository(db)
 	messageRepo := repositories.NewMessageRepository(db)
-	threadService := services.NewThreadService(threadRepo, tagRepo, messageRepo, db)
+	threadService := services.NewThreadService(threadRepo, tagRepo, messageRepo, db).ForViewer(viewerIDFromRequest(r))
 
 	params := models.PaginationParams{
 		Page:    1,
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ReviewHandler gère les critiques d'artistes, d'albums et de morceaux
+type ReviewHandler struct {
+	reviewService services.ReviewService
+}
+
+// NewReviewHandler crée une nouvelle instance du handler
+func NewReviewHandler(reviewService services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// GetReview récupère la critique d'un thread
+func (h *ReviewHandler) GetReview(w http.ResponseWriter, r *http.Request) {
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.reviewService.GetReview(uint(threadID), optionalViewerID(r))
+	if err != nil {
+		sendReviewError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Critique récupérée", map[string]interface{}{
+		"review": review,
+	})
+}
+
+// SaveReview fait d'un thread une critique notée, ou met à jour sa note
+func (h *ReviewHandler) SaveReview(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	var req services.SaveReviewDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.reviewService.SaveReview(uint(threadID), userID, req)
+	if err != nil {
+		sendReviewError(w, err)
+		return
+	}
+
+	log.Printf("⭐ Critique du thread %d enregistrée: %s %.1f/10", threadID, review.Subject.Name, review.Score)
+	sendAPISuccess(w, "Critique enregistrée", map[string]interface{}{
+		"review": review,
+	})
+}
+
+// DeleteReview retire la critique d'un thread
+func (h *ReviewHandler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.reviewService.DeleteReview(uint(threadID), userID, controllers.IsAdminFromContext(r)); err != nil {
+		sendReviewError(w, err)
+		return
+	}
+
+	log.Printf("🗑️ Critique du thread %d supprimée par %d", threadID, userID)
+	sendAPISuccess(w, "Critique supprimée", nil)
+}
+
+// GetTagReviews liste les critiques d'un tag avec sa note moyenne
+// (?sort=recent|score_desc|score_asc, ?min_score=, ?max_score=, ?page=, ?per_page=)
+func (h *ReviewHandler) GetTagReviews(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.ReviewFilter{Sort: query.Get("sort")}
+	filter.Page, _ = strconv.Atoi(query.Get("page"))
+	filter.PerPage, _ = strconv.Atoi(query.Get("per_page"))
+
+	for param, bound := range map[string]**float64{"min_score": &filter.MinScore, "max_score": &filter.MaxScore} {
+		if value := query.Get(param); value != "" {
+			score, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				sendAPIError(w, "Note invalide pour "+param, http.StatusBadRequest)
+				return
+			}
+			*bound = &score
+		}
+	}
+
+	reviews, err := h.reviewService.GetTagReviews(mux.Vars(r)["name"], optionalViewerID(r), filter)
+	if err != nil {
+		sendReviewError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Critiques récupérées", reviews)
+}
+
+// sendReviewError traduit les erreurs du service en réponses API
+func sendReviewError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrThreadNotFound):
+		sendAPIError(w, "Thread non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrReviewNotFound):
+		sendAPIError(w, "Critique non trouvée", http.StatusNotFound)
+	case errors.Is(err, utils.ErrTagNotFound):
+		sendAPIError(w, "Tag non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrUnauthorized):
+		sendAPIError(w, "Action non autorisée sur cette critique", http.StatusForbidden)
+	case errors.Is(err, utils.ErrThreadArchived):
+		sendAPIError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, utils.ErrInvalidInput):
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("❌ Erreur critique: %v", err)
+		sendAPIError(w, "Erreur lors du traitement de la critique", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,596 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Limites des soumissions des scrobblers
+const (
+	listenBrainzMaxListens = 1000     // écoutes par import (comme ListenBrainz)
+	listenBrainzMaxBody    = 10 << 20 // corps d'une soumission ListenBrainz
+	lastFMMaxScrobbles     = 50       // scrobbles par requête (comme Last.fm)
+	lastFMMaxBody          = 1 << 20
+)
+
+// Codes d'erreur de l'API Last.fm
+const (
+	lastFMErrInvalidMethod = 3
+	lastFMErrAuthFailed    = 4
+	lastFMErrInvalidParams = 6
+	lastFMErrInvalidSK     = 9
+	lastFMErrTemporary     = 16
+)
+
+// ScrobbleHandler gère les jetons des scrobblers et l'API de scrobbling compatible Last.fm et ListenBrainz
+type ScrobbleHandler struct {
+	scrobbleService  services.ScrobbleService
+	listeningService services.ListeningService
+}
+
+// NewScrobbleHandler crée une nouvelle instance du handler
+func NewScrobbleHandler(scrobbleService services.ScrobbleService, listeningService services.ListeningService) *ScrobbleHandler {
+	return &ScrobbleHandler{
+		scrobbleService:  scrobbleService,
+		listeningService: listeningService,
+	}
+}
+
+// ==========================================
+// JETONS DES SCROBBLERS
+// ==========================================
+
+// GetTokens liste les jetons de scrobbling de l'utilisateur
+func (h *ScrobbleHandler) GetTokens(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	tokens, err := h.scrobbleService.GetTokens(userID)
+	if err != nil {
+		sendScrobbleError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Jetons de scrobbling récupérés", map[string]interface{}{
+		"tokens": tokens,
+	})
+}
+
+// CreateToken crée un jeton à renseigner dans un scrobbler ; sa valeur n'est retournée qu'une fois
+func (h *ScrobbleHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données JSON invalides", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.scrobbleService.CreateToken(userID, req.Name)
+	if err != nil {
+		sendScrobbleError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Jeton de scrobbling créé", map[string]interface{}{
+		"token": token,
+	})
+}
+
+// RevokeToken révoque un jeton de scrobbling
+func (h *ScrobbleHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	tokenID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID de jeton invalide", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.scrobbleService.RevokeToken(userID, uint(tokenID)); err != nil {
+		sendScrobbleError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Jeton de scrobbling révoqué", nil)
+}
+
+// sendScrobbleError traduit les erreurs du service en réponses API
+func sendScrobbleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrScrobbleTokenNotFound):
+		sendAPIError(w, "Jeton de scrobbling non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrInvalidInput):
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("❌ Erreur jetons de scrobbling: %v", err)
+		sendAPIError(w, "Erreur lors de la gestion des jetons de scrobbling", http.StatusInternalServerError)
+	}
+}
+
+// ==========================================
+// API LISTENBRAINZ
+// ==========================================
+
+// listenBrainzSubmission corps de POST /1/submit-listens
+type listenBrainzSubmission struct {
+	ListenType string               `json:"listen_type"` // single, import ou playing_now
+	Payload    []listenBrainzListen `json:"payload"`
+}
+
+// listenBrainzListen écoute au format ListenBrainz
+type listenBrainzListen struct {
+	ListenedAt    *int64 `json:"listened_at"`
+	TrackMetadata struct {
+		ArtistName     string                 `json:"artist_name"`
+		TrackName      string                 `json:"track_name"`
+		ReleaseName    string                 `json:"release_name"`
+		AdditionalInfo map[string]interface{} `json:"additional_info"`
+	} `json:"track_metadata"`
+}
+
+// listening convertit l'écoute (durée en millisecondes ou en secondes, lien d'origine)
+func (l listenBrainzListen) listening() services.ListeningDTO {
+	meta := l.TrackMetadata
+	dto := services.ListeningDTO{
+		Track:  meta.TrackName,
+		Artist: meta.ArtistName,
+		Album:  meta.ReleaseName,
+	}
+	if ms, ok := meta.AdditionalInfo["duration_ms"].(float64); ok {
+		dto.DurationMs = int(ms)
+	} else if seconds, ok := meta.AdditionalInfo["duration"].(float64); ok {
+		dto.DurationMs = int(seconds * 1000)
+	}
+	if url, ok := meta.AdditionalInfo["origin_url"].(string); ok {
+		dto.MusicURL = url
+	}
+	return dto
+}
+
+// SubmitListens reçoit les écoutes d'un scrobbler ListenBrainz (Authorization: Token <jeton>)
+func (h *ScrobbleHandler) SubmitListens(w http.ResponseWriter, r *http.Request) {
+	token, ok := h.listenBrainzAuth(w, r)
+	if !ok {
+		return
+	}
+
+	var submission listenBrainzSubmission
+	r.Body = http.MaxBytesReader(w, r.Body, listenBrainzMaxBody)
+	if err := json.NewDecoder(r.Body).Decode(&submission); err != nil {
+		sendListenBrainzError(w, http.StatusBadRequest, "Document JSON invalide")
+		return
+	}
+
+	switch submission.ListenType {
+	case "playing_now":
+		if len(submission.Payload) != 1 {
+			sendListenBrainzError(w, http.StatusBadRequest, "Une seule écoute attendue pour playing_now")
+			return
+		}
+		if _, err := h.listeningService.RecordNowPlaying(token.UserID, submission.Payload[0].listening()); err != nil {
+			sendListenBrainzServiceError(w, err)
+			return
+		}
+
+	case "single", "import":
+		count := len(submission.Payload)
+		if count == 0 || (submission.ListenType == "single" && count != 1) || count > listenBrainzMaxListens {
+			sendListenBrainzError(w, http.StatusBadRequest,
+				fmt.Sprintf("Nombre d'écoutes invalide pour %s (%d maximum par import)", submission.ListenType, listenBrainzMaxListens))
+			return
+		}
+
+		scrobbles := make([]services.ScrobbleDTO, count)
+		for i, listen := range submission.Payload {
+			if listen.ListenedAt == nil {
+				sendListenBrainzError(w, http.StatusBadRequest, fmt.Sprintf("Écoute %d : listened_at requis", i))
+				return
+			}
+			scrobbles[i] = services.ScrobbleDTO{ListeningDTO: listen.listening(), ListenedAt: time.Unix(*listen.ListenedAt, 0)}
+		}
+
+		rejected, err := h.listeningService.RecordScrobbles(token.UserID, scrobbles, false)
+		if err != nil {
+			for i, reason := range rejected {
+				if reason != nil {
+					err = fmt.Errorf("écoute %d : %w", i, reason)
+					break
+				}
+			}
+			sendListenBrainzServiceError(w, err)
+			return
+		}
+
+	default:
+		sendListenBrainzError(w, http.StatusBadRequest, "listen_type invalide (single, import ou playing_now)")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ValidateToken vérifie un jeton ListenBrainz (?token= ou en-tête Authorization)
+func (h *ScrobbleHandler) ValidateToken(w http.ResponseWriter, r *http.Request) {
+	value := r.URL.Query().Get("token")
+	if value == "" {
+		value = listenBrainzToken(r)
+	}
+	if value == "" {
+		sendListenBrainzError(w, http.StatusBadRequest, "Jeton manquant")
+		return
+	}
+
+	token, err := h.scrobbleService.Authenticate(value)
+	if err != nil {
+		if !errors.Is(err, utils.ErrTokenInvalid) {
+			sendListenBrainzServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"code": http.StatusOK, "message": "Token invalid.", "valid": false,
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"code": http.StatusOK, "message": "Token valid.", "valid": true, "user_name": token.Username,
+	})
+}
+
+// listenBrainzAuth authentifie le scrobbler ou répond 401
+func (h *ScrobbleHandler) listenBrainzAuth(w http.ResponseWriter, r *http.Request) (*models.ScrobbleToken, bool) {
+	value := listenBrainzToken(r)
+	if value == "" {
+		sendListenBrainzError(w, http.StatusUnauthorized, "En-tête Authorization: Token <jeton> requis")
+		return nil, false
+	}
+
+	token, err := h.scrobbleService.Authenticate(value)
+	if errors.Is(err, utils.ErrTokenInvalid) {
+		sendListenBrainzError(w, http.StatusUnauthorized, "Jeton de scrobbling invalide")
+		return nil, false
+	}
+	if err != nil {
+		sendListenBrainzServiceError(w, err)
+		return nil, false
+	}
+	return token, true
+}
+
+// listenBrainzToken jeton de l'en-tête « Authorization: Token <jeton> »
+func listenBrainzToken(r *http.Request) string {
+	scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Token") {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// sendListenBrainzError envoie une erreur au format ListenBrainz
+func sendListenBrainzError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"code": status, "error": message})
+}
+
+// sendListenBrainzServiceError traduit les erreurs du service au format ListenBrainz
+func sendListenBrainzServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidInput), errors.Is(err, utils.ErrListenTooOld), errors.Is(err, utils.ErrListenTooNew):
+		sendListenBrainzError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("❌ Erreur scrobbling ListenBrainz: %v", err)
+		sendListenBrainzError(w, http.StatusInternalServerError, "Erreur lors de l'enregistrement des écoutes")
+	}
+}
+
+// writeJSON écrit une réponse JSON brute (formats imposés par les clients externes)
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// ==========================================
+// API LAST.FM (AUDIOSCROBBLER 2.0)
+// ==========================================
+
+// lastFMText texte d'une réponse Last.fm (« corrected » : jamais corrigé ici)
+type lastFMText struct {
+	Corrected string `json:"corrected" xml:"corrected,attr"`
+	Text      string `json:"#text" xml:",chardata"`
+}
+
+// lastFMIgnored raison du refus d'un scrobble (code 0 : accepté)
+type lastFMIgnored struct {
+	Code string `json:"code" xml:"code,attr"`
+	Text string `json:"#text" xml:",chardata"`
+}
+
+// lastFMTrackResult morceau accepté ou ignoré
+type lastFMTrackResult struct {
+	Track          lastFMText    `json:"track" xml:"track"`
+	Artist         lastFMText    `json:"artist" xml:"artist"`
+	Album          lastFMText    `json:"album" xml:"album"`
+	AlbumArtist    lastFMText    `json:"albumArtist" xml:"albumArtist"`
+	Timestamp      string        `json:"timestamp,omitempty" xml:"timestamp,omitempty"`
+	IgnoredMessage lastFMIgnored `json:"ignoredMessage" xml:"ignoredMessage"`
+}
+
+// lastFMSession réponse de auth.getMobileSession
+type lastFMSession struct {
+	XMLName    xml.Name `json:"-" xml:"session"`
+	Name       string   `json:"name" xml:"name"`
+	Key        string   `json:"key" xml:"key"`
+	Subscriber int      `json:"subscriber" xml:"subscriber"`
+}
+
+// lastFMNowPlaying réponse de track.updateNowPlaying
+type lastFMNowPlaying struct {
+	XMLName xml.Name `json:"-" xml:"nowplaying"`
+	lastFMTrackResult
+}
+
+// lastFMScrobbles réponse de track.scrobble (format XML)
+type lastFMScrobbles struct {
+	XMLName   xml.Name            `xml:"scrobbles"`
+	Accepted  int                 `xml:"accepted,attr"`
+	Ignored   int                 `xml:"ignored,attr"`
+	Scrobbles []lastFMTrackResult `xml:"scrobble"`
+}
+
+// lastFMResponse enveloppe XML <lfm> des réponses Last.fm
+type lastFMResponse struct {
+	XMLName xml.Name `xml:"lfm"`
+	Status  string   `xml:"status,attr"`
+	Body    interface{}
+}
+
+// lastFMError erreur Last.fm (format XML)
+type lastFMError struct {
+	XMLName xml.Name `xml:"error"`
+	Code    int      `xml:"code,attr"`
+	Message string   `xml:",chardata"`
+}
+
+// LastFM point d'entrée unique de l'API Last.fm (paramètre method) : auth.getMobileSession,
+// track.updateNowPlaying et track.scrobble. La clé de session (sk) est un jeton de scrobbling ;
+// api_key et api_sig sont acceptés sans vérification, le jeton suffisant à authentifier l'utilisateur.
+func (h *ScrobbleHandler) LastFM(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, lastFMMaxBody)
+	if err := r.ParseForm(); err != nil {
+		sendLastFMError(w, "", http.StatusBadRequest, lastFMErrInvalidParams, "Paramètres invalides")
+		return
+	}
+	format := r.Form.Get("format")
+
+	switch r.Form.Get("method") {
+	case "auth.getMobileSession":
+		h.lastFMMobileSession(w, r, format)
+	case "track.updateNowPlaying":
+		h.lastFMNowPlaying(w, r, format)
+	case "track.scrobble":
+		h.lastFMScrobble(w, r, format)
+	default:
+		sendLastFMError(w, format, http.StatusBadRequest, lastFMErrInvalidMethod, "Méthode inconnue")
+	}
+}
+
+// lastFMMobileSession connecte un scrobbler avec l'identifiant et le mot de passe de l'utilisateur
+func (h *ScrobbleHandler) lastFMMobileSession(w http.ResponseWriter, r *http.Request, format string) {
+	identifier, password := r.Form.Get("username"), r.Form.Get("password")
+	if identifier == "" || password == "" {
+		sendLastFMError(w, format, http.StatusBadRequest, lastFMErrInvalidParams, "username et password requis")
+		return
+	}
+
+	session, err := h.scrobbleService.CreateMobileSession(identifier, password, r.UserAgent())
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrInvalidCredentials):
+			sendLastFMError(w, format, http.StatusForbidden, lastFMErrAuthFailed, "Identifiants invalides")
+		case errors.Is(err, utils.ErrInvalidInput):
+			sendLastFMError(w, format, http.StatusBadRequest, lastFMErrInvalidParams, err.Error())
+		default:
+			log.Printf("❌ Erreur session Last.fm: %v", err)
+			sendLastFMError(w, format, http.StatusInternalServerError, lastFMErrTemporary, "Erreur temporaire, réessayez")
+		}
+		return
+	}
+
+	body := lastFMSession{Name: session.Username, Key: session.Token}
+	sendLastFM(w, format, map[string]interface{}{"session": body}, body)
+}
+
+// lastFMNowPlaying signale le morceau en cours de lecture
+func (h *ScrobbleHandler) lastFMNowPlaying(w http.ResponseWriter, r *http.Request, format string) {
+	token, ok := h.lastFMAuth(w, r, format)
+	if !ok {
+		return
+	}
+
+	dto := lastFMListening(r, "")
+	if _, err := h.listeningService.RecordNowPlaying(token.UserID, dto); err != nil {
+		if errors.Is(err, utils.ErrInvalidInput) {
+			sendLastFMError(w, format, http.StatusBadRequest, lastFMErrInvalidParams, err.Error())
+			return
+		}
+		log.Printf("❌ Erreur scrobbling Last.fm: %v", err)
+		sendLastFMError(w, format, http.StatusInternalServerError, lastFMErrTemporary, "Erreur temporaire, réessayez")
+		return
+	}
+
+	body := lastFMNowPlaying{lastFMTrackResult: lastFMResult(dto, "", 0)}
+	sendLastFM(w, format, map[string]interface{}{"nowplaying": body}, body)
+}
+
+// lastFMScrobble enregistre jusqu'à 50 scrobbles (paramètres artist[i], track[i], timestamp[i]...) ;
+// les scrobbles refusés sont ignorés individuellement, comme sur Last.fm
+func (h *ScrobbleHandler) lastFMScrobble(w http.ResponseWriter, r *http.Request, format string) {
+	token, ok := h.lastFMAuth(w, r, format)
+	if !ok {
+		return
+	}
+
+	var suffixes []string
+	if r.Form.Has("track") {
+		suffixes = append(suffixes, "")
+	}
+	for i := 0; i < lastFMMaxScrobbles; i++ {
+		if suffix := fmt.Sprintf("[%d]", i); r.Form.Has("track" + suffix) {
+			suffixes = append(suffixes, suffix)
+		}
+	}
+	if len(suffixes) == 0 {
+		sendLastFMError(w, format, http.StatusBadRequest, lastFMErrInvalidParams, "Aucun scrobble (track, artist, timestamp)")
+		return
+	}
+
+	scrobbles := make([]services.ScrobbleDTO, len(suffixes))
+	timestamps := make([]string, len(suffixes))
+	for i, suffix := range suffixes {
+		timestamps[i] = r.Form.Get("timestamp" + suffix)
+		scrobbles[i].ListeningDTO = lastFMListening(r, suffix)
+		if unix, err := strconv.ParseInt(timestamps[i], 10, 64); err == nil {
+			scrobbles[i].ListenedAt = time.Unix(unix, 0)
+		}
+	}
+
+	rejected, err := h.listeningService.RecordScrobbles(token.UserID, scrobbles, true)
+	if err != nil {
+		log.Printf("❌ Erreur scrobbling Last.fm: %v", err)
+		sendLastFMError(w, format, http.StatusInternalServerError, lastFMErrTemporary, "Erreur temporaire, réessayez")
+		return
+	}
+
+	results := make([]lastFMTrackResult, len(scrobbles))
+	ignored := 0
+	for i, scrobble := range scrobbles {
+		code := lastFMIgnoredCode(scrobble.ListeningDTO, rejected[i])
+		if code != 0 {
+			ignored++
+		}
+		results[i] = lastFMResult(scrobble.ListeningDTO, timestamps[i], code)
+	}
+
+	// Last.fm retourne un objet pour un seul scrobble, une liste sinon
+	var scrobbleJSON interface{} = results
+	if len(results) == 1 {
+		scrobbleJSON = results[0]
+	}
+	sendLastFM(w, format, map[string]interface{}{
+		"scrobbles": map[string]interface{}{
+			"scrobble": scrobbleJSON,
+			"@attr":    map[string]int{"accepted": len(results) - ignored, "ignored": ignored},
+		},
+	}, lastFMScrobbles{Accepted: len(results) - ignored, Ignored: ignored, Scrobbles: results})
+}
+
+// lastFMAuth authentifie le scrobbler par sa clé de session ou répond l'erreur 9
+func (h *ScrobbleHandler) lastFMAuth(w http.ResponseWriter, r *http.Request, format string) (*models.ScrobbleToken, bool) {
+	token, err := h.scrobbleService.Authenticate(r.Form.Get("sk"))
+	if errors.Is(err, utils.ErrTokenInvalid) {
+		sendLastFMError(w, format, http.StatusForbidden, lastFMErrInvalidSK, "Clé de session invalide, reconnectez le scrobbler")
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("❌ Erreur authentification scrobbler: %v", err)
+		sendLastFMError(w, format, http.StatusInternalServerError, lastFMErrTemporary, "Erreur temporaire, réessayez")
+		return nil, false
+	}
+	return token, true
+}
+
+// lastFMListening lit un morceau des paramètres Last.fm (suffix : "" ou "[i]", durée en secondes)
+func lastFMListening(r *http.Request, suffix string) services.ListeningDTO {
+	dto := services.ListeningDTO{
+		Track:  r.Form.Get("track" + suffix),
+		Artist: r.Form.Get("artist" + suffix),
+		Album:  r.Form.Get("album" + suffix),
+	}
+	if seconds, err := strconv.Atoi(r.Form.Get("duration" + suffix)); err == nil {
+		dto.DurationMs = seconds * 1000
+	}
+	return dto
+}
+
+// lastFMIgnoredCode code de refus Last.fm d'un scrobble (0 : accepté)
+func lastFMIgnoredCode(dto services.ListeningDTO, err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, utils.ErrListenTooOld):
+		return 3
+	case errors.Is(err, utils.ErrListenTooNew):
+		return 4
+	case strings.TrimSpace(dto.Artist) == "":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// lastFMResult morceau tel que renvoyé au scrobbler
+func lastFMResult(dto services.ListeningDTO, timestamp string, ignoredCode int) lastFMTrackResult {
+	return lastFMTrackResult{
+		Track:          lastFMText{Corrected: "0", Text: dto.Track},
+		Artist:         lastFMText{Corrected: "0", Text: dto.Artist},
+		Album:          lastFMText{Corrected: "0", Text: dto.Album},
+		AlbumArtist:    lastFMText{Corrected: "0"},
+		Timestamp:      timestamp,
+		IgnoredMessage: lastFMIgnored{Code: strconv.Itoa(ignoredCode)},
+	}
+}
+
+// sendLastFM envoie une réponse Last.fm en JSON (format=json) ou en XML (par défaut)
+func sendLastFM(w http.ResponseWriter, format string, jsonBody, xmlBody interface{}) {
+	if format == "json" {
+		writeJSON(w, http.StatusOK, jsonBody)
+		return
+	}
+	writeLastFMXML(w, http.StatusOK, lastFMResponse{Status: "ok", Body: xmlBody})
+}
+
+// sendLastFMError envoie une erreur Last.fm en JSON (format=json) ou en XML (par défaut)
+func sendLastFMError(w http.ResponseWriter, format string, status, code int, message string) {
+	if format == "json" {
+		writeJSON(w, status, map[string]interface{}{"error": code, "message": message})
+		return
+	}
+	writeLastFMXML(w, status, lastFMResponse{Status: "failed", Body: lastFMError{Code: code, Message: message}})
+}
+
+// writeLastFMXML écrit une réponse XML <lfm>
+func writeLastFMXML(w http.ResponseWriter, status int, response lastFMResponse) {
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write([]byte(xml.Header))
+	if err := xml.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("❌ Erreur encodage réponse Last.fm: %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/database"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ShareHandler gère le partage de threads (repost sur le profil et envoi en message privé)
+type ShareHandler struct {
+	shareService services.ShareService
+}
+
+// NewShareHandler crée une nouvelle instance du handler
+func NewShareHandler(shareService services.ShareService) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+	}
+}
+
+// RepostRequest commentaire optionnel accompagnant un repost
+type RepostRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ShareToMessageRequest destinataire et commentaire d'un partage en message privé
+type ShareToMessageRequest struct {
+	ReceiverID uint   `json:"receiver_id"`
+	Comment    string `json:"comment"`
+}
+
+// Repost republie un thread sur le profil de l'utilisateur
+func (h *ShareHandler) Repost(w http.ResponseWriter, r *http.Request) {
+	userID, threadID, ok := shareRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req RepostRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			sendAPIError(w, "Données invalides", http.StatusBadRequest)
+			return
+		}
+	}
+
+	thread, err := h.shareService.Repost(threadID, userID, req.Comment)
+	if err != nil {
+		sendShareError(w, err)
+		return
+	}
+
+	log.Printf("🔄 Thread %d republié par l'utilisateur %d", threadID, userID)
+	NotifyThreadShared(thread, userID, models.ShareKindRepost)
+
+	sendAPISuccess(w, "Thread republié sur votre profil", map[string]interface{}{
+		"reposted":    true,
+		"share_count": thread.ShareCount,
+	})
+}
+
+// Unrepost retire un thread republié du profil
+func (h *ShareHandler) Unrepost(w http.ResponseWriter, r *http.Request) {
+	userID, threadID, ok := shareRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.shareService.Unrepost(threadID, userID); err != nil {
+		sendShareError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Repost retiré", map[string]interface{}{
+		"reposted": false,
+	})
+}
+
+// ShareToMessage envoie un thread dans une conversation privée sous forme de carte
+func (h *ShareHandler) ShareToMessage(w http.ResponseWriter, r *http.Request) {
+	userID, threadID, ok := shareRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req ShareToMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	if req.ReceiverID == 0 {
+		sendAPIError(w, "ID destinataire requis", http.StatusBadRequest)
+		return
+	}
+
+	message, thread, err := h.shareService.ShareToMessage(threadID, userID, req.ReceiverID, req.Comment)
+	if err != nil {
+		sendShareError(w, err)
+		return
+	}
+
+	// Livrer le message en temps réel comme un message privé classique
+	GetMessageHub().broadcast <- &models.WebSocketMessage{
+		Type:      "message",
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+
+	log.Printf("📤 Thread %d partagé par l'utilisateur %d à l'utilisateur %d", threadID, userID, req.ReceiverID)
+	NotifyThreadShared(thread, userID, models.ShareKindMessage)
+
+	sendAPISuccess(w, "Thread partagé en message privé", map[string]interface{}{
+		"message":     message,
+		"share_count": thread.ShareCount,
+	})
+}
+
+// GetUserReposts liste les threads republiés par un utilisateur
+func (h *ShareHandler) GetUserReposts(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := strconv.ParseUint(mux.Vars(r)["userId"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID utilisateur invalide", http.StatusBadRequest)
+		return
+	}
+
+	reposts, err := h.shareService.GetUserReposts(uint(ownerID), optionalViewerID(r))
+	if err != nil {
+		sendShareError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Reposts récupérés", map[string]interface{}{
+		"reposts": reposts,
+	})
+}
+
+// shareRequestIDs extrait l'utilisateur authentifié et l'ID du thread
+func shareRequestIDs(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return userID, uint(threadID), true
+}
+
+// sendShareError traduit les erreurs du service en réponses API
+func sendShareError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrThreadNotFound):
+		sendAPIError(w, "Thread non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrUnauthorized):
+		sendAPIError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, utils.ErrAlreadyReposted):
+		sendAPIError(w, err.Error(), http.StatusConflict)
+	default:
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+// NotifyThreadShared prévient l'auteur d'un thread qu'un autre utilisateur l'a partagé
+func NotifyThreadShared(thread *services.ThreadResponseDTO, sharerID uint, kind string) {
+	if thread.Author.ID == sharerID {
+		return
+	}
+
+	sharerName := "Quelqu'un"
+	if sharer, err := repositories.NewUserRepository(database.DB).FindByID(sharerID); err == nil {
+		sharerName = sharer.Username
+	} else {
+		log.Printf("⚠️ Erreur récupération utilisateur %d pour notification de partage: %v", sharerID, err)
+	}
+
+	message := fmt.Sprintf("%s a partagé « %s » en message privé", sharerName, thread.Title)
+	if kind == models.ShareKindRepost {
+		message = fmt.Sprintf("%s a republié « %s » sur son profil", sharerName, thread.Title)
+	}
+
+	GetNotificationManager().SendNotification(
+		thread.Author.ID,
+		"thread_shared",
+		"Thread partagé",
+		message,
+		map[string]interface{}{
+			"thread_id":   thread.ID,
+			"kind":        kind,
+			"share_count": thread.ShareCount,
+			"url":         fmt.Sprintf("/thread/%d", thread.ID),
+		},
+	)
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// SubscriptionHandler gère les abonnements aux threads et la position de lecture
+type SubscriptionHandler struct {
+	subscriptionService services.SubscriptionService
+}
+
+// NewSubscriptionHandler crée une nouvelle instance du handler
+func NewSubscriptionHandler(subscriptionService services.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// MarkReadRequest position de lecture envoyée par le client
+type MarkReadRequest struct {
+	LastMessageID uint `json:"last_message_id"`
+}
+
+// GetSubscription indique si l'utilisateur suit le thread et sa position de lecture
+func (h *SubscriptionHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	userID, threadID, ok := subscriptionRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	subscribed, err := h.subscriptionService.IsSubscribed(threadID, userID)
+	if err != nil {
+		sendSubscriptionError(w, err)
+		return
+	}
+
+	lastRead, err := h.subscriptionService.GetLastRead(threadID, userID)
+	if err != nil {
+		sendSubscriptionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Abonnement récupéré", map[string]interface{}{
+		"subscribed":           subscribed,
+		"last_read_message_id": lastRead,
+	})
+}
+
+// Subscribe abonne l'utilisateur au thread
+func (h *SubscriptionHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	userID, threadID, ok := subscriptionRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.subscriptionService.Subscribe(threadID, userID); err != nil {
+		sendSubscriptionError(w, err)
+		return
+	}
+
+	log.Printf("🔔 Utilisateur %d abonné au thread %d", userID, threadID)
+	sendAPISuccess(w, "Abonné au thread", map[string]interface{}{
+		"subscribed": true,
+	})
+}
+
+// Unsubscribe désabonne l'utilisateur du thread
+func (h *SubscriptionHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	userID, threadID, ok := subscriptionRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.subscriptionService.Unsubscribe(threadID, userID); err != nil {
+		sendSubscriptionError(w, err)
+		return
+	}
+
+	log.Printf("🔕 Utilisateur %d désabonné du thread %d", userID, threadID)
+	sendAPISuccess(w, "Désabonné du thread", map[string]interface{}{
+		"subscribed": false,
+	})
+}
+
+// MarkRead enregistre la position de lecture de l'utilisateur
+func (h *SubscriptionHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, threadID, ok := subscriptionRequestIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req MarkReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.subscriptionService.MarkRead(threadID, userID, req.LastMessageID); err != nil {
+		sendSubscriptionError(w, err)
+		return
+	}
+
+	sendAPISuccess(w, "Position de lecture enregistrée", nil)
+}
+
+// subscriptionRequestIDs extrait l'utilisateur authentifié et l'ID du thread
+func subscriptionRequestIDs(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return userID, uint(threadID), true
+}
+
+// sendSubscriptionError traduit les erreurs du service en réponses API
+func sendSubscriptionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrThreadNotFound):
+		sendAPIError(w, "Thread non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrUnauthorized):
+		sendAPIError(w, "Accès refusé à ce thread", http.StatusForbidden)
+	default:
+		sendAPIError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// NotifyThreadSubscribers enregistre un nouveau commentaire auprès des abonnements
+// et notifie en temps réel les abonnés du thread
+func NotifyThreadSubscribers(subscriptionService services.SubscriptionService, message *models.Message, threadTitle, authorName string) {
+	recipients, err := subscriptionService.RecordComment(message)
+	if err != nil {
+		log.Printf("❌ Erreur abonnements après commentaire sur thread %d: %v", message.ThreadID, err)
+		return
+	}
+
+	nm := GetNotificationManager()
+	for _, recipientID := range recipients {
+		nm.SendNotification(
+			recipientID,
+			"thread_comment",
+			"Nouveau commentaire",
+			fmt.Sprintf("%s a commenté « %s »", authorName, threadTitle),
+			map[string]interface{}{
+				"thread_id":  message.ThreadID,
+				"message_id": message.ID,
+				"url":        fmt.Sprintf("/thread/%d#comment-%d", message.ThreadID, message.ID),
+			},
+		)
+	}
+
+	if len(recipients) > 0 {
+		log.Printf("🔔 %d abonné(s) notifié(s) du commentaire %d", len(recipients), message.ID)
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"rythmitbackend/internal/controllers"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/services"
+	"rythmitbackend/internal/utils"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ThreadAccessHandler gère les invitations aux threads privés
+type ThreadAccessHandler struct {
+	accessService services.ThreadAccessService
+}
+
+// NewThreadAccessHandler crée une nouvelle instance du handler
+func NewThreadAccessHandler(accessService services.ThreadAccessService) *ThreadAccessHandler {
+	return &ThreadAccessHandler{
+		accessService: accessService,
+	}
+}
+
+// InviteUserRequest représente une invitation sur un thread privé
+type InviteUserRequest struct {
+	UserID uint `json:"user_id"`
+}
+
+// GetInvitations liste les invités d'un thread privé
+func (h *ThreadAccessHandler) GetInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	invitations, err := h.accessService.GetInvitations(uint(threadID), userID, controllers.IsAdminFromContext(r))
+	if err != nil {
+		sendThreadAccessError(w, err)
+		return
+	}
+
+	if invitations == nil {
+		invitations = []*models.ThreadInvitation{}
+	}
+
+	sendAPISuccess(w, "Invitations récupérées", map[string]interface{}{
+		"invitations": invitations,
+	})
+}
+
+// InviteUser invite un utilisateur sur un thread privé et le notifie
+func (h *ThreadAccessHandler) InviteUser(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	threadID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	var req InviteUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendAPIError(w, "Données invalides", http.StatusBadRequest)
+		return
+	}
+
+	if req.UserID == 0 {
+		sendAPIError(w, "ID utilisateur invité requis", http.StatusBadRequest)
+		return
+	}
+
+	invitation, thread, err := h.accessService.InviteUser(uint(threadID), req.UserID, userID, controllers.IsAdminFromContext(r))
+	if err != nil {
+		sendThreadAccessError(w, err)
+		return
+	}
+
+	// Notifier l'invité en temps réel
+	GetNotificationManager().SendNotification(
+		req.UserID,
+		"thread_invite",
+		"Nouvelle invitation",
+		fmt.Sprintf("Vous avez été invité à rejoindre la discussion privée « %s »", thread.Title),
+		map[string]interface{}{
+			"thread_id": thread.ID,
+			"url":       fmt.Sprintf("/thread/%d", thread.ID),
+		},
+	)
+
+	log.Printf("✉️ Utilisateur %d invité sur le thread privé %d par %d", req.UserID, threadID, userID)
+	sendAPISuccess(w, "Invitation envoyée", map[string]interface{}{
+		"invitation": invitation,
+	})
+}
+
+// RevokeInvitation retire l'accès d'un invité
+func (h *ThreadAccessHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, exists := controllers.GetUserIDFromContext(r)
+	if !exists {
+		sendAPIError(w, "Utilisateur non authentifié", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	threadID, err := strconv.ParseUint(vars["id"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID thread invalide", http.StatusBadRequest)
+		return
+	}
+
+	inviteeID, err := strconv.ParseUint(vars["userId"], 10, 32)
+	if err != nil {
+		sendAPIError(w, "ID utilisateur invalide", http.StatusBadRequest)
+		return
+	}
+
+	err = h.accessService.RevokeInvitation(uint(threadID), uint(inviteeID), userID, controllers.IsAdminFromContext(r))
+	if err != nil {
+		sendThreadAccessError(w, err)
+		return
+	}
+
+	log.Printf("🚫 Invitation de l'utilisateur %d révoquée sur le thread %d", inviteeID, threadID)
+	sendAPISuccess(w, "Invitation révoquée", nil)
+}
+
+// sendThreadAccessError traduit les erreurs du service en réponses API
+func sendThreadAccessError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, utils.ErrThreadNotFound):
+		sendAPIError(w, "Thread non trouvé", http.StatusNotFound)
+	case errors.Is(err, utils.ErrUnauthorized):
+		sendAPIError(w, "Seul l'auteur du thread peut gérer ses invitations", http.StatusForbidden)
+	case errors.Is(err, utils.ErrUserNotFound):
+		sendAPIError(w, "Utilisateur non trouvé", http.StatusNotFound)
+	default:
+		sendAPIError(w, err.Error(), http.StatusBadRequest)
+	}
+}
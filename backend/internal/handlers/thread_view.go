@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// threadViewerKey identifie un visiteur pour la déduplication des vues :
+// l'utilisateur connecté, sinon une empreinte de l'adresse IP et du navigateur
+func threadViewerKey(r *http.Request, user *User) string {
+	if user != nil {
+		return fmt.Sprintf("u:%d", user.ID)
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	sum := sha256.Sum256([]byte(host + "|" + r.UserAgent()))
+	return "a:" + hex.EncodeToString(sum[:8])
+}
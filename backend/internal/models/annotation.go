@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Annotation commentaire attaché à un passage de la description d'un thread.
+// StartOffset et EndOffset sont comptés en caractères (points de code) dans
+// le texte brut de la révision Revision de la description
+type Annotation struct {
+	ID          uint      `json:"id" db:"id"`
+	ThreadID    uint      `json:"thread_id" db:"thread_id"`
+	UserID      uint      `json:"user_id" db:"user_id"`
+	StartOffset int       `json:"start_offset" db:"start_offset"`
+	EndOffset   int       `json:"end_offset" db:"end_offset"`
+	Quote       string    `json:"quote" db:"quote"` // passage annoté, pour le réancrer après une modification
+	Body        string    `json:"body" db:"body"`
+	Revision    int       `json:"revision" db:"revision"`
+	Stale       bool      `json:"stale" db:"stale"` // passage introuvable dans la révision courante
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+
+	// Chargés avec l'annotation
+	Username  string  `json:"username,omitempty"`
+	FireCount int     `json:"fire_count"`
+	SkipCount int     `json:"skip_count"`
+	UserVote  *string `json:"user_vote,omitempty"` // vote de l'utilisateur courant
+}
+
+// Score score de popularité d'une annotation (Fire - Skip)
+func (a *Annotation) Score() int {
+	return a.FireCount - a.SkipCount
+}
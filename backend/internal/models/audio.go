@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// AudioUploadDir dossier de stockage des fichiers audio envoyés (non servi tel quel : voir AudioStreamPath)
+const AudioUploadDir = "uploads/audio"
+
+// Statuts de l'analyse (forme d'onde, durée exacte) d'un fichier audio
+const (
+	WaveformStatusPending     = "pending"     // en attente du worker
+	WaveformStatusOK          = "ok"          // forme d'onde calculée
+	WaveformStatusUnsupported = "unsupported" // format sans forme d'onde (OGG, FLAC)
+	WaveformStatusError       = "error"       // échec, nouvel essai limité
+)
+
+// AudioUpload fichier audio envoyé par un utilisateur (voir pkg/audiofile)
+type AudioUpload struct {
+	ID           uint      `json:"id" db:"id"`
+	UserID       uint      `json:"user_id" db:"user_id"`
+	FileName     string    `json:"-" db:"file_name"`
+	OriginalName string    `json:"original_name" db:"original_name"`
+	Format       string    `json:"format" db:"format"`
+	SizeBytes    int64     `json:"size_bytes" db:"size_bytes"`
+	DurationMs   int       `json:"duration_ms" db:"duration_ms"`
+	Title        string    `json:"title,omitempty" db:"title"`
+	Artist       string    `json:"artist,omitempty" db:"artist"`
+	Album        string    `json:"album,omitempty" db:"album"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+
+	WaveformStatus    string     `json:"waveform_status" db:"waveform_status"`
+	Waveform          []float64  `json:"waveform,omitempty" db:"waveform"`                     // crêtes entre 0 et 1
+	WaveformEstimated bool       `json:"waveform_estimated,omitempty" db:"waveform_estimated"` // enveloppe MP3 estimée
+	AnalysisAttempts  int        `json:"-" db:"analysis_attempts"`
+	AnalyzedAt        *time.Time `json:"-" db:"analyzed_at"`
+
+	URL string `json:"url"` // chemin de lecture (session requise si le fichier n'est pas publié, sinon URL signée)
+}
+
+// AudioStreamPath chemin de lecture d'un fichier audio envoyé, avec prise en charge des plages d'octets
+func AudioStreamPath(id uint) string {
+	return fmt.Sprintf("/api/v1/audio/%d/stream", id)
+}
+
+// audioLinkRegex liens d'un texte vers un fichier audio envoyé (chemin du site, voir AudioStreamPath)
+var audioLinkRegex = regexp.MustCompile(`(?:^|[\s(\[<"'])/api/v1/audio/([0-9]+)(?:/stream)?\b`)
+
+// ExtractAudioUploadIDs retourne les fichiers audio envoyés liés dans un texte,
+// sans doublon et dans l'ordre d'apparition
+func ExtractAudioUploadIDs(text string) []uint {
+	var ids []uint
+	seen := make(map[uint]bool)
+
+	for _, match := range audioLinkRegex.FindAllStringSubmatch(text, -1) {
+		id, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || id == 0 || seen[uint(id)] {
+			continue
+		}
+		seen[uint(id)] = true
+		ids = append(ids, uint(id))
+	}
+
+	return ids
+}
+
+// Duration durée au format m:ss
+func (a *AudioUpload) Duration() string {
+	seconds := a.DurationMs / 1000
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
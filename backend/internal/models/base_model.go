@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/musiclink"
 	"strings"
 	"time"
 )
@@ -43,22 +44,30 @@ type UserProfile struct {
 // Thread modèle fil de discussion musical
 type Thread struct {
 	BaseModel
-	Title       string  `json:"title" db:"title" validate:"required,min=5,max=200"`
-	Description string  `json:"description" db:"desc_" validate:"required,min=10"`
-	ImageURL    *string `json:"image_url" db:"image_url" validate:"omitempty"`
-	State       string  `json:"state" db:"state" validate:"oneof=ouvert fermé archivé"`
-	Visibility  string  `json:"visibility" db:"visibility" validate:"oneof=public privé"`
-	UserID      uint    `json:"user_id" db:"user_id"`
-	Author      *User   `json:"author,omitempty"`
-	Tags        []*Tag  `json:"tags,omitempty"`
-	FireCount   int     `json:"fire_count"` // Compteur Fire 🔥
-	SkipCount   int     `json:"skip_count"` // Compteur Skip ⏭️
+	Title           string     `json:"title" db:"title" validate:"required,min=5,max=200"`
+	Description     string     `json:"description" db:"desc_" validate:"required,min=10"`
+	DescriptionHTML string     `json:"description_html" db:"desc_html"` // rendu Markdown assaini (cache)
+	ImageURL        *string    `json:"image_url" db:"image_url" validate:"omitempty"`
+	State           string     `json:"state" db:"state" validate:"oneof=ouvert fermé archivé"`
+	Visibility      string     `json:"visibility" db:"visibility" validate:"oneof=public privé"`
+	Access          string     `json:"access" db:"access" validate:"omitempty,oneof=invitations amis"`
+	PublishAt       *time.Time `json:"publish_at,omitempty" db:"publish_at"`         // non nil = publication programmée
+	IsAnnouncement  bool       `json:"is_announcement" db:"is_announcement"`         // affiché en bannière sur l'accueil
+	MergedIntoID    *uint      `json:"merged_into_id,omitempty" db:"merged_into_id"` // fusionné dans un autre thread
+	ViewCount       int        `json:"view_count" db:"view_count"`                   // vues dédupliquées (hors robots)
+	IsPinned        bool       `json:"is_pinned"`                                    // épinglé dans la liste courante (calculé)
+	UserID          uint       `json:"user_id" db:"user_id"`
+	Author          *User      `json:"author,omitempty"`
+	Tags            []*Tag     `json:"tags,omitempty"`
+	FireCount       int        `json:"fire_count"` // Compteur Fire 🔥
+	SkipCount       int        `json:"skip_count"` // Compteur Skip ⏭️
 }
 
 // Message modèle pour les messages
 type Message struct {
 	BaseModel
 	Content         string         `json:"content" db:"content" validate:"required,min=1,max=5000,nohtml"`
+	ContentHTML     string         `json:"content_html" db:"content_html"` // rendu Markdown assaini (cache)
 	ImageURL        *string        `json:"image_url" db:"image_url" validate:"omitempty"`
 	ThreadID        uint           `json:"thread_id" db:"thread_id" validate:"required"`
 	UserID          uint           `json:"user_id" db:"user_id" validate:"required"`
@@ -66,9 +75,11 @@ type Message struct {
 	PopularityScore int            `json:"popularity_score"` // Fire - Skip
 	UserVote        *string        `json:"user_vote,omitempty" validate:"omitempty,oneof=fire skip neutral"`
 	Embeds          *MessageEmbeds `json:"embeds,omitempty" validate:"omitempty,dive"`
+	MusicEmbeds     []*MusicEmbed  `json:"music_embeds,omitempty"` // liens musicaux reconnus
 }
 
-// MessageEmbeds embeds YouTube/Spotify dans les messages
+// MessageEmbeds embeds YouTube/Spotify saisis à part dans les messages ;
+// les liens des autres plateformes sont reconnus dans le contenu (voir MusicEmbeds)
 type MessageEmbeds struct {
 	YouTube *string `json:"youtube,omitempty" validate:"omitempty,url,youtube_url"`
 	Spotify *string `json:"spotify,omitempty" validate:"omitempty,url,spotify_url"`
@@ -85,16 +96,18 @@ func ValidateMessageEmbeds(embeds *MessageEmbeds) error {
 		return errors.New("au moins un embed (YouTube ou Spotify) doit être présent")
 	}
 
-	// Vérifier les URLs YouTube
+	// Vérifier les URLs YouTube (YouTube Music compris)
 	if embeds.YouTube != nil {
-		if !strings.Contains(*embeds.YouTube, "youtube.com") && !strings.Contains(*embeds.YouTube, "youtu.be") {
+		link, err := musiclink.Parse(*embeds.YouTube)
+		if err != nil || (link.Platform != musiclink.PlatformYouTube && link.Platform != musiclink.PlatformYouTubeMusic) {
 			return errors.New("URL YouTube invalide")
 		}
 	}
 
 	// Vérifier les URLs Spotify
 	if embeds.Spotify != nil {
-		if !strings.Contains(*embeds.Spotify, "spotify.com") {
+		link, err := musiclink.Parse(*embeds.Spotify)
+		if err != nil || link.Platform != musiclink.PlatformSpotify {
 			return errors.New("URL Spotify invalide")
 		}
 	}
@@ -157,7 +170,7 @@ func ValidateMessage(msg *Message) error {
 type Tag struct {
 	ID   uint   `json:"id" db:"tag_id"`
 	Name string `json:"name" db:"name" validate:"required,min=2,max=50"`
-	Type string `json:"type"` // "genre", "artist", "album"
+	Type string `json:"type"` // "genre", "artist", "album", "track"
 }
 
 // LikedDisliked modèle pour les votes Fire/Skip
@@ -194,6 +207,12 @@ type PaginationParams struct {
 	Order   string `json:"order"`
 }
 
+// Tris disponibles pour les listes de threads (PaginationParams.Sort)
+const (
+	ThreadSortRecent = "created_at"
+	ThreadSortViews  = "views"
+)
+
 // DefaultPagination retourne les paramètres de pagination par défaut
 func DefaultPagination() PaginationParams {
 	return PaginationParams{
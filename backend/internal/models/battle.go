@@ -22,6 +22,10 @@ type BattleOption struct {
 	MusicURL  string `json:"music_url"`  // URL ou chemin du fichier musique
 	ImageURL  string `json:"image_url"`  // URL ou chemin de l'image associée
 	VoteCount int    `json:"vote_count"` // Compte des votes pour cette option (calculé)
+
+	Embed   *MusicEmbed  `json:"embed,omitempty"`    // Lien musical reconnu de MusicURL
+	AudioID *uint        `json:"audio_id,omitempty"` // Fichier audio envoyé joué par l'option
+	Audio   *AudioUpload `json:"audio,omitempty"`
 }
 
 // BattleVote représente le vote d'un utilisateur pour une option dans une battle
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Rapprochements possibles d'un lien avec une entrée du catalogue
+const (
+	CatalogMatchManual      = "manual"       // rattaché à la main par un utilisateur
+	CatalogMatchISRC        = "isrc"         // même ISRC (ou même UPC pour un album)
+	CatalogMatchTitleArtist = "title_artist" // même titre et même artiste une fois normalisés
+)
+
+// CatalogArtist artiste du catalogue, commun à toutes les plateformes
+type CatalogArtist struct {
+	ID        uint      `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	MatchKey  string    `json:"-" db:"match_key"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CatalogAlbum album du catalogue
+type CatalogAlbum struct {
+	ID         uint      `json:"id" db:"id"`
+	ArtistID   uint      `json:"artist_id" db:"artist_id"`
+	ArtistName string    `json:"artist_name" db:"-"`
+	Title      string    `json:"title" db:"title"`
+	MatchKey   string    `json:"-" db:"match_key"`
+	UPC        *string   `json:"upc,omitempty" db:"upc"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// CatalogTrack morceau du catalogue
+type CatalogTrack struct {
+	ID         uint      `json:"id" db:"id"`
+	ArtistID   uint      `json:"artist_id" db:"artist_id"`
+	ArtistName string    `json:"artist_name" db:"-"`
+	AlbumID    *uint     `json:"album_id,omitempty" db:"album_id"`
+	AlbumTitle *string   `json:"album_title,omitempty" db:"-"`
+	Title      string    `json:"title" db:"title"`
+	MatchKey   string    `json:"-" db:"match_key"`
+	ISRC       *string   `json:"isrc,omitempty" db:"isrc"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// CatalogLink lien d'une plateforme rattaché à une entrée du catalogue (même type : track, album ou artist)
+type CatalogLink struct {
+	Platform   string    `json:"platform" db:"platform"`
+	EntityType string    `json:"type" db:"entity_type"`
+	EntityID   string    `json:"id" db:"entity_id"`
+	CatalogID  uint      `json:"catalog_id" db:"catalog_id"`
+	URL        string    `json:"url" db:"url"`
+	MatchedBy  string    `json:"matched_by" db:"matched_by"`
+	CreatedBy  *uint     `json:"-" db:"created_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Key clé plateforme:type:id du lien, identique à celle de musiclink.Link
+func (l *CatalogLink) Key() string {
+	return l.Platform + ":" + l.EntityType + ":" + l.EntityID
+}
+
+// CatalogThreadRef thread citant une entrée du catalogue, sur n'importe quelle plateforme
+type CatalogThreadRef struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CatalogBattleRef option de battle proposant une entrée du catalogue
+type CatalogBattleRef struct {
+	BattleID    uint   `json:"battle_id"`
+	BattleTitle string `json:"battle_title"`
+	OptionID    uint   `json:"option_id"`
+	OptionTitle string `json:"option_title"`
+	VoteCount   int    `json:"vote_count"`
+}
+
+// CatalogChartEntry position d'une entrée dans un classement, tous liens confondus
+type CatalogChartEntry struct {
+	CatalogType  string `json:"type"`
+	CatalogID    uint   `json:"id"`
+	Title        string `json:"title"`
+	ArtistName   string `json:"artist_name,omitempty"`
+	ThreadCount  int    `json:"thread_count"`  // threads distincts citant l'entrée (description ou commentaires)
+	MentionCount int    `json:"mention_count"` // citations au total
+}
+
+// catalogBracketPattern mention entre parenthèses ou crochets d'un titre
+var catalogBracketPattern = regexp.MustCompile(`[(\[]([^)\]]*)[)\]]`)
+
+// catalogNoiseWords mots d'une mention qui ne change pas l'enregistrement (édition, invités) ;
+// une mention comme "(Live)" ou "(Remix)" désigne un autre morceau et reste dans la clé
+var catalogNoiseWords = map[string]bool{
+	"feat": true, "ft": true, "featuring": true, "remaster": true, "remastered": true,
+	"explicit": true, "deluxe": true, "edition": true, "bonus": true, "mono": true, "stereo": true,
+}
+
+// CatalogMatchKey normalise un titre ou un nom pour le rapprochement entre plateformes :
+// minuscules, mentions d'édition ou d'invités retirées ("(Remastered 2011)", "- 2009 Remaster",
+// "feat. X") et ponctuation réduite à des espaces simples
+func CatalogMatchKey(value string) string {
+	value = strings.ToLower(value)
+	value = catalogBracketPattern.ReplaceAllStringFunc(value, func(mention string) string {
+		if isCatalogNoise(mention) {
+			return " "
+		}
+		return mention
+	})
+	if i := strings.LastIndex(value, " - "); i > 0 && isCatalogNoise(value[i:]) {
+		value = value[:i]
+	}
+
+	words := catalogWords(value)
+	for i, word := range words {
+		if i > 0 && (word == "feat" || word == "ft" || word == "featuring") {
+			words = words[:i]
+			break
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// isCatalogNoise indique si une mention de titre ne fait que préciser l'édition ou les invités
+func isCatalogNoise(mention string) bool {
+	for _, word := range catalogWords(mention) {
+		if catalogNoiseWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// catalogWords découpe un texte en mots (lettres et chiffres)
+func catalogWords(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
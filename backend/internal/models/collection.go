@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Collection liste ordonnée de threads créée par un utilisateur ("Meilleurs albums 2026", ...)
+type Collection struct {
+	ID          uint      `json:"id" db:"id"`
+	UserID      uint      `json:"user_id" db:"user_id"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	Visibility  string    `json:"visibility" db:"visibility"` // public ou privé (propriétaire uniquement)
+	ItemCount   int       `json:"item_count"`                 // calculé
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+
+	// Relations (chargées séparément)
+	Owner *User             `json:"owner,omitempty"`
+	Items []*CollectionItem `json:"items,omitempty"`
+}
+
+// CollectionItem thread placé dans une collection, avec une note optionnelle
+type CollectionItem struct {
+	CollectionID uint      `json:"collection_id" db:"collection_id"`
+	ThreadID     uint      `json:"thread_id" db:"thread_id"`
+	Position     int       `json:"position" db:"position"`
+	Note         *string   `json:"note,omitempty" db:"note"`
+	AddedAt      time.Time `json:"added_at" db:"added_at"`
+
+	// Relations (chargées séparément)
+	Thread *Thread `json:"thread,omitempty"`
+}
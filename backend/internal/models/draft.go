@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// ThreadDraft brouillon de thread sauvegardé automatiquement pendant la rédaction
+type ThreadDraft struct {
+	ID          uint       `json:"id" db:"id"`
+	UserID      uint       `json:"user_id" db:"user_id"`
+	Title       string     `json:"title" db:"title" validate:"max=200"`
+	Description string     `json:"description" db:"desc_"`
+	ImageURL    *string    `json:"image_url" db:"image_url"`
+	Tags        []string   `json:"tags" db:"tags"` // stockés séparés par des virgules
+	Visibility  string     `json:"visibility" db:"visibility" validate:"omitempty,oneof=public privé"`
+	Access      string     `json:"access" db:"access" validate:"omitempty,oneof=invitations amis"` // thread privé : voir ThreadAccessInvitees
+	PublishAt   *time.Time `json:"publish_at,omitempty" db:"publish_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxHashtagsPerThread nombre maximum de hashtags convertis en tags par thread
+const MaxHashtagsPerThread = 10
+
+// hashtagRegex repère les #hashtag candidats (le format exact est vérifié dans ExtractHashtags)
+var hashtagRegex = regexp.MustCompile(`#([\p{L}\p{N}_-]+)`)
+
+// ExtractHashtags retourne les hashtags d'un contenu, normalisés en minuscules,
+// sans doublon et dans l'ordre d'apparition
+func ExtractHashtags(content string) []string {
+	var hashtags []string
+	seen := make(map[string]bool)
+
+	for _, match := range hashtagRegex.FindAllStringSubmatchIndex(content, -1) {
+		// Ignorer les ancres d'URL et les entités : le # doit débuter un mot
+		if match[0] > 0 {
+			previous, _ := utf8.DecodeLastRuneInString(content[:match[0]])
+			if previous == '/' || previous == '&' || previous == '#' || unicode.IsLetter(previous) || unicode.IsDigit(previous) {
+				continue
+			}
+		}
+
+		hashtag := strings.ToLower(strings.Trim(content[match[2]:match[3]], "_-"))
+		length := utf8.RuneCountInString(hashtag)
+		if length < 2 || length > 50 || isNumeric(hashtag) {
+			continue
+		}
+
+		if seen[hashtag] {
+			continue
+		}
+		seen[hashtag] = true
+
+		hashtags = append(hashtags, hashtag)
+		if len(hashtags) == MaxHashtagsPerThread {
+			break
+		}
+	}
+
+	return hashtags
+}
+
+// isNumeric indique si une chaîne ne contient que des chiffres (#1, #2024 ne sont pas des tags)
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
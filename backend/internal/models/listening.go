@@ -0,0 +1,30 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ListeningEvent écoute d'un morceau par un utilisateur (historique et « en écoute »)
+type ListeningEvent struct {
+	ID         uint      `json:"id" db:"id"`
+	UserID     uint      `json:"user_id" db:"user_id"`
+	TrackName  string    `json:"track_name" db:"track_name"`
+	Artist     string    `json:"artist" db:"artist"`
+	Album      string    `json:"album,omitempty" db:"album"`
+	DurationMs int       `json:"duration_ms" db:"duration_ms"`
+	MusicURL   string    `json:"music_url,omitempty" db:"music_url"`
+	StartedAt  time.Time `json:"started_at" db:"started_at"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"` // fin prévue de l'écoute
+	InHistory  bool      `json:"-" db:"in_history"`          // FALSE : « en écoute » d'un scrobbler, pas encore scrobblé
+}
+
+// IsPlaying indique si le morceau est encore en cours d'écoute à now
+func (e *ListeningEvent) IsPlaying(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// ActivityLabel texte d'activité affiché aux amis pendant l'écoute
+func (e *ListeningEvent) ActivityLabel() string {
+	return fmt.Sprintf("🎵 Écoute: \"%s\" par %s", e.TrackName, e.Artist)
+}
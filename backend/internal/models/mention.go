@@ -0,0 +1,102 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Sources possibles d'une mention
+const (
+	MentionSourceThread  = "thread"
+	MentionSourceComment = "comment"
+	MentionSourceMessage = "message"
+)
+
+// MaxMentionsPerContent nombre maximum d'utilisateurs mentionnés pris en compte par contenu
+const MaxMentionsPerContent = 10
+
+// mentionRegex repère les @username candidats (le format exact est vérifié dans ExtractMentions)
+var mentionRegex = regexp.MustCompile(`@([a-zA-Z0-9_]+)`)
+
+// Mention utilisateur mentionné (@username) dans un thread, un commentaire ou un message privé
+type Mention struct {
+	ID              uint      `json:"id" db:"id"`
+	MentionedUserID uint      `json:"mentioned_user_id" db:"mentioned_user_id"`
+	AuthorID        uint      `json:"author_id" db:"author_id"`
+	SourceType      string    `json:"source_type" db:"source_type"`
+	SourceID        uint      `json:"source_id" db:"source_id"`
+	ThreadID        *uint     `json:"thread_id,omitempty" db:"thread_id"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+
+	// Chargé avec l'utilisateur mentionné
+	MentionedUsername string `json:"mentioned_username,omitempty"`
+}
+
+// ExtractMentions retourne les noms d'utilisateur mentionnés dans un contenu,
+// sans doublon (insensible à la casse) et dans l'ordre d'apparition
+func ExtractMentions(content string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+
+	for _, match := range mentionRegex.FindAllStringSubmatchIndex(content, -1) {
+		// Ignorer les adresses e-mail et les "@@" : le @ doit débuter un mot
+		if match[0] > 0 && isMentionChar(content[match[0]-1]) {
+			continue
+		}
+
+		username := content[match[2]:match[3]]
+		if len(username) < 3 || len(username) > 30 {
+			continue
+		}
+
+		key := strings.ToLower(username)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		usernames = append(usernames, username)
+		if len(usernames) == MaxMentionsPerContent {
+			break
+		}
+	}
+
+	return usernames
+}
+
+// ReplaceMentions remplace chaque @username valide d'un contenu par le résultat de replace.
+// Si replace retourne false, le texte d'origine est conservé.
+func ReplaceMentions(content string, replace func(username string) (string, bool)) string {
+	var b strings.Builder
+	last := 0
+
+	for _, match := range mentionRegex.FindAllStringSubmatchIndex(content, -1) {
+		if match[0] > 0 && isMentionChar(content[match[0]-1]) {
+			continue
+		}
+
+		username := content[match[2]:match[3]]
+		if len(username) < 3 || len(username) > 30 {
+			continue
+		}
+
+		replacement, ok := replace(username)
+		if !ok {
+			continue
+		}
+
+		b.WriteString(content[last:match[0]])
+		b.WriteString(replacement)
+		last = match[1]
+	}
+	b.WriteString(content[last:])
+
+	return b.String()
+}
+
+// isMentionChar indique si un caractère peut précéder un @ sans débuter une mention
+func isMentionChar(c byte) bool {
+	return c == '@' || c == '_' || c == '.' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -35,6 +35,13 @@ type DirectMessage struct {
 	// Relations (chargées séparément)
 	Sender   *User `json:"sender,omitempty"`
 	Receiver *User `json:"receiver,omitempty"`
+
+	// Carte du thread partagé (calculée à partir du contenu)
+	SharedThread *SharedThreadCard `json:"shared_thread,omitempty"`
+	// Utilisateurs mentionnés (@username)
+	Mentions []*Mention `json:"mentions,omitempty"`
+	// Réactions emoji (compteurs par emoji)
+	Reactions []*ReactionSummary `json:"reactions,omitempty"`
 }
 
 // ConversationPresence représente la présence d'un utilisateur dans une conversation
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// Actions de modération journalisées
+const (
+	ModerationActionSplit = "split"
+	ModerationActionMove  = "move"
+	ModerationActionRetag = "retag"
+	ModerationActionMerge = "merge"
+)
+
+// ModerationAction entrée du journal de modération d'un thread
+type ModerationAction struct {
+	ID             uint      `json:"id" db:"id"`
+	ModeratorID    uint      `json:"moderator_id" db:"moderator_id"`
+	Action         string    `json:"action" db:"action"`
+	ThreadID       uint      `json:"thread_id" db:"thread_id"`
+	TargetThreadID *uint     `json:"target_thread_id,omitempty" db:"target_thread_id"` // thread créé ou destination
+	Details        *string   `json:"details,omitempty" db:"details"`                   // paramètres au format JSON
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+
+	// Chargé avec le modérateur
+	ModeratorUsername string `json:"moderator_username,omitempty"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"rythmitbackend/pkg/musiclink"
+	"strconv"
+	"time"
+)
+
+// Cibles possibles d'un lien musical
+const (
+	EmbedTargetThread       = "thread"        // description d'un thread
+	EmbedTargetComment      = "comment"       // commentaire d'un thread (messages)
+	EmbedTargetBattleOption = "battle_option" // option d'une battle
+)
+
+// EmbedPlatformUpload plateforme des liens vers un fichier audio envoyé sur le site (EntityID = ID du fichier)
+const EmbedPlatformUpload = "upload"
+
+// MusicEmbed lien musical reconnu dans un contenu (voir pkg/musiclink)
+type MusicEmbed struct {
+	ID         uint      `json:"-" db:"id"`
+	TargetType string    `json:"-" db:"target_type"`
+	TargetID   uint      `json:"-" db:"target_id"`
+	Position   int       `json:"-" db:"position"`
+	Platform   string    `json:"platform" db:"platform"`
+	EntityType string    `json:"type" db:"entity_type"` // track, album, artist ou playlist
+	EntityID   string    `json:"id" db:"entity_id"`
+	URL        string    `json:"url" db:"url"` // URL canonique
+	CreatedAt  time.Time `json:"-" db:"created_at"`
+
+	// Catalogue (renseignés si le lien est rattaché à une entrée, voir CatalogLink)
+	CatalogID    *uint  `json:"catalog_id,omitempty" db:"-"`
+	PreferredURL string `json:"preferred_url,omitempty" db:"-"` // même entrée sur la plateforme préférée du lecteur
+
+	Metadata *MusicMetadata `json:"metadata,omitempty" db:"-"` // titre, artiste, pochette (une fois récupérés)
+	Audio    *AudioUpload   `json:"audio,omitempty" db:"-"`    // fichier envoyé : durée et forme d'onde (si le lecteur y a accès)
+}
+
+// NewMusicEmbed crée l'embed d'un lien reconnu pour une cible
+func NewMusicEmbed(targetType string, targetID uint, position int, link *musiclink.Link) *MusicEmbed {
+	return &MusicEmbed{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Position:   position,
+		Platform:   link.Platform,
+		EntityType: link.Type,
+		EntityID:   link.ID,
+		URL:        link.URL,
+	}
+}
+
+// NewAudioEmbed crée l'embed d'un lien vers un fichier audio envoyé pour une cible
+func NewAudioEmbed(targetType string, targetID uint, position int, audioID uint) *MusicEmbed {
+	return &MusicEmbed{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Position:   position,
+		Platform:   EmbedPlatformUpload,
+		EntityType: musiclink.TypeTrack,
+		EntityID:   strconv.FormatUint(uint64(audioID), 10),
+		URL:        AudioStreamPath(audioID),
+	}
+}
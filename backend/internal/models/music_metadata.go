@@ -0,0 +1,46 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Statuts des métadonnées d'un lien musical
+const (
+	MetadataStatusPending  = "pending"   // en attente du worker
+	MetadataStatusOK       = "ok"        // récupérées
+	MetadataStatusNotFound = "not_found" // inconnues du fournisseur
+	MetadataStatusError    = "error"     // échec, nouvel essai plus tard
+)
+
+// MusicMetadata métadonnées en cache d'une entité musicale (voir pkg/musicmeta)
+type MusicMetadata struct {
+	Platform      string     `json:"-" db:"platform"`
+	EntityType    string     `json:"-" db:"entity_type"`
+	EntityID      string     `json:"-" db:"entity_id"`
+	URL           string     `json:"-" db:"url"`
+	Status        string     `json:"-" db:"status"`
+	Title         string     `json:"title" db:"title"`
+	Artist        string     `json:"artist,omitempty" db:"artist"`
+	Album         string     `json:"album,omitempty" db:"album"`
+	DurationMs    int        `json:"duration_ms,omitempty" db:"duration_ms"`
+	ArtworkURL    string     `json:"artwork_url,omitempty" db:"artwork_url"`
+	Provider      string     `json:"-" db:"provider"`
+	Attempts      int        `json:"-" db:"attempts"`
+	FetchedAt     *time.Time `json:"-" db:"fetched_at"`
+	NextAttemptAt time.Time  `json:"-" db:"next_attempt_at"`
+}
+
+// Key clé plateforme:type:id, identique à celle de musiclink.Link
+func (m *MusicMetadata) Key() string {
+	return m.Platform + ":" + m.EntityType + ":" + m.EntityID
+}
+
+// Duration durée formatée m:ss (vide si inconnue)
+func (m *MusicMetadata) Duration() string {
+	if m.DurationMs <= 0 {
+		return ""
+	}
+	seconds := m.DurationMs / 1000
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
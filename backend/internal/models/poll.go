@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Poll sondage attaché à un thread, plus léger qu'une Battle
+type Poll struct {
+	ID             uint          `json:"id"`
+	ThreadID       uint          `json:"thread_id"`
+	Question       string        `json:"question"`
+	MultipleChoice bool          `json:"multiple_choice"`
+	HideResults    bool          `json:"hide_results"` // résultats masqués tant que l'utilisateur n'a pas voté
+	ClosesAt       *time.Time    `json:"closes_at,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	Options        []*PollOption `json:"options"`
+	VoterCount     int           `json:"voter_count"` // nombre de bulletins (calculé)
+}
+
+// PollOption représente un choix d'un sondage
+type PollOption struct {
+	ID        uint   `json:"id"`
+	PollID    uint   `json:"poll_id"`
+	Label     string `json:"label"`
+	Position  int    `json:"position"`
+	VoteCount int    `json:"vote_count"` // calculé
+}
+
+// IsClosed indique si le sondage n'accepte plus de votes
+func (p *Poll) IsClosed(now time.Time) bool {
+	return p.ClosesAt != nil && !now.Before(*p.ClosesAt)
+}
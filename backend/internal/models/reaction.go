@@ -0,0 +1,41 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Cibles possibles d'une réaction
+const (
+	ReactionTargetComment = "comment" // commentaire d'un thread (messages)
+	ReactionTargetMessage = "message" // message privé (direct_messages)
+)
+
+// DefaultReactionSet réactions proposées quand REACTION_SET n'est pas configuré
+var DefaultReactionSet = []string{"🔥", "⏭️", "😂", "🎧", "💯", "❤️", "😮"}
+
+// Reaction réaction emoji d'un utilisateur sur un commentaire ou un message privé
+type Reaction struct {
+	ID         uint      `json:"id" db:"id"`
+	UserID     uint      `json:"user_id" db:"user_id"`
+	TargetType string    `json:"target_type" db:"target_type"`
+	TargetID   uint      `json:"target_id" db:"target_id"`
+	Emoji      string    `json:"emoji" db:"emoji"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+
+	// Chargé avec l'utilisateur
+	Username string `json:"username,omitempty"`
+}
+
+// ReactionSummary nombre de réactions d'un emoji sur une cible
+type ReactionSummary struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted,omitempty"` // l'utilisateur courant a réagi avec cet emoji
+}
+
+// NormalizeReaction retire le sélecteur de variante (U+FE0F), que certains claviers omettent,
+// pour comparer deux emojis
+func NormalizeReaction(emoji string) string {
+	return strings.ReplaceAll(strings.TrimSpace(emoji), "\uFE0F", "")
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Types de tags pouvant faire l'objet d'une critique
+const (
+	TagTypeGenre  = "genre"
+	TagTypeArtist = "artist"
+	TagTypeAlbum  = "album"
+	TagTypeTrack  = "track"
+)
+
+// ReviewScoreMax note maximale d'une critique (les notes vont de 0 à 10, au dixième près)
+const ReviewScoreMax = 10.0
+
+// Tris disponibles pour les listes de critiques (ReviewFilter.Sort)
+const (
+	ReviewSortRecent    = "recent"
+	ReviewSortScoreDesc = "score_desc"
+	ReviewSortScoreAsc  = "score_asc"
+)
+
+// Review critique attachée à un thread : note globale d'un artiste, d'un album ou d'un morceau
+type Review struct {
+	ThreadID  uint           `json:"thread_id"`
+	TagID     uint           `json:"tag_id"`
+	TagName   string         `json:"tag_name"`
+	TagType   string         `json:"tag_type"`
+	Score     float64        `json:"score"`
+	Tracks    []*ReviewTrack `json:"tracks,omitempty"` // notes par morceau (optionnelles)
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+
+	// Thread critiqué (renseigné dans les listes)
+	ThreadTitle string `json:"thread_title,omitempty"`
+	AuthorID    uint   `json:"author_id,omitempty"`
+	AuthorName  string `json:"author_name,omitempty"`
+}
+
+// ReviewTrack note d'un morceau dans la critique d'un album ou d'un artiste
+type ReviewTrack struct {
+	Position int     `json:"position"`
+	Title    string  `json:"title"`
+	Score    float64 `json:"score"`
+}
+
+// TagRating note moyenne des critiques d'un tag
+type TagRating struct {
+	TagID       uint    `json:"tag_id"`
+	Average     float64 `json:"average"`
+	ReviewCount int     `json:"review_count"`
+}
+
+// ReviewFilter tri, bornes de note et pagination d'une liste de critiques
+type ReviewFilter struct {
+	MinScore *float64
+	MaxScore *float64
+	Sort     string
+	Page     int
+	PerPage  int
+}
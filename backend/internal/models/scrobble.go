@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ScrobbleToken jeton personnel d'un scrobbler (clé de session Last.fm, jeton ListenBrainz) ;
+// le jeton lui-même n'est affiché qu'à sa création
+type ScrobbleToken struct {
+	ID          uint       `json:"id" db:"id"`
+	UserID      uint       `json:"user_id" db:"user_id"`
+	Name        string     `json:"name" db:"name"`
+	TokenPrefix string     `json:"token_prefix" db:"token_prefix"` // premiers caractères, pour reconnaître le jeton
+	LastUsedAt  *time.Time `json:"last_used_at" db:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+
+	Username string `json:"-"` // propriétaire (réponses Last.fm et ListenBrainz)
+}
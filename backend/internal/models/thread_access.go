@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// ThreadInvitation donne accès à un thread privé à un utilisateur précis
+type ThreadInvitation struct {
+	ID        uint      `json:"id" db:"id"`
+	ThreadID  uint      `json:"thread_id" db:"thread_id"`
+	UserID    uint      `json:"user_id" db:"user_id"`
+	InvitedBy uint      `json:"invited_by" db:"invited_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// Relations (chargées séparément)
+	User *User `json:"user,omitempty"`
+}
+
+// Niveaux d'accès d'un thread privé (l'auteur et les invités y ont toujours accès)
+const (
+	ThreadAccessInvitees = "invitations" // auteur + invités uniquement
+	ThreadAccessFriends  = "amis"        // auteur + invités + amis de l'auteur
+)
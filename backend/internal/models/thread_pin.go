@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// ThreadPin épingle un thread en tête du fil global (TagID nil) ou d'un tag
+type ThreadPin struct {
+	ID        uint       `json:"id" db:"id"`
+	ThreadID  uint       `json:"thread_id" db:"thread_id"`
+	TagID     *uint      `json:"tag_id,omitempty" db:"tag_id"`
+	PinnedBy  uint       `json:"pinned_by" db:"pinned_by"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"` // nil = sans expiration
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
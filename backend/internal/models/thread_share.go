@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types de partage d'un thread
+const (
+	ShareKindRepost  = "repost"  // republié sur le profil de l'utilisateur
+	ShareKindMessage = "message" // envoyé dans une conversation privée
+)
+
+// ThreadShare partage d'un thread par un utilisateur
+type ThreadShare struct {
+	ID          uint      `json:"id" db:"id"`
+	ThreadID    uint      `json:"thread_id" db:"thread_id"`
+	UserID      uint      `json:"user_id" db:"user_id"`
+	Kind        string    `json:"kind" db:"kind"`
+	Comment     *string   `json:"comment,omitempty" db:"comment"`
+	RecipientID *uint     `json:"recipient_id,omitempty" db:"recipient_id"` // uniquement pour un partage en message
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+
+	// Relations (chargées séparément)
+	Thread *Thread `json:"thread,omitempty"`
+}
+
+// SharedThreadCard aperçu d'un thread partagé affiché comme carte dans un message privé
+type SharedThreadCard struct {
+	ThreadID uint   `json:"thread_id"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+}
+
+// sharedThreadPrefix marque les messages privés contenant un thread partagé
+const sharedThreadPrefix = "[thread:"
+
+// FormatSharedThreadMessage construit le contenu d'un message privé de partage :
+// une première ligne "[thread:ID] Titre" suivie du commentaire éventuel
+func FormatSharedThreadMessage(threadID uint, title, comment string) string {
+	content := fmt.Sprintf("%s%d] %s", sharedThreadPrefix, threadID, title)
+	if comment != "" {
+		content += "\n" + comment
+	}
+	return content
+}
+
+// ParseSharedThreadMessage extrait la carte d'un message de partage et le commentaire qui l'accompagne
+func ParseSharedThreadMessage(content string) (*SharedThreadCard, string, bool) {
+	if !strings.HasPrefix(content, sharedThreadPrefix) {
+		return nil, "", false
+	}
+
+	header, comment, _ := strings.Cut(content, "\n")
+	idPart, title, found := strings.Cut(strings.TrimPrefix(header, sharedThreadPrefix), "] ")
+	if !found {
+		return nil, "", false
+	}
+
+	threadID, err := strconv.ParseUint(idPart, 10, 32)
+	if err != nil {
+		return nil, "", false
+	}
+
+	return &SharedThreadCard{
+		ThreadID: uint(threadID),
+		Title:    title,
+		URL:      fmt.Sprintf("/thread/%d", threadID),
+	}, comment, true
+}
+
+// AttachSharedThread renseigne la carte du thread partagé si le message en contient un
+func (m *DirectMessage) AttachSharedThread() {
+	if card, _, ok := ParseSharedThreadMessage(m.Content); ok {
+		m.SharedThread = card
+	}
+}
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+)
+
+// AnnotationRepository interface pour les annotations de la description des threads
+type AnnotationRepository interface {
+	ThreadText(threadID uint) (string, int, error)
+	Create(annotation *models.Annotation) error
+	FindByID(id uint, viewerID *uint) (*models.Annotation, error)
+	FindByThreadID(threadID uint, viewerID *uint) ([]*models.Annotation, error)
+	UpdateAnchor(annotation *models.Annotation) error
+	Delete(id uint) error
+	SetVote(annotationID, userID uint, state string) error
+}
+
+// annotationRepository implémentation concrète
+type annotationRepository struct {
+	*BaseRepository
+}
+
+// NewAnnotationRepository crée une nouvelle instance du repository
+func NewAnnotationRepository(db *sql.DB) AnnotationRepository {
+	return &annotationRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// annotationSelect colonnes d'une annotation avec son auteur, ses votes et le vote du lecteur (premier paramètre)
+const annotationSelect = `
+	SELECT a.id, a.thread_id, a.user_id, a.start_offset, a.end_offset, a.quote, a.body, a.revision, a.stale,
+	       a.created_at, a.updated_at, u.username,
+	       (SELECT COUNT(*) FROM annotation_votes v WHERE v.annotation_id = a.id AND v.state = 'fire'),
+	       (SELECT COUNT(*) FROM annotation_votes v WHERE v.annotation_id = a.id AND v.state = 'skip'),
+	       (SELECT v.state FROM annotation_votes v WHERE v.annotation_id = a.id AND v.user_id = ?)
+	FROM thread_annotations a
+	JOIN users u ON u.id = a.user_id
+`
+
+// ThreadText récupère le texte brut de la description d'un thread et son numéro de révision
+func (r *annotationRepository) ThreadText(threadID uint) (string, int, error) {
+	var text string
+	var revision int
+	err := r.DB.QueryRow("SELECT desc_, desc_revision FROM threads WHERE id = ?", threadID).Scan(&text, &revision)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", 0, utils.ErrThreadNotFound
+		}
+		return "", 0, fmt.Errorf("erreur récupération description: %w", err)
+	}
+	return text, revision, nil
+}
+
+// Create enregistre une nouvelle annotation
+func (r *annotationRepository) Create(annotation *models.Annotation) error {
+	result, err := r.DB.Exec(`
+		INSERT INTO thread_annotations (thread_id, user_id, start_offset, end_offset, quote, body, revision, stale, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, FALSE, NOW(), NOW())
+	`, annotation.ThreadID, annotation.UserID, annotation.StartOffset, annotation.EndOffset, annotation.Quote, annotation.Body, annotation.Revision)
+	if err != nil {
+		return fmt.Errorf("erreur création annotation: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID annotation: %w", err)
+	}
+	annotation.ID = uint(id)
+
+	return nil
+}
+
+// FindByID récupère une annotation ; UserVote est renseigné pour viewerID
+func (r *annotationRepository) FindByID(id uint, viewerID *uint) (*models.Annotation, error) {
+	rows, err := r.DB.Query(annotationSelect+" WHERE a.id = ?", viewerArg(viewerID), id)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération annotation: %w", err)
+	}
+	defer rows.Close()
+
+	annotations, err := scanAnnotations(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(annotations) == 0 {
+		return nil, utils.ErrAnnotationNotFound
+	}
+
+	return annotations[0], nil
+}
+
+// FindByThreadID récupère les annotations d'un thread dans l'ordre du texte, les obsolètes en dernier
+func (r *annotationRepository) FindByThreadID(threadID uint, viewerID *uint) ([]*models.Annotation, error) {
+	rows, err := r.DB.Query(annotationSelect+`
+		WHERE a.thread_id = ?
+		ORDER BY a.stale ASC, a.start_offset ASC, a.id ASC
+	`, viewerArg(viewerID), threadID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération annotations: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAnnotations(rows)
+}
+
+// UpdateAnchor enregistre le nouvel ancrage d'une annotation après une modification du texte
+func (r *annotationRepository) UpdateAnchor(annotation *models.Annotation) error {
+	_, err := r.DB.Exec(`
+		UPDATE thread_annotations
+		SET start_offset = ?, end_offset = ?, revision = ?, stale = ?
+		WHERE id = ?
+	`, annotation.StartOffset, annotation.EndOffset, annotation.Revision, annotation.Stale, annotation.ID)
+	if err != nil {
+		return fmt.Errorf("erreur réancrage annotation: %w", err)
+	}
+	return nil
+}
+
+// Delete supprime une annotation (votes en cascade)
+func (r *annotationRepository) Delete(id uint) error {
+	if _, err := r.DB.Exec("DELETE FROM thread_annotations WHERE id = ?", id); err != nil {
+		return fmt.Errorf("erreur suppression annotation: %w", err)
+	}
+	return nil
+}
+
+// SetVote enregistre le vote Fire/Skip d'un utilisateur ; un vote neutre retire son vote
+func (r *annotationRepository) SetVote(annotationID, userID uint, state string) error {
+	if state == models.VoteNeutral {
+		if _, err := r.DB.Exec("DELETE FROM annotation_votes WHERE annotation_id = ? AND user_id = ?", annotationID, userID); err != nil {
+			return fmt.Errorf("erreur retrait vote annotation: %w", err)
+		}
+		return nil
+	}
+
+	_, err := r.DB.Exec(`
+		INSERT INTO annotation_votes (user_id, annotation_id, state, created_at, updated_at)
+		VALUES (?, ?, ?, NOW(), NOW())
+		ON DUPLICATE KEY UPDATE state = VALUES(state), updated_at = NOW()
+	`, userID, annotationID, state)
+	if err != nil {
+		return fmt.Errorf("erreur vote annotation: %w", err)
+	}
+	return nil
+}
+
+// viewerArg identifiant du lecteur pour les requêtes (0 pour un visiteur)
+func viewerArg(viewerID *uint) uint {
+	if viewerID == nil {
+		return 0
+	}
+	return *viewerID
+}
+
+// scanAnnotations lit les lignes produites par annotationSelect
+func scanAnnotations(rows *sql.Rows) ([]*models.Annotation, error) {
+	var annotations []*models.Annotation
+	for rows.Next() {
+		annotation := &models.Annotation{}
+		var userVote sql.NullString
+		err := rows.Scan(
+			&annotation.ID, &annotation.ThreadID, &annotation.UserID, &annotation.StartOffset, &annotation.EndOffset,
+			&annotation.Quote, &annotation.Body, &annotation.Revision, &annotation.Stale,
+			&annotation.CreatedAt, &annotation.UpdatedAt, &annotation.Username,
+			&annotation.FireCount, &annotation.SkipCount, &userVote,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan annotation: %w", err)
+		}
+		if userVote.Valid {
+			annotation.UserVote = &userVote.String
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations, rows.Err()
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+)
+
+// AudioRepository interface pour les fichiers audio envoyés par les utilisateurs
+type AudioRepository interface {
+	Create(audio *models.AudioUpload) error
+	FindByID(id uint) (*models.AudioUpload, error)
+	IsPublished(id uint) (bool, error)
+	FindPendingAnalysis(limit, maxAttempts int) ([]*models.AudioUpload, error)
+	SaveAnalysis(audio *models.AudioUpload) error
+}
+
+// audioRepository implémentation concrète
+type audioRepository struct {
+	*BaseRepository
+}
+
+// NewAudioRepository crée une nouvelle instance du repository
+func NewAudioRepository(db *sql.DB) AudioRepository {
+	return &audioRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create enregistre un fichier audio déjà écrit sur le disque, en attente d'analyse
+func (r *audioRepository) Create(audio *models.AudioUpload) error {
+	result, err := r.DB.Exec(`
+		INSERT INTO audio_uploads (user_id, file_name, original_name, format, size_bytes, duration_ms, title, artist, album, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), NOW())
+	`, audio.UserID, audio.FileName, audio.OriginalName, audio.Format, audio.SizeBytes, audio.DurationMs,
+		audio.Title, audio.Artist, audio.Album)
+	if err != nil {
+		return fmt.Errorf("erreur enregistrement du fichier audio: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID du fichier audio: %w", err)
+	}
+	audio.ID = uint(id)
+	audio.URL = models.AudioStreamPath(audio.ID)
+	audio.WaveformStatus = models.WaveformStatusPending
+
+	return nil
+}
+
+// FindByID récupère un fichier audio
+func (r *audioRepository) FindByID(id uint) (*models.AudioUpload, error) {
+	row := r.DB.QueryRow(`SELECT `+audioColumns+` FROM audio_uploads WHERE id = ?`, id)
+	audio, err := scanAudio(row)
+	if err == sql.ErrNoRows {
+		return nil, utils.ErrAudioNotFound
+	}
+	return audio, err
+}
+
+// IsPublished indique si le fichier est joué par une option de battle (les battles sont publiques)
+func (r *audioRepository) IsPublished(id uint) (bool, error) {
+	var published bool
+	err := r.DB.QueryRow(`SELECT EXISTS(SELECT 1 FROM battle_options WHERE audio_id = ?)`, id).Scan(&published)
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification de la publication du fichier audio: %w", err)
+	}
+	return published, nil
+}
+
+// FindPendingAnalysis liste les fichiers à analyser (nouveaux, ou en erreur moins de maxAttempts fois)
+func (r *audioRepository) FindPendingAnalysis(limit, maxAttempts int) ([]*models.AudioUpload, error) {
+	rows, err := r.DB.Query(`
+		SELECT `+audioColumns+`
+		FROM audio_uploads
+		WHERE waveform_status = 'pending' OR (waveform_status = 'error' AND analysis_attempts < ?)
+		ORDER BY created_at ASC
+		LIMIT ?
+	`, maxAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération des fichiers audio à analyser: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []*models.AudioUpload
+	for rows.Next() {
+		audio, err := scanAudio(rows)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, audio)
+	}
+
+	return pending, rows.Err()
+}
+
+// SaveAnalysis enregistre le résultat d'une analyse ; la durée n'est remplacée que par une analyse réussie
+func (r *audioRepository) SaveAnalysis(audio *models.AudioUpload) error {
+	var waveform interface{}
+	if audio.Waveform != nil {
+		data, err := json.Marshal(audio.Waveform)
+		if err != nil {
+			return fmt.Errorf("erreur encodage de la forme d'onde: %w", err)
+		}
+		waveform = string(data)
+	}
+
+	_, err := r.DB.Exec(`
+		UPDATE audio_uploads
+		SET waveform_status = ?, waveform = ?, waveform_estimated = ?, analysis_attempts = ?, analyzed_at = ?,
+		    duration_ms = IF(? = 'ok', ?, duration_ms)
+		WHERE id = ?
+	`, audio.WaveformStatus, waveform, audio.WaveformEstimated, audio.AnalysisAttempts, audio.AnalyzedAt,
+		audio.WaveformStatus, audio.DurationMs, audio.ID)
+	if err != nil {
+		return fmt.Errorf("erreur enregistrement de l'analyse du fichier audio: %w", err)
+	}
+	return nil
+}
+
+// audioColumns colonnes lues par scanAudio
+const audioColumns = `id, user_id, file_name, original_name, format, size_bytes, duration_ms, title, artist, album, created_at,
+	waveform_status, waveform, waveform_estimated, analysis_attempts, analyzed_at`
+
+// scanAudio lit une ligne de audio_uploads (colonnes de audioColumns)
+func scanAudio(row interface{ Scan(...interface{}) error }) (*models.AudioUpload, error) {
+	audio := &models.AudioUpload{}
+	var title, artist, album, waveform sql.NullString
+	err := row.Scan(&audio.ID, &audio.UserID, &audio.FileName, &audio.OriginalName, &audio.Format,
+		&audio.SizeBytes, &audio.DurationMs, &title, &artist, &album, &audio.CreatedAt,
+		&audio.WaveformStatus, &waveform, &audio.WaveformEstimated, &audio.AnalysisAttempts, &audio.AnalyzedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur scan du fichier audio: %w", err)
+	}
+
+	audio.Title, audio.Artist, audio.Album = title.String, artist.String, album.String
+	if waveform.Valid {
+		if err := json.Unmarshal([]byte(waveform.String), &audio.Waveform); err != nil {
+			return nil, fmt.Errorf("erreur lecture de la forme d'onde: %w", err)
+		}
+	}
+	audio.URL = models.AudioStreamPath(audio.ID)
+
+	return audio, nil
+}
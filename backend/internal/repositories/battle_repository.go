@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"rythmitbackend/internal/models"
 	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/musiclink"
 	"strings"
 	// Import the MySQL driver for the migrate tool. Not used directly in code,
 	// but needed for the driver to be registered if using migrate as a library.
@@ -14,7 +15,9 @@ import (
 // BattleRepository interface pour les opérations CRUD sur les battles de musique
 type BattleRepository interface {
 	Create(battle *models.Battle) error
+	AddOption(option *models.BattleOption, creatorID uint) error // Ajoute une musique : lien musical reconnu ou fichier audio envoyé par creatorID
 	FindByID(id uint) (*models.Battle, error)
+	FindCreatorID(id uint) (uint, error) // Créateur de la battle, seul autorisé à y ajouter des options
 	FindAll(params models.PaginationParams) ([]*models.Battle, int64, error)
 	FindActive(limit int) ([]*models.Battle, error)
 	Update(battle *models.Battle) error
@@ -249,6 +252,107 @@ func (r *battleRepository) FindAll(params models.PaginationParams) ([]*models.Ba
 	return battles, total, nil
 }
 
+// AddOption ajoute une option à une battle : un fichier audio envoyé par creatorID (AudioID) ou un lien
+// reconnu (voir pkg/musiclink), enregistré sous sa forme canonique avec son embed structuré
+func (r *battleRepository) AddOption(option *models.BattleOption, creatorID uint) error {
+	if option.AudioID != nil {
+		return r.addAudioOption(option, creatorID)
+	}
+
+	link, err := musiclink.Parse(option.MusicURL)
+	if err != nil {
+		return fmt.Errorf("URL musicale de l'option invalide: %w", utils.ErrInvalidInput)
+	}
+	option.MusicURL = link.URL
+
+	return r.Transaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			INSERT INTO battle_options (battle_id, title, artist, music_url, image_url)
+			VALUES (?, ?, ?, ?, ?)
+		`, option.BattleID, option.Title, option.Artist, option.MusicURL, option.ImageURL)
+		if err != nil {
+			return fmt.Errorf("erreur création option de battle: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("erreur récupération ID option: %w", err)
+		}
+		option.ID = uint(id)
+
+		option.Embed = models.NewMusicEmbed(models.EmbedTargetBattleOption, option.ID, 0, link)
+		_, err = tx.Exec(`
+			INSERT INTO music_embeds (target_type, target_id, position, platform, entity_type, entity_id, url, created_at)
+			VALUES (?, ?, 0, ?, ?, ?, ?, NOW())
+		`, models.EmbedTargetBattleOption, option.ID, link.Platform, link.Type, link.ID, link.URL)
+		if err != nil {
+			return fmt.Errorf("erreur enregistrement lien musical de l'option: %w", err)
+		}
+
+		// Métadonnées récupérées au prochain passage du worker
+		_, err = tx.Exec(`
+			INSERT IGNORE INTO music_metadata (platform, entity_type, entity_id, url, status, next_attempt_at, created_at)
+			VALUES (?, ?, ?, ?, 'pending', NOW(), NOW())
+		`, link.Platform, link.Type, link.ID, link.URL)
+		if err != nil {
+			return fmt.Errorf("erreur mise en attente des métadonnées de l'option: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// addAudioOption ajoute une option jouant un fichier audio envoyé par creatorID ; le titre et
+// l'artiste manquants sont repris des tags du fichier. Le fichier d'un autre utilisateur est
+// introuvable : l'ajouter à une option le rendrait lisible par tous (voir IsPublished)
+func (r *battleRepository) addAudioOption(option *models.BattleOption, creatorID uint) error {
+	audio, err := scanAudio(r.DB.QueryRow(`SELECT `+audioColumns+` FROM audio_uploads WHERE id = ?`, *option.AudioID))
+	if err == sql.ErrNoRows || (err == nil && audio.UserID != creatorID) {
+		return utils.ErrAudioNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(option.Title) == "" {
+		option.Title = audio.Title
+	}
+	if strings.TrimSpace(option.Artist) == "" {
+		option.Artist = audio.Artist
+	}
+	option.MusicURL = audio.URL
+
+	result, err := r.DB.Exec(`
+		INSERT INTO battle_options (battle_id, title, artist, music_url, image_url, audio_id)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, option.BattleID, option.Title, option.Artist, option.MusicURL, option.ImageURL, audio.ID)
+	if err != nil {
+		return fmt.Errorf("erreur création option de battle: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID option: %w", err)
+	}
+	option.ID = uint(id)
+	option.Audio = audio
+
+	return nil
+}
+
+// FindCreatorID récupère le créateur d'une battle
+func (r *battleRepository) FindCreatorID(id uint) (uint, error) {
+	var creatorID uint
+	err := r.DB.QueryRow(`SELECT creator_id FROM battles WHERE id = ?`, id).Scan(&creatorID)
+	if err == sql.ErrNoRows {
+		return 0, utils.ErrBattleNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erreur récupération créateur de la battle: %w", err)
+	}
+	return creatorID, nil
+}
+
 // Update met à jour une battle de musique
 func (r *battleRepository) Update(battle *models.Battle) error {
 	query := `
@@ -379,9 +483,14 @@ func (r *battleRepository) GetUserVote(battleID uint, userID uint) (uint, error)
 func (r *battleRepository) getBattleOptionsWithVotes(battleID uint) ([]*models.BattleOption, error) {
 	// Query pour sélectionner les options pour une battle
 	optionsQuery := `
-		SELECT id, battle_id, title, artist, music_url, image_url
-		FROM battle_options
-		WHERE battle_id = ?
+		SELECT bo.id, bo.battle_id, bo.title, bo.artist, bo.music_url, bo.image_url, bo.audio_id,
+		       me.platform, me.entity_type, me.entity_id, me.url,
+		       mm.title, mm.artist, mm.album, mm.duration_ms, mm.artwork_url
+		FROM battle_options bo
+		LEFT JOIN music_embeds me ON me.target_type = 'battle_option' AND me.target_id = bo.id AND me.position = 0
+		LEFT JOIN music_metadata mm ON mm.platform = me.platform AND mm.entity_type = me.entity_type
+			AND mm.entity_id = me.entity_id AND mm.title IS NOT NULL
+		WHERE bo.battle_id = ?
 	`
 	optionsRows, err := r.DB.Query(optionsQuery, battleID)
 	if err != nil {
@@ -395,6 +504,10 @@ func (r *battleRepository) getBattleOptionsWithVotes(battleID uint) ([]*models.B
 
 	for optionsRows.Next() {
 		option := &models.BattleOption{}
+		var audioID sql.NullInt64
+		var platform, entityType, entityID, embedURL sql.NullString
+		var metaTitle, metaArtist, metaAlbum, metaArtwork sql.NullString
+		var metaDuration sql.NullInt64
 		err := optionsRows.Scan(
 			&option.ID,
 			&option.BattleID,
@@ -402,10 +515,43 @@ func (r *battleRepository) getBattleOptionsWithVotes(battleID uint) ([]*models.B
 			&option.Artist,
 			&option.MusicURL,
 			&option.ImageURL,
+			&audioID,
+			&platform,
+			&entityType,
+			&entityID,
+			&embedURL,
+			&metaTitle,
+			&metaArtist,
+			&metaAlbum,
+			&metaDuration,
+			&metaArtwork,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("erreur scan option pour battle %d: %w", battleID, err)
 		}
+		if audioID.Valid {
+			id := uint(audioID.Int64)
+			option.AudioID = &id
+		}
+		if platform.Valid {
+			option.Embed = &models.MusicEmbed{
+				TargetType: models.EmbedTargetBattleOption,
+				TargetID:   option.ID,
+				Platform:   platform.String,
+				EntityType: entityType.String,
+				EntityID:   entityID.String,
+				URL:        embedURL.String,
+			}
+			if metaTitle.Valid {
+				option.Embed.Metadata = &models.MusicMetadata{
+					Title:      metaTitle.String,
+					Artist:     metaArtist.String,
+					Album:      metaAlbum.String,
+					DurationMs: int(metaDuration.Int64),
+					ArtworkURL: metaArtwork.String,
+				}
+			}
+		}
 		options = append(options, option)
 		optionIDs = append(optionIDs, option.ID)
 		optionsMap[option.ID] = option
@@ -421,6 +567,10 @@ func (r *battleRepository) getBattleOptionsWithVotes(battleID uint) ([]*models.B
 		return options, nil
 	}
 
+	if err := r.attachOptionAudio(options); err != nil {
+		return nil, err
+	}
+
 	// Récupérer les comptes de votes pour ces options
 	// Utiliser une requête groupée pour compter les votes par option_id
 	// Utiliser une clause IN pour filtrer par optionIDs
@@ -467,3 +617,37 @@ func (r *battleRepository) getBattleOptionsWithVotes(battleID uint) ([]*models.B
 	// Retourner les options avec les VoteCounts mis à jour
 	return options, nil
 }
+
+// attachOptionAudio renseigne les fichiers audio envoyés joués par les options
+func (r *battleRepository) attachOptionAudio(options []*models.BattleOption) error {
+	var args []interface{}
+	byAudio := make(map[uint][]*models.BattleOption)
+	for _, option := range options {
+		if option.AudioID != nil {
+			args = append(args, *option.AudioID)
+			byAudio[*option.AudioID] = append(byAudio[*option.AudioID], option)
+		}
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	rows, err := r.DB.Query(`SELECT `+audioColumns+` FROM audio_uploads WHERE id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return fmt.Errorf("erreur récupération des fichiers audio des options: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		audio, err := scanAudio(rows)
+		if err != nil {
+			return err
+		}
+		for _, option := range byAudio[audio.ID] {
+			option.Audio = audio
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+)
+
+// BookmarkRepository interface pour les threads mis en favoris
+type BookmarkRepository interface {
+	Add(userID, threadID uint) error
+	Remove(userID, threadID uint) (bool, error)
+	IsBookmarked(userID, threadID uint) (bool, error)
+	FindThreadsByUserID(userID uint) ([]*models.Thread, error)
+}
+
+// bookmarkRepository implémentation concrète
+type bookmarkRepository struct {
+	*BaseRepository
+}
+
+// NewBookmarkRepository crée une nouvelle instance du repository
+func NewBookmarkRepository(db *sql.DB) BookmarkRepository {
+	return &bookmarkRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Add met un thread en favori (sans effet s'il l'est déjà)
+func (r *bookmarkRepository) Add(userID, threadID uint) error {
+	query := "INSERT IGNORE INTO thread_bookmarks (user_id, thread_id, created_at) VALUES (?, ?, NOW())"
+	if _, err := r.DB.Exec(query, userID, threadID); err != nil {
+		return fmt.Errorf("erreur ajout favori: %w", err)
+	}
+	return nil
+}
+
+// Remove retire un thread des favoris ; retourne false s'il n'y était pas
+func (r *bookmarkRepository) Remove(userID, threadID uint) (bool, error) {
+	result, err := r.DB.Exec("DELETE FROM thread_bookmarks WHERE user_id = ? AND thread_id = ?", userID, threadID)
+	if err != nil {
+		return false, fmt.Errorf("erreur suppression favori: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification suppression favori: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// IsBookmarked vérifie si un thread est dans les favoris d'un utilisateur
+func (r *bookmarkRepository) IsBookmarked(userID, threadID uint) (bool, error) {
+	var bookmarked bool
+	err := r.DB.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM thread_bookmarks WHERE user_id = ? AND thread_id = ?)",
+		userID, threadID,
+	).Scan(&bookmarked)
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification favori: %w", err)
+	}
+	return bookmarked, nil
+}
+
+// FindThreadsByUserID récupère les threads favoris encore accessibles, les plus récents d'abord
+func (r *bookmarkRepository) FindThreadsByUserID(userID uint) ([]*models.Thread, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		       u.id, u.username, u.email, u.profile_pic
+		FROM thread_bookmarks b
+		JOIN threads t ON t.id = b.thread_id
+		JOIN users u ON t.user_id = u.id
+		WHERE b.user_id = ? AND %s
+		ORDER BY b.created_at DESC
+	`, threadVisibilityClause(&userID))
+
+	rows, err := r.DB.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération favoris: %w", err)
+	}
+	defer rows.Close()
+
+	var threads []*models.Thread
+	for rows.Next() {
+		thread := &models.Thread{Author: &models.User{}}
+		err := rows.Scan(
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan favori: %w", err)
+		}
+		threads = append(threads, thread)
+	}
+
+	return threads, nil
+}
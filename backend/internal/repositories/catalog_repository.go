@@ -0,0 +1,468 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/musiclink"
+	"strings"
+	"time"
+)
+
+// CatalogRepository interface pour le catalogue musical commun aux plateformes
+type CatalogRepository interface {
+	FindLink(platform, entityType, entityID string) (*models.CatalogLink, error)
+	FindLinksByEntry(catalogType string, catalogID uint) ([]*models.CatalogLink, error)
+	SaveLink(link *models.CatalogLink) error
+	DeleteLink(platform, entityType, entityID string) error
+	ResolveEmbeds(embeds []*models.MusicEmbed, preferredPlatform string) error
+
+	FindArtist(id uint) (*models.CatalogArtist, error)
+	FindOrCreateArtist(name string) (*models.CatalogArtist, bool, error)
+	FindAlbum(id uint) (*models.CatalogAlbum, error)
+	FindAlbumByUPC(upc string) (*models.CatalogAlbum, error)
+	FindAlbumByTitle(artistID uint, matchKey string) (*models.CatalogAlbum, error)
+	CreateAlbum(album *models.CatalogAlbum) error
+	FindTrack(id uint) (*models.CatalogTrack, error)
+	FindTrackByISRC(isrc string) (*models.CatalogTrack, error)
+	FindTrackByTitle(artistID uint, matchKey string) (*models.CatalogTrack, error)
+	CreateTrack(track *models.CatalogTrack) error
+
+	FindThreads(catalogType string, catalogID uint, viewerID *uint, limit int) ([]*models.CatalogThreadRef, error)
+	FindBattles(catalogType string, catalogID uint) ([]*models.CatalogBattleRef, error)
+	Charts(catalogType string, since time.Time, limit int) ([]*models.CatalogChartEntry, error)
+
+	GetPreferredPlatform(userID uint) (string, error)
+	SetPreferredPlatform(userID uint, platform string) error
+}
+
+// catalogMentionsQuery liens musicaux cités dans les threads, par la description ou les commentaires
+const catalogMentionsQuery = `
+	SELECT me.platform, me.entity_type, me.entity_id, me.target_id AS thread_id, me.created_at
+	FROM music_embeds me
+	WHERE me.target_type = 'thread'
+	UNION ALL
+	SELECT me.platform, me.entity_type, me.entity_id, m.thread_id, me.created_at
+	FROM music_embeds me
+	JOIN messages m ON m.id = me.target_id
+	WHERE me.target_type = 'comment'
+`
+
+// catalogRepository implémentation concrète
+type catalogRepository struct {
+	*BaseRepository
+}
+
+// NewCatalogRepository crée une nouvelle instance du repository
+func NewCatalogRepository(db *sql.DB) CatalogRepository {
+	return &catalogRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// FindLink récupère l'entrée du catalogue à laquelle un lien est rattaché
+func (r *catalogRepository) FindLink(platform, entityType, entityID string) (*models.CatalogLink, error) {
+	link := &models.CatalogLink{}
+	err := r.DB.QueryRow(`
+		SELECT platform, entity_type, entity_id, catalog_id, url, matched_by, created_by, created_at
+		FROM catalog_links
+		WHERE platform = ? AND entity_type = ? AND entity_id = ?
+	`, platform, entityType, entityID).Scan(
+		&link.Platform, &link.EntityType, &link.EntityID, &link.CatalogID, &link.URL, &link.MatchedBy, &link.CreatedBy, &link.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.ErrCatalogEntryNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération lien du catalogue: %w", err)
+	}
+
+	return link, nil
+}
+
+// FindLinksByEntry liste les liens de toutes les plateformes rattachés à une entrée
+func (r *catalogRepository) FindLinksByEntry(catalogType string, catalogID uint) ([]*models.CatalogLink, error) {
+	rows, err := r.DB.Query(`
+		SELECT platform, entity_type, entity_id, catalog_id, url, matched_by, created_by, created_at
+		FROM catalog_links
+		WHERE entity_type = ? AND catalog_id = ?
+		ORDER BY platform, created_at
+	`, catalogType, catalogID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération liens du catalogue: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*models.CatalogLink
+	for rows.Next() {
+		link := &models.CatalogLink{}
+		err := rows.Scan(&link.Platform, &link.EntityType, &link.EntityID, &link.CatalogID, &link.URL, &link.MatchedBy, &link.CreatedBy, &link.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan lien du catalogue: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// SaveLink rattache un lien à une entrée, ou le déplace s'il était rattaché ailleurs
+func (r *catalogRepository) SaveLink(link *models.CatalogLink) error {
+	_, err := r.DB.Exec(`
+		INSERT INTO catalog_links (platform, entity_type, entity_id, catalog_id, url, matched_by, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE catalog_id = VALUES(catalog_id), url = VALUES(url),
+			matched_by = VALUES(matched_by), created_by = VALUES(created_by)
+	`, link.Platform, link.EntityType, link.EntityID, link.CatalogID, link.URL, link.MatchedBy, link.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("erreur enregistrement lien du catalogue: %w", err)
+	}
+	return nil
+}
+
+// DeleteLink détache un lien de son entrée (l'entrée reste dans le catalogue)
+func (r *catalogRepository) DeleteLink(platform, entityType, entityID string) error {
+	result, err := r.DB.Exec(
+		"DELETE FROM catalog_links WHERE platform = ? AND entity_type = ? AND entity_id = ?",
+		platform, entityType, entityID,
+	)
+	if err != nil {
+		return fmt.Errorf("erreur suppression lien du catalogue: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return utils.ErrCatalogEntryNotFound
+	}
+	return nil
+}
+
+// ResolveEmbeds renseigne l'entrée du catalogue des liens musicaux et, si la plateforme préférée
+// est indiquée, le lien de cette plateforme pour la même entrée
+func (r *catalogRepository) ResolveEmbeds(embeds []*models.MusicEmbed, preferredPlatform string) error {
+	if len(embeds) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?, ?), ", len(embeds)), ", ")
+	query := fmt.Sprintf(`
+		SELECT cl.platform, cl.entity_type, cl.entity_id, cl.catalog_id, MIN(pref.url)
+		FROM catalog_links cl
+		LEFT JOIN catalog_links pref ON pref.entity_type = cl.entity_type AND pref.catalog_id = cl.catalog_id AND pref.platform = ?
+		WHERE (cl.platform, cl.entity_type, cl.entity_id) IN (%s)
+		GROUP BY cl.platform, cl.entity_type, cl.entity_id, cl.catalog_id
+	`, placeholders)
+
+	args := []interface{}{preferredPlatform}
+	byKey := make(map[string][]*models.MusicEmbed)
+	for _, embed := range embeds {
+		args = append(args, embed.Platform, embed.EntityType, embed.EntityID)
+		key := embed.Platform + ":" + embed.EntityType + ":" + embed.EntityID
+		byKey[key] = append(byKey[key], embed)
+	}
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("erreur résolution des liens musicaux: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		link := &models.CatalogLink{}
+		var preferredURL sql.NullString
+		if err := rows.Scan(&link.Platform, &link.EntityType, &link.EntityID, &link.CatalogID, &preferredURL); err != nil {
+			return fmt.Errorf("erreur scan résolution lien musical: %w", err)
+		}
+
+		for _, embed := range byKey[link.Key()] {
+			catalogID := link.CatalogID
+			embed.CatalogID = &catalogID
+			if preferredURL.Valid && embed.Platform != preferredPlatform {
+				embed.PreferredURL = preferredURL.String
+			}
+		}
+	}
+
+	return nil
+}
+
+// FindArtist récupère un artiste du catalogue
+func (r *catalogRepository) FindArtist(id uint) (*models.CatalogArtist, error) {
+	artist := &models.CatalogArtist{}
+	err := r.DB.QueryRow(
+		"SELECT id, name, match_key, created_at FROM catalog_artists WHERE id = ?", id,
+	).Scan(&artist.ID, &artist.Name, &artist.MatchKey, &artist.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.ErrCatalogEntryNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération artiste du catalogue: %w", err)
+	}
+	return artist, nil
+}
+
+// FindOrCreateArtist récupère l'artiste de même nom normalisé, ou le crée (created vaut alors true)
+func (r *catalogRepository) FindOrCreateArtist(name string) (*models.CatalogArtist, bool, error) {
+	matchKey := models.CatalogMatchKey(name)
+	result, err := r.DB.Exec(
+		"INSERT IGNORE INTO catalog_artists (name, match_key, created_at, updated_at) VALUES (?, ?, NOW(), NOW())",
+		name, matchKey,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("erreur création artiste du catalogue: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+
+	artist := &models.CatalogArtist{}
+	err = r.DB.QueryRow(
+		"SELECT id, name, match_key, created_at FROM catalog_artists WHERE match_key = ?", matchKey,
+	).Scan(&artist.ID, &artist.Name, &artist.MatchKey, &artist.CreatedAt)
+	if err != nil {
+		return nil, false, fmt.Errorf("erreur récupération artiste du catalogue: %w", err)
+	}
+	return artist, affected > 0, nil
+}
+
+// FindAlbum récupère un album du catalogue avec le nom de son artiste
+func (r *catalogRepository) FindAlbum(id uint) (*models.CatalogAlbum, error) {
+	return r.findAlbum("al.id = ?", id)
+}
+
+// FindAlbumByUPC récupère l'album portant un code UPC
+func (r *catalogRepository) FindAlbumByUPC(upc string) (*models.CatalogAlbum, error) {
+	return r.findAlbum("al.upc = ?", upc)
+}
+
+// FindAlbumByTitle récupère l'album d'un artiste de même titre normalisé
+func (r *catalogRepository) FindAlbumByTitle(artistID uint, matchKey string) (*models.CatalogAlbum, error) {
+	return r.findAlbum("al.artist_id = ? AND al.match_key = ? ORDER BY al.id LIMIT 1", artistID, matchKey)
+}
+
+// findAlbum récupère le premier album répondant à une condition
+func (r *catalogRepository) findAlbum(where string, args ...interface{}) (*models.CatalogAlbum, error) {
+	album := &models.CatalogAlbum{}
+	err := r.DB.QueryRow(`
+		SELECT al.id, al.artist_id, ar.name, al.title, al.match_key, al.upc, al.created_at
+		FROM catalog_albums al
+		JOIN catalog_artists ar ON ar.id = al.artist_id
+		WHERE `+where, args...).Scan(
+		&album.ID, &album.ArtistID, &album.ArtistName, &album.Title, &album.MatchKey, &album.UPC, &album.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.ErrCatalogEntryNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération album du catalogue: %w", err)
+	}
+	return album, nil
+}
+
+// CreateAlbum ajoute un album au catalogue
+func (r *catalogRepository) CreateAlbum(album *models.CatalogAlbum) error {
+	result, err := r.DB.Exec(`
+		INSERT INTO catalog_albums (artist_id, title, match_key, upc, created_at, updated_at)
+		VALUES (?, ?, ?, ?, NOW(), NOW())
+	`, album.ArtistID, album.Title, album.MatchKey, album.UPC)
+	if err != nil {
+		return fmt.Errorf("erreur création album du catalogue: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID album du catalogue: %w", err)
+	}
+	album.ID = uint(id)
+	album.CreatedAt = time.Now()
+	return nil
+}
+
+// FindTrack récupère un morceau du catalogue avec son artiste et son album
+func (r *catalogRepository) FindTrack(id uint) (*models.CatalogTrack, error) {
+	return r.findTrack("tr.id = ?", id)
+}
+
+// FindTrackByISRC récupère le morceau portant un code ISRC
+func (r *catalogRepository) FindTrackByISRC(isrc string) (*models.CatalogTrack, error) {
+	return r.findTrack("tr.isrc = ?", isrc)
+}
+
+// FindTrackByTitle récupère le morceau d'un artiste de même titre normalisé
+func (r *catalogRepository) FindTrackByTitle(artistID uint, matchKey string) (*models.CatalogTrack, error) {
+	return r.findTrack("tr.artist_id = ? AND tr.match_key = ? ORDER BY tr.id LIMIT 1", artistID, matchKey)
+}
+
+// findTrack récupère le premier morceau répondant à une condition
+func (r *catalogRepository) findTrack(where string, args ...interface{}) (*models.CatalogTrack, error) {
+	track := &models.CatalogTrack{}
+	err := r.DB.QueryRow(`
+		SELECT tr.id, tr.artist_id, ar.name, tr.album_id, al.title, tr.title, tr.match_key, tr.isrc, tr.created_at
+		FROM catalog_tracks tr
+		JOIN catalog_artists ar ON ar.id = tr.artist_id
+		LEFT JOIN catalog_albums al ON al.id = tr.album_id
+		WHERE `+where, args...).Scan(
+		&track.ID, &track.ArtistID, &track.ArtistName, &track.AlbumID, &track.AlbumTitle,
+		&track.Title, &track.MatchKey, &track.ISRC, &track.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.ErrCatalogEntryNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération morceau du catalogue: %w", err)
+	}
+	return track, nil
+}
+
+// CreateTrack ajoute un morceau au catalogue
+func (r *catalogRepository) CreateTrack(track *models.CatalogTrack) error {
+	result, err := r.DB.Exec(`
+		INSERT INTO catalog_tracks (artist_id, album_id, title, match_key, isrc, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, NOW(), NOW())
+	`, track.ArtistID, track.AlbumID, track.Title, track.MatchKey, track.ISRC)
+	if err != nil {
+		return fmt.Errorf("erreur création morceau du catalogue: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID morceau du catalogue: %w", err)
+	}
+	track.ID = uint(id)
+	track.CreatedAt = time.Now()
+	return nil
+}
+
+// FindThreads liste les threads visibles par viewerID qui citent une entrée, quelle que soit la plateforme
+func (r *catalogRepository) FindThreads(catalogType string, catalogID uint, viewerID *uint, limit int) ([]*models.CatalogThreadRef, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.title, t.created_at
+		FROM threads t
+		WHERE t.id IN (
+			SELECT src.thread_id
+			FROM (%s) src
+			JOIN catalog_links cl ON cl.platform = src.platform AND cl.entity_type = src.entity_type AND cl.entity_id = src.entity_id
+			WHERE cl.entity_type = ? AND cl.catalog_id = ?
+		) AND %s
+		ORDER BY t.created_at DESC
+		LIMIT ?
+	`, catalogMentionsQuery, threadVisibilityClause(viewerID))
+
+	rows, err := r.DB.Query(query, catalogType, catalogID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération threads de l'entrée: %w", err)
+	}
+	defer rows.Close()
+
+	var threads []*models.CatalogThreadRef
+	for rows.Next() {
+		thread := &models.CatalogThreadRef{}
+		if err := rows.Scan(&thread.ID, &thread.Title, &thread.CreatedAt); err != nil {
+			return nil, fmt.Errorf("erreur scan thread de l'entrée: %w", err)
+		}
+		threads = append(threads, thread)
+	}
+
+	return threads, nil
+}
+
+// FindBattles liste les options de battle qui proposent une entrée, quelle que soit la plateforme
+func (r *catalogRepository) FindBattles(catalogType string, catalogID uint) ([]*models.CatalogBattleRef, error) {
+	rows, err := r.DB.Query(`
+		SELECT b.id, b.title, bo.id, bo.title,
+		       (SELECT COUNT(*) FROM battle_votes bv WHERE bv.option_id = bo.id)
+		FROM music_embeds me
+		JOIN catalog_links cl ON cl.platform = me.platform AND cl.entity_type = me.entity_type AND cl.entity_id = me.entity_id
+		JOIN battle_options bo ON bo.id = me.target_id
+		JOIN battles b ON b.id = bo.battle_id
+		WHERE me.target_type = 'battle_option' AND cl.entity_type = ? AND cl.catalog_id = ?
+		ORDER BY b.created_at DESC
+	`, catalogType, catalogID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération battles de l'entrée: %w", err)
+	}
+	defer rows.Close()
+
+	var battles []*models.CatalogBattleRef
+	for rows.Next() {
+		battle := &models.CatalogBattleRef{}
+		if err := rows.Scan(&battle.BattleID, &battle.BattleTitle, &battle.OptionID, &battle.OptionTitle, &battle.VoteCount); err != nil {
+			return nil, fmt.Errorf("erreur scan battle de l'entrée: %w", err)
+		}
+		battles = append(battles, battle)
+	}
+
+	return battles, nil
+}
+
+// Charts classe les entrées d'un type les plus citées depuis une date dans les threads publics,
+// en cumulant les liens de toutes les plateformes
+func (r *catalogRepository) Charts(catalogType string, since time.Time, limit int) ([]*models.CatalogChartEntry, error) {
+	entryJoin, titleColumn, artistColumn := catalogEntryColumns(catalogType)
+	query := fmt.Sprintf(`
+		SELECT cl.catalog_id, %s, %s, COUNT(DISTINCT src.thread_id), COUNT(*)
+		FROM (%s) src
+		JOIN catalog_links cl ON cl.platform = src.platform AND cl.entity_type = src.entity_type AND cl.entity_id = src.entity_id
+		JOIN threads t ON t.id = src.thread_id
+		%s
+		WHERE cl.entity_type = ? AND src.created_at >= ? AND %s
+		GROUP BY cl.catalog_id, %s, %s
+		ORDER BY COUNT(DISTINCT src.thread_id) DESC, COUNT(*) DESC
+		LIMIT ?
+	`, titleColumn, artistColumn, catalogMentionsQuery, entryJoin, threadVisibilityClause(nil), titleColumn, artistColumn)
+
+	rows, err := r.DB.Query(query, catalogType, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erreur calcul du classement: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.CatalogChartEntry
+	for rows.Next() {
+		entry := &models.CatalogChartEntry{CatalogType: catalogType}
+		if err := rows.Scan(&entry.CatalogID, &entry.Title, &entry.ArtistName, &entry.ThreadCount, &entry.MentionCount); err != nil {
+			return nil, fmt.Errorf("erreur scan entrée du classement: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GetPreferredPlatform récupère la plateforme préférée d'un utilisateur ("" s'il n'en a pas choisi)
+func (r *catalogRepository) GetPreferredPlatform(userID uint) (string, error) {
+	var platform string
+	err := r.DB.QueryRow("SELECT platform FROM user_platform_preferences WHERE user_id = ?", userID).Scan(&platform)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("erreur récupération plateforme préférée: %w", err)
+	}
+	return platform, nil
+}
+
+// SetPreferredPlatform enregistre la plateforme préférée d'un utilisateur ("" pour l'oublier)
+func (r *catalogRepository) SetPreferredPlatform(userID uint, platform string) error {
+	var err error
+	if platform == "" {
+		_, err = r.DB.Exec("DELETE FROM user_platform_preferences WHERE user_id = ?", userID)
+	} else {
+		_, err = r.DB.Exec(`
+			INSERT INTO user_platform_preferences (user_id, platform, updated_at)
+			VALUES (?, ?, NOW())
+			ON DUPLICATE KEY UPDATE platform = VALUES(platform), updated_at = NOW()
+		`, userID, platform)
+	}
+	if err != nil {
+		return fmt.Errorf("erreur enregistrement plateforme préférée: %w", err)
+	}
+	return nil
+}
+
+// catalogEntryColumns jointure, titre et artiste d'une entrée du catalogue selon son type (liste blanche)
+func catalogEntryColumns(catalogType string) (string, string, string) {
+	switch catalogType {
+	case musiclink.TypeTrack:
+		return "JOIN catalog_tracks e ON e.id = cl.catalog_id JOIN catalog_artists ar ON ar.id = e.artist_id", "e.title", "ar.name"
+	case musiclink.TypeAlbum:
+		return "JOIN catalog_albums e ON e.id = cl.catalog_id JOIN catalog_artists ar ON ar.id = e.artist_id", "e.title", "ar.name"
+	default:
+		return "JOIN catalog_artists e ON e.id = cl.catalog_id", "e.name", "''"
+	}
+}
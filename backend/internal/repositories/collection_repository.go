@@ -0,0 +1,260 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+)
+
+// CollectionRepository interface pour les collections de threads
+type CollectionRepository interface {
+	Create(collection *models.Collection) error
+	Update(collection *models.Collection) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.Collection, error)
+	FindByUserID(userID uint, includePrivate bool) ([]*models.Collection, error)
+	NameExists(userID uint, name string, excludeID uint) (bool, error)
+	FindItems(collectionID uint, viewerID *uint) ([]*models.CollectionItem, error)
+	AddItem(collectionID, threadID uint, note *string) (bool, error)
+	UpdateItemNote(collectionID, threadID uint, note *string) (bool, error)
+	RemoveItem(collectionID, threadID uint) (bool, error)
+	Reorder(collectionID uint, threadIDs []uint) error
+}
+
+// collectionRepository implémentation concrète
+type collectionRepository struct {
+	*BaseRepository
+}
+
+// NewCollectionRepository crée une nouvelle instance du repository
+func NewCollectionRepository(db *sql.DB) CollectionRepository {
+	return &collectionRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// collectionSelect colonnes communes des requêtes de collections avec propriétaire et nombre d'éléments
+const collectionSelect = `
+	SELECT c.id, c.user_id, c.name, c.description, c.visibility, c.created_at, c.updated_at,
+	       (SELECT COUNT(*) FROM collection_items ci WHERE ci.collection_id = c.id),
+	       u.id, u.username, u.email, u.profile_pic
+	FROM collections c
+	JOIN users u ON c.user_id = u.id
+`
+
+// scanCollection lit une ligne produite par collectionSelect
+func scanCollection(row interface{ Scan(...interface{}) error }) (*models.Collection, error) {
+	collection := &models.Collection{Owner: &models.User{}}
+	err := row.Scan(
+		&collection.ID, &collection.UserID, &collection.Name, &collection.Description, &collection.Visibility, &collection.CreatedAt, &collection.UpdatedAt,
+		&collection.ItemCount,
+		&collection.Owner.ID, &collection.Owner.Username, &collection.Owner.Email, &collection.Owner.ProfilePic,
+	)
+	return collection, err
+}
+
+// Create crée une collection
+func (r *collectionRepository) Create(collection *models.Collection) error {
+	query := `
+		INSERT INTO collections (user_id, name, description, visibility, created_at, updated_at)
+		VALUES (?, ?, ?, ?, NOW(), NOW())
+	`
+
+	result, err := r.DB.Exec(query, collection.UserID, collection.Name, collection.Description, collection.Visibility)
+	if err != nil {
+		return fmt.Errorf("erreur création collection: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID collection: %w", err)
+	}
+
+	collection.ID = uint(id)
+	return nil
+}
+
+// Update met à jour le nom, la description et la visibilité d'une collection
+func (r *collectionRepository) Update(collection *models.Collection) error {
+	query := "UPDATE collections SET name = ?, description = ?, visibility = ?, updated_at = NOW() WHERE id = ?"
+
+	if _, err := r.DB.Exec(query, collection.Name, collection.Description, collection.Visibility, collection.ID); err != nil {
+		return fmt.Errorf("erreur mise à jour collection: %w", err)
+	}
+
+	return nil
+}
+
+// Delete supprime une collection et ses éléments
+func (r *collectionRepository) Delete(id uint) error {
+	if _, err := r.DB.Exec("DELETE FROM collections WHERE id = ?", id); err != nil {
+		return fmt.Errorf("erreur suppression collection: %w", err)
+	}
+	return nil
+}
+
+// FindByID trouve une collection par son ID
+func (r *collectionRepository) FindByID(id uint) (*models.Collection, error) {
+	collection, err := scanCollection(r.DB.QueryRow(collectionSelect+" WHERE c.id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.ErrCollectionNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération collection: %w", err)
+	}
+
+	return collection, nil
+}
+
+// FindByUserID récupère les collections d'un utilisateur, les privées seulement si includePrivate
+func (r *collectionRepository) FindByUserID(userID uint, includePrivate bool) ([]*models.Collection, error) {
+	query := collectionSelect + " WHERE c.user_id = ?"
+	if !includePrivate {
+		query += " AND c.visibility = 'public'"
+	}
+	query += " ORDER BY c.updated_at DESC"
+
+	rows, err := r.DB.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération collections: %w", err)
+	}
+	defer rows.Close()
+
+	var collections []*models.Collection
+	for rows.Next() {
+		collection, err := scanCollection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan collection: %w", err)
+		}
+		collections = append(collections, collection)
+	}
+
+	return collections, nil
+}
+
+// NameExists vérifie si l'utilisateur a déjà une autre collection portant ce nom
+func (r *collectionRepository) NameExists(userID uint, name string, excludeID uint) (bool, error) {
+	return r.Exists(
+		"SELECT EXISTS(SELECT 1 FROM collections WHERE user_id = ? AND name = ? AND id != ?)",
+		userID, name, excludeID,
+	)
+}
+
+// FindItems récupère les éléments d'une collection dans l'ordre, limités aux threads visibles par viewerID
+func (r *collectionRepository) FindItems(collectionID uint, viewerID *uint) ([]*models.CollectionItem, error) {
+	query := fmt.Sprintf(`
+		SELECT ci.collection_id, ci.thread_id, ci.position, ci.note, ci.added_at,
+		       t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		       u.id, u.username, u.email, u.profile_pic
+		FROM collection_items ci
+		JOIN threads t ON t.id = ci.thread_id
+		JOIN users u ON t.user_id = u.id
+		WHERE ci.collection_id = ? AND %s
+		ORDER BY ci.position ASC, ci.added_at ASC
+	`, threadVisibilityClause(viewerID))
+
+	rows, err := r.DB.Query(query, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération éléments collection: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*models.CollectionItem
+	for rows.Next() {
+		item := &models.CollectionItem{Thread: &models.Thread{Author: &models.User{}}}
+		thread := item.Thread
+		err := rows.Scan(
+			&item.CollectionID, &item.ThreadID, &item.Position, &item.Note, &item.AddedAt,
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan élément collection: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// AddItem ajoute un thread en fin de collection ; retourne false s'il y était déjà
+func (r *collectionRepository) AddItem(collectionID, threadID uint, note *string) (bool, error) {
+	query := `
+		INSERT IGNORE INTO collection_items (collection_id, thread_id, position, note, added_at)
+		SELECT ?, ?, COALESCE(MAX(position) + 1, 0), ?, NOW()
+		FROM collection_items WHERE collection_id = ?
+	`
+
+	result, err := r.DB.Exec(query, collectionID, threadID, note, collectionID)
+	if err != nil {
+		return false, fmt.Errorf("erreur ajout élément collection: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification ajout élément: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// UpdateItemNote modifie la note d'un élément ; retourne false s'il n'existe pas
+func (r *collectionRepository) UpdateItemNote(collectionID, threadID uint, note *string) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM collection_items WHERE collection_id = ? AND thread_id = ?)",
+		collectionID, threadID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification élément collection: %w", err)
+	}
+	if !exists {
+		return false, nil
+	}
+
+	_, err = r.DB.Exec("UPDATE collection_items SET note = ? WHERE collection_id = ? AND thread_id = ?", note, collectionID, threadID)
+	if err != nil {
+		return false, fmt.Errorf("erreur mise à jour note: %w", err)
+	}
+
+	return true, nil
+}
+
+// RemoveItem retire un thread d'une collection ; retourne false s'il n'y était pas
+func (r *collectionRepository) RemoveItem(collectionID, threadID uint) (bool, error) {
+	result, err := r.DB.Exec("DELETE FROM collection_items WHERE collection_id = ? AND thread_id = ?", collectionID, threadID)
+	if err != nil {
+		return false, fmt.Errorf("erreur retrait élément collection: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification retrait élément: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// Reorder applique l'ordre donné ; les threads absents de la liste gardent leur position relative en fin
+func (r *collectionRepository) Reorder(collectionID uint, threadIDs []uint) error {
+	return r.Transaction(func(tx *sql.Tx) error {
+		// Repousser d'abord tous les éléments après la nouvelle plage
+		_, err := tx.Exec("UPDATE collection_items SET position = position + ? WHERE collection_id = ?", len(threadIDs), collectionID)
+		if err != nil {
+			return fmt.Errorf("erreur décalage positions: %w", err)
+		}
+
+		for i, threadID := range threadIDs {
+			_, err := tx.Exec(
+				"UPDATE collection_items SET position = ? WHERE collection_id = ? AND thread_id = ?",
+				i, collectionID, threadID,
+			)
+			if err != nil {
+				return fmt.Errorf("erreur réordonnancement collection: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -18,6 +18,7 @@ type DirectMessageRepository interface {
 	// Messages
 	CreateMessage(message *models.DirectMessage) error
 	GetConversationMessages(conversationID uint, limit, offset int) ([]*models.DirectMessage, error)
+	GetMessageByID(messageID uint) (*models.DirectMessage, error)
 	MarkMessageAsRead(messageID uint) error
 	MarkConversationAsRead(conversationID, userID uint) error
 	GetUnreadCount(userID uint) (int, error)
@@ -263,6 +264,30 @@ func (r *directMessageRepository) GetConversationMessages(conversationID uint, l
 	return messages, nil
 }
 
+// GetMessageByID récupère un message direct par son ID
+func (r *directMessageRepository) GetMessageByID(messageID uint) (*models.DirectMessage, error) {
+	query := `
+		SELECT id, conversation_id, sender_id, receiver_id, content, is_read, read_at, created_at, updated_at
+		FROM direct_messages
+		WHERE id = ?
+	`
+
+	message := &models.DirectMessage{}
+	err := r.DB.QueryRow(query, messageID).Scan(
+		&message.ID, &message.ConversationID, &message.SenderID, &message.ReceiverID,
+		&message.Content, &message.IsRead, &message.ReadAt,
+		&message.CreatedAt, &message.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("message non trouvé")
+		}
+		return nil, fmt.Errorf("erreur récupération message: %w", err)
+	}
+
+	return message, nil
+}
+
 // MarkMessageAsRead marque un message comme lu
 func (r *directMessageRepository) MarkMessageAsRead(messageID uint) error {
 	query := `
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"strings"
+)
+
+// DraftRepository interface pour les brouillons de threads
+type DraftRepository interface {
+	Create(draft *models.ThreadDraft) error
+	Update(draft *models.ThreadDraft) error
+	FindByID(id uint) (*models.ThreadDraft, error)
+	FindByUserID(userID uint) ([]*models.ThreadDraft, error)
+	Delete(id uint) error
+}
+
+// draftRepository implémentation concrète
+type draftRepository struct {
+	*BaseRepository
+}
+
+// NewDraftRepository crée une nouvelle instance du repository
+func NewDraftRepository(db *sql.DB) DraftRepository {
+	return &draftRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create enregistre un nouveau brouillon
+func (r *draftRepository) Create(draft *models.ThreadDraft) error {
+	query := `
+		INSERT INTO thread_drafts (user_id, title, desc_, image_url, tags, visibility, access, publish_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
+	`
+
+	if draft.Access == "" {
+		draft.Access = models.ThreadAccessInvitees
+	}
+
+	result, err := r.DB.Exec(query, draft.UserID, draft.Title, draft.Description, draft.ImageURL,
+		joinDraftTags(draft.Tags), draft.Visibility, draft.Access, draft.PublishAt)
+	if err != nil {
+		return fmt.Errorf("erreur création brouillon: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID brouillon: %w", err)
+	}
+
+	draft.ID = uint(id)
+	return nil
+}
+
+// Update écrase le contenu d'un brouillon (autosave)
+func (r *draftRepository) Update(draft *models.ThreadDraft) error {
+	query := `
+		UPDATE thread_drafts
+		SET title = ?, desc_ = ?, image_url = ?, tags = ?, visibility = ?, access = ?, publish_at = ?, updated_at = NOW()
+		WHERE id = ?
+	`
+
+	if draft.Access == "" {
+		draft.Access = models.ThreadAccessInvitees
+	}
+
+	_, err := r.DB.Exec(query, draft.Title, draft.Description, draft.ImageURL,
+		joinDraftTags(draft.Tags), draft.Visibility, draft.Access, draft.PublishAt, draft.ID)
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour brouillon: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID trouve un brouillon par son ID
+func (r *draftRepository) FindByID(id uint) (*models.ThreadDraft, error) {
+	query := `
+		SELECT id, user_id, title, desc_, image_url, tags, visibility, access, publish_at, created_at, updated_at
+		FROM thread_drafts
+		WHERE id = ?
+	`
+
+	draft, err := scanDraft(r.DB.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("brouillon ID %d non trouvé", id)
+		}
+		return nil, fmt.Errorf("erreur récupération brouillon: %w", err)
+	}
+
+	return draft, nil
+}
+
+// FindByUserID liste les brouillons d'un utilisateur, le plus récent en premier
+func (r *draftRepository) FindByUserID(userID uint) ([]*models.ThreadDraft, error) {
+	query := `
+		SELECT id, user_id, title, desc_, image_url, tags, visibility, access, publish_at, created_at, updated_at
+		FROM thread_drafts
+		WHERE user_id = ?
+		ORDER BY updated_at DESC
+	`
+
+	rows, err := r.DB.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération brouillons: %w", err)
+	}
+	defer rows.Close()
+
+	var drafts []*models.ThreadDraft
+	for rows.Next() {
+		draft, err := scanDraft(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan brouillon: %w", err)
+		}
+		drafts = append(drafts, draft)
+	}
+
+	return drafts, nil
+}
+
+// Delete supprime un brouillon
+func (r *draftRepository) Delete(id uint) error {
+	_, err := r.DB.Exec("DELETE FROM thread_drafts WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("erreur suppression brouillon: %w", err)
+	}
+
+	return nil
+}
+
+// scanDraft lit une ligne de thread_drafts (QueryRow ou Rows)
+func scanDraft(row interface{ Scan(...interface{}) error }) (*models.ThreadDraft, error) {
+	draft := &models.ThreadDraft{}
+	var tags string
+	err := row.Scan(&draft.ID, &draft.UserID, &draft.Title, &draft.Description, &draft.ImageURL,
+		&tags, &draft.Visibility, &draft.Access, &draft.PublishAt, &draft.CreatedAt, &draft.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	draft.Tags = splitDraftTags(tags)
+	return draft, nil
+}
+
+// joinDraftTags sérialise les tags pour la colonne tags
+func joinDraftTags(tags []string) string {
+	var cleaned []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.ReplaceAll(tag, ",", " "))
+		if tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	return strings.Join(cleaned, ",")
+}
+
+// splitDraftTags désérialise la colonne tags
+func splitDraftTags(tags string) []string {
+	result := []string{}
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
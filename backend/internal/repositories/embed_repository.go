@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"strings"
+)
+
+// EmbedRepository interface pour les liens musicaux des threads, commentaires et options de battle
+type EmbedRepository interface {
+	ReplaceForTarget(targetType string, targetID uint, embeds []*models.MusicEmbed) error
+	FindByTargets(targetType string, targetIDs []uint) (map[uint][]*models.MusicEmbed, error)
+}
+
+// embedRepository implémentation concrète
+type embedRepository struct {
+	*BaseRepository
+}
+
+// NewEmbedRepository crée une nouvelle instance du repository
+func NewEmbedRepository(db *sql.DB) EmbedRepository {
+	return &embedRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// ReplaceForTarget remplace les liens musicaux d'une cible par ceux de son contenu actuel
+func (r *embedRepository) ReplaceForTarget(targetType string, targetID uint, embeds []*models.MusicEmbed) error {
+	return r.Transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM music_embeds WHERE target_type = ? AND target_id = ?", targetType, targetID); err != nil {
+			return fmt.Errorf("erreur suppression liens musicaux: %w", err)
+		}
+
+		for _, embed := range embeds {
+			_, err := tx.Exec(`
+				INSERT IGNORE INTO music_embeds (target_type, target_id, position, platform, entity_type, entity_id, url, created_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, NOW())
+			`, targetType, targetID, embed.Position, embed.Platform, embed.EntityType, embed.EntityID, embed.URL)
+			if err != nil {
+				return fmt.Errorf("erreur enregistrement lien musical: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// FindByTargets récupère les liens musicaux de plusieurs cibles, regroupés par cible dans l'ordre du contenu
+func (r *embedRepository) FindByTargets(targetType string, targetIDs []uint) (map[uint][]*models.MusicEmbed, error) {
+	embeds := make(map[uint][]*models.MusicEmbed)
+	if len(targetIDs) == 0 {
+		return embeds, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(targetIDs)), ", ")
+	query := fmt.Sprintf(`
+		SELECT id, target_type, target_id, position, platform, entity_type, entity_id, url, created_at
+		FROM music_embeds
+		WHERE target_type = ? AND target_id IN (%s)
+		ORDER BY target_id, position
+	`, placeholders)
+
+	args := []interface{}{targetType}
+	for _, id := range targetIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération liens musicaux: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		embed := &models.MusicEmbed{}
+		err := rows.Scan(&embed.ID, &embed.TargetType, &embed.TargetID, &embed.Position,
+			&embed.Platform, &embed.EntityType, &embed.EntityID, &embed.URL, &embed.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan lien musical: %w", err)
+		}
+		embeds[embed.TargetID] = append(embeds[embed.TargetID], embed)
+	}
+
+	return embeds, nil
+}
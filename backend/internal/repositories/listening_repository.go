@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"strings"
+	"time"
+)
+
+// ListeningRepository interface pour l'historique d'écoute des utilisateurs
+type ListeningRepository interface {
+	Create(event *models.ListeningEvent) error
+	CreateBatch(events []*models.ListeningEvent) error
+	FindNowPlaying(userIDs []uint, now time.Time) (map[uint]*models.ListeningEvent, error)
+	FindRecentByUser(userID uint, limit int) ([]*models.ListeningEvent, error)
+}
+
+// listeningRepository implémentation concrète
+type listeningRepository struct {
+	*BaseRepository
+}
+
+// NewListeningRepository crée une nouvelle instance du repository
+func NewListeningRepository(db *sql.DB) ListeningRepository {
+	return &listeningRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create enregistre une écoute et termine les écoutes commencées avant elle encore en cours ;
+// une écoute déjà enregistrée (même début, même morceau, scrobble renvoyé) n'est pas dupliquée
+func (r *listeningRepository) Create(event *models.ListeningEvent) error {
+	return r.CreateBatch([]*models.ListeningEvent{event})
+}
+
+// CreateBatch enregistre des écoutes dans l'ordre, en une seule transaction :
+// un lot de scrobbles est entièrement enregistré ou pas du tout
+func (r *listeningRepository) CreateBatch(events []*models.ListeningEvent) error {
+	return r.Transaction(func(tx *sql.Tx) error {
+		for _, event := range events {
+			if err := insertListening(tx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// insertListening enregistre une écoute dans la transaction tx (voir Create)
+func insertListening(tx *sql.Tx, event *models.ListeningEvent) error {
+	_, err := tx.Exec(`
+		UPDATE listening_events SET expires_at = ?
+		WHERE user_id = ? AND started_at < ? AND expires_at > ?
+	`, event.StartedAt, event.UserID, event.StartedAt, event.StartedAt)
+	if err != nil {
+		return fmt.Errorf("erreur fin de l'écoute en cours: %w", err)
+	}
+
+	// Les « en écoute » terminés qui n'ont jamais été scrobblés ne servent plus
+	_, err = tx.Exec(`
+		DELETE FROM listening_events
+		WHERE user_id = ? AND in_history = FALSE AND expires_at <= ?
+	`, event.UserID, event.StartedAt)
+	if err != nil {
+		return fmt.Errorf("erreur nettoyage des écoutes en cours: %w", err)
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO listening_events (user_id, track_name, artist, album, duration_ms, music_url, started_at, expires_at, in_history)
+		VALUES (?, ?, ?, NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)
+	`, event.UserID, event.TrackName, event.Artist, event.Album, event.DurationMs, event.MusicURL,
+		event.StartedAt, event.ExpiresAt, event.InHistory)
+	if err != nil {
+		return fmt.Errorf("erreur enregistrement de l'écoute: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID de l'écoute: %w", err)
+	}
+	event.ID = uint(id)
+
+	return nil
+}
+
+// FindNowPlaying récupère le morceau en cours d'écoute de chaque utilisateur (absent s'il n'écoute rien)
+func (r *listeningRepository) FindNowPlaying(userIDs []uint, now time.Time) (map[uint]*models.ListeningEvent, error) {
+	playing := make(map[uint]*models.ListeningEvent)
+	if len(userIDs) == 0 {
+		return playing, nil
+	}
+
+	args := make([]interface{}, 0, len(userIDs)+1)
+	for _, id := range userIDs {
+		args = append(args, id)
+	}
+	args = append(args, now)
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(userIDs)), ", ")
+
+	rows, err := r.DB.Query(`
+		SELECT `+listeningColumns+`
+		FROM listening_events
+		WHERE user_id IN (`+placeholders+`) AND expires_at > ?
+		ORDER BY started_at DESC, id DESC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération des écoutes en cours: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanListening(rows)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := playing[event.UserID]; !exists {
+			playing[event.UserID] = event
+		}
+	}
+
+	return playing, rows.Err()
+}
+
+// FindRecentByUser liste les dernières écoutes de l'historique d'un utilisateur, de la plus récente à la plus ancienne
+func (r *listeningRepository) FindRecentByUser(userID uint, limit int) ([]*models.ListeningEvent, error) {
+	rows, err := r.DB.Query(`
+		SELECT `+listeningColumns+`
+		FROM listening_events
+		WHERE user_id = ? AND in_history = TRUE
+		ORDER BY started_at DESC, id DESC
+		LIMIT ?
+	`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération de l'historique d'écoute: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.ListeningEvent
+	for rows.Next() {
+		event, err := scanListening(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// listeningColumns colonnes lues par scanListening
+const listeningColumns = `id, user_id, track_name, artist, album, duration_ms, music_url, started_at, expires_at, in_history`
+
+// scanListening lit une ligne de listening_events (colonnes de listeningColumns)
+func scanListening(row interface{ Scan(...interface{}) error }) (*models.ListeningEvent, error) {
+	event := &models.ListeningEvent{}
+	var album, musicURL sql.NullString
+	err := row.Scan(&event.ID, &event.UserID, &event.TrackName, &event.Artist, &album, &event.DurationMs,
+		&musicURL, &event.StartedAt, &event.ExpiresAt, &event.InHistory)
+	if err != nil {
+		return nil, fmt.Errorf("erreur scan de l'écoute: %w", err)
+	}
+	event.Album = album.String
+	event.MusicURL = musicURL.String
+	return event, nil
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"strings"
+)
+
+// MentionRepository interface pour les mentions @utilisateur
+type MentionRepository interface {
+	CreateBatch(mentions []*models.Mention) error
+	FindBySources(sourceType string, sourceIDs []uint) (map[uint][]*models.Mention, error)
+	FindCandidates(prefix string, viewerID uint, limit int) ([]*models.User, error)
+}
+
+// mentionRepository implémentation concrète
+type mentionRepository struct {
+	*BaseRepository
+}
+
+// NewMentionRepository crée une nouvelle instance du repository
+func NewMentionRepository(db *sql.DB) MentionRepository {
+	return &mentionRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// CreateBatch enregistre les mentions d'un contenu (les doublons sont ignorés)
+func (r *mentionRepository) CreateBatch(mentions []*models.Mention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	return r.Transaction(func(tx *sql.Tx) error {
+		query := `
+			INSERT IGNORE INTO mentions (mentioned_user_id, author_id, source_type, source_id, thread_id, created_at)
+			VALUES (?, ?, ?, ?, ?, NOW())
+		`
+
+		for _, mention := range mentions {
+			_, err := tx.Exec(query, mention.MentionedUserID, mention.AuthorID, mention.SourceType, mention.SourceID, mention.ThreadID)
+			if err != nil {
+				return fmt.Errorf("erreur création mention: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// FindBySources récupère les mentions de plusieurs contenus, regroupées par source
+func (r *mentionRepository) FindBySources(sourceType string, sourceIDs []uint) (map[uint][]*models.Mention, error) {
+	mentions := make(map[uint][]*models.Mention)
+	if len(sourceIDs) == 0 {
+		return mentions, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(sourceIDs)), ", ")
+	query := fmt.Sprintf(`
+		SELECT m.id, m.mentioned_user_id, m.author_id, m.source_type, m.source_id, m.thread_id, m.created_at, u.username
+		FROM mentions m
+		JOIN users u ON u.id = m.mentioned_user_id
+		WHERE m.source_type = ? AND m.source_id IN (%s)
+	`, placeholders)
+
+	args := []interface{}{sourceType}
+	for _, id := range sourceIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération mentions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		mention := &models.Mention{}
+		err := rows.Scan(
+			&mention.ID, &mention.MentionedUserID, &mention.AuthorID, &mention.SourceType, &mention.SourceID, &mention.ThreadID, &mention.CreatedAt,
+			&mention.MentionedUsername,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan mention: %w", err)
+		}
+		mentions[mention.SourceID] = append(mentions[mention.SourceID], mention)
+	}
+
+	return mentions, nil
+}
+
+// FindCandidates propose des utilisateurs à mentionner dont le nom commence par prefix :
+// amis en premier, sans l'utilisateur lui-même ni les utilisateurs bloqués dans un sens ou dans l'autre
+func (r *mentionRepository) FindCandidates(prefix string, viewerID uint, limit int) ([]*models.User, error) {
+	// Échapper les jokers LIKE saisis par l'utilisateur
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix)
+
+	query := `
+		SELECT u.id, u.username, u.profile_pic,
+		       EXISTS(
+		           SELECT 1 FROM friendships f
+		           WHERE f.status = ?
+		           AND ((f.requester_id = ? AND f.addressee_id = u.id) OR (f.requester_id = u.id AND f.addressee_id = ?))
+		       ) AS is_friend
+		FROM users u
+		WHERE u.username LIKE ? AND u.id != ?
+		AND NOT EXISTS (
+			SELECT 1 FROM friendships b
+			WHERE b.status = ?
+			AND ((b.requester_id = ? AND b.addressee_id = u.id) OR (b.requester_id = u.id AND b.addressee_id = ?))
+		)
+		ORDER BY is_friend DESC, u.username ASC
+		LIMIT ?
+	`
+
+	rows, err := r.DB.Query(query,
+		models.FriendshipStatusAccepted, viewerID, viewerID,
+		escaped+"%", viewerID,
+		models.FriendshipStatusBlocked, viewerID, viewerID,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erreur recherche utilisateurs à mentionner: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user := &models.User{}
+		var isFriend bool
+		if err := rows.Scan(&user.ID, &user.Username, &user.ProfilePic, &isFriend); err != nil {
+			return nil, fmt.Errorf("erreur scan utilisateur à mentionner: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
@@ -4,6 +4,10 @@ import (
 	"database/sql"
 	"fmt"
 	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/markdown"
+	"strings"
+	"time"
 )
 
 // MessageRepository interface pour les opérations sur les messages dans les threads (commentaires)
@@ -22,6 +26,9 @@ type MessageRepository interface {
 	// Comptage
 	CountByThreadID(threadID uint) (int, error)
 
+	// Modération
+	MoveToThread(messageIDs []uint, fromThreadID, toThreadID uint) (int64, error)
+
 	// Votes
 	SetUserVote(messageID, userID uint, voteType string) error
 	GetUserVote(messageID, userID uint) (*string, error)
@@ -43,12 +50,77 @@ func NewMessageRepository(db *sql.DB) MessageRepository {
 
 // Create crée un nouveau message
 func (r *messageRepository) Create(message *models.Message) error {
-	return fmt.Errorf("MessageRepository.Create not implemented yet - TODO")
+	query := `
+		INSERT INTO messages (content, content_html, image_url, thread_id, user_id, date_, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, NOW(), NOW(), NOW())
+	`
+
+	message.ContentHTML = markdown.Render(message.Content)
+
+	result, err := r.DB.Exec(query, message.Content, message.ContentHTML, message.ImageURL, message.ThreadID, message.UserID)
+	if err != nil {
+		return fmt.Errorf("erreur création message: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID message: %w", err)
+	}
+
+	message.ID = uint(id)
+	message.CreatedAt = time.Now()
+	message.UpdatedAt = message.CreatedAt
+	return nil
+}
+
+// messageSelect colonnes communes des requêtes de messages avec auteur et score Fire - Skip
+const messageSelect = `
+	SELECT m.id, m.content, COALESCE(m.content_html, ''), m.image_url, m.thread_id, m.user_id, m.created_at, m.updated_at,
+	       u.id, u.username, u.email, u.profile_pic,
+	       COALESCE((SELECT SUM(CASE mv.state WHEN 'fire' THEN 1 WHEN 'skip' THEN -1 ELSE 0 END)
+	                 FROM message_votes mv WHERE mv.message_id = m.id), 0) AS popularity
+	FROM messages m
+	JOIN users u ON m.user_id = u.id
+`
+
+// scanMessage lit une ligne produite par messageSelect
+func scanMessage(row interface{ Scan(...interface{}) error }) (*models.Message, error) {
+	message := &models.Message{Author: &models.User{}}
+	err := row.Scan(
+		&message.ID, &message.Content, &message.ContentHTML, &message.ImageURL, &message.ThreadID, &message.UserID, &message.CreatedAt, &message.UpdatedAt,
+		&message.Author.ID, &message.Author.Username, &message.Author.Email, &message.Author.ProfilePic,
+		&message.PopularityScore,
+	)
+	// Commentaire antérieur au cache de rendu
+	if err == nil && message.ContentHTML == "" {
+		message.ContentHTML = markdown.Render(message.Content)
+	}
+	return message, err
+}
+
+// messageOrderClause traduit le tri demandé ; chronologique par défaut
+func messageOrderClause(orderBy string) string {
+	switch orderBy {
+	case "popularity":
+		return "popularity DESC, m.created_at ASC, m.id ASC"
+	case "newest":
+		return "m.created_at DESC, m.id DESC"
+	default:
+		return "m.created_at ASC, m.id ASC"
+	}
 }
 
 // FindByID récupère un message par son ID
 func (r *messageRepository) FindByID(id uint) (*models.Message, error) {
-	return nil, fmt.Errorf("MessageRepository.FindByID not implemented yet - TODO")
+	message, err := scanMessage(r.DB.QueryRow(messageSelect+" WHERE m.id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération message: %w", err)
+	}
+
+	return message, nil
 }
 
 // Update met à jour un message
@@ -63,7 +135,32 @@ func (r *messageRepository) Delete(id uint) error {
 
 // FindByThreadID récupère les messages d'un thread
 func (r *messageRepository) FindByThreadID(threadID uint, params models.PaginationParams, orderBy string) ([]*models.Message, int, error) {
-	return nil, 0, fmt.Errorf("MessageRepository.FindByThreadID not implemented yet - TODO")
+	models.ValidatePagination(&params)
+
+	total, err := r.CountByThreadID(threadID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.PerPage
+	query := messageSelect + " WHERE m.thread_id = ? ORDER BY " + messageOrderClause(orderBy) + " LIMIT ? OFFSET ?"
+
+	rows, err := r.DB.Query(query, threadID, params.PerPage, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erreur récupération messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*models.Message
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("erreur scan message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, total, nil
 }
 
 // FindByUserID récupère les messages d'un utilisateur
@@ -71,14 +168,88 @@ func (r *messageRepository) FindByUserID(userID uint, params models.PaginationPa
 	return nil, 0, fmt.Errorf("MessageRepository.FindByUserID not implemented yet - TODO")
 }
 
-// GetMessagesWithVotes récupère les messages avec les votes
+// GetMessagesWithVotes récupère les messages avec le vote de l'utilisateur
 func (r *messageRepository) GetMessagesWithVotes(threadID uint, userID *uint, params models.PaginationParams, orderBy string) ([]*models.Message, int, error) {
-	return nil, 0, fmt.Errorf("MessageRepository.GetMessagesWithVotes not implemented yet - TODO")
+	messages, total, err := r.FindByThreadID(threadID, params, orderBy)
+	if err != nil || userID == nil || len(messages) == 0 {
+		return messages, total, err
+	}
+
+	rows, err := r.DB.Query(`
+		SELECT mv.message_id, mv.state
+		FROM message_votes mv
+		JOIN messages m ON m.id = mv.message_id
+		WHERE m.thread_id = ? AND mv.user_id = ?
+	`, threadID, *userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erreur récupération votes utilisateur: %w", err)
+	}
+	defer rows.Close()
+
+	votes := make(map[uint]string)
+	for rows.Next() {
+		var messageID uint
+		var state string
+		if err := rows.Scan(&messageID, &state); err != nil {
+			return nil, 0, fmt.Errorf("erreur scan vote: %w", err)
+		}
+		votes[messageID] = state
+	}
+
+	for _, message := range messages {
+		if state, ok := votes[message.ID]; ok {
+			vote := state
+			message.UserVote = &vote
+		}
+	}
+
+	return messages, total, nil
+}
+
+// MoveToThread déplace des commentaires d'un thread vers un autre ; retourne le nombre déplacé
+func (r *messageRepository) MoveToThread(messageIDs []uint, fromThreadID, toThreadID uint) (int64, error) {
+	if len(messageIDs) == 0 {
+		return 0, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(messageIDs)), ",")
+	args := make([]interface{}, 0, len(messageIDs)+2)
+	args = append(args, toThreadID, fromThreadID)
+	for _, id := range messageIDs {
+		args = append(args, id)
+	}
+
+	var moved int64
+	err := r.Transaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec("UPDATE messages SET thread_id = ? WHERE thread_id = ? AND id IN ("+placeholders+")", args...)
+		if err != nil {
+			return fmt.Errorf("erreur déplacement commentaires: %w", err)
+		}
+
+		moved, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("erreur vérification déplacement: %w", err)
+		}
+
+		// Les mentions des commentaires déplacés pointent vers le nouveau thread
+		mentionArgs := append([]interface{}{toThreadID, models.MentionSourceComment}, args[2:]...)
+		if _, err := tx.Exec("UPDATE mentions SET thread_id = ? WHERE source_type = ? AND source_id IN ("+placeholders+")", mentionArgs...); err != nil {
+			return fmt.Errorf("erreur déplacement mentions: %w", err)
+		}
+
+		return nil
+	})
+
+	return moved, err
 }
 
 // CountByThreadID compte les messages dans un thread
 func (r *messageRepository) CountByThreadID(threadID uint) (int, error) {
-	return 0, fmt.Errorf("MessageRepository.CountByThreadID not implemented yet - TODO")
+	var count int
+	if err := r.DB.QueryRow("SELECT COUNT(*) FROM messages WHERE thread_id = ?", threadID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("erreur comptage messages: %w", err)
+	}
+	return count, nil
 }
 
 // SetUserVote définit le vote d'un utilisateur sur un message
@@ -360,7 +360,7 @@ func TestMessageRepository(t *testing.T) {
 			} else if *vote != models.VoteFire {
 				t.Errorf("Vote direct incorrect. Attendu: %s, Obtenu: %s", models.VoteFire, *vote)
 			} else {
-				t.Logf("✅ Vote vérifié directement: %s", vote)
+				t.Logf("✅ Vote vérifié directement: %s", *vote)
 			}
 		} else {
 			t.Logf("✅ Message avec vote trouvé dans la liste")
@@ -611,6 +611,6 @@ func TestMessageRepository_EdgeCases(t *testing.T) {
 			t.Errorf("Vote neutre incorrect. Attendu: %s, Obtenu: %v", models.VoteNeutral, vote)
 		}
 
-		t.Logf("✅ Vote neutre: %s", vote)
+		t.Logf("✅ Vote neutre: %v", vote)
 	})
 }
@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"strings"
+)
+
+// MetadataRepository interface pour le cache des métadonnées des liens musicaux
+type MetadataRepository interface {
+	Enqueue(embeds []*models.MusicEmbed) error
+	AttachToEmbeds(embeds []*models.MusicEmbed) error
+	FindDue(limit int) ([]*models.MusicMetadata, error)
+	SaveResult(meta *models.MusicMetadata) error
+}
+
+// metadataRepository implémentation concrète
+type metadataRepository struct {
+	*BaseRepository
+}
+
+// NewMetadataRepository crée une nouvelle instance du repository
+func NewMetadataRepository(db *sql.DB) MetadataRepository {
+	return &metadataRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Enqueue met en attente de récupération les entités qui ne sont pas encore en cache
+func (r *metadataRepository) Enqueue(embeds []*models.MusicEmbed) error {
+	if len(embeds) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, 'pending', NOW(), NOW()), ", len(embeds)), ", ")
+	args := make([]interface{}, 0, len(embeds)*4)
+	for _, embed := range embeds {
+		args = append(args, embed.Platform, embed.EntityType, embed.EntityID, embed.URL)
+	}
+
+	_, err := r.DB.Exec(`
+		INSERT IGNORE INTO music_metadata (platform, entity_type, entity_id, url, status, next_attempt_at, created_at)
+		VALUES `+placeholders, args...)
+	if err != nil {
+		return fmt.Errorf("erreur mise en attente des métadonnées: %w", err)
+	}
+	return nil
+}
+
+// AttachToEmbeds renseigne les métadonnées en cache des liens musicaux (les liens sans métadonnées restent nus)
+func (r *metadataRepository) AttachToEmbeds(embeds []*models.MusicEmbed) error {
+	if len(embeds) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?, ?), ", len(embeds)), ", ")
+	args := make([]interface{}, 0, len(embeds)*3)
+	byKey := make(map[string][]*models.MusicEmbed)
+	for _, embed := range embeds {
+		args = append(args, embed.Platform, embed.EntityType, embed.EntityID)
+		key := embed.Platform + ":" + embed.EntityType + ":" + embed.EntityID
+		byKey[key] = append(byKey[key], embed)
+	}
+
+	rows, err := r.DB.Query(fmt.Sprintf(`
+		SELECT %s
+		FROM music_metadata
+		WHERE title IS NOT NULL AND (platform, entity_type, entity_id) IN (%s)
+	`, metadataColumns, placeholders), args...)
+	if err != nil {
+		return fmt.Errorf("erreur récupération des métadonnées: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		meta, err := scanMetadata(rows)
+		if err != nil {
+			return err
+		}
+		for _, embed := range byKey[meta.Key()] {
+			embed.Metadata = meta
+		}
+	}
+
+	return nil
+}
+
+// FindDue liste les entités à récupérer ou à rafraîchir, les plus anciennes d'abord
+func (r *metadataRepository) FindDue(limit int) ([]*models.MusicMetadata, error) {
+	rows, err := r.DB.Query(fmt.Sprintf(`
+		SELECT %s
+		FROM music_metadata
+		WHERE next_attempt_at <= NOW()
+		ORDER BY next_attempt_at ASC
+		LIMIT ?
+	`, metadataColumns), limit)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération des métadonnées en attente: %w", err)
+	}
+	defer rows.Close()
+
+	var due []*models.MusicMetadata
+	for rows.Next() {
+		meta, err := scanMetadata(rows)
+		if err != nil {
+			return nil, err
+		}
+		due = append(due, meta)
+	}
+
+	return due, nil
+}
+
+// SaveResult enregistre le résultat d'une récupération ; les métadonnées déjà en cache
+// ne sont remplacées que par une récupération réussie
+func (r *metadataRepository) SaveResult(meta *models.MusicMetadata) error {
+	var err error
+	if meta.Status == models.MetadataStatusOK {
+		_, err = r.DB.Exec(`
+			UPDATE music_metadata
+			SET status = ?, title = ?, artist = NULLIF(?, ''), album = NULLIF(?, ''), duration_ms = NULLIF(?, 0),
+			    artwork_url = NULLIF(?, ''), provider = ?, attempts = ?, fetched_at = ?, next_attempt_at = ?
+			WHERE platform = ? AND entity_type = ? AND entity_id = ?
+		`, meta.Status, meta.Title, meta.Artist, meta.Album, meta.DurationMs, meta.ArtworkURL,
+			meta.Provider, meta.Attempts, meta.FetchedAt, meta.NextAttemptAt,
+			meta.Platform, meta.EntityType, meta.EntityID)
+	} else {
+		_, err = r.DB.Exec(`
+			UPDATE music_metadata
+			SET status = ?, provider = ?, attempts = ?, next_attempt_at = ?
+			WHERE platform = ? AND entity_type = ? AND entity_id = ?
+		`, meta.Status, meta.Provider, meta.Attempts, meta.NextAttemptAt,
+			meta.Platform, meta.EntityType, meta.EntityID)
+	}
+	if err != nil {
+		return fmt.Errorf("erreur enregistrement des métadonnées: %w", err)
+	}
+	return nil
+}
+
+// metadataColumns colonnes lues par scanMetadata
+const metadataColumns = `platform, entity_type, entity_id, url, status, title, artist, album, duration_ms,
+	artwork_url, provider, attempts, fetched_at, next_attempt_at`
+
+// scanMetadata lit une ligne de music_metadata (colonnes de metadataColumns)
+func scanMetadata(rows *sql.Rows) (*models.MusicMetadata, error) {
+	meta := &models.MusicMetadata{}
+	var title, artist, album, artworkURL, provider sql.NullString
+	var durationMs sql.NullInt64
+	err := rows.Scan(&meta.Platform, &meta.EntityType, &meta.EntityID, &meta.URL, &meta.Status,
+		&title, &artist, &album, &durationMs, &artworkURL, &provider, &meta.Attempts, &meta.FetchedAt, &meta.NextAttemptAt)
+	if err != nil {
+		return nil, fmt.Errorf("erreur scan des métadonnées: %w", err)
+	}
+
+	meta.Title, meta.Artist, meta.Album = title.String, artist.String, album.String
+	meta.DurationMs = int(durationMs.Int64)
+	meta.ArtworkURL, meta.Provider = artworkURL.String, provider.String
+
+	return meta, nil
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"time"
+)
+
+// ModerationRepository interface pour le journal de modération
+type ModerationRepository interface {
+	Create(action *models.ModerationAction) error
+	FindByThreadID(threadID uint, limit int) ([]*models.ModerationAction, error)
+}
+
+// moderationRepository implémentation concrète
+type moderationRepository struct {
+	*BaseRepository
+}
+
+// NewModerationRepository crée une nouvelle instance du repository
+func NewModerationRepository(db *sql.DB) ModerationRepository {
+	return &moderationRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create enregistre une action de modération
+func (r *moderationRepository) Create(action *models.ModerationAction) error {
+	query := `
+		INSERT INTO moderation_actions (moderator_id, action, thread_id, target_thread_id, details, created_at)
+		VALUES (?, ?, ?, ?, ?, NOW())
+	`
+
+	result, err := r.DB.Exec(query, action.ModeratorID, action.Action, action.ThreadID, action.TargetThreadID, action.Details)
+	if err != nil {
+		return fmt.Errorf("erreur journalisation modération: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID action de modération: %w", err)
+	}
+
+	action.ID = uint(id)
+	action.CreatedAt = time.Now()
+	return nil
+}
+
+// FindByThreadID récupère les actions concernant un thread (source ou destination), des plus récentes aux plus anciennes
+func (r *moderationRepository) FindByThreadID(threadID uint, limit int) ([]*models.ModerationAction, error) {
+	query := `
+		SELECT ma.id, ma.moderator_id, ma.action, ma.thread_id, ma.target_thread_id, ma.details, ma.created_at, u.username
+		FROM moderation_actions ma
+		JOIN users u ON ma.moderator_id = u.id
+		WHERE ma.thread_id = ? OR ma.target_thread_id = ?
+		ORDER BY ma.created_at DESC, ma.id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.DB.Query(query, threadID, threadID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération journal de modération: %w", err)
+	}
+	defer rows.Close()
+
+	actions := []*models.ModerationAction{}
+	for rows.Next() {
+		action := &models.ModerationAction{}
+		if err := rows.Scan(
+			&action.ID, &action.ModeratorID, &action.Action, &action.ThreadID, &action.TargetThreadID, &action.Details, &action.CreatedAt,
+			&action.ModeratorUsername,
+		); err != nil {
+			return nil, fmt.Errorf("erreur scan action de modération: %w", err)
+		}
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
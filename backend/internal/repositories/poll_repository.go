@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+)
+
+// PollRepository interface pour les opérations sur les sondages de threads
+type PollRepository interface {
+	Create(poll *models.Poll) error
+	FindByThreadID(threadID uint) (*models.Poll, error)
+	Delete(pollID uint) error
+	Vote(pollID, userID uint, optionIDs []uint) error
+	GetUserVotes(pollID, userID uint) ([]uint, error)
+}
+
+// pollRepository implémentation concrète
+type pollRepository struct {
+	*BaseRepository
+}
+
+// NewPollRepository crée une nouvelle instance du repository
+func NewPollRepository(db *sql.DB) PollRepository {
+	return &pollRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create crée un sondage et ses options
+func (r *pollRepository) Create(poll *models.Poll) error {
+	return r.Transaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`
+			INSERT INTO thread_polls (thread_id, question, multiple_choice, hide_results, closes_at, created_at)
+			VALUES (?, ?, ?, ?, ?, NOW())
+		`, poll.ThreadID, poll.Question, poll.MultipleChoice, poll.HideResults, poll.ClosesAt)
+		if err != nil {
+			return fmt.Errorf("erreur création sondage: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("erreur récupération ID sondage: %w", err)
+		}
+		poll.ID = uint(id)
+
+		for i, option := range poll.Options {
+			option.PollID = poll.ID
+			option.Position = i
+			result, err := tx.Exec(
+				"INSERT INTO thread_poll_options (poll_id, label, position) VALUES (?, ?, ?)",
+				option.PollID, option.Label, option.Position,
+			)
+			if err != nil {
+				return fmt.Errorf("erreur création option sondage: %w", err)
+			}
+
+			optionID, err := result.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("erreur récupération ID option: %w", err)
+			}
+			option.ID = uint(optionID)
+		}
+
+		return nil
+	})
+}
+
+// FindByThreadID récupère le sondage d'un thread avec les votes par option
+func (r *pollRepository) FindByThreadID(threadID uint) (*models.Poll, error) {
+	query := `
+		SELECT p.id, p.thread_id, p.question, p.multiple_choice, p.hide_results, p.closes_at, p.created_at,
+		       (SELECT COUNT(*) FROM thread_poll_ballots b WHERE b.poll_id = p.id)
+		FROM thread_polls p
+		WHERE p.thread_id = ?
+	`
+
+	poll := &models.Poll{}
+	err := r.DB.QueryRow(query, threadID).Scan(
+		&poll.ID, &poll.ThreadID, &poll.Question, &poll.MultipleChoice, &poll.HideResults, &poll.ClosesAt, &poll.CreatedAt,
+		&poll.VoterCount,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.ErrPollNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération sondage: %w", err)
+	}
+
+	rows, err := r.DB.Query(`
+		SELECT o.id, o.poll_id, o.label, o.position, COUNT(v.user_id)
+		FROM thread_poll_options o
+		LEFT JOIN thread_poll_votes v ON v.option_id = o.id
+		WHERE o.poll_id = ?
+		GROUP BY o.id, o.poll_id, o.label, o.position
+		ORDER BY o.position ASC
+	`, poll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération options sondage: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		option := &models.PollOption{}
+		if err := rows.Scan(&option.ID, &option.PollID, &option.Label, &option.Position, &option.VoteCount); err != nil {
+			return nil, fmt.Errorf("erreur scan option sondage: %w", err)
+		}
+		poll.Options = append(poll.Options, option)
+	}
+
+	return poll, nil
+}
+
+// Delete supprime un sondage (options et votes en cascade)
+func (r *pollRepository) Delete(pollID uint) error {
+	if _, err := r.DB.Exec("DELETE FROM thread_polls WHERE id = ?", pollID); err != nil {
+		return fmt.Errorf("erreur suppression sondage: %w", err)
+	}
+	return nil
+}
+
+// Vote enregistre le bulletin d'un utilisateur ; un seul bulletin par utilisateur et par sondage
+func (r *pollRepository) Vote(pollID, userID uint, optionIDs []uint) error {
+	return r.Transaction(func(tx *sql.Tx) error {
+		// La clé primaire (poll_id, user_id) garantit l'unicité du bulletin
+		result, err := tx.Exec("INSERT IGNORE INTO thread_poll_ballots (poll_id, user_id, created_at) VALUES (?, ?, NOW())", pollID, userID)
+		if err != nil {
+			return fmt.Errorf("erreur enregistrement bulletin: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("erreur vérification bulletin: %w", err)
+		}
+		if affected == 0 {
+			return utils.ErrAlreadyVotedPoll
+		}
+
+		for _, optionID := range optionIDs {
+			_, err := tx.Exec(
+				"INSERT INTO thread_poll_votes (poll_id, option_id, user_id, created_at) VALUES (?, ?, ?, NOW())",
+				pollID, optionID, userID,
+			)
+			if err != nil {
+				return fmt.Errorf("erreur enregistrement vote: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetUserVotes récupère les options choisies par un utilisateur (vide s'il n'a pas voté)
+func (r *pollRepository) GetUserVotes(pollID, userID uint) ([]uint, error) {
+	rows, err := r.DB.Query("SELECT option_id FROM thread_poll_votes WHERE poll_id = ? AND user_id = ?", pollID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération votes utilisateur: %w", err)
+	}
+	defer rows.Close()
+
+	var optionIDs []uint
+	for rows.Next() {
+		var optionID uint
+		if err := rows.Scan(&optionID); err != nil {
+			return nil, fmt.Errorf("erreur scan vote utilisateur: %w", err)
+		}
+		optionIDs = append(optionIDs, optionID)
+	}
+
+	return optionIDs, nil
+}
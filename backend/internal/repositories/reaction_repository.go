@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"strings"
+)
+
+// ReactionRepository interface pour les réactions emoji
+type ReactionRepository interface {
+	Add(reaction *models.Reaction) (bool, error)
+	Remove(userID uint, targetType string, targetID uint, emoji string) (bool, error)
+	SummariesByTargets(targetType string, targetIDs []uint, viewerID *uint) (map[uint][]*models.ReactionSummary, error)
+	FindByTarget(targetType string, targetID uint, limit int) ([]*models.Reaction, error)
+}
+
+// reactionRepository implémentation concrète
+type reactionRepository struct {
+	*BaseRepository
+}
+
+// NewReactionRepository crée une nouvelle instance du repository
+func NewReactionRepository(db *sql.DB) ReactionRepository {
+	return &reactionRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Add ajoute une réaction ; retourne false si l'utilisateur avait déjà réagi avec cet emoji
+func (r *reactionRepository) Add(reaction *models.Reaction) (bool, error) {
+	result, err := r.DB.Exec(`
+		INSERT IGNORE INTO reactions (user_id, target_type, target_id, emoji, created_at)
+		VALUES (?, ?, ?, ?, NOW())
+	`, reaction.UserID, reaction.TargetType, reaction.TargetID, reaction.Emoji)
+	if err != nil {
+		return false, fmt.Errorf("erreur ajout réaction: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification ajout réaction: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// Remove retire une réaction ; retourne false si elle n'existait pas
+func (r *reactionRepository) Remove(userID uint, targetType string, targetID uint, emoji string) (bool, error) {
+	result, err := r.DB.Exec(
+		"DELETE FROM reactions WHERE user_id = ? AND target_type = ? AND target_id = ? AND emoji = ?",
+		userID, targetType, targetID, emoji,
+	)
+	if err != nil {
+		return false, fmt.Errorf("erreur suppression réaction: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification suppression réaction: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// SummariesByTargets compte les réactions de plusieurs cibles, regroupées par cible,
+// dans l'ordre de la première réaction ; Reacted est renseigné pour viewerID
+func (r *reactionRepository) SummariesByTargets(targetType string, targetIDs []uint, viewerID *uint) (map[uint][]*models.ReactionSummary, error) {
+	summaries := make(map[uint][]*models.ReactionSummary)
+	if len(targetIDs) == 0 {
+		return summaries, nil
+	}
+
+	var viewer uint
+	if viewerID != nil {
+		viewer = *viewerID
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(targetIDs)), ", ")
+	query := fmt.Sprintf(`
+		SELECT target_id, emoji, COUNT(*), SUM(user_id = ?) > 0
+		FROM reactions
+		WHERE target_type = ? AND target_id IN (%s)
+		GROUP BY target_id, emoji
+		ORDER BY target_id, MIN(created_at), emoji
+	`, placeholders)
+
+	args := []interface{}{viewer, targetType}
+	for _, id := range targetIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur comptage réactions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var targetID uint
+		summary := &models.ReactionSummary{}
+		if err := rows.Scan(&targetID, &summary.Emoji, &summary.Count, &summary.Reacted); err != nil {
+			return nil, fmt.Errorf("erreur scan réaction: %w", err)
+		}
+		summaries[targetID] = append(summaries[targetID], summary)
+	}
+
+	return summaries, nil
+}
+
+// FindByTarget récupère les réactions d'une cible avec le nom des utilisateurs, les plus anciennes en premier
+func (r *reactionRepository) FindByTarget(targetType string, targetID uint, limit int) ([]*models.Reaction, error) {
+	rows, err := r.DB.Query(`
+		SELECT re.id, re.user_id, re.target_type, re.target_id, re.emoji, re.created_at, u.username
+		FROM reactions re
+		JOIN users u ON u.id = re.user_id
+		WHERE re.target_type = ? AND re.target_id = ?
+		ORDER BY re.created_at ASC, re.id ASC
+		LIMIT ?
+	`, targetType, targetID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération réactions: %w", err)
+	}
+	defer rows.Close()
+
+	var reactions []*models.Reaction
+	for rows.Next() {
+		reaction := &models.Reaction{}
+		err := rows.Scan(
+			&reaction.ID, &reaction.UserID, &reaction.TargetType, &reaction.TargetID, &reaction.Emoji, &reaction.CreatedAt,
+			&reaction.Username,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan réaction: %w", err)
+		}
+		reactions = append(reactions, reaction)
+	}
+
+	return reactions, nil
+}
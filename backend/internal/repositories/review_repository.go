@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+)
+
+// ReviewRepository interface pour les critiques de threads
+type ReviewRepository interface {
+	Save(review *models.Review) error
+	FindByThreadID(threadID uint) (*models.Review, error)
+	Delete(threadID uint) error
+	FindByTag(tagID uint, viewerID *uint, filter models.ReviewFilter) ([]*models.Review, int64, error)
+	RatingForTag(tagID uint, viewerID *uint) (*models.TagRating, error)
+}
+
+// reviewRepository implémentation concrète
+type reviewRepository struct {
+	*BaseRepository
+}
+
+// NewReviewRepository crée une nouvelle instance du repository
+func NewReviewRepository(db *sql.DB) ReviewRepository {
+	return &reviewRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Save crée ou remplace la critique d'un thread et ses notes par morceau,
+// et rattache le tag critiqué au thread
+func (r *reviewRepository) Save(review *models.Review) error {
+	return r.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO thread_reviews (thread_id, tag_id, score, created_at, updated_at)
+			VALUES (?, ?, ?, NOW(), NOW())
+			ON DUPLICATE KEY UPDATE tag_id = VALUES(tag_id), score = VALUES(score), updated_at = NOW()
+		`, review.ThreadID, review.TagID, review.Score)
+		if err != nil {
+			return fmt.Errorf("erreur enregistrement critique: %w", err)
+		}
+
+		if _, err := tx.Exec("DELETE FROM thread_review_tracks WHERE thread_id = ?", review.ThreadID); err != nil {
+			return fmt.Errorf("erreur suppression notes des morceaux: %w", err)
+		}
+
+		for i, track := range review.Tracks {
+			track.Position = i
+			_, err := tx.Exec(
+				"INSERT INTO thread_review_tracks (thread_id, position, title, score) VALUES (?, ?, ?, ?)",
+				review.ThreadID, track.Position, track.Title, track.Score,
+			)
+			if err != nil {
+				return fmt.Errorf("erreur enregistrement note du morceau: %w", err)
+			}
+		}
+
+		if _, err := tx.Exec("INSERT IGNORE INTO thread_tags (thread_id, tag_id) VALUES (?, ?)", review.ThreadID, review.TagID); err != nil {
+			return fmt.Errorf("erreur rattachement du tag critiqué: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// FindByThreadID récupère la critique d'un thread avec ses notes par morceau
+func (r *reviewRepository) FindByThreadID(threadID uint) (*models.Review, error) {
+	review := &models.Review{}
+	err := r.DB.QueryRow(`
+		SELECT rv.thread_id, rv.tag_id, tg.name, tg.type, rv.score, rv.created_at, rv.updated_at
+		FROM thread_reviews rv
+		JOIN tags tg ON tg.id = rv.tag_id
+		WHERE rv.thread_id = ?
+	`, threadID).Scan(
+		&review.ThreadID, &review.TagID, &review.TagName, &review.TagType, &review.Score, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, utils.ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("erreur récupération critique: %w", err)
+	}
+
+	rows, err := r.DB.Query(`
+		SELECT position, title, score
+		FROM thread_review_tracks
+		WHERE thread_id = ?
+		ORDER BY position ASC
+	`, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération notes des morceaux: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		track := &models.ReviewTrack{}
+		if err := rows.Scan(&track.Position, &track.Title, &track.Score); err != nil {
+			return nil, fmt.Errorf("erreur scan note du morceau: %w", err)
+		}
+		review.Tracks = append(review.Tracks, track)
+	}
+
+	return review, nil
+}
+
+// Delete supprime la critique d'un thread (notes par morceau en cascade) ; le tag reste attaché
+func (r *reviewRepository) Delete(threadID uint) error {
+	if _, err := r.DB.Exec("DELETE FROM thread_reviews WHERE thread_id = ?", threadID); err != nil {
+		return fmt.Errorf("erreur suppression critique: %w", err)
+	}
+	return nil
+}
+
+// FindByTag liste les critiques d'un tag visibles par viewerID, sans les notes par morceau
+func (r *reviewRepository) FindByTag(tagID uint, viewerID *uint, filter models.ReviewFilter) ([]*models.Review, int64, error) {
+	where, args := reviewFilterClause(tagID, viewerID, filter)
+
+	var total int64
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM thread_reviews rv
+		JOIN threads t ON t.id = rv.thread_id
+		WHERE %s
+	`, where)
+	if err := r.DB.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("erreur comptage critiques: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT rv.thread_id, rv.tag_id, tg.name, tg.type, rv.score, rv.created_at, rv.updated_at,
+		       t.title, u.id, u.username
+		FROM thread_reviews rv
+		JOIN threads t ON t.id = rv.thread_id
+		JOIN tags tg ON tg.id = rv.tag_id
+		JOIN users u ON u.id = t.user_id
+		WHERE %s
+		ORDER BY %s
+		LIMIT ? OFFSET ?
+	`, where, reviewOrderClause(filter.Sort))
+
+	offset := (filter.Page - 1) * filter.PerPage
+	rows, err := r.DB.Query(query, append(args, filter.PerPage, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erreur récupération critiques: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []*models.Review
+	for rows.Next() {
+		review := &models.Review{}
+		err := rows.Scan(
+			&review.ThreadID, &review.TagID, &review.TagName, &review.TagType, &review.Score, &review.CreatedAt, &review.UpdatedAt,
+			&review.ThreadTitle, &review.AuthorID, &review.AuthorName,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("erreur scan critique: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, total, nil
+}
+
+// RatingForTag calcule la note moyenne des critiques d'un tag visibles par viewerID
+func (r *reviewRepository) RatingForTag(tagID uint, viewerID *uint) (*models.TagRating, error) {
+	rating := &models.TagRating{TagID: tagID}
+	query := fmt.Sprintf(`
+		SELECT COALESCE(AVG(rv.score), 0), COUNT(*)
+		FROM thread_reviews rv
+		JOIN threads t ON t.id = rv.thread_id
+		WHERE rv.tag_id = ? AND %s
+	`, threadVisibilityClause(viewerID))
+
+	if err := r.DB.QueryRow(query, tagID).Scan(&rating.Average, &rating.ReviewCount); err != nil {
+		return nil, fmt.Errorf("erreur calcul note moyenne: %w", err)
+	}
+
+	return rating, nil
+}
+
+// reviewFilterClause condition d'une liste de critiques : tag, visibilité du thread et bornes de note
+func reviewFilterClause(tagID uint, viewerID *uint, filter models.ReviewFilter) (string, []interface{}) {
+	where := "rv.tag_id = ? AND " + threadVisibilityClause(viewerID)
+	args := []interface{}{tagID}
+
+	if filter.MinScore != nil {
+		where += " AND rv.score >= ?"
+		args = append(args, *filter.MinScore)
+	}
+	if filter.MaxScore != nil {
+		where += " AND rv.score <= ?"
+		args = append(args, *filter.MaxScore)
+	}
+
+	return where, args
+}
+
+// reviewOrderClause tri SQL d'une liste de critiques (liste blanche, récentes par défaut)
+func reviewOrderClause(sort string) string {
+	switch sort {
+	case models.ReviewSortScoreDesc:
+		return "rv.score DESC, rv.created_at DESC"
+	case models.ReviewSortScoreAsc:
+		return "rv.score ASC, rv.created_at DESC"
+	default:
+		return "rv.created_at DESC"
+	}
+}
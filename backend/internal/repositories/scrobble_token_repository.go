@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+)
+
+// ScrobbleTokenRepository interface pour les jetons personnels des scrobblers
+type ScrobbleTokenRepository interface {
+	Create(token *models.ScrobbleToken, tokenHash string) error
+	FindByHash(tokenHash string) (*models.ScrobbleToken, error)
+	FindByUser(userID uint) ([]*models.ScrobbleToken, error)
+	CountByUser(userID uint) (int, error)
+	Delete(id, userID uint) error
+	TouchLastUsed(id uint) error
+}
+
+// scrobbleTokenRepository implémentation concrète
+type scrobbleTokenRepository struct {
+	*BaseRepository
+}
+
+// NewScrobbleTokenRepository crée une nouvelle instance du repository
+func NewScrobbleTokenRepository(db *sql.DB) ScrobbleTokenRepository {
+	return &scrobbleTokenRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create enregistre un jeton (seule son empreinte est conservée)
+func (r *scrobbleTokenRepository) Create(token *models.ScrobbleToken, tokenHash string) error {
+	result, err := r.DB.Exec(`
+		INSERT INTO scrobble_tokens (user_id, name, token_hash, token_prefix, created_at)
+		VALUES (?, ?, ?, ?, NOW())
+	`, token.UserID, token.Name, tokenHash, token.TokenPrefix)
+	if err != nil {
+		return fmt.Errorf("erreur création du jeton de scrobbling: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID du jeton de scrobbling: %w", err)
+	}
+	token.ID = uint(id)
+
+	return nil
+}
+
+// FindByHash récupère le jeton correspondant à une empreinte, avec le nom de son propriétaire
+func (r *scrobbleTokenRepository) FindByHash(tokenHash string) (*models.ScrobbleToken, error) {
+	token := &models.ScrobbleToken{}
+	err := r.DB.QueryRow(`
+		SELECT st.id, st.user_id, st.name, st.token_prefix, st.last_used_at, st.created_at, u.username
+		FROM scrobble_tokens st
+		JOIN users u ON u.id = st.user_id
+		WHERE st.token_hash = ?
+	`, tokenHash).Scan(&token.ID, &token.UserID, &token.Name, &token.TokenPrefix, &token.LastUsedAt,
+		&token.CreatedAt, &token.Username)
+	if err == sql.ErrNoRows {
+		return nil, utils.ErrScrobbleTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération du jeton de scrobbling: %w", err)
+	}
+	return token, nil
+}
+
+// FindByUser liste les jetons d'un utilisateur, du plus récent au plus ancien
+func (r *scrobbleTokenRepository) FindByUser(userID uint) ([]*models.ScrobbleToken, error) {
+	rows, err := r.DB.Query(`
+		SELECT id, user_id, name, token_prefix, last_used_at, created_at
+		FROM scrobble_tokens
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération des jetons de scrobbling: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []*models.ScrobbleToken{}
+	for rows.Next() {
+		token := &models.ScrobbleToken{}
+		if err := rows.Scan(&token.ID, &token.UserID, &token.Name, &token.TokenPrefix, &token.LastUsedAt, &token.CreatedAt); err != nil {
+			return nil, fmt.Errorf("erreur scan du jeton de scrobbling: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+// CountByUser compte les jetons d'un utilisateur
+func (r *scrobbleTokenRepository) CountByUser(userID uint) (int, error) {
+	var count int
+	if err := r.DB.QueryRow(`SELECT COUNT(*) FROM scrobble_tokens WHERE user_id = ?`, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("erreur comptage des jetons de scrobbling: %w", err)
+	}
+	return count, nil
+}
+
+// Delete révoque un jeton de l'utilisateur
+func (r *scrobbleTokenRepository) Delete(id, userID uint) error {
+	result, err := r.DB.Exec(`DELETE FROM scrobble_tokens WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return fmt.Errorf("erreur révocation du jeton de scrobbling: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return utils.ErrScrobbleTokenNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed note la dernière utilisation d'un jeton
+func (r *scrobbleTokenRepository) TouchLastUsed(id uint) error {
+	if _, err := r.DB.Exec(`UPDATE scrobble_tokens SET last_used_at = NOW() WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("erreur mise à jour du jeton de scrobbling: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"strings"
+)
+
+// ShareRepository interface pour les partages de threads
+type ShareRepository interface {
+	Create(share *models.ThreadShare) error
+	CreateRepost(share *models.ThreadShare) (bool, error)
+	DeleteRepost(threadID, userID uint) (bool, error)
+	CountByThreadIDs(threadIDs []uint) (map[uint]int, error)
+	FindRepostsByUserID(userID uint, viewerID *uint, limit int) ([]*models.ThreadShare, error)
+}
+
+// shareRepository implémentation concrète
+type shareRepository struct {
+	*BaseRepository
+}
+
+// NewShareRepository crée une nouvelle instance du repository
+func NewShareRepository(db *sql.DB) ShareRepository {
+	return &shareRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create enregistre un partage
+func (r *shareRepository) Create(share *models.ThreadShare) error {
+	query := `
+		INSERT INTO thread_shares (thread_id, user_id, kind, comment, recipient_id, created_at)
+		VALUES (?, ?, ?, ?, ?, NOW())
+	`
+
+	result, err := r.DB.Exec(query, share.ThreadID, share.UserID, share.Kind, share.Comment, share.RecipientID)
+	if err != nil {
+		return fmt.Errorf("erreur création partage: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("erreur récupération ID partage: %w", err)
+	}
+
+	share.ID = uint(id)
+	return nil
+}
+
+// CreateRepost enregistre un repost ; retourne false si l'utilisateur a déjà reposté ce thread
+func (r *shareRepository) CreateRepost(share *models.ThreadShare) (bool, error) {
+	query := `
+		INSERT IGNORE INTO thread_shares (thread_id, user_id, kind, comment, created_at)
+		VALUES (?, ?, ?, ?, NOW())
+	`
+
+	result, err := r.DB.Exec(query, share.ThreadID, share.UserID, models.ShareKindRepost, share.Comment)
+	if err != nil {
+		return false, fmt.Errorf("erreur création repost: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification repost: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, fmt.Errorf("erreur récupération ID repost: %w", err)
+	}
+
+	share.ID = uint(id)
+	share.Kind = models.ShareKindRepost
+	return true, nil
+}
+
+// DeleteRepost annule le repost d'un utilisateur ; retourne false s'il n'existait pas
+func (r *shareRepository) DeleteRepost(threadID, userID uint) (bool, error) {
+	result, err := r.DB.Exec(
+		"DELETE FROM thread_shares WHERE thread_id = ? AND user_id = ? AND kind = ?",
+		threadID, userID, models.ShareKindRepost,
+	)
+	if err != nil {
+		return false, fmt.Errorf("erreur suppression repost: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification suppression repost: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// CountByThreadIDs compte les partages (reposts et messages) de chaque thread
+func (r *shareRepository) CountByThreadIDs(threadIDs []uint) (map[uint]int, error) {
+	counts := make(map[uint]int)
+	if len(threadIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(threadIDs)), ", ")
+	query := fmt.Sprintf(`
+		SELECT thread_id, COUNT(*)
+		FROM thread_shares
+		WHERE thread_id IN (%s)
+		GROUP BY thread_id
+	`, placeholders)
+
+	args := make([]interface{}, len(threadIDs))
+	for i, id := range threadIDs {
+		args[i] = id
+	}
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur comptage partages: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var threadID uint
+		var count int
+		if err := rows.Scan(&threadID, &count); err != nil {
+			return nil, fmt.Errorf("erreur scan partages: %w", err)
+		}
+		counts[threadID] = count
+	}
+
+	return counts, nil
+}
+
+// FindRepostsByUserID récupère les reposts d'un utilisateur dont le thread est visible par viewerID
+func (r *shareRepository) FindRepostsByUserID(userID uint, viewerID *uint, limit int) ([]*models.ThreadShare, error) {
+	query := fmt.Sprintf(`
+		SELECT s.id, s.thread_id, s.user_id, s.kind, s.comment, s.created_at,
+		       t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		       u.id, u.username, u.email, u.profile_pic
+		FROM thread_shares s
+		JOIN threads t ON t.id = s.thread_id
+		JOIN users u ON t.user_id = u.id
+		WHERE s.user_id = ? AND s.kind = ? AND %s
+		ORDER BY s.created_at DESC
+		LIMIT ?
+	`, threadVisibilityClause(viewerID))
+
+	rows, err := r.DB.Query(query, userID, models.ShareKindRepost, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération reposts: %w", err)
+	}
+	defer rows.Close()
+
+	var shares []*models.ThreadShare
+	for rows.Next() {
+		share := &models.ThreadShare{Thread: &models.Thread{Author: &models.User{}}}
+		thread := share.Thread
+		err := rows.Scan(
+			&share.ID, &share.ThreadID, &share.UserID, &share.Kind, &share.Comment, &share.CreatedAt,
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan repost: %w", err)
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
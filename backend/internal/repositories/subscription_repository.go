@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SubscriptionRepository interface pour les abonnements aux threads et le suivi de lecture
+type SubscriptionRepository interface {
+	Subscribe(userID, threadID uint) error
+	Unsubscribe(userID, threadID uint) error
+	AutoSubscribe(userID, threadID uint) error
+	IsSubscribed(userID, threadID uint) (bool, error)
+	FindSubscribers(threadID uint) ([]uint, error)
+	MarkRead(userID, threadID, lastMessageID uint) error
+	GetLastRead(userID, threadID uint) (uint, error)
+	CountUnread(userID uint, threadIDs []uint) (map[uint]int, error)
+}
+
+// subscriptionRepository implémentation concrète
+type subscriptionRepository struct {
+	*BaseRepository
+}
+
+// NewSubscriptionRepository crée une nouvelle instance du repository
+func NewSubscriptionRepository(db *sql.DB) SubscriptionRepository {
+	return &subscriptionRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Subscribe abonne explicitement un utilisateur à un thread
+func (r *subscriptionRepository) Subscribe(userID, threadID uint) error {
+	query := `
+		INSERT INTO thread_subscriptions (user_id, thread_id, subscribed, created_at, updated_at)
+		VALUES (?, ?, TRUE, NOW(), NOW())
+		ON DUPLICATE KEY UPDATE subscribed = TRUE, updated_at = NOW()
+	`
+	if _, err := r.DB.Exec(query, userID, threadID); err != nil {
+		return fmt.Errorf("erreur abonnement thread: %w", err)
+	}
+	return nil
+}
+
+// Unsubscribe désabonne un utilisateur ; le désabonnement est conservé pour bloquer l'abonnement automatique
+func (r *subscriptionRepository) Unsubscribe(userID, threadID uint) error {
+	query := `
+		INSERT INTO thread_subscriptions (user_id, thread_id, subscribed, created_at, updated_at)
+		VALUES (?, ?, FALSE, NOW(), NOW())
+		ON DUPLICATE KEY UPDATE subscribed = FALSE, updated_at = NOW()
+	`
+	if _, err := r.DB.Exec(query, userID, threadID); err != nil {
+		return fmt.Errorf("erreur désabonnement thread: %w", err)
+	}
+	return nil
+}
+
+// AutoSubscribe abonne un auteur ou un commentateur, sauf s'il s'est désabonné auparavant
+func (r *subscriptionRepository) AutoSubscribe(userID, threadID uint) error {
+	query := "INSERT IGNORE INTO thread_subscriptions (user_id, thread_id, subscribed, created_at, updated_at) VALUES (?, ?, TRUE, NOW(), NOW())"
+	if _, err := r.DB.Exec(query, userID, threadID); err != nil {
+		return fmt.Errorf("erreur abonnement automatique: %w", err)
+	}
+	return nil
+}
+
+// IsSubscribed vérifie si un utilisateur suit un thread
+func (r *subscriptionRepository) IsSubscribed(userID, threadID uint) (bool, error) {
+	var subscribed bool
+	err := r.DB.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM thread_subscriptions WHERE user_id = ? AND thread_id = ? AND subscribed = TRUE)",
+		userID, threadID,
+	).Scan(&subscribed)
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification abonnement: %w", err)
+	}
+	return subscribed, nil
+}
+
+// FindSubscribers récupère les IDs des abonnés d'un thread
+func (r *subscriptionRepository) FindSubscribers(threadID uint) ([]uint, error) {
+	rows, err := r.DB.Query("SELECT user_id FROM thread_subscriptions WHERE thread_id = ? AND subscribed = TRUE", threadID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération abonnés: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []uint
+	for rows.Next() {
+		var userID uint
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("erreur scan abonné: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, nil
+}
+
+// MarkRead enregistre la position de lecture ; elle ne recule jamais
+func (r *subscriptionRepository) MarkRead(userID, threadID, lastMessageID uint) error {
+	query := `
+		INSERT INTO thread_reads (user_id, thread_id, last_read_message_id, read_at)
+		VALUES (?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE last_read_message_id = GREATEST(last_read_message_id, VALUES(last_read_message_id)), read_at = NOW()
+	`
+	if _, err := r.DB.Exec(query, userID, threadID, lastMessageID); err != nil {
+		return fmt.Errorf("erreur mise à jour lecture: %w", err)
+	}
+	return nil
+}
+
+// GetLastRead récupère l'ID du dernier message lu (0 si jamais lu)
+func (r *subscriptionRepository) GetLastRead(userID, threadID uint) (uint, error) {
+	var lastRead uint
+	err := r.DB.QueryRow(
+		"SELECT last_read_message_id FROM thread_reads WHERE user_id = ? AND thread_id = ?",
+		userID, threadID,
+	).Scan(&lastRead)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("erreur récupération lecture: %w", err)
+	}
+	return lastRead, nil
+}
+
+// CountUnread compte les commentaires non lus des autres utilisateurs, uniquement
+// pour les threads suivis ou déjà consultés (un thread jamais ouvert n'a pas de non-lus)
+func (r *subscriptionRepository) CountUnread(userID uint, threadIDs []uint) (map[uint]int, error) {
+	counts := make(map[uint]int)
+	if len(threadIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(threadIDs)), ", ")
+	query := fmt.Sprintf(`
+		SELECT m.thread_id, COUNT(*)
+		FROM messages m
+		LEFT JOIN thread_reads tr ON tr.thread_id = m.thread_id AND tr.user_id = ?
+		WHERE m.thread_id IN (%s)
+		AND m.user_id != ?
+		AND m.id > COALESCE(tr.last_read_message_id, 0)
+		AND (tr.user_id IS NOT NULL OR EXISTS (
+			SELECT 1 FROM thread_subscriptions s
+			WHERE s.thread_id = m.thread_id AND s.user_id = ? AND s.subscribed = TRUE
+		))
+		GROUP BY m.thread_id
+	`, placeholders)
+
+	args := []interface{}{userID}
+	for _, id := range threadIDs {
+		args = append(args, id)
+	}
+	args = append(args, userID, userID)
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur comptage non lus: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var threadID uint
+		var count int
+		if err := rows.Scan(&threadID, &count); err != nil {
+			return nil, fmt.Errorf("erreur scan non lus: %w", err)
+		}
+		counts[threadID] = count
+	}
+
+	return counts, nil
+}
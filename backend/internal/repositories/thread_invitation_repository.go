@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"rythmitbackend/internal/models"
+)
+
+// ThreadInvitationRepository interface pour les invitations aux threads privés
+type ThreadInvitationRepository interface {
+	Invite(threadID, userID, invitedBy uint) (*models.ThreadInvitation, error)
+	Revoke(threadID, userID uint) error
+	IsInvited(threadID, userID uint) (bool, error)
+	FindByThreadID(threadID uint) ([]*models.ThreadInvitation, error)
+}
+
+// threadInvitationRepository implémentation concrète
+type threadInvitationRepository struct {
+	*BaseRepository
+}
+
+// NewThreadInvitationRepository crée une nouvelle instance du repository
+func NewThreadInvitationRepository(db *sql.DB) ThreadInvitationRepository {
+	return &threadInvitationRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Invite ajoute un utilisateur aux invités d'un thread (idempotent)
+func (r *threadInvitationRepository) Invite(threadID, userID, invitedBy uint) (*models.ThreadInvitation, error) {
+	query := `
+		INSERT INTO thread_invitations (thread_id, user_id, invited_by, created_at)
+		VALUES (?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE invited_by = VALUES(invited_by)
+	`
+
+	if _, err := r.DB.Exec(query, threadID, userID, invitedBy); err != nil {
+		return nil, fmt.Errorf("erreur création invitation: %w", err)
+	}
+
+	invitation := &models.ThreadInvitation{}
+	err := r.DB.QueryRow(`
+		SELECT id, thread_id, user_id, invited_by, created_at
+		FROM thread_invitations
+		WHERE thread_id = ? AND user_id = ?
+	`, threadID, userID).Scan(&invitation.ID, &invitation.ThreadID, &invitation.UserID, &invitation.InvitedBy, &invitation.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération invitation: %w", err)
+	}
+
+	return invitation, nil
+}
+
+// Revoke retire l'invitation d'un utilisateur
+func (r *threadInvitationRepository) Revoke(threadID, userID uint) error {
+	result, err := r.DB.Exec("DELETE FROM thread_invitations WHERE thread_id = ? AND user_id = ?", threadID, userID)
+	if err != nil {
+		return fmt.Errorf("erreur révocation invitation: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erreur vérification révocation: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("aucune invitation pour l'utilisateur %d sur le thread %d", userID, threadID)
+	}
+
+	return nil
+}
+
+// IsInvited vérifie si un utilisateur est invité sur un thread
+func (r *threadInvitationRepository) IsInvited(threadID, userID uint) (bool, error) {
+	return r.Exists("SELECT EXISTS(SELECT 1 FROM thread_invitations WHERE thread_id = ? AND user_id = ?)", threadID, userID)
+}
+
+// FindByThreadID liste les invités d'un thread avec leurs infos utilisateur
+func (r *threadInvitationRepository) FindByThreadID(threadID uint) ([]*models.ThreadInvitation, error) {
+	query := `
+		SELECT ti.id, ti.thread_id, ti.user_id, ti.invited_by, ti.created_at,
+		       u.id, u.username, u.profile_pic
+		FROM thread_invitations ti
+		JOIN users u ON ti.user_id = u.id
+		WHERE ti.thread_id = ?
+		ORDER BY ti.created_at ASC
+	`
+
+	rows, err := r.DB.Query(query, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération invitations: %w", err)
+	}
+	defer rows.Close()
+
+	var invitations []*models.ThreadInvitation
+	for rows.Next() {
+		invitation := &models.ThreadInvitation{User: &models.User{}}
+		err := rows.Scan(
+			&invitation.ID, &invitation.ThreadID, &invitation.UserID, &invitation.InvitedBy, &invitation.CreatedAt,
+			&invitation.User.ID, &invitation.User.Username, &invitation.User.ProfilePic,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan invitation: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, nil
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"rythmitbackend/internal/models"
+	"rythmitbackend/pkg/markdown"
+	"time"
 )
 
 // ThreadRepository interface pour les opérations CRUD sur les threads
@@ -16,6 +18,7 @@ type ThreadRepository interface {
 	Update(thread *models.Thread) error
 	Delete(id uint) error
 	UpdateState(id uint, state string) error
+	ArchiveInactive(cutoff time.Time) (int64, error)
 	AttachTags(threadID uint, tagIDs []uint) error
 	DetachTags(threadID uint) error
 	GetThreadTags(threadID uint) ([]*models.Tag, error)
@@ -24,11 +27,23 @@ type ThreadRepository interface {
 	SearchWithTags(query string, tags []string, params models.PaginationParams) ([]*models.Thread, int64, error)
 	FindByTags(tags []string, params models.PaginationParams) ([]*models.Thread, int64, error)
 	Transaction(fn func(*sql.Tx) error) error
+	WithViewer(viewerID *uint) ThreadRepository
+	CanView(threadID, userID uint) (bool, error)
+	FindDueScheduled(now time.Time) ([]*models.Thread, error)
+	Publish(id uint) (bool, error)
+	Pin(pin *models.ThreadPin) error
+	Unpin(threadID uint, tagID *uint) (bool, error)
+	SetAnnouncement(id uint, announcement bool) error
+	FindAnnouncements(limit int) ([]*models.Thread, error)
+	FindRecent(since time.Time, limit int) ([]*models.Thread, error)
+	Merge(sourceID, targetID uint) error
+	IncrementViewCounts(counts map[uint]int) error
 }
 
 // threadRepository implémentation concrète
 type threadRepository struct {
 	*BaseRepository
+	viewerID *uint // utilisateur pour qui les listes sont filtrées (nil = anonyme)
 }
 
 // NewThreadRepository crée une nouvelle instance du repository
@@ -38,14 +53,90 @@ func NewThreadRepository(db *sql.DB) ThreadRepository {
 	}
 }
 
+// WithViewer retourne une copie du repository dont les listes incluent
+// les threads privés accessibles à viewerID
+func (r *threadRepository) WithViewer(viewerID *uint) ThreadRepository {
+	return &threadRepository{
+		BaseRepository: r.BaseRepository,
+		viewerID:       viewerID,
+	}
+}
+
+// visibilityClause construit la condition SQL de visibilité sur l'alias t
+// L'ID est un entier non signé, il peut être injecté sans risque dans la requête
+// Les threads programmés (publish_at non NULL) n'apparaissent dans aucune liste
+func (r *threadRepository) visibilityClause() string {
+	return threadVisibilityClause(r.viewerID)
+}
+
+// threadVisibilityClause condition de visibilité d'un thread (alias t) dans une liste pour viewerID
+func threadVisibilityClause(viewerID *uint) string {
+	if viewerID == nil || *viewerID == 0 {
+		return "t.publish_at IS NULL AND t.visibility = 'public'"
+	}
+	return "t.publish_at IS NULL AND " + threadAccessClause(*viewerID)
+}
+
+// threadAccessClause condition d'accès d'un utilisateur : public, auteur, invité ou ami (selon l'accès)
+func threadAccessClause(userID uint) string {
+	return fmt.Sprintf(`(t.visibility = 'public'
+		OR t.user_id = %[1]d
+		OR EXISTS (SELECT 1 FROM thread_invitations ti WHERE ti.thread_id = t.id AND ti.user_id = %[1]d)
+		OR (t.access = '%[2]s' AND EXISTS (
+			SELECT 1 FROM friendships f
+			WHERE f.status = 'accepted'
+			AND ((f.requester_id = t.user_id AND f.addressee_id = %[1]d) OR (f.addressee_id = t.user_id AND f.requester_id = %[1]d))
+		)))`, userID, models.ThreadAccessFriends)
+}
+
+// pinnedClause expression SQL vraie si le thread t a un épinglage actif sur le fil global (tagID nil) ou sur un tag
+func pinnedClause(tagID *uint) string {
+	scope := "p.tag_id IS NULL"
+	if tagID != nil {
+		scope = fmt.Sprintf("p.tag_id = %d", *tagID)
+	}
+	return fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM thread_pins p
+			WHERE p.thread_id = t.id AND %s AND (p.expires_at IS NULL OR p.expires_at > NOW())
+		)`, scope)
+}
+
+// threadOrderClause ordre SQL d'une liste de threads (alias t) selon le tri demandé
+// Le tri est comparé à une liste blanche, il n'est jamais injecté tel quel
+func threadOrderClause(sort string) string {
+	switch sort {
+	case models.ThreadSortViews:
+		return "t.view_count DESC, t.created_at DESC"
+	default:
+		return "t.created_at DESC"
+	}
+}
+
+// CanView vérifie si un utilisateur peut consulter un thread (public, auteur, invité ou ami)
+func (r *threadRepository) CanView(threadID, userID uint) (bool, error) {
+	query := "SELECT COUNT(*) FROM threads t WHERE t.id = ? AND " + threadAccessClause(userID)
+
+	var count int
+	if err := r.DB.QueryRow(query, threadID).Scan(&count); err != nil {
+		return false, fmt.Errorf("erreur vérification accès thread: %w", err)
+	}
+
+	return count > 0, nil
+}
+
 // Create crée un nouveau thread
 func (r *threadRepository) Create(thread *models.Thread) error {
 	query := `
-		INSERT INTO threads (title, desc_, image_url, state, visibility, user_id, created_at, updated_at) 
-		VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())
+		INSERT INTO threads (title, desc_, desc_html, image_url, state, visibility, access, publish_at, user_id, created_at, updated_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
 	`
 
-	result, err := r.DB.Exec(query, thread.Title, thread.Description, thread.ImageURL, thread.State, thread.Visibility, thread.UserID)
+	if thread.Access == "" {
+		thread.Access = models.ThreadAccessInvitees
+	}
+	thread.DescriptionHTML = markdown.Render(thread.Description)
+
+	result, err := r.DB.Exec(query, thread.Title, thread.Description, thread.DescriptionHTML, thread.ImageURL, thread.State, thread.Visibility, thread.Access, thread.PublishAt, thread.UserID)
 	if err != nil {
 		return fmt.Errorf("erreur création thread: %w", err)
 	}
@@ -62,7 +153,7 @@ func (r *threadRepository) Create(thread *models.Thread) error {
 // FindByID trouve un thread par son ID avec l'auteur
 func (r *threadRepository) FindByID(id uint) (*models.Thread, error) {
 	query := `
-		SELECT t.id, t.title, t.desc_, t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		SELECT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.access, t.publish_at, t.is_announcement, t.merged_into_id, t.view_count, t.user_id, t.created_at, t.updated_at,
 		       u.id, u.username, u.email, u.profile_pic
 		FROM threads t
 		JOIN users u ON t.user_id = u.id
@@ -71,7 +162,7 @@ func (r *threadRepository) FindByID(id uint) (*models.Thread, error) {
 
 	thread := &models.Thread{Author: &models.User{}}
 	err := r.DB.QueryRow(query, id).Scan(
-		&thread.ID, &thread.Title, &thread.Description, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+		&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.Access, &thread.PublishAt, &thread.IsAnnouncement, &thread.MergedIntoID, &thread.ViewCount, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
 		&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
 	)
 
@@ -82,6 +173,11 @@ func (r *threadRepository) FindByID(id uint) (*models.Thread, error) {
 		return nil, fmt.Errorf("erreur récupération thread: %w", err)
 	}
 
+	// Thread antérieur au cache de rendu
+	if thread.DescriptionHTML == "" {
+		thread.DescriptionHTML = markdown.Render(thread.Description)
+	}
+
 	// Charger les tags du thread
 	tags, err := r.GetThreadTags(thread.ID)
 	if err != nil {
@@ -98,24 +194,25 @@ func (r *threadRepository) FindPublicThreads(params models.PaginationParams) ([]
 	models.ValidatePagination(&params)
 
 	// Compter le total
-	countQuery := "SELECT COUNT(*) FROM threads WHERE visibility = 'public' AND state != 'archivé'"
+	countQuery := "SELECT COUNT(*) FROM threads t WHERE " + r.visibilityClause() + " AND t.state != 'archivé'"
 	var total int64
 	err := r.DB.QueryRow(countQuery).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("erreur comptage threads: %w", err)
 	}
 
-	// Récupérer les threads avec l'auteur
+	// Récupérer les threads avec l'auteur, les threads épinglés en premier
 	offset := (params.Page - 1) * params.PerPage
-	query := `
-		SELECT t.id, t.title, t.desc_, t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+	query := fmt.Sprintf(`
+		SELECT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.is_announcement, t.user_id, t.view_count, t.created_at, t.updated_at,
+		       %s AS is_pinned,
 		       u.id, u.username, u.email, u.profile_pic
 		FROM threads t
 		JOIN users u ON t.user_id = u.id
-		WHERE t.visibility = 'public' AND t.state != 'archivé'
-		ORDER BY t.created_at DESC
+		WHERE %s AND t.state != 'archivé'
+		ORDER BY is_pinned DESC, %s
 		LIMIT ? OFFSET ?
-	`
+	`, pinnedClause(nil), r.visibilityClause(), threadOrderClause(params.Sort))
 
 	rows, err := r.DB.Query(query, params.PerPage, offset)
 	if err != nil {
@@ -127,7 +224,8 @@ func (r *threadRepository) FindPublicThreads(params models.PaginationParams) ([]
 	for rows.Next() {
 		thread := &models.Thread{Author: &models.User{}}
 		err := rows.Scan(
-			&thread.ID, &thread.Title, &thread.Description, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.IsAnnouncement, &thread.UserID, &thread.ViewCount, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.IsPinned,
 			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
 		)
 		if err != nil {
@@ -162,7 +260,7 @@ func (r *threadRepository) FindAll(params models.PaginationParams) ([]*models.Th
 	// Récupérer les threads
 	offset := (params.Page - 1) * params.PerPage
 	query := `
-		SELECT t.id, t.title, t.desc_, t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		SELECT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.publish_at, t.view_count, t.user_id, t.created_at, t.updated_at,
 		       u.id, u.username, u.email, u.profile_pic
 		FROM threads t
 		JOIN users u ON t.user_id = u.id
@@ -180,7 +278,7 @@ func (r *threadRepository) FindAll(params models.PaginationParams) ([]*models.Th
 	for rows.Next() {
 		thread := &models.Thread{Author: &models.User{}}
 		err := rows.Scan(
-			&thread.ID, &thread.Title, &thread.Description, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.PublishAt, &thread.ViewCount, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
 			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
 		)
 		if err != nil {
@@ -198,7 +296,7 @@ func (r *threadRepository) FindAll(params models.PaginationParams) ([]*models.Th
 // FindByUserID trouve les threads d'un utilisateur
 func (r *threadRepository) FindByUserID(userID uint) ([]*models.Thread, error) {
 	query := `
-		SELECT t.id, t.title, t.desc_, t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		SELECT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.publish_at, t.user_id, t.created_at, t.updated_at,
 		       u.id, u.username, u.email, u.profile_pic
 		FROM threads t
 		JOIN users u ON t.user_id = u.id
@@ -216,7 +314,7 @@ func (r *threadRepository) FindByUserID(userID uint) ([]*models.Thread, error) {
 	for rows.Next() {
 		thread := &models.Thread{Author: &models.User{}}
 		err := rows.Scan(
-			&thread.ID, &thread.Title, &thread.Description, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.PublishAt, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
 			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
 		)
 		if err != nil {
@@ -231,15 +329,20 @@ func (r *threadRepository) FindByUserID(userID uint) ([]*models.Thread, error) {
 	return threads, nil
 }
 
-// Update met à jour un thread
+// Update met à jour un thread ; la révision de la description n'avance que si son texte change
 func (r *threadRepository) Update(thread *models.Thread) error {
 	query := `
 		UPDATE threads 
-		SET title = ?, desc_ = ?, image_url = ?, state = ?, visibility = ?, updated_at = NOW()
+		SET title = ?, desc_revision = desc_revision + (BINARY desc_ <> BINARY ?), desc_ = ?, desc_html = ?, image_url = ?, state = ?, visibility = ?, access = ?, updated_at = NOW()
 		WHERE id = ?
 	`
 
-	_, err := r.DB.Exec(query, thread.Title, thread.Description, thread.ImageURL, thread.State, thread.Visibility, thread.ID)
+	if thread.Access == "" {
+		thread.Access = models.ThreadAccessInvitees
+	}
+	thread.DescriptionHTML = markdown.Render(thread.Description)
+
+	_, err := r.DB.Exec(query, thread.Title, thread.Description, thread.Description, thread.DescriptionHTML, thread.ImageURL, thread.State, thread.Visibility, thread.Access, thread.ID)
 	if err != nil {
 		return fmt.Errorf("erreur mise à jour thread: %w", err)
 	}
@@ -290,6 +393,288 @@ func (r *threadRepository) UpdateState(id uint, state string) error {
 	return nil
 }
 
+// ArchiveInactive archive les threads dont la dernière activité (édition ou message) précède cutoff
+func (r *threadRepository) ArchiveInactive(cutoff time.Time) (int64, error) {
+	query := `
+		UPDATE threads t
+		SET t.state = 'archivé', t.updated_at = NOW()
+		WHERE t.state != 'archivé'
+		AND t.publish_at IS NULL
+		AND t.updated_at < ?
+		AND NOT EXISTS (
+			SELECT 1 FROM messages m
+			WHERE m.thread_id = t.id AND m.created_at >= ?
+		)`
+
+	result, err := r.DB.Exec(query, cutoff, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("erreur archivage threads inactifs: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("erreur vérification archivage: %w", err)
+	}
+
+	return affected, nil
+}
+
+// FindDueScheduled récupère les threads programmés dont l'heure de publication est passée
+func (r *threadRepository) FindDueScheduled(now time.Time) ([]*models.Thread, error) {
+	query := `
+		SELECT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		       u.id, u.username, u.email, u.profile_pic
+		FROM threads t
+		JOIN users u ON t.user_id = u.id
+		WHERE t.publish_at IS NOT NULL AND t.publish_at <= ?
+		ORDER BY t.publish_at ASC
+	`
+
+	rows, err := r.DB.Query(query, now)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération threads programmés: %w", err)
+	}
+	defer rows.Close()
+
+	var threads []*models.Thread
+	for rows.Next() {
+		thread := &models.Thread{Author: &models.User{}}
+		err := rows.Scan(
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan thread programmé: %w", err)
+		}
+		threads = append(threads, thread)
+	}
+
+	return threads, nil
+}
+
+// Publish rend un thread programmé visible ; retourne false s'il était déjà publié
+func (r *threadRepository) Publish(id uint) (bool, error) {
+	query := "UPDATE threads SET publish_at = NULL, created_at = NOW(), updated_at = NOW() WHERE id = ? AND publish_at IS NOT NULL"
+
+	result, err := r.DB.Exec(query, id)
+	if err != nil {
+		return false, fmt.Errorf("erreur publication thread: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification publication: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// Pin épingle un thread ; un épinglage existant sur le même périmètre est remplacé
+func (r *threadRepository) Pin(pin *models.ThreadPin) error {
+	return r.Transaction(func(tx *sql.Tx) error {
+		// <=> compare aussi les NULL (épinglage global)
+		if _, err := tx.Exec("DELETE FROM thread_pins WHERE thread_id = ? AND tag_id <=> ?", pin.ThreadID, pin.TagID); err != nil {
+			return fmt.Errorf("erreur suppression ancien épinglage: %w", err)
+		}
+
+		result, err := tx.Exec(
+			"INSERT INTO thread_pins (thread_id, tag_id, pinned_by, expires_at, created_at) VALUES (?, ?, ?, ?, NOW())",
+			pin.ThreadID, pin.TagID, pin.PinnedBy, pin.ExpiresAt,
+		)
+		if err != nil {
+			return fmt.Errorf("erreur épinglage thread: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("erreur récupération ID épinglage: %w", err)
+		}
+
+		pin.ID = uint(id)
+		pin.CreatedAt = time.Now()
+		return nil
+	})
+}
+
+// Unpin retire l'épinglage d'un thread sur un périmètre ; retourne false s'il n'était pas épinglé
+func (r *threadRepository) Unpin(threadID uint, tagID *uint) (bool, error) {
+	result, err := r.DB.Exec("DELETE FROM thread_pins WHERE thread_id = ? AND tag_id <=> ?", threadID, tagID)
+	if err != nil {
+		return false, fmt.Errorf("erreur désépinglage thread: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("erreur vérification désépinglage: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// SetAnnouncement active ou désactive le statut d'annonce d'un thread
+func (r *threadRepository) SetAnnouncement(id uint, announcement bool) error {
+	result, err := r.DB.Exec("UPDATE threads SET is_announcement = ? WHERE id = ?", announcement, id)
+	if err != nil {
+		return fmt.Errorf("erreur mise à jour annonce: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erreur vérification annonce: %w", err)
+	}
+
+	if affected == 0 {
+		// MySQL ne compte pas les lignes inchangées : vérifier l'existence
+		var exists bool
+		if err := r.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM threads WHERE id = ?)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("erreur vérification thread: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("thread ID %d non trouvé pour annonce", id)
+		}
+	}
+
+	return nil
+}
+
+// FindAnnouncements récupère les annonces visibles, les plus récentes en premier
+func (r *threadRepository) FindAnnouncements(limit int) ([]*models.Thread, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.is_announcement, t.user_id, t.created_at, t.updated_at,
+		       u.id, u.username, u.email, u.profile_pic
+		FROM threads t
+		JOIN users u ON t.user_id = u.id
+		WHERE t.is_announcement = TRUE AND %s AND t.state != 'archivé'
+		ORDER BY t.created_at DESC
+		LIMIT ?
+	`, r.visibilityClause())
+
+	rows, err := r.DB.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération annonces: %w", err)
+	}
+	defer rows.Close()
+
+	var threads []*models.Thread
+	for rows.Next() {
+		thread := &models.Thread{Author: &models.User{}}
+		err := rows.Scan(
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.IsAnnouncement, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan annonce: %w", err)
+		}
+		threads = append(threads, thread)
+	}
+
+	return threads, nil
+}
+
+// FindRecent récupère les threads publiés depuis since et visibles, avec leurs tags
+func (r *threadRepository) FindRecent(since time.Time, limit int) ([]*models.Thread, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		       u.id, u.username, u.email, u.profile_pic
+		FROM threads t
+		JOIN users u ON t.user_id = u.id
+		WHERE t.created_at >= ? AND %s AND t.state != 'archivé'
+		ORDER BY t.created_at DESC
+		LIMIT ?
+	`, r.visibilityClause())
+
+	rows, err := r.DB.Query(query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération threads récents: %w", err)
+	}
+	defer rows.Close()
+
+	var threads []*models.Thread
+	for rows.Next() {
+		thread := &models.Thread{Author: &models.User{}}
+		err := rows.Scan(
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur scan thread récent: %w", err)
+		}
+
+		tags, err := r.GetThreadTags(thread.ID)
+		if err != nil {
+			tags = []*models.Tag{}
+		}
+		thread.Tags = tags
+
+		threads = append(threads, thread)
+	}
+
+	return threads, nil
+}
+
+// Merge déplace les commentaires et likes de sourceID vers targetID,
+// puis archive sourceID en le marquant comme fusionné
+func (r *threadRepository) Merge(sourceID, targetID uint) error {
+	return r.Transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("UPDATE messages SET thread_id = ? WHERE thread_id = ?", targetID, sourceID); err != nil {
+			return fmt.Errorf("erreur déplacement commentaires: %w", err)
+		}
+
+		// Les mentions des commentaires déplacés pointent vers le nouveau thread
+		if _, err := tx.Exec("UPDATE mentions SET thread_id = ? WHERE source_type = ? AND thread_id = ?", targetID, models.MentionSourceComment, sourceID); err != nil {
+			return fmt.Errorf("erreur déplacement mentions: %w", err)
+		}
+
+		// Un utilisateur ayant liké les deux threads ne compte qu'une fois
+		if _, err := tx.Exec(`
+			INSERT IGNORE INTO thread_likes (user_id, thread_id, created_at)
+			SELECT user_id, ?, created_at FROM thread_likes WHERE thread_id = ?
+		`, targetID, sourceID); err != nil {
+			return fmt.Errorf("erreur déplacement likes: %w", err)
+		}
+		if _, err := tx.Exec("DELETE FROM thread_likes WHERE thread_id = ?", sourceID); err != nil {
+			return fmt.Errorf("erreur suppression likes: %w", err)
+		}
+		if _, err := tx.Exec(`
+			UPDATE threads SET likes_count = (SELECT COUNT(*) FROM thread_likes WHERE thread_id = ?) WHERE id = ?
+		`, targetID, targetID); err != nil {
+			return fmt.Errorf("erreur recalcul likes: %w", err)
+		}
+
+		if _, err := tx.Exec(`
+			UPDATE threads SET state = ?, likes_count = 0, merged_into_id = ?, is_announcement = FALSE, updated_at = NOW()
+			WHERE id = ?
+		`, models.ThreadStateArchived, targetID, sourceID); err != nil {
+			return fmt.Errorf("erreur archivage thread fusionné: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// IncrementViewCounts ajoute en une transaction les vues accumulées de plusieurs threads
+func (r *threadRepository) IncrementViewCounts(counts map[uint]int) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	return r.Transaction(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare("UPDATE threads SET view_count = view_count + ? WHERE id = ?")
+		if err != nil {
+			return fmt.Errorf("erreur préparation mise à jour des vues: %w", err)
+		}
+		defer stmt.Close()
+
+		for threadID, views := range counts {
+			if _, err := stmt.Exec(views, threadID); err != nil {
+				return fmt.Errorf("erreur mise à jour des vues du thread %d: %w", threadID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
 // AttachTags attache des tags à un thread
 func (r *threadRepository) AttachTags(threadID uint, tagIDs []uint) error {
 	if len(tagIDs) == 0 {
@@ -361,30 +746,31 @@ func (r *threadRepository) FindByTag(tagID uint, params models.PaginationParams)
 	models.ValidatePagination(&params)
 
 	// Compter le total
-	countQuery := `
+	countQuery := fmt.Sprintf(`
 		SELECT COUNT(DISTINCT t.id)
 		FROM threads t
 		JOIN thread_tags tt ON t.id = tt.thread_id
-		WHERE tt.tag_id = ? AND t.visibility = 'public' AND t.state != 'archivé'
-	`
+		WHERE tt.tag_id = ? AND %s AND t.state != 'archivé'
+	`, r.visibilityClause())
 	var total int64
 	err := r.DB.QueryRow(countQuery, tagID).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("erreur comptage threads par tag: %w", err)
 	}
 
-	// Récupérer les threads
+	// Récupérer les threads, ceux épinglés sur ce tag en premier
 	offset := (params.Page - 1) * params.PerPage
-	query := `
-		SELECT DISTINCT t.id, t.title, t.desc_, t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+	query := fmt.Sprintf(`
+		SELECT DISTINCT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.is_announcement, t.user_id, t.view_count, t.created_at, t.updated_at,
+		       %s AS is_pinned,
 		       u.id, u.username, u.email, u.profile_pic
 		FROM threads t
 		JOIN thread_tags tt ON t.id = tt.thread_id
 		JOIN users u ON t.user_id = u.id
-		WHERE tt.tag_id = ? AND t.visibility = 'public' AND t.state != 'archivé'
-		ORDER BY t.created_at DESC
+		WHERE tt.tag_id = ? AND %s AND t.state != 'archivé'
+		ORDER BY is_pinned DESC, %s
 		LIMIT ? OFFSET ?
-	`
+	`, pinnedClause(&tagID), r.visibilityClause(), threadOrderClause(params.Sort))
 
 	rows, err := r.DB.Query(query, tagID, params.PerPage, offset)
 	if err != nil {
@@ -396,7 +782,8 @@ func (r *threadRepository) FindByTag(tagID uint, params models.PaginationParams)
 	for rows.Next() {
 		thread := &models.Thread{Author: &models.User{}}
 		err := rows.Scan(
-			&thread.ID, &thread.Title, &thread.Description, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.IsAnnouncement, &thread.UserID, &thread.ViewCount, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.IsPinned,
 			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
 		)
 		if err != nil {
@@ -418,11 +805,11 @@ func (r *threadRepository) Search(query string, params models.PaginationParams)
 	searchTerm := "%" + query + "%"
 
 	// Compter le total - recherche dans titre ET description
-	countQuery := `
+	countQuery := fmt.Sprintf(`
 		SELECT COUNT(*)
 		FROM threads t
-		WHERE (t.title LIKE ? OR t.desc_ LIKE ?) AND t.visibility = 'public' AND t.state != 'archivé'
-	`
+		WHERE (t.title LIKE ? OR t.desc_ LIKE ?) AND %s AND t.state != 'archivé'
+	`, r.visibilityClause())
 	var total int64
 	err := r.DB.QueryRow(countQuery, searchTerm, searchTerm).Scan(&total)
 	if err != nil {
@@ -431,15 +818,15 @@ func (r *threadRepository) Search(query string, params models.PaginationParams)
 
 	// Récupérer les threads
 	offset := (params.Page - 1) * params.PerPage
-	searchQuery := `
-		SELECT t.id, t.title, t.desc_, t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+	searchQuery := fmt.Sprintf(`
+		SELECT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.user_id, t.view_count, t.created_at, t.updated_at,
 		       u.id, u.username, u.email, u.profile_pic
 		FROM threads t
 		JOIN users u ON t.user_id = u.id
-		WHERE (t.title LIKE ? OR t.desc_ LIKE ?) AND t.visibility = 'public' AND t.state != 'archivé'
-		ORDER BY t.created_at DESC
+		WHERE (t.title LIKE ? OR t.desc_ LIKE ?) AND %s AND t.state != 'archivé'
+		ORDER BY %s
 		LIMIT ? OFFSET ?
-	`
+	`, r.visibilityClause(), threadOrderClause(params.Sort))
 
 	rows, err := r.DB.Query(searchQuery, searchTerm, searchTerm, params.PerPage, offset)
 	if err != nil {
@@ -451,7 +838,7 @@ func (r *threadRepository) Search(query string, params models.PaginationParams)
 	for rows.Next() {
 		thread := &models.Thread{Author: &models.User{}}
 		err := rows.Scan(
-			&thread.ID, &thread.Title, &thread.Description, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.ViewCount, &thread.CreatedAt, &thread.UpdatedAt,
 			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
 		)
 		if err != nil {
@@ -494,12 +881,12 @@ func (r *threadRepository) SearchWithTags(query string, tags []string, params mo
 		JOIN thread_tags tt ON t.id = tt.thread_id
 		JOIN tags tag ON tt.tag_id = tag.id
 		WHERE (t.title LIKE ? OR t.desc_ LIKE ?) 
-		  AND t.visibility = 'public' 
+		  AND %s
 		  AND t.state != 'archivé'
 		  AND tag.name IN (%s)
 		GROUP BY t.id
 		HAVING COUNT(DISTINCT tag.name) = ?
-	`, tagPlaceholders)
+	`, r.visibilityClause(), tagPlaceholders)
 
 	// Préparer les arguments pour la requête de comptage
 	countArgs := []interface{}{searchTerm, searchTerm}
@@ -523,22 +910,22 @@ func (r *threadRepository) SearchWithTags(query string, tags []string, params mo
 	// Récupérer les threads
 	offset := (params.Page - 1) * params.PerPage
 	searchQuery := fmt.Sprintf(`
-		SELECT DISTINCT t.id, t.title, t.desc_, t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		SELECT DISTINCT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.user_id, t.view_count, t.created_at, t.updated_at,
 		       u.id, u.username, u.email, u.profile_pic
 		FROM threads t
 		JOIN users u ON t.user_id = u.id
 		JOIN thread_tags tt ON t.id = tt.thread_id
 		JOIN tags tag ON tt.tag_id = tag.id
 		WHERE (t.title LIKE ? OR t.desc_ LIKE ?) 
-		  AND t.visibility = 'public' 
+		  AND %s
 		  AND t.state != 'archivé'
 		  AND tag.name IN (%s)
-		GROUP BY t.id, t.title, t.desc_, t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		GROUP BY t.id, t.title, t.desc_, t.desc_html, t.image_url, t.state, t.visibility, t.user_id, t.view_count, t.created_at, t.updated_at,
 		         u.id, u.username, u.email, u.profile_pic
 		HAVING COUNT(DISTINCT tag.name) = ?
-		ORDER BY t.created_at DESC
+		ORDER BY %s
 		LIMIT ? OFFSET ?
-	`, tagPlaceholders)
+	`, r.visibilityClause(), tagPlaceholders, threadOrderClause(params.Sort))
 
 	// Préparer les arguments pour la requête principale
 	searchArgs := []interface{}{searchTerm, searchTerm}
@@ -557,7 +944,7 @@ func (r *threadRepository) SearchWithTags(query string, tags []string, params mo
 	for rows.Next() {
 		thread := &models.Thread{Author: &models.User{}}
 		err := rows.Scan(
-			&thread.ID, &thread.Title, &thread.Description, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.ViewCount, &thread.CreatedAt, &thread.UpdatedAt,
 			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
 		)
 		if err != nil {
@@ -597,12 +984,12 @@ func (r *threadRepository) FindByTags(tags []string, params models.PaginationPar
 		FROM threads t
 		JOIN thread_tags tt ON t.id = tt.thread_id
 		JOIN tags tag ON tt.tag_id = tag.id
-		WHERE t.visibility = 'public' 
+		WHERE %s
 		  AND t.state != 'archivé'
 		  AND tag.name IN (%s)
 		GROUP BY t.id
 		HAVING COUNT(DISTINCT tag.name) = ?
-	`, tagPlaceholders)
+	`, r.visibilityClause(), tagPlaceholders)
 
 	// Préparer les arguments pour la requête de comptage
 	countArgs := []interface{}{}
@@ -626,21 +1013,21 @@ func (r *threadRepository) FindByTags(tags []string, params models.PaginationPar
 	// Récupérer les threads
 	offset := (params.Page - 1) * params.PerPage
 	searchQuery := fmt.Sprintf(`
-		SELECT DISTINCT t.id, t.title, t.desc_, t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		SELECT DISTINCT t.id, t.title, t.desc_, COALESCE(t.desc_html, ''), t.image_url, t.state, t.visibility, t.user_id, t.view_count, t.created_at, t.updated_at,
 		       u.id, u.username, u.email, u.profile_pic
 		FROM threads t
 		JOIN users u ON t.user_id = u.id
 		JOIN thread_tags tt ON t.id = tt.thread_id
 		JOIN tags tag ON tt.tag_id = tag.id
-		WHERE t.visibility = 'public' 
+		WHERE %s
 		  AND t.state != 'archivé'
 		  AND tag.name IN (%s)
-		GROUP BY t.id, t.title, t.desc_, t.image_url, t.state, t.visibility, t.user_id, t.created_at, t.updated_at,
+		GROUP BY t.id, t.title, t.desc_, t.desc_html, t.image_url, t.state, t.visibility, t.user_id, t.view_count, t.created_at, t.updated_at,
 		         u.id, u.username, u.email, u.profile_pic
 		HAVING COUNT(DISTINCT tag.name) = ?
-		ORDER BY t.created_at DESC
+		ORDER BY %s
 		LIMIT ? OFFSET ?
-	`, tagPlaceholders)
+	`, r.visibilityClause(), tagPlaceholders, threadOrderClause(params.Sort))
 
 	// Préparer les arguments pour la requête principale
 	searchArgs := []interface{}{}
@@ -659,7 +1046,7 @@ func (r *threadRepository) FindByTags(tags []string, params models.PaginationPar
 	for rows.Next() {
 		thread := &models.Thread{Author: &models.User{}}
 		err := rows.Scan(
-			&thread.ID, &thread.Title, &thread.Description, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.CreatedAt, &thread.UpdatedAt,
+			&thread.ID, &thread.Title, &thread.Description, &thread.DescriptionHTML, &thread.ImageURL, &thread.State, &thread.Visibility, &thread.UserID, &thread.ViewCount, &thread.CreatedAt, &thread.UpdatedAt,
 			&thread.Author.ID, &thread.Author.Username, &thread.Author.Email, &thread.Author.ProfilePic,
 		)
 		if err != nil {
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"rythmitbackend/internal/models"
+)
+
+// placeholderDriver driver SQL sans base : chaque requête annonce son nombre de « ? »,
+// database/sql refuse donc tout appel dont le nombre d'arguments diffère
+type placeholderDriver struct{}
+
+func (d *placeholderDriver) Open(name string) (driver.Conn, error) {
+	return &placeholderConn{}, nil
+}
+
+type placeholderConn struct{}
+
+func (c *placeholderConn) Prepare(query string) (driver.Stmt, error) {
+	return &placeholderStmt{query: query}, nil
+}
+
+func (c *placeholderConn) Close() error              { return nil }
+func (c *placeholderConn) Begin() (driver.Tx, error) { return placeholderTx{}, nil }
+
+type placeholderTx struct{}
+
+func (placeholderTx) Commit() error   { return nil }
+func (placeholderTx) Rollback() error { return nil }
+
+type placeholderStmt struct {
+	query string
+}
+
+func (s *placeholderStmt) Close() error  { return nil }
+func (s *placeholderStmt) NumInput() int { return strings.Count(s.query, "?") }
+
+func (s *placeholderStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return placeholderResult{}, nil
+}
+
+type placeholderResult struct{}
+
+func (placeholderResult) LastInsertId() (int64, error) { return 1, nil }
+func (placeholderResult) RowsAffected() (int64, error) { return 1, nil }
+
+func (s *placeholderStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return placeholderRows{}, nil
+}
+
+type placeholderRows struct{}
+
+func (placeholderRows) Columns() []string              { return nil }
+func (placeholderRows) Close() error                   { return nil }
+func (placeholderRows) Next(dest []driver.Value) error { return io.EOF }
+
+var registerPlaceholderDriver sync.Once
+
+// newPlaceholderDB ouvre une connexion sur le driver qui vérifie le nombre d'arguments
+func newPlaceholderDB(t *testing.T) *sql.DB {
+	registerPlaceholderDriver.Do(func() {
+		sql.Register("placeholder", &placeholderDriver{})
+	})
+	db, err := sql.Open("placeholder", "")
+	if err != nil {
+		t.Fatalf("Ouverture du driver échouée: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestThreadRepositoryArguments(t *testing.T) {
+	repo := NewThreadRepository(newPlaceholderDB(t))
+	thread := &models.Thread{
+		Title:       "Drake vs Kendrick",
+		Description: "Qui est le meilleur ?",
+		State:       models.ThreadStateOpen,
+		Visibility:  models.VisibilityPublic,
+		UserID:      1,
+	}
+	thread.ID = 7
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"Create", func() error { return repo.Create(thread) }},
+		{"Update", func() error { return repo.Update(thread) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Errorf("Erreur inattendue: %v", err)
+			}
+		})
+	}
+}
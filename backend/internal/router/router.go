@@ -49,6 +49,10 @@ func Init(cfg *configs.Config) *mux.Router {
 			http.FileServer(http.Dir("../frontend/styles/"))))
 	}
 
+	// Les fichiers audio ne sont pas servis en statique : la lecture passe par
+	// /api/v1/audio/{id}/stream, qui vérifie l'accès
+	Router.PathPrefix("/uploads/audio/").HandlerFunc(http.NotFound)
+
 	// Servir les images uploadées
 	Router.PathPrefix("/uploads/").Handler(http.StripPrefix("/uploads/",
 		http.FileServer(http.Dir("uploads/"))))
@@ -59,6 +63,9 @@ func Init(cfg *configs.Config) *mux.Router {
 	// Routes API publiques
 	setupAPIRoutes()
 
+	// API de scrobbling compatible Last.fm et ListenBrainz
+	setupScrobbleRoutes()
+
 	// Configuration CORS
 	allowedOrigins := []string{"http://localhost:3000", "http://localhost:5173", "http://localhost:8085"}
 	if extraOrigins := os.Getenv("CORS_ALLOWED_ORIGINS"); extraOrigins != "" {
@@ -105,6 +112,9 @@ func setupPageRoutes() {
 	Router.HandleFunc("/thread/{id:[0-9]+}/delete", handlers.DeleteThreadHandler).Methods("POST")
 	Router.HandleFunc("/thread/{id:[0-9]+}/edit", handlers.EditThreadHandler).Methods("GET", "POST")
 
+	// Page collection de threads
+	Router.HandleFunc("/collection/{id:[0-9]+}", handlers.CollectionPageHandler).Methods("GET")
+
 	// Pages d'authentification
 	Router.HandleFunc("/signin", handlers.SigninHandler).Methods("GET", "POST")
 	Router.HandleFunc("/login", handlers.SigninHandler).Methods("GET", "POST") // Alias pour /signin
@@ -175,6 +185,62 @@ func setupAPIRoutes() {
 	// Routes de messagerie (authentification requise)
 	setupMessageRoutes(mixed)
 
+	// Routes d'accès aux threads privés (authentification requise)
+	setupThreadAccessRoutes(mixed)
+
+	// Routes des brouillons (authentification requise)
+	setupDraftRoutes(mixed)
+
+	// Routes des sondages de threads
+	setupPollRoutes(mixed)
+
+	// Routes des critiques notées (artistes, albums, morceaux)
+	setupReviewRoutes(mixed)
+
+	// Routes des annotations de passages des threads
+	setupAnnotationRoutes(mixed)
+
+	// Routes du catalogue musical (mêmes morceaux sur toutes les plateformes)
+	setupCatalogRoutes(mixed)
+
+	// Envoi de fichiers audio pour les options de battle
+	setupAudioRoutes(mixed)
+
+	// Composition des battles (musiques proposées par leur créateur)
+	setupBattleRoutes(mixed)
+
+	// Historique d'écoute des utilisateurs (écoutes récentes, en écoute)
+	setupListeningRoutes(mixed)
+
+	// Jetons personnels des scrobblers (authentification requise)
+	setupScrobbleTokenRoutes(mixed)
+
+	// Routes d'abonnement aux threads (authentification requise)
+	setupSubscriptionRoutes(mixed)
+
+	// Routes des favoris et collections
+	setupBookmarkRoutes(mixed)
+	setupCollectionRoutes(mixed)
+
+	// Routes de partage des threads (repost et message privé)
+	setupShareRoutes(mixed)
+
+	// Routes d'autocomplétion des mentions @utilisateur
+	setupMentionRoutes(mixed)
+
+	// Réactions emoji sur les commentaires et les messages privés
+	setupReactionRoutes(mixed)
+
+	// Aperçu Markdown et des liens musicaux pour l'éditeur
+	mixed.HandleFunc("/markdown/preview", handlers.MarkdownPreviewHandler).Methods("POST")
+	mixed.HandleFunc("/music-links/resolve", handlers.ResolveMusicLinkHandler).Methods("GET")
+
+	// Détection des doublons avant publication
+	setupDuplicateRoutes(mixed)
+
+	// Routes d'administration (droits administrateur requis)
+	setupAdminRoutes(mixed)
+
 	// Routes avec préfixe v1 (pour compatibilité frontend)
 	v1 := api.PathPrefix("/v1").Subrouter()
 	v1.Use(middleware.OptionalAuthMiddleware)
@@ -198,6 +264,334 @@ func setupAPIRoutes() {
 
 	// Routes de messagerie pour v1 aussi
 	setupMessageRoutes(v1)
+
+	// Routes d'accès aux threads privés pour v1 aussi
+	setupThreadAccessRoutes(v1)
+
+	// Routes des brouillons pour v1 aussi
+	setupDraftRoutes(v1)
+
+	// Routes des sondages pour v1 aussi
+	setupPollRoutes(v1)
+
+	// Routes des critiques pour v1 aussi
+	setupReviewRoutes(v1)
+
+	// Routes des annotations pour v1 aussi
+	setupAnnotationRoutes(v1)
+
+	// Routes du catalogue musical pour v1 aussi
+	setupCatalogRoutes(v1)
+
+	// Fichiers audio pour v1 aussi
+	setupAudioRoutes(v1)
+
+	// Battles pour v1 aussi
+	setupBattleRoutes(v1)
+
+	// Historique d'écoute pour v1 aussi
+	setupListeningRoutes(v1)
+
+	// Jetons des scrobblers pour v1 aussi
+	setupScrobbleTokenRoutes(v1)
+
+	// Routes d'abonnement pour v1 aussi
+	setupSubscriptionRoutes(v1)
+
+	// Routes des favoris et collections pour v1 aussi
+	setupBookmarkRoutes(v1)
+	setupCollectionRoutes(v1)
+
+	// Routes de partage pour v1 aussi
+	setupShareRoutes(v1)
+
+	// Routes des mentions pour v1 aussi
+	setupMentionRoutes(v1)
+	v1.HandleFunc("/markdown/preview", handlers.MarkdownPreviewHandler).Methods("POST")
+	v1.HandleFunc("/music-links/resolve", handlers.ResolveMusicLinkHandler).Methods("GET")
+
+	// Routes des réactions pour v1 aussi
+	setupReactionRoutes(v1)
+
+	// Détection des doublons pour v1 aussi
+	setupDuplicateRoutes(v1)
+
+	// Routes d'administration pour v1 aussi
+	setupAdminRoutes(v1)
+}
+
+// setupBookmarkRoutes configure les routes des threads mis en favoris
+func setupBookmarkRoutes(router *mux.Router) {
+	db := database.DB
+	threadService := services.NewThreadService(
+		repositories.NewThreadRepository(db),
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+	bookmarkService := services.NewBookmarkService(repositories.NewBookmarkRepository(db), threadService)
+	bookmarkHandler := handlers.NewBookmarkHandler(bookmarkService)
+
+	router.HandleFunc("/bookmarks", bookmarkHandler.GetBookmarks).Methods("GET")
+	router.HandleFunc("/threads/{id:[0-9]+}/bookmark", bookmarkHandler.AddBookmark).Methods("POST")
+	router.HandleFunc("/threads/{id:[0-9]+}/bookmark", bookmarkHandler.RemoveBookmark).Methods("DELETE")
+}
+
+// setupShareRoutes configure les routes de partage des threads
+func setupShareRoutes(router *mux.Router) {
+	db := database.DB
+	threadRepo := repositories.NewThreadRepository(db)
+	threadService := services.NewThreadService(
+		threadRepo,
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+	messageService := services.NewMessageService(repositories.NewDirectMessageRepository(db), repositories.NewFriendshipRepository(db), services.NewMentionServiceWithDB(db), repositories.NewReactionRepository(db))
+	shareService := services.NewShareService(repositories.NewShareRepository(db), threadRepo, threadService, messageService)
+	shareHandler := handlers.NewShareHandler(shareService)
+
+	router.HandleFunc("/threads/{id:[0-9]+}/repost", shareHandler.Repost).Methods("POST")
+	router.HandleFunc("/threads/{id:[0-9]+}/repost", shareHandler.Unrepost).Methods("DELETE")
+	router.HandleFunc("/threads/{id:[0-9]+}/share", shareHandler.ShareToMessage).Methods("POST")
+	router.HandleFunc("/users/{userId:[0-9]+}/reposts", shareHandler.GetUserReposts).Methods("GET")
+}
+
+// setupMentionRoutes configure l'autocomplétion des mentions @utilisateur
+func setupMentionRoutes(router *mux.Router) {
+	mentionHandler := handlers.NewMentionHandler(services.NewMentionServiceWithDB(database.DB))
+
+	router.HandleFunc("/mentions/suggest", mentionHandler.SuggestUsers).Methods("GET")
+}
+
+// setupReactionRoutes configure les réactions emoji sur les commentaires et les messages privés
+func setupReactionRoutes(router *mux.Router) {
+	reactionHandler := handlers.NewReactionHandler(services.NewReactionServiceWithDB(database.DB))
+
+	router.HandleFunc("/reactions", reactionHandler.GetAllowedReactions).Methods("GET")
+	router.HandleFunc("/comments/{id:[0-9]+}/reactions", reactionHandler.GetCommentReactions).Methods("GET")
+	router.HandleFunc("/comments/{id:[0-9]+}/reactions", reactionHandler.ToggleCommentReaction).Methods("POST")
+	router.HandleFunc("/direct-messages/{id:[0-9]+}/reactions", reactionHandler.GetMessageReactions).Methods("GET")
+	router.HandleFunc("/direct-messages/{id:[0-9]+}/reactions", reactionHandler.ToggleMessageReaction).Methods("POST")
+}
+
+// setupCollectionRoutes configure les routes des collections de threads
+func setupCollectionRoutes(router *mux.Router) {
+	db := database.DB
+	threadService := services.NewThreadService(
+		repositories.NewThreadRepository(db),
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+	collectionService := services.NewCollectionService(repositories.NewCollectionRepository(db), threadService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+
+	router.HandleFunc("/collections", collectionHandler.GetMyCollections).Methods("GET")
+	router.HandleFunc("/collections", collectionHandler.CreateCollection).Methods("POST")
+	router.HandleFunc("/users/{userId:[0-9]+}/collections", collectionHandler.GetUserCollections).Methods("GET")
+	router.HandleFunc("/collections/{collectionId:[0-9]+}", collectionHandler.GetCollection).Methods("GET")
+	router.HandleFunc("/collections/{collectionId:[0-9]+}", collectionHandler.UpdateCollection).Methods("PUT")
+	router.HandleFunc("/collections/{collectionId:[0-9]+}", collectionHandler.DeleteCollection).Methods("DELETE")
+	router.HandleFunc("/collections/{collectionId:[0-9]+}/items", collectionHandler.AddItem).Methods("POST")
+	router.HandleFunc("/collections/{collectionId:[0-9]+}/items/{threadId:[0-9]+}", collectionHandler.UpdateItem).Methods("PUT")
+	router.HandleFunc("/collections/{collectionId:[0-9]+}/items/{threadId:[0-9]+}", collectionHandler.RemoveItem).Methods("DELETE")
+	router.HandleFunc("/collections/{collectionId:[0-9]+}/order", collectionHandler.ReorderItems).Methods("PUT")
+}
+
+// setupSubscriptionRoutes configure les routes d'abonnement et de suivi de lecture des threads
+func setupSubscriptionRoutes(router *mux.Router) {
+	db := database.DB
+	threadRepo := repositories.NewThreadRepository(db)
+	threadService := services.NewThreadService(
+		threadRepo,
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+	subscriptionService := services.NewSubscriptionService(repositories.NewSubscriptionRepository(db), threadRepo, threadService)
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionService)
+
+	router.HandleFunc("/threads/{id:[0-9]+}/subscription", subscriptionHandler.GetSubscription).Methods("GET")
+	router.HandleFunc("/threads/{id:[0-9]+}/subscription", subscriptionHandler.Subscribe).Methods("POST")
+	router.HandleFunc("/threads/{id:[0-9]+}/subscription", subscriptionHandler.Unsubscribe).Methods("DELETE")
+	router.HandleFunc("/threads/{id:[0-9]+}/read", subscriptionHandler.MarkRead).Methods("PUT")
+}
+
+// setupPollRoutes configure les routes des sondages intégrés aux threads
+func setupPollRoutes(router *mux.Router) {
+	db := database.DB
+	threadService := services.NewThreadService(
+		repositories.NewThreadRepository(db),
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+	pollService := services.NewPollService(repositories.NewPollRepository(db), threadService)
+	pollHandler := handlers.NewPollHandler(pollService)
+
+	router.HandleFunc("/threads/{id:[0-9]+}/poll", pollHandler.GetPoll).Methods("GET")
+	router.HandleFunc("/threads/{id:[0-9]+}/poll", pollHandler.CreatePoll).Methods("POST")
+	router.HandleFunc("/threads/{id:[0-9]+}/poll", pollHandler.DeletePoll).Methods("DELETE")
+	router.HandleFunc("/threads/{id:[0-9]+}/poll/vote", pollHandler.Vote).Methods("POST")
+}
+
+// setupReviewRoutes configure les routes des critiques notées et de la note moyenne des tags
+func setupReviewRoutes(router *mux.Router) {
+	reviewHandler := handlers.NewReviewHandler(services.NewReviewServiceWithDB(database.DB))
+
+	router.HandleFunc("/threads/{id:[0-9]+}/review", reviewHandler.GetReview).Methods("GET")
+	router.HandleFunc("/threads/{id:[0-9]+}/review", reviewHandler.SaveReview).Methods("PUT")
+	router.HandleFunc("/threads/{id:[0-9]+}/review", reviewHandler.DeleteReview).Methods("DELETE")
+	router.HandleFunc("/tags/{name}/reviews", reviewHandler.GetTagReviews).Methods("GET")
+}
+
+// setupAnnotationRoutes configure les routes des annotations de la description des threads
+func setupAnnotationRoutes(router *mux.Router) {
+	annotationHandler := handlers.NewAnnotationHandler(services.NewAnnotationServiceWithDB(database.DB))
+
+	router.HandleFunc("/threads/{id:[0-9]+}/annotations", annotationHandler.GetThreadAnnotations).Methods("GET")
+	router.HandleFunc("/threads/{id:[0-9]+}/annotations", annotationHandler.CreateAnnotation).Methods("POST")
+	router.HandleFunc("/annotations/{id:[0-9]+}", annotationHandler.DeleteAnnotation).Methods("DELETE")
+	router.HandleFunc("/annotations/{id:[0-9]+}/vote", annotationHandler.VoteAnnotation).Methods("POST")
+}
+
+// setupCatalogRoutes configure les routes du catalogue musical, des classements et de la plateforme préférée
+func setupCatalogRoutes(router *mux.Router) {
+	catalogHandler := handlers.NewCatalogHandler(services.NewCatalogServiceWithDB(database.DB))
+
+	router.HandleFunc("/catalog/links", catalogHandler.RegisterLink).Methods("POST")
+	router.HandleFunc("/catalog/links", catalogHandler.UnlinkLink).Methods("DELETE")
+	router.HandleFunc("/catalog/lookup", catalogHandler.LookupLink).Methods("GET")
+	router.HandleFunc("/catalog/charts", catalogHandler.GetCharts).Methods("GET")
+	router.HandleFunc("/catalog/preferences", catalogHandler.GetPreferences).Methods("GET")
+	router.HandleFunc("/catalog/preferences", catalogHandler.SetPreferences).Methods("PUT")
+	router.HandleFunc("/catalog/{type:tracks|albums|artists}/{id:[0-9]+}", catalogHandler.GetEntry).Methods("GET")
+	router.HandleFunc("/catalog/{type:tracks|albums|artists}/{id:[0-9]+}/links", catalogHandler.LinkToEntry).Methods("POST")
+}
+
+// setupAudioRoutes configure l'envoi des fichiers audio (MP3, OGG, FLAC, WAV)
+func setupAudioRoutes(router *mux.Router) {
+	audioHandler := handlers.NewAudioHandler(services.NewAudioServiceWithDB(database.DB))
+
+	router.HandleFunc("/audio", audioHandler.Upload).Methods("POST")
+	router.HandleFunc("/audio/{id:[0-9]+}", audioHandler.GetAudio).Methods("GET")
+	router.HandleFunc("/audio/{id:[0-9]+}/url", audioHandler.GetStreamURL).Methods("GET")
+	router.HandleFunc("/audio/{id:[0-9]+}/stream", audioHandler.Stream).Methods("GET", "HEAD")
+}
+
+// setupBattleRoutes configure l'ajout de musiques aux battles (lien musical ou fichier audio envoyé)
+func setupBattleRoutes(router *mux.Router) {
+	battleHandler := handlers.NewBattleHandler(services.NewBattleServiceWithDB(database.DB))
+
+	router.HandleFunc("/battles/{id:[0-9]+}/options", battleHandler.AddOption).Methods("POST")
+}
+
+// setupListeningRoutes configure l'historique d'écoute (alimenté par les activités « listening »)
+func setupListeningRoutes(router *mux.Router) {
+	listeningHandler := handlers.NewListeningHandler(services.NewListeningServiceWithDB(database.DB))
+
+	router.HandleFunc("/users/{userId:[0-9]+}/listens", listeningHandler.GetUserListens).Methods("GET")
+}
+
+// setupScrobbleTokenRoutes configure les jetons à renseigner dans les scrobblers
+func setupScrobbleTokenRoutes(router *mux.Router) {
+	scrobbleHandler := handlers.NewScrobbleHandler(services.NewScrobbleServiceWithDB(database.DB), services.NewListeningServiceWithDB(database.DB))
+
+	router.HandleFunc("/scrobble/tokens", scrobbleHandler.GetTokens).Methods("GET")
+	router.HandleFunc("/scrobble/tokens", scrobbleHandler.CreateToken).Methods("POST")
+	router.HandleFunc("/scrobble/tokens/{id:[0-9]+}", scrobbleHandler.RevokeToken).Methods("DELETE")
+}
+
+// setupScrobbleRoutes configure les API utilisées par les scrobblers, hors de /api car leurs
+// formats sont imposés : ListenBrainz (/scrobble/listenbrainz/1/...) et Last.fm (/scrobble/lastfm/2.0/)
+func setupScrobbleRoutes() {
+	scrobbleHandler := handlers.NewScrobbleHandler(services.NewScrobbleServiceWithDB(database.DB), services.NewListeningServiceWithDB(database.DB))
+
+	listenBrainz := Router.PathPrefix("/scrobble/listenbrainz/1").Subrouter()
+	listenBrainz.HandleFunc("/submit-listens", scrobbleHandler.SubmitListens).Methods("POST")
+	listenBrainz.HandleFunc("/validate-token", scrobbleHandler.ValidateToken).Methods("GET")
+
+	// Last.fm impose POST pour l'authentification et le scrobbling (mot de passe et clé hors de l'URL)
+	Router.HandleFunc("/scrobble/lastfm/2.0/", scrobbleHandler.LastFM).Methods("POST")
+	Router.HandleFunc("/scrobble/lastfm/2.0", scrobbleHandler.LastFM).Methods("POST")
+}
+
+// setupAdminRoutes configure les routes d'administration des threads (épinglage, annonces, modération)
+func setupAdminRoutes(router *mux.Router) {
+	admin := router.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.AdminMiddleware)
+
+	db := database.DB
+	threadService := services.NewThreadService(
+		repositories.NewThreadRepository(db),
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+	adminHandler := handlers.NewAdminThreadHandler(threadService)
+	moderationHandler := handlers.NewModerationHandler(services.NewModerationServiceWithDB(db))
+
+	admin.HandleFunc("/threads/{id:[0-9]+}/pin", adminHandler.PinThread).Methods("POST")
+	admin.HandleFunc("/threads/{id:[0-9]+}/pin", adminHandler.UnpinThread).Methods("DELETE")
+	admin.HandleFunc("/threads/{id:[0-9]+}/announcement", adminHandler.SetAnnouncement).Methods("PUT")
+
+	// Modération : scission, déplacement, retag et fusion (journalisés)
+	admin.HandleFunc("/threads/{id:[0-9]+}/split", moderationHandler.SplitThread).Methods("POST")
+	admin.HandleFunc("/threads/{id:[0-9]+}/move", moderationHandler.MoveComments).Methods("POST")
+	admin.HandleFunc("/threads/{id:[0-9]+}/tags", moderationHandler.RetagThread).Methods("PUT")
+	admin.HandleFunc("/threads/{id:[0-9]+}/merge", moderationHandler.MergeThread).Methods("POST")
+	admin.HandleFunc("/threads/{id:[0-9]+}/moderation", moderationHandler.GetModerationLog).Methods("GET")
+}
+
+// setupDuplicateRoutes configure la détection des threads en double
+func setupDuplicateRoutes(router *mux.Router) {
+	db := database.DB
+	threadService := services.NewThreadService(
+		repositories.NewThreadRepository(db),
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+	duplicateHandler := handlers.NewDuplicateHandler(threadService)
+
+	router.HandleFunc("/threads/duplicates", duplicateHandler.CheckDuplicates).Methods("POST")
+}
+
+// setupDraftRoutes configure les routes des brouillons de threads
+func setupDraftRoutes(router *mux.Router) {
+	db := database.DB
+	threadService := services.NewThreadService(
+		repositories.NewThreadRepository(db),
+		repositories.NewTagRepository(db),
+		repositories.NewMessageRepository(db),
+		db,
+	)
+	draftService := services.NewDraftService(repositories.NewDraftRepository(db), threadService)
+	draftHandler := handlers.NewDraftHandler(draftService)
+
+	router.HandleFunc("/drafts", draftHandler.GetDrafts).Methods("GET")
+	router.HandleFunc("/drafts", draftHandler.CreateDraft).Methods("POST")
+	router.HandleFunc("/drafts/{draftId:[0-9]+}", draftHandler.GetDraft).Methods("GET")
+	router.HandleFunc("/drafts/{draftId:[0-9]+}", draftHandler.AutosaveDraft).Methods("PUT")
+	router.HandleFunc("/drafts/{draftId:[0-9]+}", draftHandler.DeleteDraft).Methods("DELETE")
+	router.HandleFunc("/drafts/{draftId:[0-9]+}/publish", draftHandler.PublishDraft).Methods("POST")
+}
+
+// setupThreadAccessRoutes configure les routes d'invitation aux threads privés
+func setupThreadAccessRoutes(router *mux.Router) {
+	db := database.DB
+	accessService := services.NewThreadAccessService(
+		repositories.NewThreadRepository(db),
+		repositories.NewThreadInvitationRepository(db),
+		repositories.NewUserRepository(db),
+		repositories.NewFriendshipRepository(db),
+	)
+	accessHandler := handlers.NewThreadAccessHandler(accessService)
+
+	router.HandleFunc("/threads/{id:[0-9]+}/invitations", accessHandler.GetInvitations).Methods("GET")
+	router.HandleFunc("/threads/{id:[0-9]+}/invitations", accessHandler.InviteUser).Methods("POST")
+	router.HandleFunc("/threads/{id:[0-9]+}/invitations/{userId:[0-9]+}", accessHandler.RevokeInvitation).Methods("DELETE")
 }
 
 // setupMessageRoutes configure les routes pour l'API des messages directs
@@ -206,7 +600,7 @@ func setupMessageRoutes(router *mux.Router) {
 	db := database.DB
 	messageRepo := repositories.NewDirectMessageRepository(db)
 	friendshipRepo := repositories.NewFriendshipRepository(db)
-	messageService := services.NewMessageService(messageRepo, friendshipRepo)
+	messageService := services.NewMessageService(messageRepo, friendshipRepo, services.NewMentionServiceWithDB(db), repositories.NewReactionRepository(db))
 	messageHandler := handlers.NewMessageHandler(messageService)
 
 	// Routes pour les conversations
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limites des annotations
+const (
+	maxAnnotationQuote = 1000 // caractères du passage annoté
+	maxAnnotationBody  = 2000 // caractères du commentaire
+)
+
+// AnnotationService interface pour les annotations de passages de la description des threads
+type AnnotationService interface {
+	CreateAnnotation(threadID, userID uint, dto CreateAnnotationDTO) (*AnnotationResponseDTO, error)
+	GetThreadAnnotations(threadID uint, viewerID *uint) (*ThreadAnnotationsDTO, error)
+	DeleteAnnotation(id, userID uint, isAdmin bool) error
+	VoteAnnotation(id, userID uint, vote string) (*AnnotationResponseDTO, error)
+}
+
+// CreateAnnotationDTO passage sélectionné (offsets en caractères dans la révision indiquée) et son commentaire
+type CreateAnnotationDTO struct {
+	StartOffset int    `json:"start_offset"`
+	EndOffset   int    `json:"end_offset"`
+	Revision    int    `json:"revision"`
+	Body        string `json:"body"`
+}
+
+// AnnotationResponseDTO annotation d'un passage
+type AnnotationResponseDTO struct {
+	ID          uint           `json:"id"`
+	ThreadID    uint           `json:"thread_id"`
+	Author      UserSummaryDTO `json:"author"`
+	StartOffset int            `json:"start_offset"`
+	EndOffset   int            `json:"end_offset"`
+	Quote       string         `json:"quote"`
+	Body        string         `json:"body"`
+	Revision    int            `json:"revision"`
+	Stale       bool           `json:"stale"` // passage introuvable dans la révision courante
+	FireCount   int            `json:"fire_count"`
+	SkipCount   int            `json:"skip_count"`
+	Score       int            `json:"score"`
+	UserVote    *string        `json:"user_vote,omitempty"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+}
+
+// ThreadAnnotationsDTO annotations d'un thread avec le texte brut de la révision courante,
+// pour les afficher et calculer les offsets d'une nouvelle sélection
+type ThreadAnnotationsDTO struct {
+	Revision    int                     `json:"revision"`
+	Description string                  `json:"description"`
+	Annotations []AnnotationResponseDTO `json:"annotations"`
+}
+
+// annotationService implémentation concrète
+type annotationService struct {
+	annotationRepo repositories.AnnotationRepository
+	threadService  ThreadService
+}
+
+// NewAnnotationService crée une nouvelle instance du service
+func NewAnnotationService(annotationRepo repositories.AnnotationRepository, threadService ThreadService) AnnotationService {
+	return &annotationService{
+		annotationRepo: annotationRepo,
+		threadService:  threadService,
+	}
+}
+
+// CreateAnnotation annote un passage de la description d'un thread visible et ouvert aux commentaires
+func (s *annotationService) CreateAnnotation(threadID, userID uint, dto CreateAnnotationDTO) (*AnnotationResponseDTO, error) {
+	thread, err := s.threadService.GetThread(threadID, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStateAllows(thread.State, ThreadActionComment); err != nil {
+		return nil, err
+	}
+
+	text, revision, err := s.annotationRepo.ThreadText(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Les offsets ne valent que pour la révision affichée lors de la sélection
+	if dto.Revision != revision {
+		return nil, utils.ErrAnnotationOutdated
+	}
+
+	quote, err := selectPassage([]rune(text), dto.StartOffset, dto.EndOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(dto.Body)
+	if body == "" || utf8.RuneCountInString(body) > maxAnnotationBody {
+		return nil, fmt.Errorf("l'annotation doit faire entre 1 et %d caractères: %w", maxAnnotationBody, utils.ErrInvalidInput)
+	}
+
+	annotation := &models.Annotation{
+		ThreadID:    threadID,
+		UserID:      userID,
+		StartOffset: dto.StartOffset,
+		EndOffset:   dto.EndOffset,
+		Quote:       quote,
+		Body:        body,
+		Revision:    revision,
+	}
+	if err := s.annotationRepo.Create(annotation); err != nil {
+		return nil, err
+	}
+
+	created, err := s.annotationRepo.FindByID(annotation.ID, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return annotationToDTO(created), nil
+}
+
+// GetThreadAnnotations récupère les annotations d'un thread accessible au lecteur
+func (s *annotationService) GetThreadAnnotations(threadID uint, viewerID *uint) (*ThreadAnnotationsDTO, error) {
+	if _, err := s.threadService.GetThread(threadID, viewerID); err != nil {
+		return nil, err
+	}
+
+	text, revision, err := s.annotationRepo.ThreadText(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := s.annotationRepo.FindByThreadID(threadID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ThreadAnnotationsDTO{
+		Revision:    revision,
+		Description: text,
+		Annotations: make([]AnnotationResponseDTO, 0, len(annotations)),
+	}
+	for _, annotation := range annotations {
+		result.Annotations = append(result.Annotations, *annotationToDTO(annotation))
+	}
+
+	return result, nil
+}
+
+// DeleteAnnotation supprime une annotation (auteur ou admin)
+func (s *annotationService) DeleteAnnotation(id, userID uint, isAdmin bool) error {
+	annotation, err := s.annotationRepo.FindByID(id, nil)
+	if err != nil {
+		return err
+	}
+
+	if !isAdmin && annotation.UserID != userID {
+		return utils.ErrUnauthorized
+	}
+
+	return s.annotationRepo.Delete(id)
+}
+
+// VoteAnnotation enregistre un vote Fire/Skip (neutral pour le retirer) sur une annotation
+func (s *annotationService) VoteAnnotation(id, userID uint, vote string) (*AnnotationResponseDTO, error) {
+	switch vote {
+	case models.VoteFire, models.VoteSkip, models.VoteNeutral:
+	default:
+		return nil, fmt.Errorf("vote invalide (fire, skip ou neutral): %w", utils.ErrInvalidInput)
+	}
+
+	annotation, err := s.annotationRepo.FindByID(id, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := s.threadService.GetThread(annotation.ThreadID, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStateAllows(thread.State, ThreadActionVote); err != nil {
+		return nil, err
+	}
+
+	if err := s.annotationRepo.SetVote(id, userID, vote); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.annotationRepo.FindByID(id, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return annotationToDTO(updated), nil
+}
+
+// reanchorThreadAnnotations réancre les annotations d'un thread sur la révision courante de sa
+// description ; celles dont le passage a disparu sont marquées obsolètes et gardent leur révision
+func reanchorThreadAnnotations(annotationRepo repositories.AnnotationRepository, threadID uint) error {
+	text, revision, err := annotationRepo.ThreadText(threadID)
+	if err != nil {
+		return err
+	}
+
+	annotations, err := annotationRepo.FindByThreadID(threadID, nil)
+	if err != nil {
+		return err
+	}
+
+	runes := []rune(text)
+	moved, stale := 0, 0
+	for _, annotation := range annotations {
+		if annotation.Revision == revision && !annotation.Stale {
+			continue
+		}
+
+		if reanchorAnnotation(runes, annotation) {
+			annotation.Revision = revision
+			moved++
+		} else {
+			stale++
+		}
+
+		if err := annotationRepo.UpdateAnchor(annotation); err != nil {
+			return err
+		}
+	}
+
+	if moved > 0 || stale > 0 {
+		log.Printf("📝 Annotations du thread %d: %d réancrées, %d obsolètes (révision %d)", threadID, moved, stale, revision)
+	}
+
+	return nil
+}
+
+// reanchorAnnotation replace une annotation sur son passage : à ses offsets s'il n'a pas bougé,
+// sinon à l'occurrence la plus proche de son ancienne position ; false si le passage a disparu
+func reanchorAnnotation(text []rune, annotation *models.Annotation) bool {
+	quote := []rune(annotation.Quote)
+	if len(quote) == 0 {
+		annotation.Stale = true
+		return false
+	}
+
+	if annotation.StartOffset >= 0 && annotation.EndOffset <= len(text) && annotation.StartOffset < annotation.EndOffset &&
+		string(text[annotation.StartOffset:annotation.EndOffset]) == annotation.Quote {
+		annotation.Stale = false
+		return true
+	}
+
+	// Recherche dans le texte en octets, positions converties en caractères
+	content := string(text)
+	best := -1
+	for from, runesBefore := 0, 0; ; {
+		index := strings.Index(content[from:], annotation.Quote)
+		if index < 0 {
+			break
+		}
+		runesBefore += utf8.RuneCountInString(content[from : from+index])
+		if best < 0 || absInt(runesBefore-annotation.StartOffset) < absInt(best-annotation.StartOffset) {
+			best = runesBefore
+		}
+
+		// Occurrence suivante à partir du caractère suivant (les occurrences peuvent se chevaucher)
+		_, size := utf8.DecodeRuneInString(content[from+index:])
+		from += index + size
+		runesBefore++
+	}
+
+	if best < 0 {
+		annotation.Stale = true
+		return false
+	}
+
+	annotation.StartOffset, annotation.EndOffset = best, best+len(quote)
+	annotation.Stale = false
+	return true
+}
+
+// selectPassage vérifie les offsets d'une sélection et retourne le passage correspondant
+func selectPassage(text []rune, start, end int) (string, error) {
+	if start < 0 || end > len(text) || start >= end {
+		return "", fmt.Errorf("sélection hors du texte du thread: %w", utils.ErrInvalidInput)
+	}
+	if end-start > maxAnnotationQuote {
+		return "", fmt.Errorf("%d caractères annotés au maximum: %w", maxAnnotationQuote, utils.ErrInvalidInput)
+	}
+
+	quote := string(text[start:end])
+	if strings.TrimSpace(quote) == "" {
+		return "", fmt.Errorf("la sélection ne contient que des espaces: %w", utils.ErrInvalidInput)
+	}
+
+	return quote, nil
+}
+
+// absInt valeur absolue d'un entier
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// annotationToDTO convertit une annotation
+func annotationToDTO(annotation *models.Annotation) *AnnotationResponseDTO {
+	return &AnnotationResponseDTO{
+		ID:          annotation.ID,
+		ThreadID:    annotation.ThreadID,
+		Author:      UserSummaryDTO{ID: annotation.UserID, Username: annotation.Username},
+		StartOffset: annotation.StartOffset,
+		EndOffset:   annotation.EndOffset,
+		Quote:       annotation.Quote,
+		Body:        annotation.Body,
+		Revision:    annotation.Revision,
+		Stale:       annotation.Stale,
+		FireCount:   annotation.FireCount,
+		SkipCount:   annotation.SkipCount,
+		Score:       annotation.Score(),
+		UserVote:    annotation.UserVote,
+		CreatedAt:   annotation.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   annotation.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
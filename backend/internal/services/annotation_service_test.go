@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+	"testing"
+)
+
+func TestReanchorAnnotation(t *testing.T) {
+	cases := []struct {
+		name      string
+		text      string
+		start     int
+		quote     string
+		wantOK    bool
+		wantStart int
+	}{
+		{"passage inchangé", "Je rappe pour les miens", 3, "rappe", true, 3},
+		{"passage décalé", "Intro\nJe rappe pour les miens", 3, "rappe", true, 9},
+		{"occurrence la plus proche", "rappe ici, puis je rappe là", 17, "rappe", true, 19},
+		{"caractères multi-octets", "Écoute ça — « rappe »", 10, "rappe", true, 14},
+		{"passage supprimé", "Couplet réécrit entièrement", 3, "rappe", false, 3},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			annotation := &models.Annotation{StartOffset: c.start, EndOffset: c.start + len([]rune(c.quote)), Quote: c.quote}
+
+			ok := reanchorAnnotation([]rune(c.text), annotation)
+			if ok != c.wantOK {
+				t.Fatalf("Réancrage attendu: %v, Obtenu: %v", c.wantOK, ok)
+			}
+			if annotation.Stale == c.wantOK {
+				t.Errorf("Stale attendu: %v, Obtenu: %v", !c.wantOK, annotation.Stale)
+			}
+			if annotation.StartOffset != c.wantStart {
+				t.Errorf("Début attendu: %d, Obtenu: %d", c.wantStart, annotation.StartOffset)
+			}
+			if ok && string([]rune(c.text)[annotation.StartOffset:annotation.EndOffset]) != c.quote {
+				t.Errorf("Le passage réancré ne correspond pas: %q", string([]rune(c.text)[annotation.StartOffset:annotation.EndOffset]))
+			}
+		})
+	}
+}
+
+func TestReanchorAnnotationRecoversStale(t *testing.T) {
+	annotation := &models.Annotation{StartOffset: 0, EndOffset: 5, Quote: "rappe", Stale: true}
+
+	if !reanchorAnnotation([]rune("Je rappe encore"), annotation) || annotation.Stale {
+		t.Errorf("Une annotation obsolète dont le passage revient doit être réancrée: %+v", annotation)
+	}
+}
+
+func TestSelectPassage(t *testing.T) {
+	text := []rune("Mic check, « un deux »")
+
+	quote, err := selectPassage(text, 13, 20)
+	if err != nil || quote != "un deux" {
+		t.Errorf("Passage attendu: %q, Obtenu: %q (%v)", "un deux", quote, err)
+	}
+
+	for _, bounds := range [][2]int{{-1, 3}, {5, 5}, {8, 3}, {0, len(text) + 1}, {3, 4}} {
+		if _, err := selectPassage(text, bounds[0], bounds[1]); !errors.Is(err, utils.ErrInvalidInput) {
+			t.Errorf("Sélection %v: ErrInvalidInput attendu, Obtenu: %v", bounds, err)
+		}
+	}
+}
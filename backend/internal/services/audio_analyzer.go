@@ -0,0 +1,154 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/pkg/audiofile"
+	"time"
+)
+
+// audioAnalysisMaxAttempts nombre d'essais avant d'abandonner l'analyse d'un fichier
+const audioAnalysisMaxAttempts = 3
+
+// AudioAnalyzer calcule en tâche de fond la forme d'onde et la durée exacte des fichiers audio envoyés,
+// pour que l'envoi réponde sans attendre le parcours complet du fichier
+type AudioAnalyzer struct {
+	repo      repositories.AudioRepository
+	dir       string
+	points    int
+	batchSize int
+	wake      chan struct{}
+	now       func() time.Time
+}
+
+// audioAnalyzer worker global réveillé après chaque envoi (nil tant qu'il n'est pas démarré)
+var audioAnalyzer *AudioAnalyzer
+
+// NewAudioAnalyzer crée un worker ; points est le nombre de crêtes de chaque forme d'onde
+func NewAudioAnalyzer(repo repositories.AudioRepository, dir string, points, batchSize int) *AudioAnalyzer {
+	return &AudioAnalyzer{
+		repo:      repo,
+		dir:       dir,
+		points:    points,
+		batchSize: batchSize,
+		wake:      make(chan struct{}, 1),
+		now:       time.Now,
+	}
+}
+
+// AnalyzePending analyse un lot de fichiers en attente et retourne leur nombre
+func (a *AudioAnalyzer) AnalyzePending() (int, error) {
+	pending, err := a.repo.FindPendingAnalysis(a.batchSize, audioAnalysisMaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, audio := range pending {
+		a.analyze(audio)
+		if err := a.repo.SaveAnalysis(audio); err != nil {
+			return i, err
+		}
+	}
+
+	return len(pending), nil
+}
+
+// Wake demande un lot sans attendre le prochain tick (sans effet si une demande est déjà en attente)
+func (a *AudioAnalyzer) Wake() {
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+}
+
+// analyze parcourt le fichier et met à jour le statut, la forme d'onde et la durée
+func (a *AudioAnalyzer) analyze(audio *models.AudioUpload) {
+	now := a.now()
+	audio.AnalyzedAt = &now
+
+	file, err := os.Open(filepath.Join(a.dir, audio.FileName))
+	if err == nil {
+		defer file.Close()
+		var analysis *audiofile.Analysis
+		analysis, err = audiofile.Analyze(file, a.points)
+		if err == nil {
+			audio.WaveformStatus = models.WaveformStatusOK
+			audio.Waveform = roundPeaks(analysis.Peaks)
+			audio.WaveformEstimated = analysis.Estimated
+			audio.DurationMs = int(analysis.Duration.Milliseconds())
+			return
+		}
+	}
+
+	if errors.Is(err, audiofile.ErrUnsupported) {
+		audio.WaveformStatus = models.WaveformStatusUnsupported
+		return
+	}
+	audio.WaveformStatus = models.WaveformStatusError
+	audio.AnalysisAttempts++
+	log.Printf("⚠️ Analyse du fichier audio %s impossible (essai %d): %v", audio.FileName, audio.AnalysisAttempts, err)
+}
+
+// roundPeaks arrondit les crêtes au millième pour alléger le JSON
+func roundPeaks(peaks []float64) []float64 {
+	rounded := make([]float64, len(peaks))
+	for i, peak := range peaks {
+		rounded[i] = math.Round(peak*1000) / 1000
+	}
+	return rounded
+}
+
+// StartAudioAnalyzer installe le worker global et lance l'analyse périodique (et à chaque envoi) ;
+// sans worker, les fichiers restent jouables avec la durée lue à l'envoi, sans forme d'onde
+func StartAudioAnalyzer(repo repositories.AudioRepository, interval time.Duration, points int) {
+	if interval <= 0 {
+		log.Println("⏸️  Analyse des fichiers audio désactivée")
+		return
+	}
+
+	if points <= 0 {
+		points = 200
+	}
+
+	batchSize := 5
+	analyzer := NewAudioAnalyzer(repo, models.AudioUploadDir, points, batchSize)
+	audioAnalyzer = analyzer
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-analyzer.wake:
+			}
+
+			// Vider la file par lots tant qu'ils sont pleins
+			for {
+				count, err := analyzer.AnalyzePending()
+				if err != nil {
+					log.Printf("❌ Erreur analyse des fichiers audio: %v", err)
+					break
+				}
+				if count < batchSize {
+					break
+				}
+			}
+		}
+	}()
+
+	log.Printf("✅ Analyse des fichiers audio active (%d crêtes, toutes les %s)", points, interval)
+}
+
+// WakeAudioAnalyzer réveille le worker global (sans effet s'il n'est pas démarré)
+func WakeAudioAnalyzer() {
+	if audioAnalyzer != nil {
+		audioAnalyzer.Wake()
+	}
+}
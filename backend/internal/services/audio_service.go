@@ -0,0 +1,276 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/audiofile"
+	"strconv"
+	"time"
+)
+
+// Limites des fichiers audio envoyés (voir SetAudioLimits)
+var (
+	audioMaxBytes    int64 = 20 << 20
+	audioMaxDuration       = 10 * time.Minute
+)
+
+// SetAudioLimits remplace la taille et la durée maximales des fichiers audio (configuration AUDIO_UPLOAD_*)
+func SetAudioLimits(maxBytes int64, maxDuration time.Duration) {
+	if maxBytes > 0 {
+		audioMaxBytes = maxBytes
+	}
+	if maxDuration > 0 {
+		audioMaxDuration = maxDuration
+	}
+}
+
+// AudioMaxBytes taille maximale d'un fichier audio envoyé
+func AudioMaxBytes() int64 {
+	return audioMaxBytes
+}
+
+// Signature des URL de lecture (voir SetAudioStreamSigning) ; la clé aléatoire par défaut
+// invalide les URL déjà émises à chaque redémarrage
+var (
+	audioSigningKey = randomAudioSigningKey()
+	audioStreamTTL  = 15 * time.Minute
+)
+
+// SetAudioStreamSigning remplace la clé de signature et la validité des URL de lecture (configuration AUDIO_*)
+func SetAudioStreamSigning(secret string, ttl time.Duration) {
+	if secret != "" {
+		audioSigningKey = []byte(secret)
+	}
+	if ttl > 0 {
+		audioStreamTTL = ttl
+	}
+}
+
+// AudioStreamURL URL de lecture signée, utilisable sans session (balise <audio>, lecteur externe)
+type AudioStreamURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AudioService interface pour les fichiers audio envoyés par les utilisateurs
+type AudioService interface {
+	Upload(userID uint, file io.ReadSeeker, originalName string) (*models.AudioUpload, error)
+	GetAudio(id uint, viewerID *uint, isAdmin bool) (*models.AudioUpload, error)
+	SignStreamURL(id uint, viewerID *uint, isAdmin bool) (*AudioStreamURL, error)
+	OpenStream(id uint, viewerID *uint, isAdmin bool, expires, signature string) (*models.AudioUpload, *os.File, error)
+}
+
+// audioService implémentation du service
+type audioService struct {
+	audioRepo repositories.AudioRepository
+	dir       string
+}
+
+// NewAudioService crée une nouvelle instance du service
+func NewAudioService(audioRepo repositories.AudioRepository) AudioService {
+	return &audioService{
+		audioRepo: audioRepo,
+		dir:       models.AudioUploadDir,
+	}
+}
+
+// Upload vérifie un fichier audio d'après son contenu (format, taille, durée), lit ses tags
+// puis l'enregistre sous uploads/audio ; le titre et l'artiste servent à préremplir l'option
+func (s *audioService) Upload(userID uint, file io.ReadSeeker, originalName string) (*models.AudioUpload, error) {
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture du fichier audio: %w", err)
+	}
+	if size > audioMaxBytes {
+		return nil, fmt.Errorf("%w (maximum %d Mo)", utils.ErrAudioTooLarge, audioMaxBytes>>20)
+	}
+
+	info, err := audiofile.Probe(file)
+	if errors.Is(err, audiofile.ErrUnsupported) {
+		return nil, utils.ErrAudioUnsupported
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture du fichier audio: %w", err)
+	}
+	if info.Duration > audioMaxDuration {
+		return nil, fmt.Errorf("morceau trop long (maximum %s): %w", audioMaxDuration, utils.ErrInvalidInput)
+	}
+
+	fileName, err := audioFileName(info.Format)
+	if err != nil {
+		return nil, fmt.Errorf("erreur génération du nom de fichier: %w", err)
+	}
+	if err := s.store(file, fileName); err != nil {
+		return nil, err
+	}
+
+	audio := &models.AudioUpload{
+		UserID:       userID,
+		FileName:     fileName,
+		OriginalName: truncateRunes(filepath.Base(originalName), 255),
+		Format:       info.Format,
+		SizeBytes:    size,
+		DurationMs:   int(info.Duration.Milliseconds()),
+		Title:        truncateRunes(info.Title, 300),
+		Artist:       truncateRunes(info.Artist, 200),
+		Album:        truncateRunes(info.Album, 300),
+	}
+	if err := s.audioRepo.Create(audio); err != nil {
+		os.Remove(filepath.Join(s.dir, fileName))
+		return nil, err
+	}
+
+	log.Printf("🎵 Fichier audio %s envoyé par %d (%s, %s)", fileName, userID, info.Format, info.Duration.Round(time.Second))
+
+	// Forme d'onde et durée exacte calculées par le worker
+	WakeAudioAnalyzer()
+	return audio, nil
+}
+
+// GetAudio récupère un fichier audio que le visiteur peut écouter
+func (s *audioService) GetAudio(id uint, viewerID *uint, isAdmin bool) (*models.AudioUpload, error) {
+	audio, err := s.audioRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAccess(audio, viewerID, isAdmin); err != nil {
+		return nil, err
+	}
+	return audio, nil
+}
+
+// SignStreamURL émet une URL de lecture signée, valable le temps de lire le morceau en entier
+// (durée du morceau + AUDIO_STREAM_TTL_MINUTES)
+func (s *audioService) SignStreamURL(id uint, viewerID *uint, isAdmin bool) (*AudioStreamURL, error) {
+	audio, err := s.GetAudio(id, viewerID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(audioStreamTTL + time.Duration(audio.DurationMs)*time.Millisecond).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return &AudioStreamURL{
+		URL:       audio.URL + "?expires=" + expires + "&sig=" + audioSignature(audio.ID, expires),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// OpenStream ouvre un fichier audio à lire : une URL signée suffit, sinon le visiteur doit y avoir accès
+func (s *audioService) OpenStream(id uint, viewerID *uint, isAdmin bool, expires, signature string) (*models.AudioUpload, *os.File, error) {
+	var audio *models.AudioUpload
+	var err error
+	if signature != "" {
+		if err := verifyAudioSignature(id, expires, signature, time.Now()); err != nil {
+			return nil, nil, err
+		}
+		audio, err = s.audioRepo.FindByID(id)
+	} else {
+		audio, err = s.GetAudio(id, viewerID, isAdmin)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(filepath.Join(s.dir, audio.FileName))
+	if os.IsNotExist(err) {
+		log.Printf("⚠️ Fichier audio %s absent du disque", audio.FileName)
+		return nil, nil, utils.ErrAudioNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur ouverture du fichier audio: %w", err)
+	}
+	return audio, file, nil
+}
+
+// checkAccess vérifie que le visiteur peut écouter le fichier : l'auteur de l'envoi et les administrateurs
+// toujours, les autres une fois le fichier publié dans une battle ; sinon le fichier est « introuvable »
+func (s *audioService) checkAccess(audio *models.AudioUpload, viewerID *uint, isAdmin bool) error {
+	if isAdmin || (viewerID != nil && *viewerID == audio.UserID) {
+		return nil
+	}
+	published, err := s.audioRepo.IsPublished(audio.ID)
+	if err != nil {
+		return err
+	}
+	if !published {
+		return utils.ErrAudioNotFound
+	}
+	return nil
+}
+
+// store copie le fichier dans le dossier des fichiers audio
+func (s *audioService) store(file io.ReadSeeker, fileName string) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("erreur création du dossier audio: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("erreur lecture du fichier audio: %w", err)
+	}
+
+	path := filepath.Join(s.dir, fileName)
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("erreur création du fichier audio: %w", err)
+	}
+	_, err = io.Copy(dst, file)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("erreur sauvegarde du fichier audio: %w", err)
+	}
+	return nil
+}
+
+// audioSignature signe l'accès à un fichier audio jusqu'à expires (horodatage Unix)
+func audioSignature(id uint, expires string) string {
+	mac := hmac.New(sha256.New, audioSigningKey)
+	fmt.Fprintf(mac, "audio:%d:%s", id, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyAudioSignature vérifie une URL signée et son expiration
+func verifyAudioSignature(id uint, expires, signature string, now time.Time) error {
+	if !hmac.Equal([]byte(signature), []byte(audioSignature(id, expires))) {
+		return utils.ErrTokenInvalid
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return utils.ErrTokenInvalid
+	}
+	if now.Unix() > expiresAt {
+		return utils.ErrTokenExpired
+	}
+	return nil
+}
+
+// randomAudioSigningKey clé de signature utilisée tant qu'aucun secret n'est configuré
+func randomAudioSigningKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("génération de la clé de signature audio impossible: %v", err))
+	}
+	return key
+}
+
+// audioFileName génère un nom de fichier unique (horodatage + aléatoire) avec l'extension du format reconnu
+func audioFileName(format string) (string, error) {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d_%s.%s", time.Now().Unix(), hex.EncodeToString(bytes), format), nil
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeAudioRepository fichiers audio en mémoire
+type fakeAudioRepository struct {
+	created   []*models.AudioUpload
+	published map[uint]bool
+}
+
+func (r *fakeAudioRepository) Create(audio *models.AudioUpload) error {
+	audio.ID = uint(len(r.created) + 1)
+	audio.URL = models.AudioStreamPath(audio.ID)
+	audio.WaveformStatus = models.WaveformStatusPending
+	r.created = append(r.created, audio)
+	return nil
+}
+
+func (r *fakeAudioRepository) FindByID(id uint) (*models.AudioUpload, error) {
+	if id == 0 || int(id) > len(r.created) {
+		return nil, utils.ErrAudioNotFound
+	}
+	return r.created[id-1], nil
+}
+
+func (r *fakeAudioRepository) FindPendingAnalysis(limit, maxAttempts int) ([]*models.AudioUpload, error) {
+	var pending []*models.AudioUpload
+	for _, audio := range r.created {
+		if audio.WaveformStatus == models.WaveformStatusPending ||
+			(audio.WaveformStatus == models.WaveformStatusError && audio.AnalysisAttempts < maxAttempts) {
+			pending = append(pending, audio)
+		}
+	}
+	return pending, nil
+}
+
+func (r *fakeAudioRepository) SaveAnalysis(audio *models.AudioUpload) error { return nil }
+
+func (r *fakeAudioRepository) IsPublished(id uint) (bool, error) { return r.published[id], nil }
+
+// testWAV construit un WAV PCM 8 bits mono 8 kHz de la durée demandée, avec un titre INFO
+func testWAV(seconds int, title string) []byte {
+	value := append([]byte(title), 0)
+	if len(value)%2 == 1 {
+		value = append(value, 0)
+	}
+	var chunks bytes.Buffer
+	chunks.WriteString("WAVEfmt ")
+	binary.Write(&chunks, binary.LittleEndian, []uint32{16})
+	binary.Write(&chunks, binary.LittleEndian, []uint16{1, 1})
+	binary.Write(&chunks, binary.LittleEndian, []uint32{8000, 8000})
+	binary.Write(&chunks, binary.LittleEndian, []uint16{1, 8})
+	chunks.WriteString("LIST")
+	binary.Write(&chunks, binary.LittleEndian, uint32(12+len(value)))
+	chunks.WriteString("INFOINAM")
+	binary.Write(&chunks, binary.LittleEndian, uint32(len(value)))
+	chunks.Write(value)
+	chunks.WriteString("data")
+	binary.Write(&chunks, binary.LittleEndian, uint32(seconds*8000))
+	chunks.Write(make([]byte, seconds*8000))
+
+	var file bytes.Buffer
+	file.WriteString("RIFF")
+	binary.Write(&file, binary.LittleEndian, uint32(chunks.Len()))
+	file.Write(chunks.Bytes())
+	return file.Bytes()
+}
+
+func TestAudioServiceUpload(t *testing.T) {
+	SetAudioLimits(1<<20, time.Minute)
+	defer SetAudioLimits(20<<20, 10*time.Minute)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"WAV valide", testWAV(3, "Démo"), nil},
+		{"image renommée en .mp3", append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...), utils.ErrAudioUnsupported},
+		{"fichier trop volumineux", make([]byte, 1<<20+1), utils.ErrAudioTooLarge},
+		{"morceau trop long", testWAV(61, ""), utils.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAudioRepository{}
+			service := &audioService{audioRepo: repo, dir: t.TempDir()}
+
+			audio, err := service.Upload(7, bytes.NewReader(tt.data), "../morceau.mp3")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Erreur attendue: %v, Obtenue: %v", tt.wantErr, err)
+				}
+				if entries, _ := os.ReadDir(service.dir); len(entries) != 0 {
+					t.Errorf("Aucun fichier ne doit être écrit, Obtenu: %d", len(entries))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Erreur inattendue: %v", err)
+			}
+
+			if audio.Format != "wav" || audio.DurationMs != 3000 || audio.Title != "Démo" || audio.OriginalName != "morceau.mp3" {
+				t.Errorf("Fichier audio inattendu: %+v", audio)
+			}
+			if filepath.Ext(audio.FileName) != ".wav" {
+				t.Errorf("Extension attendue: .wav, Obtenu: %s", audio.FileName)
+			}
+			stored, err := os.ReadFile(filepath.Join(service.dir, audio.FileName))
+			if err != nil || !bytes.Equal(stored, tt.data) {
+				t.Errorf("Contenu enregistré différent du fichier envoyé (%v)", err)
+			}
+		})
+	}
+}
+
+func TestAudioServiceAccess(t *testing.T) {
+	uploader, other := uint(7), uint(8)
+	repo := &fakeAudioRepository{published: map[uint]bool{2: true}}
+	service := &audioService{audioRepo: repo, dir: t.TempDir()}
+	for i := 0; i < 2; i++ {
+		if _, err := service.Upload(uploader, bytes.NewReader(testWAV(1, "")), "demo.wav"); err != nil {
+			t.Fatalf("Erreur inattendue: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		audioID  uint
+		viewerID *uint
+		isAdmin  bool
+		wantErr  error
+	}{
+		{"auteur, fichier non publié", 1, &uploader, false, nil},
+		{"administrateur, fichier non publié", 1, &other, true, nil},
+		{"autre utilisateur, fichier non publié", 1, &other, false, utils.ErrAudioNotFound},
+		{"visiteur anonyme, fichier non publié", 1, nil, false, utils.ErrAudioNotFound},
+		{"visiteur anonyme, fichier publié dans une battle", 2, nil, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, file, err := service.OpenStream(tt.audioID, tt.viewerID, tt.isAdmin, "", "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Erreur attendue: %v, Obtenue: %v", tt.wantErr, err)
+			}
+			if file != nil {
+				file.Close()
+			}
+		})
+	}
+}
+
+func TestVerifyAudioSignature(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	valid := strconv.FormatInt(now.Add(time.Minute).Unix(), 10)
+	past := strconv.FormatInt(now.Add(-time.Second).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		audioID   uint
+		expires   string
+		signature string
+		wantErr   error
+	}{
+		{"signature valide", 1, valid, audioSignature(1, valid), nil},
+		{"lien expiré", 1, past, audioSignature(1, past), utils.ErrTokenExpired},
+		{"expiration modifiée", 1, valid + "0", audioSignature(1, valid), utils.ErrTokenInvalid},
+		{"signature d'un autre fichier", 2, valid, audioSignature(1, valid), utils.ErrTokenInvalid},
+		{"signature vide", 1, valid, "", utils.ErrTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyAudioSignature(tt.audioID, tt.expires, tt.signature, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Erreur attendue: %v, Obtenue: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAudioAnalyzerAnalyzePending(t *testing.T) {
+	dir := t.TempDir()
+	repo := &fakeAudioRepository{}
+	service := &audioService{audioRepo: repo, dir: dir}
+
+	audio, err := service.Upload(7, bytes.NewReader(testWAV(2, "")), "demo.wav")
+	if err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+	missing := &models.AudioUpload{FileName: "absent.wav", WaveformStatus: models.WaveformStatusPending}
+	repo.created = append(repo.created, missing)
+
+	analyzer := NewAudioAnalyzer(repo, dir, 10, 5)
+	for i := 0; i < audioAnalysisMaxAttempts+1; i++ {
+		if _, err := analyzer.AnalyzePending(); err != nil {
+			t.Fatalf("Erreur inattendue: %v", err)
+		}
+	}
+
+	if audio.WaveformStatus != models.WaveformStatusOK || len(audio.Waveform) != 10 || audio.DurationMs != 2000 || audio.WaveformEstimated {
+		t.Errorf("Analyse du WAV inattendue: %+v", audio)
+	}
+	if missing.WaveformStatus != models.WaveformStatusError || missing.AnalysisAttempts != audioAnalysisMaxAttempts {
+		t.Errorf("Attendu: %d essais en erreur, Obtenu: %s après %d essais", audioAnalysisMaxAttempts, missing.WaveformStatus, missing.AnalysisAttempts)
+	}
+}
+
+func TestRoundPeaks(t *testing.T) {
+	got := roundPeaks([]float64{0, 0.12345, 0.9996, 1})
+	want := []float64{0, 0.123, 1, 1}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Attendu: %v, Obtenu: %v", want, got)
+		}
+	}
+}
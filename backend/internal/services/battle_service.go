@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"strings"
+)
+
+// BattleService interface pour la composition des battles de musique
+type BattleService interface {
+	AddOption(battleID, userID uint, dto AddBattleOptionDTO) (*models.BattleOption, error)
+}
+
+// AddBattleOptionDTO musique proposée dans une battle : un lien musical reconnu ou un fichier audio
+// envoyé par l'utilisateur (AudioID) dont les tags préremplissent le titre et l'artiste
+type AddBattleOptionDTO struct {
+	Title    string `json:"title"`
+	Artist   string `json:"artist"`
+	MusicURL string `json:"music_url"`
+	ImageURL string `json:"image_url"`
+	AudioID  *uint  `json:"audio_id"`
+}
+
+// battleService implémentation du service
+type battleService struct {
+	battleRepo repositories.BattleRepository
+}
+
+// NewBattleService crée une nouvelle instance du service
+func NewBattleService(battleRepo repositories.BattleRepository) BattleService {
+	return &battleService{
+		battleRepo: battleRepo,
+	}
+}
+
+// AddOption ajoute une musique à une battle ; seul son créateur compose la battle, avec ses propres fichiers audio
+func (s *battleService) AddOption(battleID, userID uint, dto AddBattleOptionDTO) (*models.BattleOption, error) {
+	creatorID, err := s.battleRepo.FindCreatorID(battleID)
+	if err != nil {
+		return nil, err
+	}
+	if creatorID != userID {
+		return nil, utils.ErrUnauthorized
+	}
+
+	option := &models.BattleOption{
+		BattleID: battleID,
+		Title:    truncateRunes(strings.TrimSpace(dto.Title), 200),
+		Artist:   truncateRunes(strings.TrimSpace(dto.Artist), 200),
+		MusicURL: strings.TrimSpace(dto.MusicURL),
+		ImageURL: strings.TrimSpace(dto.ImageURL),
+		AudioID:  dto.AudioID,
+	}
+	if option.AudioID == nil && (option.MusicURL == "" || option.Title == "") {
+		return nil, fmt.Errorf("un titre et un lien musical ou un fichier audio sont requis: %w", utils.ErrInvalidInput)
+	}
+
+	if err := s.battleRepo.AddOption(option, userID); err != nil {
+		return nil, err
+	}
+	return option, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"testing"
+)
+
+// fakeBattleRepository battles en mémoire ; les fichiers audio appartiennent à audioOwners
+type fakeBattleRepository struct {
+	repositories.BattleRepository
+	creators    map[uint]uint
+	audioOwners map[uint]uint
+	options     []*models.BattleOption
+}
+
+func (r *fakeBattleRepository) FindCreatorID(id uint) (uint, error) {
+	creatorID, ok := r.creators[id]
+	if !ok {
+		return 0, utils.ErrBattleNotFound
+	}
+	return creatorID, nil
+}
+
+func (r *fakeBattleRepository) AddOption(option *models.BattleOption, creatorID uint) error {
+	if option.AudioID != nil && r.audioOwners[*option.AudioID] != creatorID {
+		return utils.ErrAudioNotFound
+	}
+	r.options = append(r.options, option)
+	return nil
+}
+
+func TestAddBattleOption(t *testing.T) {
+	ownAudio, otherAudio := uint(10), uint(11)
+
+	tests := []struct {
+		name     string
+		battleID uint
+		userID   uint
+		dto      AddBattleOptionDTO
+		wantErr  error
+	}{
+		{"lien musical", 1, 3, AddBattleOptionDTO{Title: "Strobe", MusicURL: "https://open.spotify.com/track/1"}, nil},
+		{"fichier audio du créateur", 1, 3, AddBattleOptionDTO{AudioID: &ownAudio}, nil},
+		{"fichier audio d'un autre utilisateur", 1, 3, AddBattleOptionDTO{AudioID: &otherAudio}, utils.ErrAudioNotFound},
+		{"pas le créateur de la battle", 1, 4, AddBattleOptionDTO{AudioID: &otherAudio}, utils.ErrUnauthorized},
+		{"battle inconnue", 2, 3, AddBattleOptionDTO{AudioID: &ownAudio}, utils.ErrBattleNotFound},
+		{"ni lien ni fichier", 1, 3, AddBattleOptionDTO{Title: "Strobe"}, utils.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeBattleRepository{
+				creators:    map[uint]uint{1: 3},
+				audioOwners: map[uint]uint{ownAudio: 3, otherAudio: 4},
+			}
+			service := NewBattleService(repo)
+
+			_, err := service.AddOption(tt.battleID, tt.userID, tt.dto)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Erreur attendue: %v, Obtenue: %v", tt.wantErr, err)
+			}
+			if want := tt.wantErr == nil; (len(repo.options) == 1) != want {
+				t.Errorf("Options enregistrées: %d", len(repo.options))
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"rythmitbackend/internal/repositories"
+)
+
+// BookmarkService interface pour les threads mis en favoris
+type BookmarkService interface {
+	AddBookmark(threadID, userID uint) error
+	RemoveBookmark(threadID, userID uint) error
+	IsBookmarked(threadID, userID uint) (bool, error)
+	GetBookmarks(userID uint) ([]*ThreadResponseDTO, error)
+}
+
+// bookmarkService implémentation concrète
+type bookmarkService struct {
+	bookmarkRepo  repositories.BookmarkRepository
+	threadService ThreadService
+}
+
+// NewBookmarkService crée une nouvelle instance du service
+func NewBookmarkService(bookmarkRepo repositories.BookmarkRepository, threadService ThreadService) BookmarkService {
+	return &bookmarkService{
+		bookmarkRepo:  bookmarkRepo,
+		threadService: threadService,
+	}
+}
+
+// AddBookmark met en favori un thread que l'utilisateur peut consulter
+func (s *bookmarkService) AddBookmark(threadID, userID uint) error {
+	if _, err := s.threadService.GetThread(threadID, &userID); err != nil {
+		return err
+	}
+
+	return s.bookmarkRepo.Add(userID, threadID)
+}
+
+// RemoveBookmark retire un thread des favoris (sans erreur s'il n'y était pas)
+func (s *bookmarkService) RemoveBookmark(threadID, userID uint) error {
+	_, err := s.bookmarkRepo.Remove(userID, threadID)
+	return err
+}
+
+// IsBookmarked vérifie si un thread est dans les favoris d'un utilisateur
+func (s *bookmarkService) IsBookmarked(threadID, userID uint) (bool, error) {
+	return s.bookmarkRepo.IsBookmarked(userID, threadID)
+}
+
+// GetBookmarks récupère les favoris d'un utilisateur encore accessibles
+func (s *bookmarkService) GetBookmarks(userID uint) ([]*ThreadResponseDTO, error) {
+	threads, err := s.bookmarkRepo.FindThreadsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks := make([]*ThreadResponseDTO, 0, len(threads))
+	for _, thread := range threads {
+		bookmarks = append(bookmarks, newThreadResponseDTO(thread))
+	}
+
+	return bookmarks, nil
+}
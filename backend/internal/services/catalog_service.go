@@ -0,0 +1,439 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/musiclink"
+	"strings"
+	"time"
+)
+
+// Limites des classements du catalogue
+const (
+	defaultChartDays  = 30
+	maxChartDays      = 365
+	defaultChartLimit = 20
+	maxChartLimit     = 100
+	maxCatalogThreads = 20
+)
+
+var (
+	// isrcRegex code ISRC sans tirets : pays, déclarant, année, numéro
+	isrcRegex = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$`)
+	// upcRegex code UPC ou EAN d'un album
+	upcRegex = regexp.MustCompile(`^[0-9]{12,14}$`)
+)
+
+// CatalogService interface pour le catalogue musical commun aux plateformes
+type CatalogService interface {
+	RegisterLink(userID uint, dto RegisterCatalogLinkDTO) (*CatalogEntryDTO, error)
+	LinkToEntry(catalogType string, catalogID, userID uint, isAdmin bool, rawURL string) (*CatalogEntryDTO, error)
+	UnlinkLink(rawURL string, isAdmin bool) error
+	LookupLink(rawURL string, viewerID *uint) (*CatalogEntryDTO, error)
+	GetEntry(catalogType string, catalogID uint, viewerID *uint) (*CatalogEntryDTO, error)
+	GetCharts(catalogType string, days, limit int) ([]*models.CatalogChartEntry, error)
+	GetPreferredPlatform(userID uint) (string, error)
+	SetPreferredPlatform(userID uint, platform string) error
+}
+
+// RegisterCatalogLinkDTO lien d'une plateforme et métadonnées servant au rapprochement
+type RegisterCatalogLinkDTO struct {
+	URL    string `json:"url"`
+	Title  string `json:"title"`  // titre du morceau ou de l'album (ignoré pour un artiste)
+	Artist string `json:"artist"` // nom de l'artiste principal
+	Album  string `json:"album,omitempty"`
+	ISRC   string `json:"isrc,omitempty"` // morceau uniquement
+	UPC    string `json:"upc,omitempty"`  // album uniquement
+}
+
+// CatalogEntryDTO entrée du catalogue avec ses liens sur toutes les plateformes
+type CatalogEntryDTO struct {
+	Type         string                     `json:"type"`
+	ID           uint                       `json:"id"`
+	Title        string                     `json:"title"` // nom pour un artiste
+	Artist       *CatalogRefDTO             `json:"artist,omitempty"`
+	Album        *CatalogRefDTO             `json:"album,omitempty"`
+	ISRC         *string                    `json:"isrc,omitempty"`
+	UPC          *string                    `json:"upc,omitempty"`
+	Links        []*models.CatalogLink      `json:"links"`
+	PreferredURL string                     `json:"preferred_url,omitempty"` // plateforme préférée du lecteur, sinon premier lien
+	Threads      []*models.CatalogThreadRef `json:"threads"`
+	Battles      []*models.CatalogBattleRef `json:"battles"`
+}
+
+// CatalogRefDTO référence vers une autre entrée du catalogue
+type CatalogRefDTO struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+// catalogService implémentation concrète
+type catalogService struct {
+	catalogRepo repositories.CatalogRepository
+}
+
+// NewCatalogService crée une nouvelle instance du service
+func NewCatalogService(catalogRepo repositories.CatalogRepository) CatalogService {
+	return &catalogService{
+		catalogRepo: catalogRepo,
+	}
+}
+
+// RegisterLink rattache un lien au catalogue : par ISRC (ou UPC), sinon par titre et artiste
+// normalisés, sinon en créant l'entrée ; un lien déjà rattaché garde son entrée
+func (s *catalogService) RegisterLink(userID uint, dto RegisterCatalogLinkDTO) (*CatalogEntryDTO, error) {
+	link, err := parseCatalogURL(dto.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing, err := s.catalogRepo.FindLink(link.Platform, link.Type, link.ID); err == nil {
+		return s.GetEntry(existing.EntityType, existing.CatalogID, &userID)
+	} else if !errors.Is(err, utils.ErrCatalogEntryNotFound) {
+		return nil, err
+	}
+
+	if err := validateCatalogLinkDTO(link.Type, &dto); err != nil {
+		return nil, err
+	}
+
+	catalogID, matchedBy, err := s.matchEntry(link.Type, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.catalogRepo.SaveLink(&models.CatalogLink{
+		Platform:   link.Platform,
+		EntityType: link.Type,
+		EntityID:   link.ID,
+		CatalogID:  catalogID,
+		URL:        link.URL,
+		MatchedBy:  matchedBy,
+		CreatedBy:  &userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetEntry(link.Type, catalogID, &userID)
+}
+
+// LinkToEntry rattache un lien à la main à une entrée existante ; seul un admin peut
+// déplacer un lien déjà rattaché à une autre entrée
+func (s *catalogService) LinkToEntry(catalogType string, catalogID, userID uint, isAdmin bool, rawURL string) (*CatalogEntryDTO, error) {
+	link, err := parseCatalogURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if link.Type != catalogType {
+		return nil, fmt.Errorf("un lien de type %s ne peut pas être rattaché à un %s: %w", link.Type, catalogType, utils.ErrInvalidInput)
+	}
+
+	if _, err := s.GetEntry(catalogType, catalogID, &userID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.catalogRepo.FindLink(link.Platform, link.Type, link.ID)
+	if err != nil && !errors.Is(err, utils.ErrCatalogEntryNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.CatalogID != catalogID && !isAdmin {
+		return nil, utils.ErrCatalogLinkTaken
+	}
+
+	err = s.catalogRepo.SaveLink(&models.CatalogLink{
+		Platform:   link.Platform,
+		EntityType: link.Type,
+		EntityID:   link.ID,
+		CatalogID:  catalogID,
+		URL:        link.URL,
+		MatchedBy:  models.CatalogMatchManual,
+		CreatedBy:  &userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetEntry(catalogType, catalogID, &userID)
+}
+
+// UnlinkLink détache un lien de son entrée (admin uniquement), par exemple après un mauvais rapprochement
+func (s *catalogService) UnlinkLink(rawURL string, isAdmin bool) error {
+	if !isAdmin {
+		return utils.ErrUnauthorized
+	}
+
+	link, err := parseCatalogURL(rawURL)
+	if err != nil {
+		return err
+	}
+
+	return s.catalogRepo.DeleteLink(link.Platform, link.Type, link.ID)
+}
+
+// LookupLink retrouve l'entrée du catalogue d'un lien, quelle que soit la forme de l'URL
+func (s *catalogService) LookupLink(rawURL string, viewerID *uint) (*CatalogEntryDTO, error) {
+	link, err := parseCatalogURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.catalogRepo.FindLink(link.Platform, link.Type, link.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetEntry(existing.EntityType, existing.CatalogID, viewerID)
+}
+
+// GetEntry récupère une entrée avec ses liens, les threads visibles qui la citent et les battles qui la proposent
+func (s *catalogService) GetEntry(catalogType string, catalogID uint, viewerID *uint) (*CatalogEntryDTO, error) {
+	entry := &CatalogEntryDTO{Type: catalogType, ID: catalogID}
+
+	switch catalogType {
+	case musiclink.TypeTrack:
+		track, err := s.catalogRepo.FindTrack(catalogID)
+		if err != nil {
+			return nil, err
+		}
+		entry.Title = track.Title
+		entry.Artist = &CatalogRefDTO{ID: track.ArtistID, Title: track.ArtistName}
+		if track.AlbumID != nil && track.AlbumTitle != nil {
+			entry.Album = &CatalogRefDTO{ID: *track.AlbumID, Title: *track.AlbumTitle}
+		}
+		entry.ISRC = track.ISRC
+	case musiclink.TypeAlbum:
+		album, err := s.catalogRepo.FindAlbum(catalogID)
+		if err != nil {
+			return nil, err
+		}
+		entry.Title = album.Title
+		entry.Artist = &CatalogRefDTO{ID: album.ArtistID, Title: album.ArtistName}
+		entry.UPC = album.UPC
+	case musiclink.TypeArtist:
+		artist, err := s.catalogRepo.FindArtist(catalogID)
+		if err != nil {
+			return nil, err
+		}
+		entry.Title = artist.Name
+	default:
+		return nil, fmt.Errorf("type d'entrée inconnu %q: %w", catalogType, utils.ErrInvalidInput)
+	}
+
+	links, err := s.catalogRepo.FindLinksByEntry(catalogType, catalogID)
+	if err != nil {
+		return nil, err
+	}
+	entry.Links = links
+
+	preferred := ""
+	if viewerID != nil {
+		if preferred, err = s.catalogRepo.GetPreferredPlatform(*viewerID); err != nil {
+			return nil, err
+		}
+	}
+	entry.PreferredURL = preferredLinkURL(links, preferred)
+
+	if entry.Threads, err = s.catalogRepo.FindThreads(catalogType, catalogID, viewerID, maxCatalogThreads); err != nil {
+		return nil, err
+	}
+	if entry.Battles, err = s.catalogRepo.FindBattles(catalogType, catalogID); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// GetCharts classe les entrées d'un type les plus citées sur les derniers jours, toutes plateformes confondues
+func (s *catalogService) GetCharts(catalogType string, days, limit int) ([]*models.CatalogChartEntry, error) {
+	switch catalogType {
+	case musiclink.TypeTrack, musiclink.TypeAlbum, musiclink.TypeArtist:
+	default:
+		return nil, fmt.Errorf("un classement porte sur des morceaux, des albums ou des artistes: %w", utils.ErrInvalidInput)
+	}
+
+	if days <= 0 {
+		days = defaultChartDays
+	}
+	if days > maxChartDays {
+		days = maxChartDays
+	}
+	if limit <= 0 {
+		limit = defaultChartLimit
+	}
+	if limit > maxChartLimit {
+		limit = maxChartLimit
+	}
+
+	return s.catalogRepo.Charts(catalogType, time.Now().AddDate(0, 0, -days), limit)
+}
+
+// GetPreferredPlatform récupère la plateforme préférée d'un utilisateur ("" s'il n'en a pas choisi)
+func (s *catalogService) GetPreferredPlatform(userID uint) (string, error) {
+	return s.catalogRepo.GetPreferredPlatform(userID)
+}
+
+// SetPreferredPlatform choisit la plateforme sur laquelle ouvrir les liens ("" pour revenir aux liens d'origine)
+func (s *catalogService) SetPreferredPlatform(userID uint, platform string) error {
+	if platform != "" && !musiclink.IsPlatform(platform) {
+		return fmt.Errorf("plateforme inconnue %q: %w", platform, utils.ErrInvalidInput)
+	}
+	return s.catalogRepo.SetPreferredPlatform(userID, platform)
+}
+
+// matchEntry trouve ou crée l'entrée d'un lien et indique comment elle a été rapprochée
+func (s *catalogService) matchEntry(entityType string, dto RegisterCatalogLinkDTO) (uint, string, error) {
+	if entityType == musiclink.TypeTrack && dto.ISRC != "" {
+		track, err := s.catalogRepo.FindTrackByISRC(dto.ISRC)
+		if err == nil {
+			return track.ID, models.CatalogMatchISRC, nil
+		}
+		if !errors.Is(err, utils.ErrCatalogEntryNotFound) {
+			return 0, "", err
+		}
+	}
+	if entityType == musiclink.TypeAlbum && dto.UPC != "" {
+		album, err := s.catalogRepo.FindAlbumByUPC(dto.UPC)
+		if err == nil {
+			return album.ID, models.CatalogMatchISRC, nil
+		}
+		if !errors.Is(err, utils.ErrCatalogEntryNotFound) {
+			return 0, "", err
+		}
+	}
+
+	artist, created, err := s.catalogRepo.FindOrCreateArtist(dto.Artist)
+	if err != nil {
+		return 0, "", err
+	}
+
+	switch entityType {
+	case musiclink.TypeArtist:
+		if created {
+			return artist.ID, models.CatalogMatchManual, nil
+		}
+		return artist.ID, models.CatalogMatchTitleArtist, nil
+	case musiclink.TypeAlbum:
+		album, matched, err := s.findOrCreateAlbum(artist.ID, dto.Title, dto.UPC)
+		if err != nil {
+			return 0, "", err
+		}
+		if matched {
+			return album.ID, models.CatalogMatchTitleArtist, nil
+		}
+		return album.ID, models.CatalogMatchManual, nil
+	}
+
+	matchKey := models.CatalogMatchKey(dto.Title)
+	track, err := s.catalogRepo.FindTrackByTitle(artist.ID, matchKey)
+	if err == nil {
+		return track.ID, models.CatalogMatchTitleArtist, nil
+	}
+	if !errors.Is(err, utils.ErrCatalogEntryNotFound) {
+		return 0, "", err
+	}
+
+	track = &models.CatalogTrack{ArtistID: artist.ID, Title: dto.Title, MatchKey: matchKey}
+	if dto.ISRC != "" {
+		track.ISRC = &dto.ISRC
+	}
+	if dto.Album != "" {
+		album, _, err := s.findOrCreateAlbum(artist.ID, dto.Album, "")
+		if err != nil {
+			return 0, "", err
+		}
+		track.AlbumID = &album.ID
+	}
+
+	if err := s.catalogRepo.CreateTrack(track); err != nil {
+		return 0, "", err
+	}
+	return track.ID, models.CatalogMatchManual, nil
+}
+
+// findOrCreateAlbum trouve l'album d'un artiste par titre normalisé, ou le crée (matched vaut alors false)
+func (s *catalogService) findOrCreateAlbum(artistID uint, title, upc string) (*models.CatalogAlbum, bool, error) {
+	matchKey := models.CatalogMatchKey(title)
+	album, err := s.catalogRepo.FindAlbumByTitle(artistID, matchKey)
+	if err == nil {
+		return album, true, nil
+	}
+	if !errors.Is(err, utils.ErrCatalogEntryNotFound) {
+		return nil, false, err
+	}
+
+	album = &models.CatalogAlbum{ArtistID: artistID, Title: title, MatchKey: matchKey}
+	if upc != "" {
+		album.UPC = &upc
+	}
+	if err := s.catalogRepo.CreateAlbum(album); err != nil {
+		return nil, false, err
+	}
+	return album, false, nil
+}
+
+// parseCatalogURL reconnaît un lien pouvant entrer au catalogue (les playlists n'y entrent pas)
+func parseCatalogURL(rawURL string) (*musiclink.Link, error) {
+	link, err := musiclink.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, utils.ErrInvalidInput)
+	}
+	if link.Type == musiclink.TypePlaylist {
+		return nil, fmt.Errorf("les playlists ne font pas partie du catalogue: %w", utils.ErrInvalidInput)
+	}
+	return link, nil
+}
+
+// validateCatalogLinkDTO nettoie et valide les métadonnées d'un lien selon son type
+func validateCatalogLinkDTO(entityType string, dto *RegisterCatalogLinkDTO) error {
+	dto.Artist = strings.TrimSpace(dto.Artist)
+	dto.Title = strings.TrimSpace(dto.Title)
+	dto.Album = strings.TrimSpace(dto.Album)
+	dto.ISRC = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(dto.ISRC), "-", ""))
+	dto.UPC = strings.TrimSpace(dto.UPC)
+
+	if entityType == musiclink.TypeArtist {
+		dto.Title, dto.Album, dto.ISRC, dto.UPC = "", "", "", ""
+	}
+	if entityType == musiclink.TypeAlbum {
+		dto.Album, dto.ISRC = "", ""
+	}
+	if entityType == musiclink.TypeTrack {
+		dto.UPC = ""
+	}
+
+	if models.CatalogMatchKey(dto.Artist) == "" || len(dto.Artist) > 200 {
+		return fmt.Errorf("le nom de l'artiste doit faire entre 1 et 200 caractères: %w", utils.ErrInvalidInput)
+	}
+	if entityType != musiclink.TypeArtist && (models.CatalogMatchKey(dto.Title) == "" || len(dto.Title) > 300) {
+		return fmt.Errorf("le titre doit faire entre 1 et 300 caractères: %w", utils.ErrInvalidInput)
+	}
+	if len(dto.Album) > 300 || (dto.Album != "" && models.CatalogMatchKey(dto.Album) == "") {
+		return fmt.Errorf("titre d'album invalide (1 à 300 caractères): %w", utils.ErrInvalidInput)
+	}
+	if dto.ISRC != "" && !isrcRegex.MatchString(dto.ISRC) {
+		return fmt.Errorf("code ISRC invalide: %w", utils.ErrInvalidInput)
+	}
+	if dto.UPC != "" && !upcRegex.MatchString(dto.UPC) {
+		return fmt.Errorf("code UPC invalide: %w", utils.ErrInvalidInput)
+	}
+
+	return nil
+}
+
+// preferredLinkURL lien d'une entrée sur la plateforme préférée, sinon le premier lien
+func preferredLinkURL(links []*models.CatalogLink, platform string) string {
+	if len(links) == 0 {
+		return ""
+	}
+	for _, link := range links {
+		if link.Platform == platform {
+			return link.URL
+		}
+	}
+	return links[0].URL
+}
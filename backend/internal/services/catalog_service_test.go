@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+	"testing"
+)
+
+func TestCatalogMatchKey(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"casse et ponctuation", "Get Lucky!", "get lucky"},
+		{"remaster entre parenthèses", "Paranoid Android (Remastered 2017)", "paranoid android"},
+		{"remaster après un tiret", "Here Comes The Sun - 2019 Mix Remaster", "here comes the sun"},
+		{"invité entre crochets", "Get Lucky [feat. Pharrell Williams]", "get lucky"},
+		{"invité sans parenthèses", "Get Lucky feat. Pharrell Williams", "get lucky"},
+		{"live conservé", "Creep (Live)", "creep live"},
+		{"remix conservé", "Windowlicker - Remix", "windowlicker remix"},
+		{"accents conservés", "Beyoncé", "beyoncé"},
+		{"que de la ponctuation", "?!", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := models.CatalogMatchKey(c.value); got != c.want {
+				t.Errorf("Attendu: %q, Obtenu: %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateCatalogLinkDTO(t *testing.T) {
+	cases := []struct {
+		name       string
+		entityType string
+		dto        RegisterCatalogLinkDTO
+		wantErr    bool
+	}{
+		{"morceau avec ISRC", "track", RegisterCatalogLinkDTO{Title: "One More Time", Artist: "Daft Punk", ISRC: "gb-dup-00-00001"}, false},
+		{"album avec UPC", "album", RegisterCatalogLinkDTO{Title: "Discovery", Artist: "Daft Punk", UPC: "724384960650"}, false},
+		{"artiste sans titre", "artist", RegisterCatalogLinkDTO{Artist: "Daft Punk"}, false},
+		{"artiste manquant", "track", RegisterCatalogLinkDTO{Title: "One More Time", Artist: " "}, true},
+		{"titre manquant", "album", RegisterCatalogLinkDTO{Artist: "Daft Punk"}, true},
+		{"ISRC invalide", "track", RegisterCatalogLinkDTO{Title: "One More Time", Artist: "Daft Punk", ISRC: "123"}, true},
+		{"UPC invalide", "album", RegisterCatalogLinkDTO{Title: "Discovery", Artist: "Daft Punk", UPC: "abc"}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateCatalogLinkDTO(c.entityType, &c.dto)
+			if (err != nil) != c.wantErr {
+				t.Errorf("Erreur attendue: %v, Obtenu: %v", c.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, utils.ErrInvalidInput) {
+				t.Errorf("Erreur non typée ErrInvalidInput: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateCatalogLinkDTONormalizes(t *testing.T) {
+	dto := RegisterCatalogLinkDTO{Title: " One More Time ", Artist: "Daft Punk", ISRC: "gb-dup-00-00001", UPC: "724384960650"}
+	if err := validateCatalogLinkDTO("track", &dto); err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+
+	if dto.Title != "One More Time" || dto.ISRC != "GBDUP0000001" || dto.UPC != "" {
+		t.Errorf("Métadonnées mal nettoyées: %+v", dto)
+	}
+}
+
+func TestPreferredLinkURL(t *testing.T) {
+	links := []*models.CatalogLink{
+		{Platform: "deezer", URL: "https://www.deezer.com/track/3135556"},
+		{Platform: "spotify", URL: "https://open.spotify.com/track/0DiWol3AO6WpXZgp0goxAV"},
+	}
+
+	if got := preferredLinkURL(links, "spotify"); got != links[1].URL {
+		t.Errorf("Plateforme préférée ignorée: %s", got)
+	}
+	if got := preferredLinkURL(links, "tidal"); got != links[0].URL {
+		t.Errorf("Premier lien attendu sans lien sur la plateforme préférée: %s", got)
+	}
+	if got := preferredLinkURL(nil, "spotify"); got != "" {
+		t.Errorf("Aucun lien attendu: %s", got)
+	}
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"strings"
+)
+
+// CollectionService interface pour les collections de threads des utilisateurs
+type CollectionService interface {
+	CreateCollection(userID uint, dto CollectionDTO) (*CollectionResponseDTO, error)
+	UpdateCollection(id, userID uint, dto CollectionDTO) (*CollectionResponseDTO, error)
+	DeleteCollection(id, userID uint) error
+	GetCollection(id uint, viewerID *uint) (*CollectionResponseDTO, error)
+	GetUserCollections(ownerID uint, viewerID *uint) ([]*CollectionResponseDTO, error)
+	AddItem(collectionID, userID uint, dto CollectionItemDTO) error
+	UpdateItemNote(collectionID, threadID, userID uint, note *string) error
+	RemoveItem(collectionID, threadID, userID uint) error
+	ReorderItems(collectionID, userID uint, threadIDs []uint) error
+}
+
+// CollectionDTO données de création ou de modification d'une collection
+type CollectionDTO struct {
+	Name        string  `json:"name" validate:"required,min=1,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+	Visibility  string  `json:"visibility" validate:"omitempty,oneof=public privé"`
+}
+
+// CollectionItemDTO thread à ajouter dans une collection
+type CollectionItemDTO struct {
+	ThreadID uint    `json:"thread_id" validate:"required"`
+	Note     *string `json:"note" validate:"omitempty,max=500"`
+}
+
+// CollectionResponseDTO collection telle que renvoyée par l'API
+type CollectionResponseDTO struct {
+	ID          uint                        `json:"id"`
+	Name        string                      `json:"name"`
+	Description *string                     `json:"description,omitempty"`
+	Visibility  string                      `json:"visibility"`
+	ItemCount   int                         `json:"item_count"`
+	Owner       UserSummaryDTO              `json:"owner"`
+	CreatedAt   string                      `json:"created_at"`
+	UpdatedAt   string                      `json:"updated_at"`
+	Items       []CollectionItemResponseDTO `json:"items,omitempty"` // uniquement pour le détail d'une collection
+}
+
+// CollectionItemResponseDTO élément d'une collection avec son thread
+type CollectionItemResponseDTO struct {
+	Position int               `json:"position"`
+	Note     *string           `json:"note,omitempty"`
+	AddedAt  string            `json:"added_at"`
+	Thread   ThreadResponseDTO `json:"thread"`
+}
+
+// collectionService implémentation concrète
+type collectionService struct {
+	collectionRepo repositories.CollectionRepository
+	threadService  ThreadService
+}
+
+// NewCollectionService crée une nouvelle instance du service
+func NewCollectionService(collectionRepo repositories.CollectionRepository, threadService ThreadService) CollectionService {
+	return &collectionService{
+		collectionRepo: collectionRepo,
+		threadService:  threadService,
+	}
+}
+
+// CreateCollection crée une collection pour l'utilisateur
+func (s *collectionService) CreateCollection(userID uint, dto CollectionDTO) (*CollectionResponseDTO, error) {
+	if err := s.validateCollectionDTO(userID, 0, &dto); err != nil {
+		return nil, err
+	}
+
+	collection := &models.Collection{
+		UserID:      userID,
+		Name:        dto.Name,
+		Description: dto.Description,
+		Visibility:  dto.Visibility,
+	}
+
+	if err := s.collectionRepo.Create(collection); err != nil {
+		return nil, err
+	}
+
+	return s.GetCollection(collection.ID, &userID)
+}
+
+// UpdateCollection modifie une collection appartenant à l'utilisateur
+func (s *collectionService) UpdateCollection(id, userID uint, dto CollectionDTO) (*CollectionResponseDTO, error) {
+	collection, err := s.findOwnedCollection(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateCollectionDTO(userID, id, &dto); err != nil {
+		return nil, err
+	}
+
+	collection.Name = dto.Name
+	collection.Description = dto.Description
+	collection.Visibility = dto.Visibility
+
+	if err := s.collectionRepo.Update(collection); err != nil {
+		return nil, err
+	}
+
+	return s.GetCollection(id, &userID)
+}
+
+// DeleteCollection supprime une collection appartenant à l'utilisateur
+func (s *collectionService) DeleteCollection(id, userID uint) error {
+	if _, err := s.findOwnedCollection(id, userID); err != nil {
+		return err
+	}
+
+	return s.collectionRepo.Delete(id)
+}
+
+// GetCollection récupère une collection et ses éléments visibles par viewerID ;
+// une collection privée n'existe que pour son propriétaire
+func (s *collectionService) GetCollection(id uint, viewerID *uint) (*CollectionResponseDTO, error) {
+	collection, err := s.collectionRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canViewCollection(collection, viewerID) {
+		return nil, utils.ErrCollectionNotFound
+	}
+
+	items, err := s.collectionRepo.FindItems(id, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := collectionToDTO(collection)
+	dto.Items = make([]CollectionItemResponseDTO, 0, len(items))
+	for _, item := range items {
+		dto.Items = append(dto.Items, CollectionItemResponseDTO{
+			Position: item.Position,
+			Note:     item.Note,
+			AddedAt:  item.AddedAt.Format("2006-01-02T15:04:05Z"),
+			Thread:   *newThreadResponseDTO(item.Thread),
+		})
+	}
+	// Le compteur ne doit pas trahir les threads que le lecteur ne peut pas voir
+	dto.ItemCount = len(dto.Items)
+
+	return dto, nil
+}
+
+// GetUserCollections liste les collections d'un utilisateur (les privées seulement pour lui-même)
+func (s *collectionService) GetUserCollections(ownerID uint, viewerID *uint) ([]*CollectionResponseDTO, error) {
+	includePrivate := viewerID != nil && *viewerID == ownerID
+
+	collections, err := s.collectionRepo.FindByUserID(ownerID, includePrivate)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*CollectionResponseDTO, 0, len(collections))
+	for _, collection := range collections {
+		dtos = append(dtos, collectionToDTO(collection))
+	}
+
+	return dtos, nil
+}
+
+// AddItem ajoute un thread consultable en fin de collection
+func (s *collectionService) AddItem(collectionID, userID uint, dto CollectionItemDTO) error {
+	if validationErrors := utils.ValidateStruct(dto); len(validationErrors) > 0 {
+		return fmt.Errorf("erreur validation: %v", validationErrors)
+	}
+
+	if _, err := s.findOwnedCollection(collectionID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.threadService.GetThread(dto.ThreadID, &userID); err != nil {
+		return err
+	}
+
+	added, err := s.collectionRepo.AddItem(collectionID, dto.ThreadID, normalizeNote(dto.Note))
+	if err != nil {
+		return err
+	}
+	if !added {
+		return utils.ErrCollectionItemExists
+	}
+
+	return nil
+}
+
+// UpdateItemNote modifie la note attachée à un élément
+func (s *collectionService) UpdateItemNote(collectionID, threadID, userID uint, note *string) error {
+	if note != nil && len(*note) > 500 {
+		return fmt.Errorf("erreur validation: la note ne peut pas dépasser 500 caractères")
+	}
+
+	if _, err := s.findOwnedCollection(collectionID, userID); err != nil {
+		return err
+	}
+
+	updated, err := s.collectionRepo.UpdateItemNote(collectionID, threadID, normalizeNote(note))
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return utils.ErrCollectionItemNotFound
+	}
+
+	return nil
+}
+
+// RemoveItem retire un thread d'une collection
+func (s *collectionService) RemoveItem(collectionID, threadID, userID uint) error {
+	if _, err := s.findOwnedCollection(collectionID, userID); err != nil {
+		return err
+	}
+
+	removed, err := s.collectionRepo.RemoveItem(collectionID, threadID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return utils.ErrCollectionItemNotFound
+	}
+
+	return nil
+}
+
+// ReorderItems applique un nouvel ordre aux éléments d'une collection
+func (s *collectionService) ReorderItems(collectionID, userID uint, threadIDs []uint) error {
+	if len(threadIDs) == 0 {
+		return fmt.Errorf("erreur validation: l'ordre des threads est requis")
+	}
+
+	seen := make(map[uint]bool, len(threadIDs))
+	for _, threadID := range threadIDs {
+		if seen[threadID] {
+			return fmt.Errorf("erreur validation: thread %d présent plusieurs fois", threadID)
+		}
+		seen[threadID] = true
+	}
+
+	if _, err := s.findOwnedCollection(collectionID, userID); err != nil {
+		return err
+	}
+
+	return s.collectionRepo.Reorder(collectionID, threadIDs)
+}
+
+// Helper methods
+
+// findOwnedCollection récupère une collection en vérifiant que l'utilisateur en est propriétaire
+func (s *collectionService) findOwnedCollection(id, userID uint) (*models.Collection, error) {
+	collection, err := s.collectionRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if collection.UserID != userID {
+		if collection.Visibility == "privé" {
+			return nil, utils.ErrCollectionNotFound
+		}
+		return nil, utils.ErrUnauthorized
+	}
+
+	return collection, nil
+}
+
+// validateCollectionDTO valide et normalise un DTO de collection
+func (s *collectionService) validateCollectionDTO(userID, excludeID uint, dto *CollectionDTO) error {
+	dto.Name = strings.TrimSpace(dto.Name)
+	dto.Description = normalizeNote(dto.Description)
+	if dto.Visibility == "" {
+		dto.Visibility = "public"
+	}
+
+	if validationErrors := utils.ValidateStruct(*dto); len(validationErrors) > 0 {
+		return fmt.Errorf("erreur validation: %v", validationErrors)
+	}
+
+	taken, err := s.collectionRepo.NameExists(userID, dto.Name, excludeID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return utils.ErrCollectionNameTaken
+	}
+
+	return nil
+}
+
+// canViewCollection indique si une collection est visible par viewerID
+func canViewCollection(collection *models.Collection, viewerID *uint) bool {
+	if collection.Visibility != "privé" {
+		return true
+	}
+	return viewerID != nil && *viewerID == collection.UserID
+}
+
+// normalizeNote supprime les espaces superflus et transforme une note vide en nil
+func normalizeNote(note *string) *string {
+	if note == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(*note)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// collectionToDTO convertit une collection en DTO (sans ses éléments)
+func collectionToDTO(collection *models.Collection) *CollectionResponseDTO {
+	dto := &CollectionResponseDTO{
+		ID:          collection.ID,
+		Name:        collection.Name,
+		Description: collection.Description,
+		Visibility:  collection.Visibility,
+		ItemCount:   collection.ItemCount,
+		CreatedAt:   collection.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   collection.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if collection.Owner != nil {
+		dto.Owner = UserSummaryDTO{
+			ID:         collection.Owner.ID,
+			Username:   collection.Owner.Username,
+			ProfilePic: collection.Owner.ProfilePic,
+		}
+	}
+
+	return dto
+}
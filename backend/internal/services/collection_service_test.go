@@ -0,0 +1,50 @@
+package services
+
+import (
+	"rythmitbackend/internal/models"
+	"testing"
+)
+
+func TestCanViewCollection(t *testing.T) {
+	owner := uint(1)
+	other := uint(2)
+
+	public := &models.Collection{UserID: owner, Visibility: "public"}
+	private := &models.Collection{UserID: owner, Visibility: "privé"}
+
+	cases := []struct {
+		name       string
+		collection *models.Collection
+		viewerID   *uint
+		want       bool
+	}{
+		{"publique anonyme", public, nil, true},
+		{"publique autre utilisateur", public, &other, true},
+		{"privée anonyme", private, nil, false},
+		{"privée autre utilisateur", private, &other, false},
+		{"privée propriétaire", private, &owner, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := canViewCollection(tc.collection, tc.viewerID); got != tc.want {
+				t.Errorf("canViewCollection() = %v, attendu %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeNote(t *testing.T) {
+	blank := "   "
+	padded := "  à écouter en boucle  "
+
+	if got := normalizeNote(nil); got != nil {
+		t.Errorf("note nil: attendu nil, obtenu %q", *got)
+	}
+	if got := normalizeNote(&blank); got != nil {
+		t.Errorf("note vide: attendu nil, obtenu %q", *got)
+	}
+	if got := normalizeNote(&padded); got == nil || *got != "à écouter en boucle" {
+		t.Errorf("note non nettoyée: %v", got)
+	}
+}
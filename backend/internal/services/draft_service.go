@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"strings"
+	"time"
+)
+
+// DraftService interface pour la gestion des brouillons de threads
+type DraftService interface {
+	SaveDraft(userID, draftID uint, dto SaveDraftDTO) (*models.ThreadDraft, error)
+	GetDraft(id, userID uint) (*models.ThreadDraft, error)
+	GetUserDrafts(userID uint) ([]*models.ThreadDraft, error)
+	DeleteDraft(id, userID uint) error
+	PublishDraft(id, userID uint) (*ThreadResponseDTO, error)
+}
+
+// SaveDraftDTO contenu d'un brouillon (tous les champs sont optionnels pendant la rédaction)
+type SaveDraftDTO struct {
+	Title       string     `json:"title" validate:"max=200"`
+	Description string     `json:"description" validate:"max=60000"`
+	ImageURL    *string    `json:"image_url" validate:"omitempty"`
+	Tags        []string   `json:"tags" validate:"omitempty,max=10"`
+	Visibility  string     `json:"visibility" validate:"omitempty,oneof=public privé"`
+	Access      string     `json:"access" validate:"omitempty,oneof=invitations amis"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+}
+
+// draftService implémentation
+type draftService struct {
+	draftRepo     repositories.DraftRepository
+	threadService ThreadService
+}
+
+// NewDraftService crée une nouvelle instance du service
+func NewDraftService(draftRepo repositories.DraftRepository, threadService ThreadService) DraftService {
+	return &draftService{
+		draftRepo:     draftRepo,
+		threadService: threadService,
+	}
+}
+
+// SaveDraft crée (draftID = 0) ou écrase un brouillon de l'utilisateur
+func (s *draftService) SaveDraft(userID, draftID uint, dto SaveDraftDTO) (*models.ThreadDraft, error) {
+	if validationErrors := utils.ValidateStruct(dto); len(validationErrors) > 0 {
+		return nil, fmt.Errorf("erreur validation: %v", validationErrors)
+	}
+
+	if dto.Visibility == "" {
+		dto.Visibility = models.VisibilityPublic
+	}
+	if dto.Access == "" {
+		dto.Access = models.ThreadAccessInvitees
+	}
+
+	draft := &models.ThreadDraft{UserID: userID}
+	if draftID != 0 {
+		existing, err := s.GetDraft(draftID, userID)
+		if err != nil {
+			return nil, err
+		}
+		draft = existing
+	}
+
+	draft.Title = strings.TrimSpace(dto.Title)
+	draft.Description = dto.Description
+	draft.ImageURL = dto.ImageURL
+	draft.Tags = dto.Tags
+	draft.Visibility = dto.Visibility
+	draft.Access = dto.Access
+	draft.PublishAt = dto.PublishAt
+
+	if draft.ID == 0 {
+		if err := s.draftRepo.Create(draft); err != nil {
+			return nil, err
+		}
+	} else if err := s.draftRepo.Update(draft); err != nil {
+		return nil, err
+	}
+
+	draft.UpdatedAt = time.Now()
+	return draft, nil
+}
+
+// GetDraft récupère un brouillon appartenant à l'utilisateur
+func (s *draftService) GetDraft(id, userID uint) (*models.ThreadDraft, error) {
+	draft, err := s.draftRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrDraftNotFound
+	}
+
+	// Un brouillon n'est visible que par son auteur
+	if draft.UserID != userID {
+		return nil, utils.ErrDraftNotFound
+	}
+
+	return draft, nil
+}
+
+// GetUserDrafts liste les brouillons de l'utilisateur
+func (s *draftService) GetUserDrafts(userID uint) ([]*models.ThreadDraft, error) {
+	drafts, err := s.draftRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération brouillons: %w", err)
+	}
+
+	if drafts == nil {
+		drafts = []*models.ThreadDraft{}
+	}
+
+	return drafts, nil
+}
+
+// DeleteDraft supprime un brouillon de l'utilisateur
+func (s *draftService) DeleteDraft(id, userID uint) error {
+	if _, err := s.GetDraft(id, userID); err != nil {
+		return err
+	}
+
+	return s.draftRepo.Delete(id)
+}
+
+// PublishDraft transforme un brouillon en thread (immédiat ou programmé) puis le supprime
+func (s *draftService) PublishDraft(id, userID uint) (*ThreadResponseDTO, error) {
+	draft, err := s.GetDraft(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := s.threadService.CreateThread(CreateThreadDTO{
+		Title:       draft.Title,
+		Description: draft.Description,
+		ImageURL:    draft.ImageURL,
+		Tags:        draft.Tags,
+		Visibility:  draft.Visibility,
+		Access:      draft.Access,
+		PublishAt:   draft.PublishAt,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.draftRepo.Delete(id); err != nil {
+		return thread, fmt.Errorf("thread publié mais brouillon non supprimé: %w", err)
+	}
+
+	return thread, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"testing"
+)
+
+// fakeDraftRepository brouillons en mémoire
+type fakeDraftRepository struct {
+	drafts map[uint]*models.ThreadDraft
+}
+
+func (r *fakeDraftRepository) Create(draft *models.ThreadDraft) error {
+	draft.ID = uint(len(r.drafts) + 1)
+	r.drafts[draft.ID] = draft
+	return nil
+}
+
+func (r *fakeDraftRepository) Update(draft *models.ThreadDraft) error {
+	r.drafts[draft.ID] = draft
+	return nil
+}
+
+func (r *fakeDraftRepository) FindByID(id uint) (*models.ThreadDraft, error) {
+	if draft, ok := r.drafts[id]; ok {
+		return draft, nil
+	}
+	return nil, utils.ErrDraftNotFound
+}
+
+func (r *fakeDraftRepository) FindByUserID(userID uint) ([]*models.ThreadDraft, error) {
+	return nil, nil
+}
+
+func (r *fakeDraftRepository) Delete(id uint) error {
+	delete(r.drafts, id)
+	return nil
+}
+
+var _ repositories.DraftRepository = (*fakeDraftRepository)(nil)
+
+// recordingThreadService note le thread créé à la publication d'un brouillon
+type recordingThreadService struct {
+	ThreadService
+	created CreateThreadDTO
+}
+
+func (s *recordingThreadService) CreateThread(dto CreateThreadDTO, userID uint) (*ThreadResponseDTO, error) {
+	s.created = dto
+	return &ThreadResponseDTO{}, nil
+}
+
+func TestPublishDraftKeepsAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		dto        SaveDraftDTO
+		wantAccess string
+	}{
+		{"privé réservé aux amis", SaveDraftDTO{Title: "Playlist", Visibility: models.VisibilityPrivate, Access: models.ThreadAccessFriends}, models.ThreadAccessFriends},
+		{"privé sur invitation", SaveDraftDTO{Title: "Playlist", Visibility: models.VisibilityPrivate}, models.ThreadAccessInvitees},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threads := &recordingThreadService{}
+			service := NewDraftService(&fakeDraftRepository{drafts: make(map[uint]*models.ThreadDraft)}, threads)
+
+			draft, err := service.SaveDraft(3, 0, tt.dto)
+			if err != nil {
+				t.Fatalf("Erreur inattendue: %v", err)
+			}
+			if _, err := service.PublishDraft(draft.ID, 3); err != nil {
+				t.Fatalf("Erreur inattendue: %v", err)
+			}
+			if threads.created.Access != tt.wantAccess {
+				t.Errorf("Accès attendu: %q, Obtenu: %q", tt.wantAccess, threads.created.Access)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/musiclink"
+	"strconv"
+	"strings"
+)
+
+// EmbedService interface pour les liens musicaux (embeds structurés) des contenus
+type EmbedService interface {
+	SyncEmbeds(targetType string, targetID uint, contents ...string) ([]*models.MusicEmbed, error)
+	GetEmbeds(targetType string, targetIDs []uint, viewerID *uint) (map[uint][]*models.MusicEmbed, error)
+}
+
+// embedService implémentation concrète
+type embedService struct {
+	embedRepo    repositories.EmbedRepository
+	catalogRepo  repositories.CatalogRepository
+	metadataRepo repositories.MetadataRepository
+	audioService AudioService
+}
+
+// NewEmbedService crée une nouvelle instance du service
+func NewEmbedService(embedRepo repositories.EmbedRepository, catalogRepo repositories.CatalogRepository, metadataRepo repositories.MetadataRepository, audioService AudioService) EmbedService {
+	return &embedService{
+		embedRepo:    embedRepo,
+		catalogRepo:  catalogRepo,
+		metadataRepo: metadataRepo,
+		audioService: audioService,
+	}
+}
+
+// SyncEmbeds remplace les liens musicaux d'une cible par ceux reconnus dans ses contenus
+// (texte et URLs saisies à part) : liens des plateformes puis fichiers audio envoyés sur le site
+func (s *embedService) SyncEmbeds(targetType string, targetID uint, contents ...string) ([]*models.MusicEmbed, error) {
+	text := strings.Join(contents, "\n")
+	links := musiclink.Extract(text)
+	audioIDs := models.ExtractAudioUploadIDs(text)
+
+	embeds := make([]*models.MusicEmbed, 0, len(links)+len(audioIDs))
+	for i, link := range links {
+		embeds = append(embeds, models.NewMusicEmbed(targetType, targetID, i, link))
+	}
+	for i, audioID := range audioIDs {
+		embeds = append(embeds, models.NewAudioEmbed(targetType, targetID, len(links)+i, audioID))
+	}
+
+	if err := s.embedRepo.ReplaceForTarget(targetType, targetID, embeds); err != nil {
+		return nil, err
+	}
+
+	// Les métadonnées des nouveaux liens sont récupérées en tâche de fond ;
+	// celles des fichiers envoyés viennent de leur analyse (voir AudioAnalyzer)
+	if err := s.metadataRepo.Enqueue(embeds[:len(links)]); err != nil {
+		return nil, err
+	}
+	WakeMusicMetadataFetcher()
+
+	return embeds, nil
+}
+
+// GetEmbeds récupère les liens musicaux de plusieurs cibles, avec leur entrée du catalogue,
+// le lien de la plateforme préférée du lecteur pour la même entrée et les métadonnées en cache
+func (s *embedService) GetEmbeds(targetType string, targetIDs []uint, viewerID *uint) (map[uint][]*models.MusicEmbed, error) {
+	embeds, err := s.embedRepo.FindByTargets(targetType, targetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []*models.MusicEmbed
+	for _, targetEmbeds := range embeds {
+		all = append(all, targetEmbeds...)
+	}
+
+	platform := ""
+	if viewerID != nil {
+		if platform, err = s.catalogRepo.GetPreferredPlatform(*viewerID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.catalogRepo.ResolveEmbeds(all, platform); err != nil {
+		return nil, err
+	}
+
+	if err := s.metadataRepo.AttachToEmbeds(all); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachAudio(all, viewerID); err != nil {
+		return nil, err
+	}
+
+	return embeds, nil
+}
+
+// attachAudio renseigne la durée et la forme d'onde des fichiers envoyés liés ;
+// un fichier que le lecteur ne peut pas écouter reste un lien nu
+func (s *embedService) attachAudio(embeds []*models.MusicEmbed, viewerID *uint) error {
+	uploads := make(map[uint]*models.AudioUpload)
+	for _, embed := range embeds {
+		if embed.Platform != models.EmbedPlatformUpload {
+			continue
+		}
+		id, err := strconv.ParseUint(embed.EntityID, 10, 32)
+		if err != nil {
+			continue
+		}
+
+		audio, loaded := uploads[uint(id)]
+		if !loaded {
+			audio, err = s.audioService.GetAudio(uint(id), viewerID, false)
+			if err != nil && !errors.Is(err, utils.ErrAudioNotFound) {
+				return err
+			}
+			uploads[uint(id)] = audio
+		}
+		embed.Audio = audio
+	}
+	return nil
+}
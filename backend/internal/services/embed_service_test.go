@@ -0,0 +1,114 @@
+package services
+
+import (
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"testing"
+)
+
+// fakeEmbedRepository embeds en mémoire, par cible
+type fakeEmbedRepository struct {
+	embeds map[uint][]*models.MusicEmbed
+}
+
+func (r *fakeEmbedRepository) ReplaceForTarget(targetType string, targetID uint, embeds []*models.MusicEmbed) error {
+	r.embeds[targetID] = embeds
+	return nil
+}
+
+func (r *fakeEmbedRepository) FindByTargets(targetType string, targetIDs []uint) (map[uint][]*models.MusicEmbed, error) {
+	found := make(map[uint][]*models.MusicEmbed)
+	for _, id := range targetIDs {
+		found[id] = r.embeds[id]
+	}
+	return found, nil
+}
+
+// stubCatalogRepository catalogue vide
+type stubCatalogRepository struct {
+	repositories.CatalogRepository
+}
+
+func (r *stubCatalogRepository) GetPreferredPlatform(userID uint) (string, error) {
+	return "", nil
+}
+
+func (r *stubCatalogRepository) ResolveEmbeds(embeds []*models.MusicEmbed, preferredPlatform string) error {
+	return nil
+}
+
+// recordingMetadataRepository note les liens mis en attente de métadonnées
+type recordingMetadataRepository struct {
+	repositories.MetadataRepository
+	enqueued []*models.MusicEmbed
+}
+
+func (r *recordingMetadataRepository) Enqueue(embeds []*models.MusicEmbed) error {
+	r.enqueued = append(r.enqueued, embeds...)
+	return nil
+}
+
+func (r *recordingMetadataRepository) AttachToEmbeds(embeds []*models.MusicEmbed) error {
+	return nil
+}
+
+// stubAudioService fichiers audio en mémoire, privés sauf pour leur auteur
+type stubAudioService struct {
+	AudioService
+	uploads map[uint]*models.AudioUpload
+}
+
+func (s *stubAudioService) GetAudio(id uint, viewerID *uint, isAdmin bool) (*models.AudioUpload, error) {
+	audio, ok := s.uploads[id]
+	if !ok || viewerID == nil || *viewerID != audio.UserID {
+		return nil, utils.ErrAudioNotFound
+	}
+	return audio, nil
+}
+
+func TestUploadEmbedsCarryAudioAnalysis(t *testing.T) {
+	upload := &models.AudioUpload{ID: 12, UserID: 3, DurationMs: 184000, Waveform: []float64{0.2, 0.9, 0.4}, WaveformStatus: models.WaveformStatusOK}
+	embedRepo := &fakeEmbedRepository{embeds: make(map[uint][]*models.MusicEmbed)}
+	metadataRepo := &recordingMetadataRepository{}
+	service := NewEmbedService(embedRepo, &stubCatalogRepository{}, metadataRepo, &stubAudioService{uploads: map[uint]*models.AudioUpload{12: upload}})
+
+	content := "Ma prod : [écouter](/api/v1/audio/12/stream), la référence https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC et /api/v1/audio/12"
+	embeds, err := service.SyncEmbeds(models.EmbedTargetThread, 7, content)
+	if err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+	if len(embeds) != 2 || embeds[1].Platform != models.EmbedPlatformUpload || embeds[1].EntityID != "12" {
+		t.Fatalf("Embeds inattendus: %+v", embeds)
+	}
+	if len(metadataRepo.enqueued) != 1 || metadataRepo.enqueued[0].Platform == models.EmbedPlatformUpload {
+		t.Errorf("Seuls les liens des plateformes attendent des métadonnées: %+v", metadataRepo.enqueued)
+	}
+
+	author, other := uint(3), uint(4)
+	tests := []struct {
+		name      string
+		viewerID  *uint
+		wantAudio bool
+	}{
+		{"auteur du fichier", &author, true},
+		{"autre utilisateur", &other, false},
+		{"visiteur anonyme", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := service.GetEmbeds(models.EmbedTargetThread, []uint{7}, tt.viewerID)
+			if err != nil {
+				t.Fatalf("Erreur inattendue: %v", err)
+			}
+			audio := found[7][1].Audio
+			if (audio != nil) != tt.wantAudio {
+				t.Fatalf("Analyse attendue: %v, Obtenue: %+v", tt.wantAudio, audio)
+			}
+			if audio != nil && (audio.DurationMs != 184000 || len(audio.Waveform) != 3) {
+				t.Errorf("Durée et forme d'onde inattendues: %+v", audio)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package services
 
 import (
 	"fmt"
+	"log"
 	"rythmitbackend/internal/models"
 	"rythmitbackend/internal/repositories"
+	"time"
 )
 
 // FriendshipService interface pour la logique métier des amitiés
@@ -49,13 +51,15 @@ type FriendshipStats struct {
 type friendshipService struct {
 	friendshipRepo repositories.FriendshipRepository
 	userRepo       repositories.UserRepository
+	listeningRepo  repositories.ListeningRepository
 }
 
 // NewFriendshipService crée une nouvelle instance du service
-func NewFriendshipService(friendshipRepo repositories.FriendshipRepository, userRepo repositories.UserRepository) FriendshipService {
+func NewFriendshipService(friendshipRepo repositories.FriendshipRepository, userRepo repositories.UserRepository, listeningRepo repositories.ListeningRepository) FriendshipService {
 	return &friendshipService{
 		friendshipRepo: friendshipRepo,
 		userRepo:       userRepo,
+		listeningRepo:  listeningRepo,
 	}
 }
 
@@ -219,12 +223,8 @@ func (s *friendshipService) GetFriends(userID uint) ([]*models.Friend, error) {
 		return nil, fmt.Errorf("erreur récupération amis: %w", err)
 	}
 
-	// Enrichir les données des amis (activité, etc.)
-	for _, friend := range friends {
-		// Ici on pourrait ajouter la logique pour récupérer l'activité actuelle
-		// Par exemple, la dernière piste écoutée, le statut personnalisé, etc.
-		s.enrichFriendData(friend)
-	}
+	// Enrichir les données des amis (morceau en cours d'écoute)
+	s.enrichFriendData(friends)
 
 	return friends, nil
 }
@@ -399,26 +399,28 @@ func (s *friendshipService) CanSendRequest(requesterID, addresseeID uint) (bool,
 	return true, "", nil
 }
 
-// enrichFriendData enrichit les données d'un ami avec des informations supplémentaires
-func (s *friendshipService) enrichFriendData(friend *models.Friend) {
-	// Ici on pourrait ajouter la logique pour récupérer:
-	// - L'activité musicale actuelle
-	// - Le statut personnalisé
-	// - Les playlists récentes
-	// - etc.
-
-	// Pour l'instant, on simule une activité basée sur le statut en ligne
-	if friend.OnlineStatus == "online" {
-		activities := []string{
-			"🎵 Écoute: \"Chill Vibes Mix\"",
-			"🎧 Découvre de nouveaux artistes",
-			"🎼 Crée une playlist",
-			"🎤 Partage ses découvertes",
-		}
+// enrichFriendData renseigne l'activité des amis qui écoutent un morceau (expirée à la fin du morceau) ;
+// une erreur est seulement journalisée, la liste d'amis reste affichée sans activité
+func (s *friendshipService) enrichFriendData(friends []*models.Friend) {
+	if len(friends) == 0 {
+		return
+	}
+
+	userIDs := make([]uint, len(friends))
+	for i, friend := range friends {
+		userIDs[i] = friend.ID
+	}
 
-		// Utiliser l'ID de l'ami pour avoir une activité "stable"
-		activityIndex := int(friend.ID) % len(activities)
-		activity := activities[activityIndex]
-		friend.Activity = &activity
+	playing, err := s.listeningRepo.FindNowPlaying(userIDs, time.Now())
+	if err != nil {
+		log.Printf("⚠️ Erreur récupération des écoutes en cours: %v", err)
+		return
+	}
+
+	for _, friend := range friends {
+		if event, ok := playing[friend.ID]; ok {
+			activity := event.ActivityLabel()
+			friend.Activity = &activity
+		}
 	}
 }
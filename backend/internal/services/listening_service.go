@@ -0,0 +1,222 @@
+package services
+
+import (
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"strings"
+	"time"
+)
+
+// Durées d'écoute retenues pour l'expiration du « en écoute »
+const (
+	listeningDefaultDuration = 5 * time.Minute // durée du morceau inconnue
+	listeningMaxDuration     = time.Hour       // plafond (mix, durée fantaisiste)
+	listeningMinDuration     = 10 * time.Second
+)
+
+// RecentListensLimit nombre d'écoutes récentes affichées sur un profil
+const RecentListensLimit = 10
+
+// Dates acceptées pour un scrobble (horloge du lecteur approximative, imports d'historique)
+var (
+	scrobbleMinTime   = time.Date(2002, 1, 1, 0, 0, 0, 0, time.UTC) // premiers scrobbles Audioscrobbler
+	scrobbleMaxFuture = 10 * time.Minute
+)
+
+// ListeningDTO morceau écouté, tel qu'envoyé avec une activité « listening »
+type ListeningDTO struct {
+	Track      string `json:"track"`
+	Artist     string `json:"artist"`
+	Album      string `json:"album,omitempty"`
+	DurationMs int    `json:"duration_ms,omitempty"` // 0 si inconnue
+	MusicURL   string `json:"music_url,omitempty"`
+}
+
+// ScrobbleDTO morceau écouté envoyé par un scrobbler, avec le début de l'écoute
+type ScrobbleDTO struct {
+	ListeningDTO
+	ListenedAt time.Time `json:"listened_at"`
+}
+
+// ListeningService interface pour l'historique d'écoute et le « en écoute » des utilisateurs
+type ListeningService interface {
+	RecordListening(userID uint, dto ListeningDTO) (*models.ListeningEvent, error)
+	RecordNowPlaying(userID uint, dto ListeningDTO) (*models.ListeningEvent, error)
+	RecordScrobbles(userID uint, scrobbles []ScrobbleDTO, skipInvalid bool) ([]error, error)
+	GetNowPlaying(userIDs []uint) (map[uint]*models.ListeningEvent, error)
+	GetRecentListens(userID uint, limit int) ([]*models.ListeningEvent, error)
+	GetUserListens(viewerID, userID uint, limit int) (*models.ListeningEvent, []*models.ListeningEvent, error)
+}
+
+// listeningService implémentation du service
+type listeningService struct {
+	listeningRepo  repositories.ListeningRepository
+	friendshipRepo repositories.FriendshipRepository
+	now            func() time.Time
+}
+
+// NewListeningService crée une nouvelle instance du service
+func NewListeningService(listeningRepo repositories.ListeningRepository, friendshipRepo repositories.FriendshipRepository) ListeningService {
+	return &listeningService{
+		listeningRepo:  listeningRepo,
+		friendshipRepo: friendshipRepo,
+		now:            time.Now,
+	}
+}
+
+// RecordListening enregistre une écoute dans l'historique ; elle devient le « en écoute » de
+// l'utilisateur jusqu'à la fin du morceau (ou jusqu'à l'écoute suivante)
+func (s *listeningService) RecordListening(userID uint, dto ListeningDTO) (*models.ListeningEvent, error) {
+	event, err := newListeningEvent(userID, dto, s.now())
+	if err != nil {
+		return nil, err
+	}
+	event.InHistory = true
+
+	if err := s.listeningRepo.Create(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// RecordNowPlaying signale le morceau qu'un scrobbler commence à lire : il devient le « en écoute »
+// de l'utilisateur mais n'entre dans l'historique qu'au scrobble
+func (s *listeningService) RecordNowPlaying(userID uint, dto ListeningDTO) (*models.ListeningEvent, error) {
+	event, err := newListeningEvent(userID, dto, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.listeningRepo.Create(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// RecordScrobbles ajoute des écoutes passées à l'historique et retourne l'erreur de chaque scrobble
+// refusé (nil si accepté). Avec skipInvalid, les scrobbles valides sont enregistrés malgré les refus
+// (Last.fm) ; sinon un seul refus annule tout le lot (ListenBrainz) et l'erreur retournée l'indique.
+func (s *listeningService) RecordScrobbles(userID uint, scrobbles []ScrobbleDTO, skipInvalid bool) ([]error, error) {
+	now := s.now()
+	events := make([]*models.ListeningEvent, len(scrobbles))
+	rejected := make([]error, len(scrobbles))
+	invalid := 0
+	for i, scrobble := range scrobbles {
+		events[i], rejected[i] = newScrobbleEvent(userID, scrobble, now)
+		if rejected[i] != nil {
+			invalid++
+		}
+	}
+	if invalid > 0 && !skipInvalid {
+		return rejected, fmt.Errorf("%d écoute(s) refusée(s): %w", invalid, utils.ErrInvalidInput)
+	}
+
+	accepted := make([]*models.ListeningEvent, 0, len(events)-invalid)
+	for _, event := range events {
+		if event != nil {
+			accepted = append(accepted, event)
+		}
+	}
+	if err := s.listeningRepo.CreateBatch(accepted); err != nil {
+		return rejected, err
+	}
+	return rejected, nil
+}
+
+// GetNowPlaying récupère le morceau en cours d'écoute de chaque utilisateur qui écoute quelque chose
+func (s *listeningService) GetNowPlaying(userIDs []uint) (map[uint]*models.ListeningEvent, error) {
+	return s.listeningRepo.FindNowPlaying(userIDs, s.now())
+}
+
+// GetRecentListens récupère les dernières écoutes d'un utilisateur
+func (s *listeningService) GetRecentListens(userID uint, limit int) ([]*models.ListeningEvent, error) {
+	if limit <= 0 || limit > 50 {
+		limit = RecentListensLimit
+	}
+	return s.listeningRepo.FindRecentByUser(userID, limit)
+}
+
+// GetUserListens récupère le « en écoute » (nil si aucun) et les dernières écoutes d'un utilisateur ;
+// comme les activités « listening », ils ne sont visibles que de l'utilisateur et de ses amis
+func (s *listeningService) GetUserListens(viewerID, userID uint, limit int) (*models.ListeningEvent, []*models.ListeningEvent, error) {
+	if viewerID != userID {
+		areFriends, err := s.friendshipRepo.AreFriends(viewerID, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !areFriends {
+			return nil, nil, fmt.Errorf("écoutes réservées aux amis: %w", utils.ErrUnauthorized)
+		}
+	}
+
+	playing, err := s.GetNowPlaying([]uint{userID})
+	if err != nil {
+		return nil, nil, err
+	}
+	listens, err := s.GetRecentListens(userID, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return playing[userID], listens, nil
+}
+
+// newListeningEvent vérifie un morceau écouté à partir de startedAt et calcule la fin prévue de l'écoute
+func newListeningEvent(userID uint, dto ListeningDTO, startedAt time.Time) (*models.ListeningEvent, error) {
+	track := truncateRunes(strings.TrimSpace(dto.Track), 300)
+	artist := truncateRunes(strings.TrimSpace(dto.Artist), 200)
+	if track == "" || artist == "" {
+		return nil, fmt.Errorf("le titre et l'artiste du morceau sont requis: %w", utils.ErrInvalidInput)
+	}
+
+	musicURL := strings.TrimSpace(dto.MusicURL)
+	if len(musicURL) > 500 || (musicURL != "" && !strings.HasPrefix(musicURL, "https://") && !strings.HasPrefix(musicURL, "http://")) {
+		musicURL = ""
+	}
+
+	startedAt = startedAt.Truncate(time.Second)
+	duration := listeningDuration(dto.DurationMs)
+	return &models.ListeningEvent{
+		UserID:     userID,
+		TrackName:  track,
+		Artist:     artist,
+		Album:      truncateRunes(strings.TrimSpace(dto.Album), 300),
+		DurationMs: int(duration / time.Millisecond),
+		MusicURL:   musicURL,
+		StartedAt:  startedAt,
+		ExpiresAt:  startedAt.Add(duration),
+	}, nil
+}
+
+// newScrobbleEvent vérifie un scrobble, y compris sa date d'écoute
+func newScrobbleEvent(userID uint, scrobble ScrobbleDTO, now time.Time) (*models.ListeningEvent, error) {
+	if scrobble.ListenedAt.Before(scrobbleMinTime) {
+		return nil, fmt.Errorf("date d'écoute trop ancienne: %w", utils.ErrListenTooOld)
+	}
+	if scrobble.ListenedAt.After(now.Add(scrobbleMaxFuture)) {
+		return nil, fmt.Errorf("date d'écoute dans le futur: %w", utils.ErrListenTooNew)
+	}
+
+	event, err := newListeningEvent(userID, scrobble.ListeningDTO, scrobble.ListenedAt)
+	if err != nil {
+		return nil, err
+	}
+	event.InHistory = true
+	return event, nil
+}
+
+// listeningDuration durée retenue pour un morceau (défaut si inconnue, bornée sinon)
+func listeningDuration(durationMs int) time.Duration {
+	if durationMs <= 0 {
+		return listeningDefaultDuration
+	}
+	duration := time.Duration(durationMs) * time.Millisecond
+	if duration < listeningMinDuration {
+		return listeningMinDuration
+	}
+	if duration > listeningMaxDuration {
+		return listeningMaxDuration
+	}
+	return duration
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"errors"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"testing"
+	"time"
+)
+
+// fakeListeningRepository historique d'écoute en mémoire
+type fakeListeningRepository struct {
+	events []*models.ListeningEvent
+}
+
+func (r *fakeListeningRepository) Create(event *models.ListeningEvent) error {
+	for _, previous := range r.events {
+		if previous.UserID == event.UserID && previous.StartedAt.Before(event.StartedAt) && previous.ExpiresAt.After(event.StartedAt) {
+			previous.ExpiresAt = event.StartedAt
+		}
+	}
+	event.ID = uint(len(r.events) + 1)
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *fakeListeningRepository) CreateBatch(events []*models.ListeningEvent) error {
+	for _, event := range events {
+		if err := r.Create(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeListeningRepository) FindNowPlaying(userIDs []uint, now time.Time) (map[uint]*models.ListeningEvent, error) {
+	playing := make(map[uint]*models.ListeningEvent)
+	for _, userID := range userIDs {
+		for i := len(r.events) - 1; i >= 0; i-- {
+			if event := r.events[i]; event.UserID == userID && event.IsPlaying(now) {
+				playing[userID] = event
+				break
+			}
+		}
+	}
+	return playing, nil
+}
+
+func (r *fakeListeningRepository) FindRecentByUser(userID uint, limit int) ([]*models.ListeningEvent, error) {
+	var recent []*models.ListeningEvent
+	for i := len(r.events) - 1; i >= 0 && len(recent) < limit; i-- {
+		if r.events[i].UserID == userID && r.events[i].InHistory {
+			recent = append(recent, r.events[i])
+		}
+	}
+	return recent, nil
+}
+
+func TestRecordListening(t *testing.T) {
+	start := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		dto          ListeningDTO
+		wantErr      error
+		wantDuration time.Duration
+		wantURL      string
+	}{
+		{"durée connue", ListeningDTO{Track: "Strobe", Artist: "Deadmau5", DurationMs: 634000}, nil, 634 * time.Second, ""},
+		{"durée inconnue", ListeningDTO{Track: "Strobe", Artist: "Deadmau5"}, nil, listeningDefaultDuration, ""},
+		{"durée plafonnée", ListeningDTO{Track: "Mix", Artist: "DJ", DurationMs: 5 * 3600 * 1000}, nil, listeningMaxDuration, ""},
+		{"lien non HTTP ignoré", ListeningDTO{Track: "Strobe", Artist: "Deadmau5", MusicURL: "javascript:alert(1)"}, nil, listeningDefaultDuration, ""},
+		{"lien conservé", ListeningDTO{Track: "Strobe", Artist: "Deadmau5", MusicURL: "https://open.spotify.com/track/1"}, nil, listeningDefaultDuration, "https://open.spotify.com/track/1"},
+		{"titre manquant", ListeningDTO{Artist: "Deadmau5"}, utils.ErrInvalidInput, 0, ""},
+		{"artiste vide", ListeningDTO{Track: "Strobe", Artist: "   "}, utils.ErrInvalidInput, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &listeningService{listeningRepo: &fakeListeningRepository{}, now: func() time.Time { return start }}
+
+			event, err := service.RecordListening(3, tt.dto)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Erreur attendue: %v, Obtenue: %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := event.ExpiresAt.Sub(event.StartedAt); got != tt.wantDuration {
+				t.Errorf("Durée attendue: %s, Obtenue: %s", tt.wantDuration, got)
+			}
+			if event.MusicURL != tt.wantURL {
+				t.Errorf("Lien attendu: %q, Obtenu: %q", tt.wantURL, event.MusicURL)
+			}
+		})
+	}
+}
+
+func TestNowPlayingExpiration(t *testing.T) {
+	now := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+	repo := &fakeListeningRepository{}
+	service := &listeningService{listeningRepo: repo, now: func() time.Time { return now }}
+
+	if _, err := service.RecordListening(3, ListeningDTO{Track: "One More Time", Artist: "Daft Punk", DurationMs: 320000}); err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+	now = now.Add(time.Minute)
+	if _, err := service.RecordListening(3, ListeningDTO{Track: "Digital Love", Artist: "Daft Punk", DurationMs: 301000}); err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		after     time.Duration
+		wantTrack string
+	}{
+		{"écoute suivante en cours", time.Minute, "Digital Love"},
+		{"fin du morceau", 301 * time.Second, ""},
+		{"après la fin prévue du morceau précédent", 6 * time.Minute, ""},
+	}
+
+	start := now
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = start.Add(tt.after)
+			playing, err := service.GetNowPlaying([]uint{3, 4})
+			if err != nil {
+				t.Fatalf("Erreur inattendue: %v", err)
+			}
+			if _, ok := playing[4]; ok {
+				t.Errorf("Aucune écoute attendue pour l'utilisateur 4")
+			}
+			got := ""
+			if event, ok := playing[3]; ok {
+				got = event.TrackName
+			}
+			if got != tt.wantTrack {
+				t.Errorf("En écoute attendu: %q, Obtenu: %q", tt.wantTrack, got)
+			}
+		})
+	}
+
+	recent, _ := service.GetRecentListens(3, 0)
+	if len(recent) != 2 || recent[0].TrackName != "Digital Love" {
+		t.Errorf("Historique inattendu: %+v", recent)
+	}
+	if !recent[1].ExpiresAt.Equal(recent[0].StartedAt) {
+		t.Errorf("L'écoute précédente doit se terminer au début de la suivante")
+	}
+}
+
+func TestRecordScrobbles(t *testing.T) {
+	now := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+	valid := ListeningDTO{Track: "Around the World", Artist: "Daft Punk", DurationMs: 429000}
+
+	tests := []struct {
+		name         string
+		listenedAt   []time.Time
+		skipInvalid  bool
+		wantErr      error
+		wantRejected []error
+		wantHistory  int
+	}{
+		{"lot valide", []time.Time{now.Add(-time.Hour), now.Add(-2 * time.Hour)}, false, nil, []error{nil, nil}, 2},
+		{"import d'historique ancien", []time.Time{time.Date(2005, 3, 1, 0, 0, 0, 0, time.UTC)}, false, nil, []error{nil}, 1},
+		{"trop ancien, lot refusé", []time.Time{now.Add(-time.Hour), time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)}, false, utils.ErrInvalidInput, []error{nil, utils.ErrListenTooOld}, 0},
+		{"dans le futur, lot refusé", []time.Time{now.Add(time.Hour)}, false, utils.ErrInvalidInput, []error{utils.ErrListenTooNew}, 0},
+		{"refus ignorés", []time.Time{now.Add(-time.Hour), {}, now.Add(time.Hour)}, true, nil, []error{nil, utils.ErrListenTooOld, utils.ErrListenTooNew}, 1},
+		{"horloge du lecteur en avance", []time.Time{now.Add(5 * time.Minute)}, true, nil, []error{nil}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeListeningRepository{}
+			service := &listeningService{listeningRepo: repo, now: func() time.Time { return now }}
+
+			scrobbles := make([]ScrobbleDTO, len(tt.listenedAt))
+			for i, listenedAt := range tt.listenedAt {
+				scrobbles[i] = ScrobbleDTO{ListeningDTO: valid, ListenedAt: listenedAt}
+			}
+
+			rejected, err := service.RecordScrobbles(3, scrobbles, tt.skipInvalid)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Erreur attendue: %v, Obtenue: %v", tt.wantErr, err)
+			}
+			for i, want := range tt.wantRejected {
+				if !errors.Is(rejected[i], want) || (want == nil) != (rejected[i] == nil) {
+					t.Errorf("Scrobble %d : refus attendu: %v, Obtenu: %v", i, want, rejected[i])
+				}
+			}
+			history, _ := service.GetRecentListens(3, 50)
+			if len(history) != tt.wantHistory {
+				t.Errorf("Écoutes attendues dans l'historique: %d, Obtenues: %d", tt.wantHistory, len(history))
+			}
+		})
+	}
+}
+
+// failingListeningRepository refuse les lots et note leur taille
+type failingListeningRepository struct {
+	fakeListeningRepository
+	batches []int
+}
+
+func (r *failingListeningRepository) CreateBatch(events []*models.ListeningEvent) error {
+	r.batches = append(r.batches, len(events))
+	return errors.New("connexion perdue")
+}
+
+func TestRecordScrobblesSingleBatch(t *testing.T) {
+	now := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+	repo := &failingListeningRepository{}
+	service := &listeningService{listeningRepo: repo, now: func() time.Time { return now }}
+
+	scrobbles := []ScrobbleDTO{
+		{ListeningDTO: ListeningDTO{Track: "One More Time", Artist: "Daft Punk"}, ListenedAt: now.Add(-2 * time.Hour)},
+		{ListeningDTO: ListeningDTO{Track: "Aerodynamic", Artist: "Daft Punk"}, ListenedAt: now.Add(-time.Hour)},
+		{ListeningDTO: ListeningDTO{Track: "Digital Love"}, ListenedAt: now.Add(-time.Hour)},
+	}
+
+	if _, err := service.RecordScrobbles(3, scrobbles, true); err == nil {
+		t.Fatalf("L'échec d'écriture du lot doit être retourné")
+	}
+	if len(repo.batches) != 1 || repo.batches[0] != 2 {
+		t.Errorf("Un seul lot de 2 écoutes attendu, Obtenus: %v", repo.batches)
+	}
+}
+
+func TestNowPlayingNotInHistory(t *testing.T) {
+	now := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+	repo := &fakeListeningRepository{}
+	service := &listeningService{listeningRepo: repo, now: func() time.Time { return now }}
+
+	if _, err := service.RecordNowPlaying(3, ListeningDTO{Track: "Veridis Quo", Artist: "Daft Punk", DurationMs: 345000}); err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+
+	playing, _ := service.GetNowPlaying([]uint{3})
+	if event, ok := playing[3]; !ok || event.TrackName != "Veridis Quo" {
+		t.Errorf("Le morceau signalé doit être en écoute: %+v", playing)
+	}
+	if history, _ := service.GetRecentListens(3, 0); len(history) != 0 {
+		t.Errorf("Le morceau signalé ne doit pas entrer dans l'historique: %+v", history)
+	}
+}
+
+// stubFriendshipRepository amitiés en mémoire ; seules les vérifications utilisées sont implémentées
+type stubFriendshipRepository struct {
+	repositories.FriendshipRepository
+	friends map[[2]uint]bool
+}
+
+func (r *stubFriendshipRepository) AreFriends(userID1, userID2 uint) (bool, error) {
+	return r.friends[[2]uint{userID1, userID2}] || r.friends[[2]uint{userID2, userID1}], nil
+}
+
+func TestGetUserListens(t *testing.T) {
+	now := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+	repo := &fakeListeningRepository{}
+	service := &listeningService{
+		listeningRepo:  repo,
+		friendshipRepo: &stubFriendshipRepository{friends: map[[2]uint]bool{{3, 4}: true}},
+		now:            func() time.Time { return now },
+	}
+
+	if _, err := service.RecordListening(3, ListeningDTO{Track: "Around the World", Artist: "Daft Punk"}); err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		viewerID uint
+		wantErr  error
+	}{
+		{"l'utilisateur lui-même", 3, nil},
+		{"un ami", 4, nil},
+		{"un inconnu", 5, utils.ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playing, listens, err := service.GetUserListens(tt.viewerID, 3, 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Erreur attendue: %v, Obtenue: %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				if playing != nil || len(listens) != 0 {
+					t.Errorf("Aucune écoute ne doit être retournée: %+v %+v", playing, listens)
+				}
+				return
+			}
+			if playing == nil || playing.TrackName != "Around the World" || len(listens) != 1 {
+				t.Errorf("Écoutes inattendues: %+v %+v", playing, listens)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"strings"
+)
+
+// maxMentionSuggestions nombre maximum de suggestions pour l'autocomplétion
+const maxMentionSuggestions = 8
+
+// MentionNotifier prévient un utilisateur qu'il a été mentionné.
+// Branché au démarrage sur les notifications temps réel (voir SetMentionNotifier)
+type MentionNotifier func(mention *models.Mention, author *models.User)
+
+// mentionNotifier notificateur global (nil = mentions enregistrées sans notification)
+var mentionNotifier MentionNotifier
+
+// SetMentionNotifier enregistre la fonction appelée pour chaque nouvelle mention
+func SetMentionNotifier(notifier MentionNotifier) {
+	mentionNotifier = notifier
+}
+
+// MentionService interface pour la détection et la notification des mentions @utilisateur
+type MentionService interface {
+	RecordMentions(source MentionSource) ([]*models.Mention, error)
+	GetMentions(sourceType string, sourceIDs []uint) (map[uint][]*models.Mention, error)
+	SuggestUsers(prefix string, viewerID uint) ([]UserSummaryDTO, error)
+}
+
+// MentionSource contenu dans lequel rechercher des mentions
+type MentionSource struct {
+	Type        string // models.MentionSourceThread, MentionSourceComment ou MentionSourceMessage
+	ID          uint
+	ThreadID    *uint // thread concerné pour un thread ou un commentaire
+	RecipientID *uint // destinataire d'un message privé
+	AuthorID    uint
+	Content     string
+}
+
+// mentionService implémentation concrète
+type mentionService struct {
+	mentionRepo    repositories.MentionRepository
+	userRepo       repositories.UserRepository
+	friendshipRepo repositories.FriendshipRepository
+	threadRepo     repositories.ThreadRepository
+}
+
+// NewMentionService crée une nouvelle instance du service
+func NewMentionService(mentionRepo repositories.MentionRepository, userRepo repositories.UserRepository, friendshipRepo repositories.FriendshipRepository, threadRepo repositories.ThreadRepository) MentionService {
+	return &mentionService{
+		mentionRepo:    mentionRepo,
+		userRepo:       userRepo,
+		friendshipRepo: friendshipRepo,
+		threadRepo:     threadRepo,
+	}
+}
+
+// RecordMentions enregistre les utilisateurs mentionnés qui peuvent voir le contenu
+// et les notifie. Les mentions inconnues, de soi-même ou bloquées sont ignorées.
+func (s *mentionService) RecordMentions(source MentionSource) ([]*models.Mention, error) {
+	usernames := models.ExtractMentions(source.Content)
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	author, err := s.userRepo.FindByID(source.AuthorID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération auteur: %w", err)
+	}
+
+	var mentions []*models.Mention
+	for _, username := range usernames {
+		user, err := s.userRepo.FindByUsername(username)
+		if err != nil {
+			// Nom inconnu : simple texte
+			continue
+		}
+
+		allowed, err := s.canMention(source, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			continue
+		}
+
+		mentions = append(mentions, &models.Mention{
+			MentionedUserID:   user.ID,
+			AuthorID:          source.AuthorID,
+			SourceType:        source.Type,
+			SourceID:          source.ID,
+			ThreadID:          source.ThreadID,
+			MentionedUsername: user.Username,
+		})
+	}
+
+	if err := s.mentionRepo.CreateBatch(mentions); err != nil {
+		return nil, err
+	}
+
+	if mentionNotifier != nil {
+		for _, mention := range mentions {
+			mentionNotifier(mention, author)
+		}
+	}
+
+	if len(mentions) > 0 {
+		log.Printf("📣 %d mention(s) enregistrée(s) pour %s %d", len(mentions), source.Type, source.ID)
+	}
+
+	return mentions, nil
+}
+
+// GetMentions récupère les mentions déjà enregistrées de plusieurs contenus
+func (s *mentionService) GetMentions(sourceType string, sourceIDs []uint) (map[uint][]*models.Mention, error) {
+	return s.mentionRepo.FindBySources(sourceType, sourceIDs)
+}
+
+// SuggestUsers propose des utilisateurs à mentionner pour l'autocomplétion
+func (s *mentionService) SuggestUsers(prefix string, viewerID uint) ([]UserSummaryDTO, error) {
+	prefix = strings.TrimPrefix(strings.TrimSpace(prefix), "@")
+	if prefix == "" {
+		return []UserSummaryDTO{}, nil
+	}
+
+	users, err := s.mentionRepo.FindCandidates(prefix, viewerID, maxMentionSuggestions)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]UserSummaryDTO, 0, len(users))
+	for _, user := range users {
+		suggestions = append(suggestions, UserSummaryDTO{
+			ID:         user.ID,
+			Username:   user.Username,
+			ProfilePic: user.ProfilePic,
+		})
+	}
+
+	return suggestions, nil
+}
+
+// canMention vérifie qu'un utilisateur peut être mentionné dans ce contenu :
+// pas l'auteur, pas de blocage entre eux, et accès au contenu mentionné
+func (s *mentionService) canMention(source MentionSource, userID uint) (bool, error) {
+	if userID == source.AuthorID {
+		return false, nil
+	}
+
+	blocked, err := s.friendshipRepo.IsBlocked(userID, source.AuthorID)
+	if err != nil {
+		return false, err
+	}
+	if !blocked {
+		blocked, err = s.friendshipRepo.IsBlocked(source.AuthorID, userID)
+		if err != nil {
+			return false, err
+		}
+	}
+	if blocked {
+		return false, nil
+	}
+
+	switch {
+	case source.RecipientID != nil:
+		// Un message privé n'est visible que par son destinataire
+		return userID == *source.RecipientID, nil
+	case source.ThreadID != nil:
+		allowed, err := s.threadRepo.CanView(*source.ThreadID, userID)
+		if err != nil {
+			return false, fmt.Errorf("erreur vérification accès mention: %w", err)
+		}
+		return allowed, nil
+	}
+
+	return true, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"log"
 	"rythmitbackend/internal/models"
 	"rythmitbackend/internal/repositories"
 )
@@ -33,13 +34,17 @@ type MessageService interface {
 type messageService struct {
 	messageRepo    repositories.DirectMessageRepository
 	friendshipRepo repositories.FriendshipRepository
+	mentionService MentionService
+	reactionRepo   repositories.ReactionRepository
 }
 
 // NewMessageService crée une nouvelle instance du service
-func NewMessageService(messageRepo repositories.DirectMessageRepository, friendshipRepo repositories.FriendshipRepository) MessageService {
+func NewMessageService(messageRepo repositories.DirectMessageRepository, friendshipRepo repositories.FriendshipRepository, mentionService MentionService, reactionRepo repositories.ReactionRepository) MessageService {
 	return &messageService{
 		messageRepo:    messageRepo,
 		friendshipRepo: friendshipRepo,
+		mentionService: mentionService,
+		reactionRepo:   reactionRepo,
 	}
 }
 
@@ -117,6 +122,20 @@ func (s *messageService) SendMessage(senderID, receiverID uint, content string)
 	if err != nil {
 		return nil, fmt.Errorf("erreur création message: %w", err)
 	}
+	message.AttachSharedThread()
+
+	// Seul le destinataire peut être notifié d'une mention dans un message privé
+	mentions, err := s.mentionService.RecordMentions(MentionSource{
+		Type:        models.MentionSourceMessage,
+		ID:          message.ID,
+		RecipientID: &receiverID,
+		AuthorID:    senderID,
+		Content:     content,
+	})
+	if err != nil {
+		log.Printf("❌ Erreur mentions du message %d: %v", message.ID, err)
+	}
+	message.Mentions = mentions
 
 	return message, nil
 }
@@ -138,7 +157,32 @@ func (s *messageService) GetConversationMessages(conversationID, userID uint, li
 		limit = 50
 	}
 
-	return s.messageRepo.GetConversationMessages(conversationID, limit, offset)
+	messages, err := s.messageRepo.GetConversationMessages(conversationID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	messageIDs := make([]uint, len(messages))
+	for i, message := range messages {
+		messageIDs[i] = message.ID
+	}
+	mentions, err := s.mentionService.GetMentions(models.MentionSourceMessage, messageIDs)
+	if err != nil {
+		return nil, err
+	}
+	reactions, err := s.reactionRepo.SummariesByTargets(models.ReactionTargetMessage, messageIDs, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Afficher les threads partagés sous forme de carte, les mentions sous forme de lien et les réactions
+	for _, message := range messages {
+		message.AttachSharedThread()
+		message.Mentions = mentions[message.ID]
+		message.Reactions = reactions[message.ID]
+	}
+
+	return messages, nil
 }
 
 // MarkConversationAsRead marque tous les messages d'une conversation comme lus
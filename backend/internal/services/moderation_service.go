@@ -0,0 +1,314 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"strings"
+)
+
+// maxModerationLogEntries nombre maximum d'entrées retournées par le journal d'un thread
+const maxModerationLogEntries = 100
+
+// ModerationService interface pour les outils de modération des threads
+// (scission, déplacement de commentaires, retag, fusion) avec journal d'audit
+type ModerationService interface {
+	SplitThread(threadID uint, dto SplitThreadDTO, moderatorID uint) (*ThreadResponseDTO, error)
+	MoveMessages(threadID uint, dto MoveMessagesDTO, moderatorID uint) (int64, error)
+	RetagThread(threadID uint, dto RetagThreadDTO, moderatorID uint) (*ThreadResponseDTO, error)
+	MergeThreads(sourceID, targetID, moderatorID uint) (*ThreadResponseDTO, error)
+	GetThreadLog(threadID uint) ([]*models.ModerationAction, error)
+}
+
+// SplitThreadDTO commentaires à extraire dans un nouveau thread
+type SplitThreadDTO struct {
+	MessageIDs []uint   `json:"message_ids" validate:"required,min=1,max=100"`
+	Title      string   `json:"title" validate:"required,min=5,max=200"`
+	Tags       []string `json:"tags" validate:"max=10"` // vide = tags du thread d'origine
+}
+
+// MoveMessagesDTO commentaires à déplacer vers un thread existant
+type MoveMessagesDTO struct {
+	MessageIDs []uint `json:"message_ids" validate:"required,min=1,max=100"`
+	TargetID   uint   `json:"target_id" validate:"required"`
+}
+
+// RetagThreadDTO nouveaux tags d'un thread (vide = aucun tag)
+type RetagThreadDTO struct {
+	Tags []string `json:"tags" validate:"max=10"`
+}
+
+// moderationService implémentation concrète
+type moderationService struct {
+	moderationRepo repositories.ModerationRepository
+	threadRepo     repositories.ThreadRepository
+	messageRepo    repositories.MessageRepository
+	tagRepo        repositories.TagRepository
+	threadService  ThreadService
+}
+
+// NewModerationService crée une nouvelle instance du service
+func NewModerationService(moderationRepo repositories.ModerationRepository, threadRepo repositories.ThreadRepository, messageRepo repositories.MessageRepository, tagRepo repositories.TagRepository, threadService ThreadService) ModerationService {
+	return &moderationService{
+		moderationRepo: moderationRepo,
+		threadRepo:     threadRepo,
+		messageRepo:    messageRepo,
+		tagRepo:        tagRepo,
+		threadService:  threadService,
+	}
+}
+
+// SplitThread crée un nouveau thread à partir d'une sélection de commentaires
+// et laisse une note de redirection dans le thread d'origine
+func (s *moderationService) SplitThread(threadID uint, dto SplitThreadDTO, moderatorID uint) (*ThreadResponseDTO, error) {
+	if validationErrors := utils.ValidateStruct(dto); len(validationErrors) > 0 {
+		return nil, fmt.Errorf("erreur validation: %v: %w", validationErrors, utils.ErrInvalidInput)
+	}
+
+	source, err := s.findModeratedThread(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	messageIDs, err := s.validateSelection(threadID, dto.MessageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tagNames := dto.Tags
+	if len(tagNames) == 0 {
+		for _, tag := range source.Tags {
+			tagNames = append(tagNames, tag.Name)
+		}
+	}
+	tagIDs, err := resolveTagIDs(s.tagRepo, mergeThreadTags(tagNames, nil, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	// Le nouveau thread hérite de la visibilité de l'origine et appartient au modérateur
+	thread := &models.Thread{
+		Title:       strings.TrimSpace(dto.Title),
+		Description: fmt.Sprintf("Discussion séparée de [%s](/thread/%d).", markdownLinkText(source.Title), source.ID),
+		State:       models.ThreadStateOpen,
+		Visibility:  source.Visibility,
+		Access:      source.Access,
+		UserID:      moderatorID,
+	}
+	if err := s.threadRepo.Create(thread); err != nil {
+		return nil, fmt.Errorf("erreur création thread scindé: %w", err)
+	}
+
+	// Le nouveau thread est supprimé si les commentaires n'ont pas pu y être déplacés
+	if err := s.threadRepo.AttachTags(thread.ID, tagIDs); err != nil {
+		s.discardSplitThread(thread.ID)
+		return nil, fmt.Errorf("erreur attachement tags: %w", err)
+	}
+
+	moved, err := s.messageRepo.MoveToThread(messageIDs, threadID, thread.ID)
+	if err != nil {
+		s.discardSplitThread(thread.ID)
+		return nil, err
+	}
+
+	s.leaveRedirectNote(threadID, moderatorID, fmt.Sprintf(
+		"🔀 %d commentaire(s) déplacé(s) par la modération vers une nouvelle discussion : [%s](/thread/%d)",
+		moved, markdownLinkText(thread.Title), thread.ID,
+	))
+	s.record(models.ModerationActionSplit, threadID, &thread.ID, moderatorID, map[string]interface{}{
+		"message_ids": messageIDs,
+		"title":       thread.Title,
+	})
+
+	log.Printf("✂️ %d commentaire(s) du thread %d scindé(s) dans le thread %d par %d", moved, threadID, thread.ID, moderatorID)
+	return s.threadService.GetThread(thread.ID, &moderatorID)
+}
+
+// discardSplitThread supprime le thread créé par une scission qui a échoué
+func (s *moderationService) discardSplitThread(threadID uint) {
+	if err := s.threadRepo.Delete(threadID); err != nil {
+		log.Printf("❌ Erreur suppression du thread scindé %d: %v", threadID, err)
+	}
+}
+
+// MoveMessages déplace une sélection de commentaires vers un thread existant
+func (s *moderationService) MoveMessages(threadID uint, dto MoveMessagesDTO, moderatorID uint) (int64, error) {
+	if validationErrors := utils.ValidateStruct(dto); len(validationErrors) > 0 {
+		return 0, fmt.Errorf("erreur validation: %v: %w", validationErrors, utils.ErrInvalidInput)
+	}
+	if dto.TargetID == threadID {
+		return 0, fmt.Errorf("le thread de destination doit être différent: %w", utils.ErrInvalidInput)
+	}
+
+	if _, err := s.findModeratedThread(threadID); err != nil {
+		return 0, err
+	}
+	target, err := s.findModeratedThread(dto.TargetID)
+	if err != nil {
+		return 0, err
+	}
+
+	messageIDs, err := s.validateSelection(threadID, dto.MessageIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	moved, err := s.messageRepo.MoveToThread(messageIDs, threadID, target.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	s.leaveRedirectNote(threadID, moderatorID, fmt.Sprintf(
+		"🔀 %d commentaire(s) déplacé(s) par la modération vers [%s](/thread/%d)",
+		moved, markdownLinkText(target.Title), target.ID,
+	))
+	s.record(models.ModerationActionMove, threadID, &target.ID, moderatorID, map[string]interface{}{
+		"message_ids": messageIDs,
+	})
+
+	log.Printf("🔀 %d commentaire(s) déplacé(s) du thread %d vers le thread %d par %d", moved, threadID, target.ID, moderatorID)
+	return moved, nil
+}
+
+// RetagThread remplace les tags d'un thread
+func (s *moderationService) RetagThread(threadID uint, dto RetagThreadDTO, moderatorID uint) (*ThreadResponseDTO, error) {
+	if validationErrors := utils.ValidateStruct(dto); len(validationErrors) > 0 {
+		return nil, fmt.Errorf("erreur validation: %v: %w", validationErrors, utils.ErrInvalidInput)
+	}
+
+	thread, err := s.threadRepo.FindByID(threadID)
+	if err != nil {
+		return nil, utils.ErrThreadNotFound
+	}
+
+	previousTags := make([]string, 0, len(thread.Tags))
+	for _, tag := range thread.Tags {
+		previousTags = append(previousTags, tag.Name)
+	}
+
+	tagIDs, err := resolveTagIDs(s.tagRepo, mergeThreadTags(dto.Tags, nil, nil))
+	if err != nil {
+		return nil, err
+	}
+	if len(tagIDs) > 0 {
+		err = s.threadRepo.AttachTags(threadID, tagIDs)
+	} else {
+		err = s.threadRepo.DetachTags(threadID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur mise à jour tags: %w", err)
+	}
+
+	s.record(models.ModerationActionRetag, threadID, nil, moderatorID, map[string]interface{}{
+		"previous_tags": previousTags,
+		"tags":          dto.Tags,
+	})
+
+	log.Printf("🏷️ Thread %d retagué par %d: %v → %v", threadID, moderatorID, previousTags, dto.Tags)
+	return s.threadService.GetThread(threadID, &thread.UserID)
+}
+
+// MergeThreads fusionne un doublon dans un autre thread et journalise l'action
+func (s *moderationService) MergeThreads(sourceID, targetID, moderatorID uint) (*ThreadResponseDTO, error) {
+	target, err := s.threadService.MergeThreads(sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(models.ModerationActionMerge, sourceID, &targetID, moderatorID, nil)
+	return target, nil
+}
+
+// GetThreadLog récupère le journal de modération d'un thread
+func (s *moderationService) GetThreadLog(threadID uint) ([]*models.ModerationAction, error) {
+	return s.moderationRepo.FindByThreadID(threadID, maxModerationLogEntries)
+}
+
+// findModeratedThread récupère un thread dont les commentaires peuvent être déplacés
+func (s *moderationService) findModeratedThread(threadID uint) (*models.Thread, error) {
+	thread, err := s.threadRepo.FindByID(threadID)
+	if err != nil {
+		return nil, utils.ErrThreadNotFound
+	}
+	if thread.State == models.ThreadStateArchived {
+		return nil, utils.ErrThreadArchived
+	}
+
+	return thread, nil
+}
+
+// validateSelection vérifie que tous les commentaires sélectionnés appartiennent au thread
+// et retourne la sélection sans doublon
+func (s *moderationService) validateSelection(threadID uint, messageIDs []uint) ([]uint, error) {
+	inThread := make(map[uint]bool)
+	params := models.PaginationParams{Page: 1, PerPage: 100}
+	for {
+		messages, total, err := s.messageRepo.FindByThreadID(threadID, params, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range messages {
+			inThread[message.ID] = true
+		}
+		if len(messages) == 0 || params.Page*params.PerPage >= total {
+			break
+		}
+		params.Page++
+	}
+
+	var selection []uint
+	seen := make(map[uint]bool)
+	for _, id := range messageIDs {
+		if !inThread[id] {
+			return nil, fmt.Errorf("le commentaire %d n'appartient pas au thread %d: %w", id, threadID, utils.ErrInvalidInput)
+		}
+		if !seen[id] {
+			seen[id] = true
+			selection = append(selection, id)
+		}
+	}
+
+	return selection, nil
+}
+
+// leaveRedirectNote publie dans le thread d'origine une note indiquant où sont partis les commentaires
+func (s *moderationService) leaveRedirectNote(threadID, moderatorID uint, content string) {
+	note := &models.Message{
+		Content:  content,
+		ThreadID: threadID,
+		UserID:   moderatorID,
+	}
+	if err := s.messageRepo.Create(note); err != nil {
+		log.Printf("❌ Erreur note de redirection dans le thread %d: %v", threadID, err)
+	}
+}
+
+// record ajoute une entrée au journal de modération ; un échec est journalisé sans annuler l'action
+func (s *moderationService) record(action string, threadID uint, targetThreadID *uint, moderatorID uint, details map[string]interface{}) {
+	entry := &models.ModerationAction{
+		ModeratorID:    moderatorID,
+		Action:         action,
+		ThreadID:       threadID,
+		TargetThreadID: targetThreadID,
+	}
+
+	if details != nil {
+		encoded, err := json.Marshal(details)
+		if err == nil {
+			detailsJSON := string(encoded)
+			entry.Details = &detailsJSON
+		}
+	}
+
+	if err := s.moderationRepo.Create(entry); err != nil {
+		log.Printf("❌ Erreur journal de modération (%s, thread %d): %v", action, threadID, err)
+	}
+}
+
+// markdownLinkText neutralise les crochets d'un titre utilisé comme texte de lien Markdown
+func markdownLinkText(title string) string {
+	return strings.NewReplacer("[", "(", "]", ")").Replace(title)
+}
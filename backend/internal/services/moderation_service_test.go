@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"testing"
+)
+
+// splitThreadRepository thread d'origine en mémoire ; note les threads créés et supprimés
+type splitThreadRepository struct {
+	stubThreadRepository
+	attachErr error
+	created   []uint
+	deleted   []uint
+}
+
+func (r *splitThreadRepository) Create(thread *models.Thread) error {
+	thread.ID = 50
+	r.created = append(r.created, thread.ID)
+	return nil
+}
+
+func (r *splitThreadRepository) AttachTags(threadID uint, tagIDs []uint) error {
+	return r.attachErr
+}
+
+func (r *splitThreadRepository) Delete(id uint) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+// splitMessageRepository commentaires d'un thread dont le déplacement peut échouer
+type splitMessageRepository struct {
+	repositories.MessageRepository
+	messages []*models.Message
+	moveErr  error
+}
+
+func (r *splitMessageRepository) FindByThreadID(threadID uint, params models.PaginationParams, orderBy string) ([]*models.Message, int, error) {
+	return r.messages, len(r.messages), nil
+}
+
+func (r *splitMessageRepository) MoveToThread(messageIDs []uint, fromThreadID, toThreadID uint) (int64, error) {
+	return 0, r.moveErr
+}
+
+// failingTagRepository refuse la création de tags
+type failingTagRepository struct {
+	repositories.TagRepository
+}
+
+func (r *failingTagRepository) FindOrCreate(name, tagType string) (*models.Tag, error) {
+	return nil, errors.New("tag verrouillé")
+}
+
+func TestSplitThreadFailureLeavesNoThread(t *testing.T) {
+	tests := []struct {
+		name        string
+		tags        []string
+		attachErr   error
+		moveErr     error
+		wantCreated int
+	}{
+		{"tags introuvables", []string{"drill"}, nil, nil, 0},
+		{"attachement des tags en échec", nil, errors.New("connexion perdue"), nil, 1},
+		{"déplacement en échec", nil, nil, errors.New("connexion perdue"), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &models.Thread{Title: "Débat rap FR", State: models.ThreadStateOpen, Visibility: models.VisibilityPublic}
+			source.ID = 9
+			message := &models.Message{}
+			message.ID = 3
+
+			threadRepo := &splitThreadRepository{stubThreadRepository: stubThreadRepository{thread: source}, attachErr: tt.attachErr}
+			service := NewModerationService(nil, threadRepo,
+				&splitMessageRepository{messages: []*models.Message{message}, moveErr: tt.moveErr},
+				&failingTagRepository{}, nil)
+
+			dto := SplitThreadDTO{MessageIDs: []uint{3}, Title: "Débat drill UK", Tags: tt.tags}
+			if _, err := service.SplitThread(9, dto, 1); err == nil {
+				t.Fatalf("La scission doit échouer")
+			}
+			if len(threadRepo.created) != tt.wantCreated {
+				t.Errorf("Threads créés attendus: %d, Obtenus: %v", tt.wantCreated, threadRepo.created)
+			}
+			if len(threadRepo.deleted) != len(threadRepo.created) {
+				t.Errorf("Le thread créé doit être supprimé, créés: %v, supprimés: %v", threadRepo.created, threadRepo.deleted)
+			}
+		})
+	}
+}
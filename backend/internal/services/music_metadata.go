@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/pkg/musiclink"
+	"rythmitbackend/pkg/musicmeta"
+	"time"
+)
+
+// Délais avant la prochaine récupération des métadonnées d'un lien
+const (
+	metadataRefreshAfter  = 30 * 24 * time.Hour // rafraîchissement des métadonnées récupérées
+	metadataNotFoundRetry = 7 * 24 * time.Hour  // lien inconnu du fournisseur
+	metadataMaxBackoff    = 24 * time.Hour      // plafond des nouveaux essais après erreur
+)
+
+// MusicMetadataFetcher récupère en tâche de fond les métadonnées des liens musicaux en attente,
+// pour que les requêtes ne lisent que le cache et n'attendent jamais une plateforme
+type MusicMetadataFetcher struct {
+	repo      repositories.MetadataRepository
+	provider  musicmeta.Provider
+	batchSize int
+	timeout   time.Duration
+	wake      chan struct{}
+	now       func() time.Time
+}
+
+// musicMetadata worker global réveillé par les services (nil tant qu'il n'est pas démarré)
+var musicMetadata *MusicMetadataFetcher
+
+// NewMusicMetadataFetcher crée un worker ; timeout borne chaque appel au fournisseur
+func NewMusicMetadataFetcher(repo repositories.MetadataRepository, provider musicmeta.Provider, batchSize int, timeout time.Duration) *MusicMetadataFetcher {
+	return &MusicMetadataFetcher{
+		repo:      repo,
+		provider:  provider,
+		batchSize: batchSize,
+		timeout:   timeout,
+		wake:      make(chan struct{}, 1),
+		now:       time.Now,
+	}
+}
+
+// FetchDue récupère un lot de métadonnées en attente ou à rafraîchir et retourne leur nombre
+func (f *MusicMetadataFetcher) FetchDue() (int, error) {
+	due, err := f.repo.FindDue(f.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, meta := range due {
+		f.fetch(meta)
+		if err := f.repo.SaveResult(meta); err != nil {
+			return i, err
+		}
+	}
+
+	return len(due), nil
+}
+
+// Wake demande un lot sans attendre le prochain tick (sans effet si une demande est déjà en attente)
+func (f *MusicMetadataFetcher) Wake() {
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+// fetch interroge le fournisseur et met à jour le statut et la prochaine récupération
+func (f *MusicMetadataFetcher) fetch(meta *models.MusicMetadata) {
+	link := &musiclink.Link{Platform: meta.Platform, Type: meta.EntityType, ID: meta.EntityID, URL: meta.URL}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+	result, err := f.provider.Fetch(ctx, link)
+
+	now := f.now()
+	meta.Provider = f.provider.Name()
+	switch {
+	case err == nil:
+		meta.Status = models.MetadataStatusOK
+		meta.Title = truncateRunes(result.Title, 300)
+		meta.Artist = truncateRunes(result.Artist, 200)
+		meta.Album = truncateRunes(result.Album, 300)
+		meta.DurationMs = result.DurationMs
+		meta.ArtworkURL = result.ArtworkURL
+		if len(meta.ArtworkURL) > 500 {
+			meta.ArtworkURL = ""
+		}
+		meta.Attempts = 0
+		meta.FetchedAt = &now
+	case errors.Is(err, musicmeta.ErrNotFound):
+		meta.Status = models.MetadataStatusNotFound
+		meta.Attempts = 0
+	default:
+		meta.Status = models.MetadataStatusError
+		meta.Attempts++
+		log.Printf("⚠️ Métadonnées de %s indisponibles (essai %d): %v", link.Key(), meta.Attempts, err)
+	}
+
+	meta.NextAttemptAt = metadataNextAttempt(meta.Status, meta.Attempts, now)
+}
+
+// metadataNextAttempt date de la prochaine récupération selon le résultat ;
+// après une erreur, l'attente double à chaque essai (1 min, 2 min, 4 min... jusqu'à 24 h)
+func metadataNextAttempt(status string, attempts int, now time.Time) time.Time {
+	switch status {
+	case models.MetadataStatusOK:
+		return now.Add(metadataRefreshAfter)
+	case models.MetadataStatusNotFound:
+		return now.Add(metadataNotFoundRetry)
+	}
+
+	backoff := time.Minute
+	for i := 1; i < attempts && backoff < metadataMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > metadataMaxBackoff {
+		backoff = metadataMaxBackoff
+	}
+	return now.Add(backoff)
+}
+
+// truncateRunes coupe un texte à limit caractères sans couper un caractère multi-octets
+func truncateRunes(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
+
+// StartMusicMetadataFetcher installe le worker global et lance la récupération périodique
+// (et à chaque réveil) ; sans fournisseur, les liens restent affichés sans métadonnées
+func StartMusicMetadataFetcher(repo repositories.MetadataRepository, provider musicmeta.Provider, interval time.Duration, batchSize int, timeout time.Duration) {
+	if provider == nil || interval <= 0 {
+		log.Println("⏸️  Récupération des métadonnées musicales désactivée")
+		return
+	}
+
+	if batchSize <= 0 {
+		batchSize = 20
+	}
+
+	fetcher := NewMusicMetadataFetcher(repo, provider, batchSize, timeout)
+	musicMetadata = fetcher
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-fetcher.wake:
+			}
+
+			// Vider la file par lots tant qu'ils sont pleins
+			for {
+				count, err := fetcher.FetchDue()
+				if err != nil {
+					log.Printf("❌ Erreur récupération des métadonnées musicales: %v", err)
+					break
+				}
+				if count < batchSize {
+					break
+				}
+			}
+		}
+	}()
+
+	log.Printf("✅ Métadonnées musicales actives (fournisseur %s, toutes les %s)", provider.Name(), interval)
+}
+
+// WakeMusicMetadataFetcher réveille le worker global (sans effet s'il n'est pas démarré)
+func WakeMusicMetadataFetcher() {
+	if musicMetadata != nil {
+		musicMetadata.Wake()
+	}
+}
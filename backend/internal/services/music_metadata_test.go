@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/pkg/musiclink"
+	"rythmitbackend/pkg/musicmeta"
+	"testing"
+	"time"
+)
+
+// fakeMetadataRepository file de métadonnées en mémoire
+type fakeMetadataRepository struct {
+	due   []*models.MusicMetadata
+	saved []*models.MusicMetadata
+}
+
+func (r *fakeMetadataRepository) Enqueue(embeds []*models.MusicEmbed) error        { return nil }
+func (r *fakeMetadataRepository) AttachToEmbeds(embeds []*models.MusicEmbed) error { return nil }
+func (r *fakeMetadataRepository) FindDue(limit int) ([]*models.MusicMetadata, error) {
+	return r.due, nil
+}
+func (r *fakeMetadataRepository) SaveResult(meta *models.MusicMetadata) error {
+	r.saved = append(r.saved, meta)
+	return nil
+}
+
+// failingProvider fournisseur toujours indisponible
+type failingProvider struct{}
+
+func (failingProvider) Name() string { return "failing" }
+func (failingProvider) Fetch(ctx context.Context, link *musiclink.Link) (*musicmeta.Metadata, error) {
+	return nil, errors.New("plateforme indisponible")
+}
+
+func TestMetadataNextAttempt(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		status   string
+		attempts int
+		want     time.Duration
+	}{
+		{"récupération réussie", models.MetadataStatusOK, 0, metadataRefreshAfter},
+		{"lien inconnu", models.MetadataStatusNotFound, 0, metadataNotFoundRetry},
+		{"première erreur", models.MetadataStatusError, 1, time.Minute},
+		{"troisième erreur", models.MetadataStatusError, 3, 4 * time.Minute},
+		{"erreurs répétées plafonnées", models.MetadataStatusError, 40, metadataMaxBackoff},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := metadataNextAttempt(c.status, c.attempts, now).Sub(now); got != c.want {
+				t.Errorf("Attendu: %s, Obtenu: %s", c.want, got)
+			}
+		})
+	}
+}
+
+func TestMusicMetadataFetcherFetchDue(t *testing.T) {
+	provider := musicmeta.NewFixtureProvider(map[string]*musicmeta.Metadata{
+		"deezer:track:3135556": {Title: "Harder, Better, Faster, Stronger", Artist: "Daft Punk", DurationMs: 224000},
+	})
+
+	t.Run("statuts selon le fournisseur", func(t *testing.T) {
+		repo := &fakeMetadataRepository{due: []*models.MusicMetadata{
+			{Platform: "deezer", EntityType: "track", EntityID: "3135556", Status: models.MetadataStatusPending},
+			{Platform: "deezer", EntityType: "track", EntityID: "1", Status: models.MetadataStatusPending},
+		}}
+		count, err := NewMusicMetadataFetcher(repo, provider, 20, time.Second).FetchDue()
+		if err != nil || count != 2 || len(repo.saved) != 2 {
+			t.Fatalf("Attendu 2 résultats enregistrés, Obtenu: %d (%v)", len(repo.saved), err)
+		}
+		if found := repo.saved[0]; found.Status != models.MetadataStatusOK || found.Artist != "Daft Punk" || found.FetchedAt == nil {
+			t.Errorf("Métadonnées non renseignées: %+v", found)
+		}
+		if missing := repo.saved[1]; missing.Status != models.MetadataStatusNotFound || missing.Title != "" {
+			t.Errorf("Attendu: not_found, Obtenu: %+v", missing)
+		}
+	})
+
+	t.Run("erreur conserve le cache", func(t *testing.T) {
+		cached := &models.MusicMetadata{Platform: "deezer", EntityType: "track", EntityID: "3135556",
+			Status: models.MetadataStatusOK, Title: "Ancien titre", Attempts: 1}
+		repo := &fakeMetadataRepository{due: []*models.MusicMetadata{cached}}
+		if _, err := NewMusicMetadataFetcher(repo, failingProvider{}, 20, time.Second).FetchDue(); err != nil {
+			t.Fatalf("Erreur inattendue: %v", err)
+		}
+		if cached.Status != models.MetadataStatusError || cached.Attempts != 2 || cached.Title != "Ancien titre" {
+			t.Errorf("Attendu: erreur au 2e essai sans perte du titre, Obtenu: %+v", cached)
+		}
+	})
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"strings"
+	"time"
+)
+
+// PollService interface pour la logique métier des sondages de threads
+type PollService interface {
+	CreatePoll(threadID, userID uint, dto CreatePollDTO) (*PollResponseDTO, error)
+	GetPoll(threadID uint, userID *uint) (*PollResponseDTO, error)
+	Vote(threadID, userID uint, optionIDs []uint) (*PollResponseDTO, error)
+	DeletePoll(threadID, userID uint, isAdmin bool) error
+}
+
+// CreatePollDTO données de création d'un sondage
+type CreatePollDTO struct {
+	Question       string     `json:"question" validate:"required,min=1,max=300"`
+	Options        []string   `json:"options" validate:"required,min=2,max=10"`
+	MultipleChoice bool       `json:"multiple_choice"`
+	HideResults    bool       `json:"hide_results"`
+	ClosesAt       *time.Time `json:"closes_at,omitempty"`
+}
+
+// PollResponseDTO sondage tel que vu par un utilisateur donné
+type PollResponseDTO struct {
+	ID             uint                    `json:"id"`
+	Question       string                  `json:"question"`
+	MultipleChoice bool                    `json:"multiple_choice"`
+	HideResults    bool                    `json:"hide_results"`
+	ClosesAt       *string                 `json:"closes_at,omitempty"`
+	IsClosed       bool                    `json:"is_closed"`
+	HasVoted       bool                    `json:"has_voted"`
+	UserVotes      []uint                  `json:"user_votes,omitempty"`
+	ResultsVisible bool                    `json:"results_visible"`
+	VoterCount     *int                    `json:"voter_count,omitempty"` // nil si les résultats sont masqués
+	Options        []PollOptionResponseDTO `json:"options"`
+}
+
+// PollOptionResponseDTO choix d'un sondage ; VoteCount nil si les résultats sont masqués
+type PollOptionResponseDTO struct {
+	ID        uint   `json:"id"`
+	Label     string `json:"label"`
+	VoteCount *int   `json:"vote_count,omitempty"`
+}
+
+// pollService implémentation concrète
+type pollService struct {
+	pollRepo      repositories.PollRepository
+	threadService ThreadService
+}
+
+// NewPollService crée une nouvelle instance du service
+func NewPollService(pollRepo repositories.PollRepository, threadService ThreadService) PollService {
+	return &pollService{
+		pollRepo:      pollRepo,
+		threadService: threadService,
+	}
+}
+
+// CreatePoll ajoute un sondage à un thread (auteur uniquement, un sondage par thread)
+func (s *pollService) CreatePoll(threadID, userID uint, dto CreatePollDTO) (*PollResponseDTO, error) {
+	thread, err := s.threadService.GetThread(threadID, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if thread.Author.ID != userID {
+		return nil, utils.ErrUnauthorized
+	}
+
+	if err := checkStateAllows(thread.State, ThreadActionEdit); err != nil {
+		return nil, err
+	}
+
+	if err := validatePollDTO(&dto); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.pollRepo.FindByThreadID(threadID); err == nil {
+		return nil, fmt.Errorf("ce thread a déjà un sondage: %w", utils.ErrInvalidInput)
+	} else if !errors.Is(err, utils.ErrPollNotFound) {
+		return nil, err
+	}
+
+	poll := newPollFromDTO(threadID, dto)
+	if err := s.pollRepo.Create(poll); err != nil {
+		return nil, err
+	}
+
+	return pollToDTO(poll, nil, time.Now()), nil
+}
+
+// GetPoll récupère le sondage d'un thread accessible à l'utilisateur
+func (s *pollService) GetPoll(threadID uint, userID *uint) (*PollResponseDTO, error) {
+	if _, err := s.threadService.GetThread(threadID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.loadPoll(threadID, userID)
+}
+
+// Vote enregistre le bulletin d'un utilisateur et retourne les résultats à jour
+func (s *pollService) Vote(threadID, userID uint, optionIDs []uint) (*PollResponseDTO, error) {
+	thread, err := s.threadService.GetThread(threadID, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Un thread fermé ou archivé n'accepte plus de votes
+	if err := checkStateAllows(thread.State, ThreadActionVote); err != nil {
+		return nil, err
+	}
+
+	poll, err := s.pollRepo.FindByThreadID(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.IsClosed(time.Now()) {
+		return nil, utils.ErrPollClosed
+	}
+
+	if err := validatePollChoice(poll, optionIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.pollRepo.Vote(poll.ID, userID, optionIDs); err != nil {
+		return nil, err
+	}
+
+	return s.loadPoll(threadID, &userID)
+}
+
+// DeletePoll supprime le sondage d'un thread (auteur ou admin)
+func (s *pollService) DeletePoll(threadID, userID uint, isAdmin bool) error {
+	thread, err := s.threadService.GetThread(threadID, &userID)
+	if err != nil && !(isAdmin && errors.Is(err, utils.ErrUnauthorized)) {
+		return err
+	}
+
+	if !isAdmin && thread.Author.ID != userID {
+		return utils.ErrUnauthorized
+	}
+
+	poll, err := s.pollRepo.FindByThreadID(threadID)
+	if err != nil {
+		return err
+	}
+
+	return s.pollRepo.Delete(poll.ID)
+}
+
+// loadPoll charge un sondage et le vote de l'utilisateur
+func (s *pollService) loadPoll(threadID uint, userID *uint) (*PollResponseDTO, error) {
+	poll, err := s.pollRepo.FindByThreadID(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	var userVotes []uint
+	if userID != nil {
+		userVotes, err = s.pollRepo.GetUserVotes(poll.ID, *userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pollToDTO(poll, userVotes, time.Now()), nil
+}
+
+// validatePollDTO nettoie et valide un sondage avant sa création
+func validatePollDTO(dto *CreatePollDTO) error {
+	dto.Question = strings.TrimSpace(dto.Question)
+
+	var options []string
+	seen := make(map[string]bool)
+	for _, option := range dto.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || seen[strings.ToLower(option)] {
+			continue
+		}
+		if len(option) > 200 {
+			return fmt.Errorf("option de sondage trop longue (200 caractères max): %w", utils.ErrInvalidInput)
+		}
+		seen[strings.ToLower(option)] = true
+		options = append(options, option)
+	}
+	dto.Options = options
+
+	if validationErrors := utils.ValidateStruct(*dto); len(validationErrors) > 0 {
+		return fmt.Errorf("erreur validation sondage: %v: %w", validationErrors, utils.ErrInvalidInput)
+	}
+
+	if dto.ClosesAt != nil && !dto.ClosesAt.After(time.Now()) {
+		return fmt.Errorf("la date de clôture doit être dans le futur: %w", utils.ErrInvalidInput)
+	}
+
+	return nil
+}
+
+// validatePollChoice vérifie que les options choisies appartiennent au sondage et respectent son mode
+func validatePollChoice(poll *models.Poll, optionIDs []uint) error {
+	if len(optionIDs) == 0 {
+		return fmt.Errorf("aucune option choisie: %w", utils.ErrInvalidInput)
+	}
+
+	if !poll.MultipleChoice && len(optionIDs) > 1 {
+		return fmt.Errorf("ce sondage n'accepte qu'un seul choix: %w", utils.ErrInvalidInput)
+	}
+
+	valid := make(map[uint]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		valid[option.ID] = true
+	}
+
+	chosen := make(map[uint]bool, len(optionIDs))
+	for _, id := range optionIDs {
+		if !valid[id] {
+			return fmt.Errorf("option %d inconnue pour ce sondage: %w", id, utils.ErrInvalidInput)
+		}
+		if chosen[id] {
+			return fmt.Errorf("option %d choisie plusieurs fois: %w", id, utils.ErrInvalidInput)
+		}
+		chosen[id] = true
+	}
+
+	return nil
+}
+
+// newPollFromDTO construit le modèle d'un sondage validé
+func newPollFromDTO(threadID uint, dto CreatePollDTO) *models.Poll {
+	poll := &models.Poll{
+		ThreadID:       threadID,
+		Question:       dto.Question,
+		MultipleChoice: dto.MultipleChoice,
+		HideResults:    dto.HideResults,
+		ClosesAt:       dto.ClosesAt,
+		CreatedAt:      time.Now(),
+	}
+
+	for _, label := range dto.Options {
+		poll.Options = append(poll.Options, &models.PollOption{Label: label})
+	}
+
+	return poll
+}
+
+// pollToDTO convertit un sondage pour un utilisateur : les résultats masqués
+// ne sont visibles qu'après avoir voté ou une fois le sondage clôturé
+func pollToDTO(poll *models.Poll, userVotes []uint, now time.Time) *PollResponseDTO {
+	isClosed := poll.IsClosed(now)
+	hasVoted := len(userVotes) > 0
+	resultsVisible := !poll.HideResults || hasVoted || isClosed
+
+	dto := &PollResponseDTO{
+		ID:             poll.ID,
+		Question:       poll.Question,
+		MultipleChoice: poll.MultipleChoice,
+		HideResults:    poll.HideResults,
+		IsClosed:       isClosed,
+		HasVoted:       hasVoted,
+		UserVotes:      userVotes,
+		ResultsVisible: resultsVisible,
+		Options:        make([]PollOptionResponseDTO, 0, len(poll.Options)),
+	}
+
+	if poll.ClosesAt != nil {
+		closesAt := poll.ClosesAt.Format("2006-01-02T15:04:05Z")
+		dto.ClosesAt = &closesAt
+	}
+
+	if resultsVisible {
+		voterCount := poll.VoterCount
+		dto.VoterCount = &voterCount
+	}
+
+	for _, option := range poll.Options {
+		optionDTO := PollOptionResponseDTO{ID: option.ID, Label: option.Label}
+		if resultsVisible {
+			voteCount := option.VoteCount
+			optionDTO.VoteCount = &voteCount
+		}
+		dto.Options = append(dto.Options, optionDTO)
+	}
+
+	return dto
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+	"testing"
+	"time"
+)
+
+func newTestPoll(multipleChoice, hideResults bool, closesAt *time.Time) *models.Poll {
+	return &models.Poll{
+		ID:             1,
+		Question:       "Meilleur album de 2024 ?",
+		MultipleChoice: multipleChoice,
+		HideResults:    hideResults,
+		ClosesAt:       closesAt,
+		VoterCount:     3,
+		Options: []*models.PollOption{
+			{ID: 10, Label: "A", VoteCount: 2},
+			{ID: 11, Label: "B", VoteCount: 1},
+		},
+	}
+}
+
+func TestValidatePollChoice(t *testing.T) {
+	single := newTestPoll(false, false, nil)
+	multiple := newTestPoll(true, false, nil)
+
+	cases := []struct {
+		name    string
+		poll    *models.Poll
+		options []uint
+		wantErr bool
+	}{
+		{"choix unique valide", single, []uint{10}, false},
+		{"aucun choix", single, nil, true},
+		{"deux choix sur choix unique", single, []uint{10, 11}, true},
+		{"option inconnue", single, []uint{99}, true},
+		{"choix multiple valide", multiple, []uint{10, 11}, false},
+		{"option en double", multiple, []uint{10, 10}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validatePollChoice(c.poll, c.options)
+			if (err != nil) != c.wantErr {
+				t.Errorf("Erreur attendue: %v, Obtenu: %v", c.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, utils.ErrInvalidInput) {
+				t.Errorf("Erreur non typée ErrInvalidInput: %v", err)
+			}
+		})
+	}
+}
+
+func TestPollToDTOHidesResultsUntilVoted(t *testing.T) {
+	now := time.Now()
+	poll := newTestPoll(false, true, nil)
+
+	dto := pollToDTO(poll, nil, now)
+	if dto.ResultsVisible || dto.VoterCount != nil || dto.Options[0].VoteCount != nil {
+		t.Errorf("Les résultats devraient être masqués avant le vote")
+	}
+
+	dto = pollToDTO(poll, []uint{10}, now)
+	if !dto.ResultsVisible || dto.Options[0].VoteCount == nil || *dto.Options[0].VoteCount != 2 {
+		t.Errorf("Les résultats devraient être visibles après le vote")
+	}
+
+	closed := now.Add(-time.Hour)
+	dto = pollToDTO(newTestPoll(false, true, &closed), nil, now)
+	if !dto.IsClosed || !dto.ResultsVisible {
+		t.Errorf("Les résultats d'un sondage clôturé devraient être visibles")
+	}
+}
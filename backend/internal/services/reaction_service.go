@@ -0,0 +1,236 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+)
+
+// maxReactorsPerTarget nombre maximum de réactions détaillées (qui a réagi) retournées par cible
+const maxReactorsPerTarget = 500
+
+// allowedReactions réactions proposées (voir SetAllowedReactions)
+var allowedReactions = models.DefaultReactionSet
+
+// SetAllowedReactions remplace l'ensemble des réactions proposées (configuration REACTION_SET)
+func SetAllowedReactions(set []string) {
+	if len(set) > 0 {
+		allowedReactions = set
+	}
+}
+
+// ReactionNotifier fonction appelée après chaque ajout ou retrait de réaction
+// Branché au démarrage sur le WebSocket (voir SetReactionNotifier)
+type ReactionNotifier func(change *ReactionChange)
+
+// reactionNotifier notificateur global (nil = réactions enregistrées sans diffusion)
+var reactionNotifier ReactionNotifier
+
+// SetReactionNotifier enregistre la fonction appelée pour chaque changement de réaction
+func SetReactionNotifier(notifier ReactionNotifier) {
+	reactionNotifier = notifier
+}
+
+// ReactionService interface pour les réactions emoji sur les commentaires et les messages privés
+type ReactionService interface {
+	AllowedReactions() []string
+	ToggleReaction(targetType string, targetID uint, emoji string, userID uint) (*ReactionChange, error)
+	GetReactions(targetType string, targetID, userID uint) ([]ReactionDetailDTO, error)
+}
+
+// ReactionChange ajout ou retrait d'une réaction, avec les compteurs à jour de la cible
+type ReactionChange struct {
+	TargetType     string                    `json:"target_type"`
+	TargetID       uint                      `json:"target_id"`
+	ThreadID       uint                      `json:"thread_id,omitempty"`       // commentaire
+	ConversationID uint                      `json:"conversation_id,omitempty"` // message privé
+	Participants   []uint                    `json:"-"`                         // message privé : expéditeur et destinataire
+	UserID         uint                      `json:"user_id"`
+	Emoji          string                    `json:"emoji"`
+	Added          bool                      `json:"added"`
+	Reactions      []*models.ReactionSummary `json:"reactions"`
+}
+
+// ReactionDetailDTO réactions d'un emoji avec la liste des utilisateurs
+type ReactionDetailDTO struct {
+	Emoji   string           `json:"emoji"`
+	Count   int              `json:"count"`
+	Reacted bool             `json:"reacted"`
+	Users   []UserSummaryDTO `json:"users"`
+}
+
+// reactionService implémentation concrète
+type reactionService struct {
+	reactionRepo      repositories.ReactionRepository
+	messageRepo       repositories.MessageRepository
+	directMessageRepo repositories.DirectMessageRepository
+	threadRepo        repositories.ThreadRepository
+}
+
+// NewReactionService crée une nouvelle instance du service
+func NewReactionService(reactionRepo repositories.ReactionRepository, messageRepo repositories.MessageRepository, directMessageRepo repositories.DirectMessageRepository, threadRepo repositories.ThreadRepository) ReactionService {
+	return &reactionService{
+		reactionRepo:      reactionRepo,
+		messageRepo:       messageRepo,
+		directMessageRepo: directMessageRepo,
+		threadRepo:        threadRepo,
+	}
+}
+
+// AllowedReactions retourne les réactions proposées
+func (s *reactionService) AllowedReactions() []string {
+	return allowedReactions
+}
+
+// ToggleReaction ajoute la réaction de l'utilisateur, ou la retire s'il avait déjà réagi avec cet emoji
+func (s *reactionService) ToggleReaction(targetType string, targetID uint, emoji string, userID uint) (*ReactionChange, error) {
+	canonical, ok := canonicalReaction(emoji)
+	if !ok {
+		return nil, utils.ErrReactionInvalid
+	}
+
+	change, err := s.authorizeTarget(targetType, targetID, userID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.reactionRepo.Remove(userID, targetType, targetID, canonical)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		if _, err := s.reactionRepo.Add(&models.Reaction{
+			UserID:     userID,
+			TargetType: targetType,
+			TargetID:   targetID,
+			Emoji:      canonical,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	summaries, err := s.reactionRepo.SummariesByTargets(targetType, []uint{targetID}, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	change.UserID = userID
+	change.Emoji = canonical
+	change.Added = !removed
+	change.Reactions = summaries[targetID]
+	if change.Reactions == nil {
+		change.Reactions = []*models.ReactionSummary{}
+	}
+
+	if reactionNotifier != nil {
+		reactionNotifier(change)
+	}
+
+	action := "ajoutée"
+	if removed {
+		action = "retirée"
+	}
+	log.Printf("😀 Réaction %s %s par %d sur %s %d", canonical, action, userID, targetType, targetID)
+	return change, nil
+}
+
+// GetReactions retourne les réactions d'une cible regroupées par emoji avec les utilisateurs
+func (s *reactionService) GetReactions(targetType string, targetID, userID uint) ([]ReactionDetailDTO, error) {
+	if _, err := s.authorizeTarget(targetType, targetID, userID, false); err != nil {
+		return nil, err
+	}
+
+	reactions, err := s.reactionRepo.FindByTarget(targetType, targetID, maxReactorsPerTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	details := []ReactionDetailDTO{}
+	index := make(map[string]int)
+	for _, reaction := range reactions {
+		i, ok := index[reaction.Emoji]
+		if !ok {
+			i = len(details)
+			index[reaction.Emoji] = i
+			details = append(details, ReactionDetailDTO{Emoji: reaction.Emoji, Users: []UserSummaryDTO{}})
+		}
+
+		details[i].Count++
+		details[i].Reacted = details[i].Reacted || reaction.UserID == userID
+		details[i].Users = append(details[i].Users, UserSummaryDTO{
+			ID:       reaction.UserID,
+			Username: reaction.Username,
+		})
+	}
+
+	return details, nil
+}
+
+// authorizeTarget vérifie que la cible existe et que l'utilisateur peut la consulter
+// (et y réagir si react, ce qui exclut les threads archivés) ;
+// retourne un changement pré-rempli avec le contexte de la cible
+func (s *reactionService) authorizeTarget(targetType string, targetID, userID uint, react bool) (*ReactionChange, error) {
+	change := &ReactionChange{TargetType: targetType, TargetID: targetID}
+
+	switch targetType {
+	case models.ReactionTargetComment:
+		message, err := s.messageRepo.FindByID(targetID)
+		if err != nil {
+			return nil, utils.ErrMessageNotFound
+		}
+
+		thread, err := s.threadRepo.FindByID(message.ThreadID)
+		if err != nil {
+			return nil, utils.ErrThreadNotFound
+		}
+		if react && thread.State == models.ThreadStateArchived {
+			return nil, utils.ErrThreadArchived
+		}
+		if thread.Visibility != models.VisibilityPublic {
+			canView, err := s.threadRepo.CanView(thread.ID, userID)
+			if err != nil {
+				return nil, err
+			}
+			if !canView {
+				return nil, utils.ErrUnauthorized
+			}
+		}
+
+		change.ThreadID = thread.ID
+
+	case models.ReactionTargetMessage:
+		message, err := s.directMessageRepo.GetMessageByID(targetID)
+		if err != nil {
+			return nil, utils.ErrMessageNotFound
+		}
+		if message.SenderID != userID && message.ReceiverID != userID {
+			return nil, utils.ErrUnauthorized
+		}
+
+		change.ConversationID = message.ConversationID
+		change.Participants = []uint{message.SenderID, message.ReceiverID}
+
+	default:
+		return nil, fmt.Errorf("type de réaction inconnu %q: %w", targetType, utils.ErrInvalidInput)
+	}
+
+	return change, nil
+}
+
+// canonicalReaction retrouve l'emoji de l'ensemble configuré, avec ou sans sélecteur de variante
+func canonicalReaction(emoji string) (string, bool) {
+	normalized := models.NormalizeReaction(emoji)
+	if normalized == "" {
+		return "", false
+	}
+
+	for _, allowed := range allowedReactions {
+		if models.NormalizeReaction(allowed) == normalized {
+			return allowed, true
+		}
+	}
+
+	return "", false
+}
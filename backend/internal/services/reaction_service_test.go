@@ -0,0 +1,40 @@
+package services
+
+import (
+	"rythmitbackend/internal/models"
+	"testing"
+)
+
+func TestCanonicalReaction(t *testing.T) {
+	defer SetAllowedReactions(models.DefaultReactionSet)
+	SetAllowedReactions([]string{"🔥", "⏭️", "💯"})
+
+	cases := map[string]struct {
+		want string
+		ok   bool
+	}{
+		"🔥":    {"🔥", true},
+		"⏭️":   {"⏭️", true},
+		"⏭":    {"⏭️", true}, // sans sélecteur de variante
+		" 💯 ":  {"💯", true},
+		"😂":    {"", false}, // hors de l'ensemble configuré
+		"":     {"", false},
+		"fire": {"", false},
+	}
+
+	for emoji, expected := range cases {
+		got, ok := canonicalReaction(emoji)
+		if got != expected.want || ok != expected.ok {
+			t.Errorf("canonicalReaction(%q) = (%q, %v), attendu (%q, %v)", emoji, got, ok, expected.want, expected.ok)
+		}
+	}
+}
+
+func TestSetAllowedReactionsIgnoresEmptySet(t *testing.T) {
+	defer SetAllowedReactions(models.DefaultReactionSet)
+
+	SetAllowedReactions(nil)
+	if len(allowedReactions) != len(models.DefaultReactionSet) {
+		t.Errorf("Un ensemble vide ne devrait pas remplacer les réactions par défaut, Obtenu: %v", allowedReactions)
+	}
+}
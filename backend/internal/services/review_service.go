@@ -0,0 +1,329 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"strings"
+)
+
+// maxReviewTracks nombre maximum de morceaux notés dans une critique
+const maxReviewTracks = 50
+
+// ReviewService interface pour les critiques d'artistes, d'albums et de morceaux
+type ReviewService interface {
+	SaveReview(threadID, userID uint, dto SaveReviewDTO) (*ReviewResponseDTO, error)
+	GetReview(threadID uint, userID *uint) (*ReviewResponseDTO, error)
+	DeleteReview(threadID, userID uint, isAdmin bool) error
+	GetTagReviews(tagName string, viewerID *uint, filter models.ReviewFilter) (*TagReviewsDTO, error)
+}
+
+// SaveReviewDTO données d'une critique : le sujet (tag) et sa note, avec les notes par morceau optionnelles
+type SaveReviewDTO struct {
+	Subject     string                `json:"subject"`      // nom du tag critiqué
+	SubjectType string                `json:"subject_type"` // artist, album ou track
+	Score       float64               `json:"score"`
+	Tracks      []ReviewTrackScoreDTO `json:"tracks,omitempty"`
+}
+
+// ReviewTrackScoreDTO note d'un morceau
+type ReviewTrackScoreDTO struct {
+	Title string  `json:"title"`
+	Score float64 `json:"score"`
+}
+
+// ReviewResponseDTO critique d'un thread
+type ReviewResponseDTO struct {
+	ThreadID     uint                  `json:"thread_id"`
+	ThreadTitle  string                `json:"thread_title,omitempty"`
+	Author       *UserSummaryDTO       `json:"author,omitempty"`
+	Subject      TagResponseDTO        `json:"subject"`
+	Score        float64               `json:"score"`
+	Tracks       []ReviewTrackScoreDTO `json:"tracks,omitempty"`
+	TrackAverage *float64              `json:"track_average,omitempty"` // moyenne des notes par morceau
+	CreatedAt    string                `json:"created_at"`
+	UpdatedAt    string                `json:"updated_at"`
+}
+
+// TagReviewsDTO critiques d'un tag avec sa note moyenne
+type TagReviewsDTO struct {
+	Tag        TagResponseDTO      `json:"tag"`
+	Rating     TagRatingDTO        `json:"rating"`
+	Reviews    []ReviewResponseDTO `json:"reviews"`
+	Pagination PaginationInfo      `json:"pagination"`
+}
+
+// TagRatingDTO note moyenne des critiques d'un tag (nil s'il n'a pas encore de critique)
+type TagRatingDTO struct {
+	Average     *float64 `json:"average"`
+	ReviewCount int      `json:"review_count"`
+}
+
+// reviewService implémentation concrète
+type reviewService struct {
+	reviewRepo    repositories.ReviewRepository
+	tagRepo       repositories.TagRepository
+	threadService ThreadService
+}
+
+// NewReviewService crée une nouvelle instance du service
+func NewReviewService(reviewRepo repositories.ReviewRepository, tagRepo repositories.TagRepository, threadService ThreadService) ReviewService {
+	return &reviewService{
+		reviewRepo:    reviewRepo,
+		tagRepo:       tagRepo,
+		threadService: threadService,
+	}
+}
+
+// SaveReview fait d'un thread une critique, ou la met à jour (auteur uniquement)
+func (s *reviewService) SaveReview(threadID, userID uint, dto SaveReviewDTO) (*ReviewResponseDTO, error) {
+	thread, err := s.threadService.GetThread(threadID, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if thread.Author.ID != userID {
+		return nil, utils.ErrUnauthorized
+	}
+
+	if err := checkStateAllows(thread.State, ThreadActionEdit); err != nil {
+		return nil, err
+	}
+
+	if err := validateReviewDTO(&dto); err != nil {
+		return nil, err
+	}
+
+	tag, err := s.resolveSubjectTag(dto.Subject, dto.SubjectType)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &models.Review{
+		ThreadID: threadID,
+		TagID:    tag.ID,
+		Score:    dto.Score,
+	}
+	for _, track := range dto.Tracks {
+		review.Tracks = append(review.Tracks, &models.ReviewTrack{Title: track.Title, Score: track.Score})
+	}
+
+	if err := s.reviewRepo.Save(review); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.reviewRepo.FindByThreadID(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return reviewToDTO(saved), nil
+}
+
+// GetReview récupère la critique d'un thread accessible à l'utilisateur
+func (s *reviewService) GetReview(threadID uint, userID *uint) (*ReviewResponseDTO, error) {
+	if _, err := s.threadService.GetThread(threadID, userID); err != nil {
+		return nil, err
+	}
+
+	review, err := s.reviewRepo.FindByThreadID(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return reviewToDTO(review), nil
+}
+
+// DeleteReview repasse un thread critique en discussion simple (auteur ou admin)
+func (s *reviewService) DeleteReview(threadID, userID uint, isAdmin bool) error {
+	thread, err := s.threadService.GetThread(threadID, &userID)
+	if err != nil && !(isAdmin && errors.Is(err, utils.ErrUnauthorized)) {
+		return err
+	}
+
+	if !isAdmin && thread.Author.ID != userID {
+		return utils.ErrUnauthorized
+	}
+
+	if _, err := s.reviewRepo.FindByThreadID(threadID); err != nil {
+		return err
+	}
+
+	return s.reviewRepo.Delete(threadID)
+}
+
+// GetTagReviews liste les critiques d'un tag, triées et filtrées par note, avec la note moyenne
+func (s *reviewService) GetTagReviews(tagName string, viewerID *uint, filter models.ReviewFilter) (*TagReviewsDTO, error) {
+	tag, err := s.tagRepo.FindByName(tagName)
+	if err != nil {
+		return nil, utils.ErrTagNotFound
+	}
+
+	if err := validateReviewFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	rating, err := s.reviewRepo.RatingForTag(tag.ID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, total, err := s.reviewRepo.FindByTag(tag.ID, viewerID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TagReviewsDTO{
+		Tag:     TagResponseDTO{ID: tag.ID, Name: tag.Name, Type: tag.Type},
+		Rating:  TagRatingDTO{ReviewCount: rating.ReviewCount},
+		Reviews: make([]ReviewResponseDTO, 0, len(reviews)),
+		Pagination: PaginationInfo{
+			Page:       filter.Page,
+			PerPage:    filter.PerPage,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(filter.PerPage))),
+		},
+	}
+	if rating.ReviewCount > 0 {
+		average := roundScore(rating.Average)
+		result.Rating.Average = &average
+	}
+	for _, review := range reviews {
+		result.Reviews = append(result.Reviews, *reviewToDTO(review))
+	}
+
+	return result, nil
+}
+
+// resolveSubjectTag trouve ou crée le tag critiqué ; un tag créé par défaut comme artiste
+// (voir determineTagType) prend le type album ou morceau indiqué par la critique
+func (s *reviewService) resolveSubjectTag(name, subjectType string) (*models.Tag, error) {
+	tag, err := s.tagRepo.FindOrCreate(name, subjectType)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag.Type == subjectType {
+		return tag, nil
+	}
+	if tag.Type != models.TagTypeArtist || subjectType == models.TagTypeArtist {
+		return nil, fmt.Errorf("le tag %q désigne déjà un %s: %w", tag.Name, tag.Type, utils.ErrInvalidInput)
+	}
+
+	tag.Type = subjectType
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// validateReviewDTO nettoie et valide une critique avant son enregistrement
+func validateReviewDTO(dto *SaveReviewDTO) error {
+	dto.Subject = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(dto.Subject), "#"))
+	if dto.Subject == "" || len(dto.Subject) > 50 {
+		return fmt.Errorf("le sujet de la critique doit faire entre 1 et 50 caractères: %w", utils.ErrInvalidInput)
+	}
+
+	switch dto.SubjectType {
+	case models.TagTypeArtist, models.TagTypeAlbum, models.TagTypeTrack:
+	default:
+		return fmt.Errorf("une critique porte sur un artiste, un album ou un morceau: %w", utils.ErrInvalidInput)
+	}
+
+	score, err := normalizeScore(dto.Score)
+	if err != nil {
+		return err
+	}
+	dto.Score = score
+
+	if len(dto.Tracks) > 0 && dto.SubjectType == models.TagTypeTrack {
+		return fmt.Errorf("la critique d'un morceau n'a pas de notes par morceau: %w", utils.ErrInvalidInput)
+	}
+	if len(dto.Tracks) > maxReviewTracks {
+		return fmt.Errorf("%d morceaux notés au maximum: %w", maxReviewTracks, utils.ErrInvalidInput)
+	}
+
+	for i := range dto.Tracks {
+		dto.Tracks[i].Title = strings.TrimSpace(dto.Tracks[i].Title)
+		if dto.Tracks[i].Title == "" || len(dto.Tracks[i].Title) > 200 {
+			return fmt.Errorf("titre de morceau invalide (1 à 200 caractères): %w", utils.ErrInvalidInput)
+		}
+
+		score, err := normalizeScore(dto.Tracks[i].Score)
+		if err != nil {
+			return err
+		}
+		dto.Tracks[i].Score = score
+	}
+
+	return nil
+}
+
+// validateReviewFilter vérifie les bornes de note et applique le tri et la pagination par défaut
+func validateReviewFilter(filter *models.ReviewFilter) error {
+	for _, bound := range []*float64{filter.MinScore, filter.MaxScore} {
+		if bound != nil && (*bound < 0 || *bound > models.ReviewScoreMax) {
+			return fmt.Errorf("les bornes de note vont de 0 à %g: %w", models.ReviewScoreMax, utils.ErrInvalidInput)
+		}
+	}
+	if filter.MinScore != nil && filter.MaxScore != nil && *filter.MinScore > *filter.MaxScore {
+		return fmt.Errorf("la note minimale dépasse la note maximale: %w", utils.ErrInvalidInput)
+	}
+
+	switch filter.Sort {
+	case models.ReviewSortScoreDesc, models.ReviewSortScoreAsc:
+	default:
+		filter.Sort = models.ReviewSortRecent
+	}
+
+	params := models.PaginationParams{Page: filter.Page, PerPage: filter.PerPage}
+	models.ValidatePagination(&params)
+	filter.Page, filter.PerPage = params.Page, params.PerPage
+
+	return nil
+}
+
+// normalizeScore vérifie qu'une note est comprise entre 0 et 10 et l'arrondit au dixième
+func normalizeScore(score float64) (float64, error) {
+	if math.IsNaN(score) || score < 0 || score > models.ReviewScoreMax {
+		return 0, fmt.Errorf("la note doit être comprise entre 0 et %g: %w", models.ReviewScoreMax, utils.ErrInvalidInput)
+	}
+	return roundScore(score), nil
+}
+
+// roundScore arrondit une note au dixième
+func roundScore(score float64) float64 {
+	return math.Round(score*10) / 10
+}
+
+// reviewToDTO convertit une critique ; la moyenne des morceaux n'est calculée que s'ils sont notés
+func reviewToDTO(review *models.Review) *ReviewResponseDTO {
+	dto := &ReviewResponseDTO{
+		ThreadID:    review.ThreadID,
+		ThreadTitle: review.ThreadTitle,
+		Subject:     TagResponseDTO{ID: review.TagID, Name: review.TagName, Type: review.TagType},
+		Score:       review.Score,
+		CreatedAt:   review.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   review.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if review.AuthorID != 0 {
+		dto.Author = &UserSummaryDTO{ID: review.AuthorID, Username: review.AuthorName}
+	}
+
+	if len(review.Tracks) > 0 {
+		var sum float64
+		for _, track := range review.Tracks {
+			dto.Tracks = append(dto.Tracks, ReviewTrackScoreDTO{Title: track.Title, Score: track.Score})
+			sum += track.Score
+		}
+		average := roundScore(sum / float64(len(review.Tracks)))
+		dto.TrackAverage = &average
+	}
+
+	return dto
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/utils"
+	"testing"
+)
+
+func TestValidateReviewDTO(t *testing.T) {
+	cases := []struct {
+		name    string
+		dto     SaveReviewDTO
+		wantErr bool
+	}{
+		{"album noté", SaveReviewDTO{Subject: "#Discovery", SubjectType: "album", Score: 8.5}, false},
+		{"album avec morceaux", SaveReviewDTO{Subject: "discovery", SubjectType: "album", Score: 9, Tracks: []ReviewTrackScoreDTO{{"One More Time", 10}, {"Digital Love", 9.5}}}, false},
+		{"sujet vide", SaveReviewDTO{Subject: " # ", SubjectType: "album", Score: 5}, true},
+		{"type genre", SaveReviewDTO{Subject: "house", SubjectType: "genre", Score: 5}, true},
+		{"note négative", SaveReviewDTO{Subject: "daft punk", SubjectType: "artist", Score: -1}, true},
+		{"note au-dessus de 10", SaveReviewDTO{Subject: "daft punk", SubjectType: "artist", Score: 10.5}, true},
+		{"morceaux sur un morceau", SaveReviewDTO{Subject: "around the world", SubjectType: "track", Score: 7, Tracks: []ReviewTrackScoreDTO{{"Intro", 5}}}, true},
+		{"morceau sans titre", SaveReviewDTO{Subject: "discovery", SubjectType: "album", Score: 7, Tracks: []ReviewTrackScoreDTO{{" ", 5}}}, true},
+		{"morceau mal noté", SaveReviewDTO{Subject: "discovery", SubjectType: "album", Score: 7, Tracks: []ReviewTrackScoreDTO{{"Aerodynamic", 11}}}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateReviewDTO(&c.dto)
+			if (err != nil) != c.wantErr {
+				t.Errorf("Erreur attendue: %v, Obtenu: %v", c.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, utils.ErrInvalidInput) {
+				t.Errorf("Erreur non typée ErrInvalidInput: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateReviewDTONormalizes(t *testing.T) {
+	dto := SaveReviewDTO{Subject: "  #Discovery ", SubjectType: "album", Score: 8.46}
+	if err := validateReviewDTO(&dto); err != nil {
+		t.Fatalf("validateReviewDTO: %v", err)
+	}
+	if dto.Subject != "Discovery" {
+		t.Errorf("Sujet attendu: Discovery, Obtenu: %q", dto.Subject)
+	}
+	if dto.Score != 8.5 {
+		t.Errorf("Note attendue arrondie au dixième: 8.5, Obtenu: %v", dto.Score)
+	}
+}
+
+func TestValidateReviewFilter(t *testing.T) {
+	low, high := 3.0, 8.0
+
+	filter := models.ReviewFilter{MinScore: &low, MaxScore: &high, Sort: "inconnu"}
+	if err := validateReviewFilter(&filter); err != nil {
+		t.Fatalf("validateReviewFilter: %v", err)
+	}
+	if filter.Sort != models.ReviewSortRecent || filter.Page != 1 || filter.PerPage != 10 {
+		t.Errorf("Tri et pagination par défaut attendus, Obtenu: %+v", filter)
+	}
+
+	inverted := models.ReviewFilter{MinScore: &high, MaxScore: &low}
+	if err := validateReviewFilter(&inverted); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Errorf("Bornes inversées: ErrInvalidInput attendu, Obtenu: %v", err)
+	}
+}
+
+func TestReviewToDTOAveragesTracks(t *testing.T) {
+	dto := reviewToDTO(&models.Review{
+		ThreadID: 4,
+		TagName:  "discovery",
+		TagType:  models.TagTypeAlbum,
+		Score:    9,
+		Tracks: []*models.ReviewTrack{
+			{Title: "One More Time", Score: 10},
+			{Title: "Aerodynamic", Score: 9},
+			{Title: "Digital Love", Score: 8},
+		},
+	})
+
+	if dto.TrackAverage == nil || *dto.TrackAverage != 9 {
+		t.Errorf("Moyenne des morceaux attendue: 9, Obtenu: %v", dto.TrackAverage)
+	}
+	if dto.Author != nil {
+		t.Errorf("Pas d'auteur attendu hors des listes, Obtenu: %+v", dto.Author)
+	}
+
+	if reviewToDTO(&models.Review{Score: 6}).TrackAverage != nil {
+		t.Error("Pas de moyenne attendue sans morceaux notés")
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/auth"
+	"strings"
+)
+
+// Jetons de scrobbling
+const (
+	scrobbleTokenBytes   = 16 // 32 caractères hexadécimaux, comme une clé de session Last.fm
+	scrobbleTokenMax     = 10 // jetons par utilisateur
+	scrobbleTokenNameMax = 100
+
+	scrobbleMobileSessionName = "Scrobbler Last.fm" // préfixe du nom des jetons créés par auth.getMobileSession
+)
+
+// CreatedScrobbleTokenDTO jeton qui vient d'être créé : Token n'est jamais affiché de nouveau
+type CreatedScrobbleTokenDTO struct {
+	*models.ScrobbleToken
+	Token string `json:"token"`
+}
+
+// ScrobbleService interface pour les jetons personnels des scrobblers (Last.fm, ListenBrainz)
+type ScrobbleService interface {
+	CreateToken(userID uint, name string) (*CreatedScrobbleTokenDTO, error)
+	GetTokens(userID uint) ([]*models.ScrobbleToken, error)
+	RevokeToken(userID, tokenID uint) error
+	Authenticate(token string) (*models.ScrobbleToken, error)
+	CreateMobileSession(identifier, password, client string) (*CreatedScrobbleTokenDTO, error)
+}
+
+// scrobbleService implémentation du service
+type scrobbleService struct {
+	tokenRepo repositories.ScrobbleTokenRepository
+	userRepo  repositories.UserRepository
+}
+
+// NewScrobbleService crée une nouvelle instance du service
+func NewScrobbleService(tokenRepo repositories.ScrobbleTokenRepository, userRepo repositories.UserRepository) ScrobbleService {
+	return &scrobbleService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// CreateToken crée un jeton à renseigner dans un scrobbler (clé de session Last.fm ou jeton ListenBrainz)
+func (s *scrobbleService) CreateToken(userID uint, name string) (*CreatedScrobbleTokenDTO, error) {
+	name = truncateRunes(strings.TrimSpace(name), scrobbleTokenNameMax)
+	if name == "" {
+		return nil, fmt.Errorf("le nom du jeton est requis: %w", utils.ErrInvalidInput)
+	}
+
+	count, err := s.tokenRepo.CountByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= scrobbleTokenMax {
+		return nil, fmt.Errorf("%d jetons de scrobbling au maximum, révoquez-en un: %w", scrobbleTokenMax, utils.ErrInvalidInput)
+	}
+
+	raw := make([]byte, scrobbleTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("erreur génération du jeton de scrobbling: %w", err)
+	}
+	value := hex.EncodeToString(raw)
+
+	token := &models.ScrobbleToken{
+		UserID:      userID,
+		Name:        name,
+		TokenPrefix: value[:6],
+	}
+	if err := s.tokenRepo.Create(token, scrobbleTokenHash(value)); err != nil {
+		return nil, err
+	}
+
+	return &CreatedScrobbleTokenDTO{ScrobbleToken: token, Token: value}, nil
+}
+
+// GetTokens liste les jetons d'un utilisateur (sans leur valeur)
+func (s *scrobbleService) GetTokens(userID uint) ([]*models.ScrobbleToken, error) {
+	return s.tokenRepo.FindByUser(userID)
+}
+
+// RevokeToken révoque un jeton : les scrobblers qui l'utilisent sont refusés
+func (s *scrobbleService) RevokeToken(userID, tokenID uint) error {
+	return s.tokenRepo.Delete(tokenID, userID)
+}
+
+// Authenticate retrouve le jeton présenté par un scrobbler et son propriétaire
+func (s *scrobbleService) Authenticate(value string) (*models.ScrobbleToken, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, utils.ErrTokenInvalid
+	}
+
+	token, err := s.tokenRepo.FindByHash(scrobbleTokenHash(value))
+	if errors.Is(err, utils.ErrScrobbleTokenNotFound) {
+		return nil, utils.ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.TouchLastUsed(token.ID); err != nil {
+		log.Printf("⚠️ Erreur mise à jour du jeton de scrobbling %d: %v", token.ID, err)
+	}
+	return token, nil
+}
+
+// CreateMobileSession connecte un scrobbler Last.fm avec l'identifiant et le mot de passe de
+// l'utilisateur (auth.getMobileSession) et lui attribue un nouveau jeton. Les scrobblers se
+// reconnectent à chaque réinstallation : au plafond, le plus ancien jeton de session mobile est remplacé.
+func (s *scrobbleService) CreateMobileSession(identifier, password, client string) (*CreatedScrobbleTokenDTO, error) {
+	var user *models.User
+	var err error
+	if utils.ValidateEmail(identifier) {
+		user, err = s.userRepo.FindByEmail(identifier)
+	} else {
+		user, err = s.userRepo.FindByUsername(identifier)
+	}
+	if err != nil || !auth.CheckPassword(password, user.Password) {
+		return nil, utils.ErrInvalidCredentials
+	}
+
+	if err := s.revokeOldestMobileSession(user.ID); err != nil {
+		return nil, err
+	}
+
+	name := scrobbleMobileSessionName
+	if client = strings.TrimSpace(client); client != "" {
+		name = fmt.Sprintf("%s (%s)", scrobbleMobileSessionName, client)
+	}
+	created, err := s.CreateToken(user.ID, name)
+	if err != nil {
+		return nil, err
+	}
+	created.Username = user.Username
+	return created, nil
+}
+
+// revokeOldestMobileSession libère une place pour une session mobile lorsque l'utilisateur a atteint
+// le plafond de jetons ; les jetons créés à la main ne sont jamais révoqués
+func (s *scrobbleService) revokeOldestMobileSession(userID uint) error {
+	tokens, err := s.tokenRepo.FindByUser(userID)
+	if err != nil {
+		return err
+	}
+	if len(tokens) < scrobbleTokenMax {
+		return nil
+	}
+
+	var oldest *models.ScrobbleToken
+	for _, token := range tokens {
+		if !strings.HasPrefix(token.Name, scrobbleMobileSessionName) {
+			continue
+		}
+		if oldest == nil || token.CreatedAt.Before(oldest.CreatedAt) ||
+			(token.CreatedAt.Equal(oldest.CreatedAt) && token.ID < oldest.ID) {
+			oldest = token
+		}
+	}
+	if oldest == nil {
+		return nil
+	}
+
+	log.Printf("🔁 Session mobile %d remplacée pour l'utilisateur %d", oldest.ID, userID)
+	return s.tokenRepo.Delete(oldest.ID, userID)
+}
+
+// scrobbleTokenHash empreinte conservée d'un jeton
+func scrobbleTokenHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
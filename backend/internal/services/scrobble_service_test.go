@@ -0,0 +1,188 @@
+package services
+
+import (
+	"errors"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/auth"
+	"strings"
+	"testing"
+)
+
+// fakeScrobbleTokenRepository jetons de scrobbling en mémoire, indexés par empreinte
+type fakeScrobbleTokenRepository struct {
+	tokens map[string]*models.ScrobbleToken
+	nextID uint
+}
+
+func (r *fakeScrobbleTokenRepository) Create(token *models.ScrobbleToken, tokenHash string) error {
+	r.nextID++
+	token.ID = r.nextID
+	r.tokens[tokenHash] = token
+	return nil
+}
+
+func (r *fakeScrobbleTokenRepository) FindByHash(tokenHash string) (*models.ScrobbleToken, error) {
+	if token, ok := r.tokens[tokenHash]; ok {
+		return token, nil
+	}
+	return nil, utils.ErrScrobbleTokenNotFound
+}
+
+func (r *fakeScrobbleTokenRepository) FindByUser(userID uint) ([]*models.ScrobbleToken, error) {
+	var tokens []*models.ScrobbleToken
+	for _, token := range r.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *fakeScrobbleTokenRepository) CountByUser(userID uint) (int, error) {
+	tokens, _ := r.FindByUser(userID)
+	return len(tokens), nil
+}
+
+func (r *fakeScrobbleTokenRepository) Delete(id, userID uint) error {
+	for hash, token := range r.tokens {
+		if token.ID == id && token.UserID == userID {
+			delete(r.tokens, hash)
+			return nil
+		}
+	}
+	return utils.ErrScrobbleTokenNotFound
+}
+
+func (r *fakeScrobbleTokenRepository) TouchLastUsed(id uint) error {
+	return nil
+}
+
+func TestScrobbleTokens(t *testing.T) {
+	repo := &fakeScrobbleTokenRepository{tokens: make(map[string]*models.ScrobbleToken)}
+	service := NewScrobbleService(repo, nil)
+
+	created, err := service.CreateToken(3, "  Pano Scrobbler  ")
+	if err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+	if len(created.Token) != 2*scrobbleTokenBytes || !strings.HasPrefix(created.Token, created.TokenPrefix) {
+		t.Errorf("Jeton inattendu: %q (préfixe %q)", created.Token, created.TokenPrefix)
+	}
+	if created.Name != "Pano Scrobbler" {
+		t.Errorf("Nom attendu: %q, Obtenu: %q", "Pano Scrobbler", created.Name)
+	}
+	if _, stored := repo.tokens[created.Token]; stored {
+		t.Errorf("Le jeton ne doit pas être conservé en clair")
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"jeton valide", created.Token, nil},
+		{"espaces autour du jeton", " " + created.Token + "\n", nil},
+		{"jeton inconnu", strings.Repeat("0", 2*scrobbleTokenBytes), utils.ErrTokenInvalid},
+		{"jeton vide", "", utils.ErrTokenInvalid},
+		{"empreinte présentée comme jeton", scrobbleTokenHash(created.Token), utils.ErrTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := service.Authenticate(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Erreur attendue: %v, Obtenue: %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && token.UserID != 3 {
+				t.Errorf("Utilisateur attendu: 3, Obtenu: %d", token.UserID)
+			}
+		})
+	}
+
+	if err := service.RevokeToken(4, created.ID); !errors.Is(err, utils.ErrScrobbleTokenNotFound) {
+		t.Errorf("Un autre utilisateur ne doit pas révoquer le jeton: %v", err)
+	}
+	if err := service.RevokeToken(3, created.ID); err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+	if _, err := service.Authenticate(created.Token); !errors.Is(err, utils.ErrTokenInvalid) {
+		t.Errorf("Un jeton révoqué doit être refusé: %v", err)
+	}
+
+	for i := 0; i < scrobbleTokenMax; i++ {
+		if _, err := service.CreateToken(5, "Scrobbler"); err != nil {
+			t.Fatalf("Erreur inattendue: %v", err)
+		}
+	}
+	if _, err := service.CreateToken(5, "Scrobbler"); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Errorf("Erreur attendue: %v, Obtenue: %v", utils.ErrInvalidInput, err)
+	}
+}
+
+// stubScrobbleUserRepository utilisateur unique retrouvé par son nom
+type stubScrobbleUserRepository struct {
+	repositories.UserRepository
+	user *models.User
+}
+
+func (r *stubScrobbleUserRepository) FindByUsername(username string) (*models.User, error) {
+	if username != r.user.Username {
+		return nil, utils.ErrUserNotFound
+	}
+	return r.user, nil
+}
+
+func TestCreateMobileSessionAtTokenLimit(t *testing.T) {
+	hash, err := auth.HashPassword("Motdepasse-2026!", 4)
+	if err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+	user := &models.User{Username: "alice", Password: hash}
+	user.ID = 3
+	repo := &fakeScrobbleTokenRepository{tokens: make(map[string]*models.ScrobbleToken)}
+	service := NewScrobbleService(repo, &stubScrobbleUserRepository{user: user})
+
+	manual, err := service.CreateToken(3, "Web Scrobbler")
+	if err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+	first, err := service.CreateMobileSession("alice", "Motdepasse-2026!", "Pano Scrobbler")
+	if err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+
+	// Reconnexions répétées bien au-delà du plafond
+	var last *CreatedScrobbleTokenDTO
+	for i := 0; i < 2*scrobbleTokenMax; i++ {
+		if last, err = service.CreateMobileSession("alice", "Motdepasse-2026!", "Pano Scrobbler"); err != nil {
+			t.Fatalf("Reconnexion %d refusée: %v", i+1, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"dernière session valide", last.Token, nil},
+		{"jeton manuel conservé", manual.Token, nil},
+		{"plus ancienne session remplacée", first.Token, utils.ErrTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Authenticate(tt.token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Erreur attendue: %v, Obtenue: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if count, _ := repo.CountByUser(3); count != scrobbleTokenMax {
+		t.Errorf("Jetons attendus: %d, Obtenus: %d", scrobbleTokenMax, count)
+	}
+	if _, err := service.CreateMobileSession("alice", "faux", "Pano Scrobbler"); !errors.Is(err, utils.ErrInvalidCredentials) {
+		t.Errorf("Erreur attendue: %v, Obtenue: %v", utils.ErrInvalidCredentials, err)
+	}
+}
@@ -32,5 +32,92 @@ func (s *BaseService) GetRepository() repositories.Repository {
 func NewFriendshipServiceWithDB(db *sql.DB) FriendshipService {
 	friendshipRepo := repositories.NewFriendshipRepository(db)
 	userRepo := repositories.NewUserRepository(db)
-	return NewFriendshipService(friendshipRepo, userRepo)
+	return NewFriendshipService(friendshipRepo, userRepo, repositories.NewListeningRepository(db))
+}
+
+// NewEmbedServiceWithDB crée un nouveau service de liens musicaux avec une connexion DB
+func NewEmbedServiceWithDB(db *sql.DB) EmbedService {
+	return NewEmbedService(
+		repositories.NewEmbedRepository(db),
+		repositories.NewCatalogRepository(db),
+		repositories.NewMetadataRepository(db),
+		NewAudioServiceWithDB(db),
+	)
+}
+
+// NewCatalogServiceWithDB crée un nouveau service de catalogue musical avec une connexion DB
+func NewCatalogServiceWithDB(db *sql.DB) CatalogService {
+	return NewCatalogService(repositories.NewCatalogRepository(db))
+}
+
+// NewAudioServiceWithDB crée un nouveau service de fichiers audio avec une connexion DB
+func NewAudioServiceWithDB(db *sql.DB) AudioService {
+	return NewAudioService(repositories.NewAudioRepository(db))
+}
+
+// NewListeningServiceWithDB crée un nouveau service d'historique d'écoute avec une connexion DB
+func NewListeningServiceWithDB(db *sql.DB) ListeningService {
+	return NewListeningService(repositories.NewListeningRepository(db), repositories.NewFriendshipRepository(db))
+}
+
+// NewScrobbleServiceWithDB crée un nouveau service de jetons de scrobbling avec une connexion DB
+func NewScrobbleServiceWithDB(db *sql.DB) ScrobbleService {
+	return NewScrobbleService(repositories.NewScrobbleTokenRepository(db), repositories.NewUserRepository(db))
+}
+
+// NewMentionServiceWithDB crée un nouveau service de mentions avec une connexion DB
+func NewMentionServiceWithDB(db *sql.DB) MentionService {
+	return NewMentionService(
+		repositories.NewMentionRepository(db),
+		repositories.NewUserRepository(db),
+		repositories.NewFriendshipRepository(db),
+		repositories.NewThreadRepository(db),
+	)
+}
+
+// NewModerationServiceWithDB crée un nouveau service de modération avec une connexion DB
+func NewModerationServiceWithDB(db *sql.DB) ModerationService {
+	threadRepo := repositories.NewThreadRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	messageRepo := repositories.NewMessageRepository(db)
+	return NewModerationService(
+		repositories.NewModerationRepository(db),
+		threadRepo,
+		messageRepo,
+		tagRepo,
+		NewThreadService(threadRepo, tagRepo, messageRepo, db),
+	)
+}
+
+// NewReactionServiceWithDB crée un nouveau service de réactions avec une connexion DB
+func NewReactionServiceWithDB(db *sql.DB) ReactionService {
+	return NewReactionService(
+		repositories.NewReactionRepository(db),
+		repositories.NewMessageRepository(db),
+		repositories.NewDirectMessageRepository(db),
+		repositories.NewThreadRepository(db),
+	)
+}
+
+// NewReviewServiceWithDB crée un nouveau service de critiques avec une connexion DB
+func NewReviewServiceWithDB(db *sql.DB) ReviewService {
+	tagRepo := repositories.NewTagRepository(db)
+	return NewReviewService(
+		repositories.NewReviewRepository(db),
+		tagRepo,
+		NewThreadService(repositories.NewThreadRepository(db), tagRepo, repositories.NewMessageRepository(db), db),
+	)
+}
+
+// NewAnnotationServiceWithDB crée un nouveau service d'annotations avec une connexion DB
+func NewAnnotationServiceWithDB(db *sql.DB) AnnotationService {
+	return NewAnnotationService(
+		repositories.NewAnnotationRepository(db),
+		NewThreadService(repositories.NewThreadRepository(db), repositories.NewTagRepository(db), repositories.NewMessageRepository(db), db),
+	)
+}
+
+// NewBattleServiceWithDB crée un nouveau service de battles avec une connexion DB
+func NewBattleServiceWithDB(db *sql.DB) BattleService {
+	return NewBattleService(repositories.NewBattleRepository(db))
 }
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+	"strings"
+)
+
+// maxShareCommentLength longueur maximale du commentaire accompagnant un partage
+const maxShareCommentLength = 500
+
+// maxProfileReposts nombre de reposts affichés sur un profil
+const maxProfileReposts = 50
+
+// ShareService interface pour le partage de threads (repost et message privé)
+type ShareService interface {
+	Repost(threadID, userID uint, comment string) (*ThreadResponseDTO, error)
+	Unrepost(threadID, userID uint) error
+	ShareToMessage(threadID, senderID, receiverID uint, comment string) (*models.DirectMessage, *ThreadResponseDTO, error)
+	GetUserReposts(userID uint, viewerID *uint) ([]*RepostResponseDTO, error)
+}
+
+// RepostResponseDTO thread republié sur un profil
+type RepostResponseDTO struct {
+	ID        uint              `json:"id"`
+	Comment   *string           `json:"comment,omitempty"`
+	CreatedAt string            `json:"created_at"`
+	Thread    ThreadResponseDTO `json:"thread"`
+}
+
+// shareService implémentation concrète
+type shareService struct {
+	shareRepo      repositories.ShareRepository
+	threadRepo     repositories.ThreadRepository
+	threadService  ThreadService
+	messageService MessageService
+}
+
+// NewShareService crée une nouvelle instance du service
+func NewShareService(shareRepo repositories.ShareRepository, threadRepo repositories.ThreadRepository, threadService ThreadService, messageService MessageService) ShareService {
+	return &shareService{
+		shareRepo:      shareRepo,
+		threadRepo:     threadRepo,
+		threadService:  threadService,
+		messageService: messageService,
+	}
+}
+
+// Repost republie un thread public sur le profil de l'utilisateur
+func (s *shareService) Repost(threadID, userID uint, comment string) (*ThreadResponseDTO, error) {
+	comment, err := validateShareComment(comment)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := s.threadService.GetThread(threadID, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Un repost est visible de tous : il ne doit pas exposer un thread privé
+	if thread.Visibility != models.VisibilityPublic || thread.PublishAt != nil {
+		return nil, utils.ErrThreadNotRepostable
+	}
+
+	share := &models.ThreadShare{
+		ThreadID: threadID,
+		UserID:   userID,
+		Comment:  normalizeNote(&comment),
+	}
+
+	created, err := s.shareRepo.CreateRepost(share)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, utils.ErrAlreadyReposted
+	}
+
+	thread.ShareCount++
+	return thread, nil
+}
+
+// Unrepost retire un repost du profil (sans erreur s'il n'existait pas)
+func (s *shareService) Unrepost(threadID, userID uint) error {
+	_, err := s.shareRepo.DeleteRepost(threadID, userID)
+	return err
+}
+
+// ShareToMessage envoie un thread sous forme de carte dans la conversation avec receiverID
+func (s *shareService) ShareToMessage(threadID, senderID, receiverID uint, comment string) (*models.DirectMessage, *ThreadResponseDTO, error) {
+	comment, err := validateShareComment(comment)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	thread, err := s.threadService.GetThread(threadID, &senderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Le destinataire doit pouvoir ouvrir le thread partagé
+	if receiverID != thread.Author.ID {
+		allowed, err := s.threadRepo.CanView(threadID, receiverID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("erreur vérification accès destinataire: %w", err)
+		}
+		if !allowed {
+			return nil, nil, fmt.Errorf("le destinataire n'a pas accès à ce thread: %w", utils.ErrUnauthorized)
+		}
+	}
+
+	content := models.FormatSharedThreadMessage(thread.ID, thread.Title, comment)
+	message, err := s.messageService.SendMessage(senderID, receiverID, content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	share := &models.ThreadShare{
+		ThreadID:    threadID,
+		UserID:      senderID,
+		Kind:        models.ShareKindMessage,
+		Comment:     normalizeNote(&comment),
+		RecipientID: &receiverID,
+	}
+	if err := s.shareRepo.Create(share); err != nil {
+		return nil, nil, err
+	}
+
+	thread.ShareCount++
+	return message, thread, nil
+}
+
+// GetUserReposts récupère les reposts d'un utilisateur visibles par viewerID
+func (s *shareService) GetUserReposts(userID uint, viewerID *uint) ([]*RepostResponseDTO, error) {
+	shares, err := s.shareRepo.FindRepostsByUserID(userID, viewerID, maxProfileReposts)
+	if err != nil {
+		return nil, err
+	}
+
+	reposts := make([]*RepostResponseDTO, 0, len(shares))
+	for _, share := range shares {
+		reposts = append(reposts, &RepostResponseDTO{
+			ID:        share.ID,
+			Comment:   share.Comment,
+			CreatedAt: share.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			Thread:    *newThreadResponseDTO(share.Thread),
+		})
+	}
+
+	return reposts, nil
+}
+
+// validateShareComment nettoie et valide le commentaire d'un partage
+func validateShareComment(comment string) (string, error) {
+	comment = strings.TrimSpace(comment)
+	if len(comment) > maxShareCommentLength {
+		return "", fmt.Errorf("erreur validation: le commentaire ne peut pas dépasser %d caractères", maxShareCommentLength)
+	}
+	return comment, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+)
+
+// SubscriptionService interface pour les abonnements aux threads et le suivi de lecture
+type SubscriptionService interface {
+	Subscribe(threadID, userID uint) error
+	Unsubscribe(threadID, userID uint) error
+	IsSubscribed(threadID, userID uint) (bool, error)
+	GetLastRead(threadID, userID uint) (uint, error)
+	MarkRead(threadID, userID, lastMessageID uint) error
+	RecordComment(message *models.Message) ([]uint, error)
+}
+
+// subscriptionService implémentation concrète
+type subscriptionService struct {
+	subscriptionRepo repositories.SubscriptionRepository
+	threadRepo       repositories.ThreadRepository
+	threadService    ThreadService
+}
+
+// NewSubscriptionService crée une nouvelle instance du service
+func NewSubscriptionService(subscriptionRepo repositories.SubscriptionRepository, threadRepo repositories.ThreadRepository, threadService ThreadService) SubscriptionService {
+	return &subscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		threadRepo:       threadRepo,
+		threadService:    threadService,
+	}
+}
+
+// Subscribe abonne un utilisateur à un thread qu'il peut consulter
+func (s *subscriptionService) Subscribe(threadID, userID uint) error {
+	if _, err := s.threadService.GetThread(threadID, &userID); err != nil {
+		return err
+	}
+
+	return s.subscriptionRepo.Subscribe(userID, threadID)
+}
+
+// Unsubscribe désabonne un utilisateur d'un thread
+func (s *subscriptionService) Unsubscribe(threadID, userID uint) error {
+	return s.subscriptionRepo.Unsubscribe(userID, threadID)
+}
+
+// IsSubscribed vérifie si un utilisateur suit un thread
+func (s *subscriptionService) IsSubscribed(threadID, userID uint) (bool, error) {
+	return s.subscriptionRepo.IsSubscribed(userID, threadID)
+}
+
+// GetLastRead récupère l'ID du dernier commentaire lu (0 si jamais lu)
+func (s *subscriptionService) GetLastRead(threadID, userID uint) (uint, error) {
+	return s.subscriptionRepo.GetLastRead(userID, threadID)
+}
+
+// MarkRead avance la position de lecture d'un utilisateur
+func (s *subscriptionService) MarkRead(threadID, userID, lastMessageID uint) error {
+	return s.subscriptionRepo.MarkRead(userID, threadID, lastMessageID)
+}
+
+// RecordComment abonne le commentateur, marque son commentaire comme lu et
+// retourne les abonnés à notifier (hors commentateur, accès au thread vérifié)
+func (s *subscriptionService) RecordComment(message *models.Message) ([]uint, error) {
+	if err := s.subscriptionRepo.AutoSubscribe(message.UserID, message.ThreadID); err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepo.MarkRead(message.UserID, message.ThreadID, message.ID); err != nil {
+		return nil, err
+	}
+
+	subscribers, err := s.subscriptionRepo.FindSubscribers(message.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+
+	var recipients []uint
+	for _, subscriberID := range subscribers {
+		if subscriberID == message.UserID {
+			continue
+		}
+
+		// Un abonné ayant perdu l'accès (invitation révoquée, amitié rompue) n'est plus notifié
+		allowed, err := s.threadRepo.CanView(message.ThreadID, subscriberID)
+		if err != nil {
+			return nil, fmt.Errorf("erreur vérification accès abonné: %w", err)
+		}
+		if allowed {
+			recipients = append(recipients, subscriberID)
+		}
+	}
+
+	return recipients, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"fmt"
+	"rythmitbackend/internal/models"
+	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
+)
+
+// ThreadAccessService interface pour la gestion des invitations aux threads privés
+type ThreadAccessService interface {
+	InviteUser(threadID, inviteeID, requesterID uint, isAdmin bool) (*models.ThreadInvitation, *models.Thread, error)
+	RevokeInvitation(threadID, inviteeID, requesterID uint, isAdmin bool) error
+	GetInvitations(threadID, requesterID uint, isAdmin bool) ([]*models.ThreadInvitation, error)
+}
+
+// threadAccessService implémentation concrète
+type threadAccessService struct {
+	threadRepo     repositories.ThreadRepository
+	invitationRepo repositories.ThreadInvitationRepository
+	userRepo       repositories.UserRepository
+	friendshipRepo repositories.FriendshipRepository
+}
+
+// NewThreadAccessService crée une nouvelle instance du service
+func NewThreadAccessService(threadRepo repositories.ThreadRepository, invitationRepo repositories.ThreadInvitationRepository, userRepo repositories.UserRepository, friendshipRepo repositories.FriendshipRepository) ThreadAccessService {
+	return &threadAccessService{
+		threadRepo:     threadRepo,
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		friendshipRepo: friendshipRepo,
+	}
+}
+
+// InviteUser invite un utilisateur sur un thread privé (auteur ou admin uniquement)
+func (s *threadAccessService) InviteUser(threadID, inviteeID, requesterID uint, isAdmin bool) (*models.ThreadInvitation, *models.Thread, error) {
+	thread, err := s.findManagedThread(threadID, requesterID, isAdmin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if thread.Visibility != models.VisibilityPrivate {
+		return nil, nil, fmt.Errorf("seuls les threads privés acceptent des invitations")
+	}
+
+	if inviteeID == thread.UserID {
+		return nil, nil, fmt.Errorf("l'auteur a déjà accès à son thread")
+	}
+
+	if _, err := s.userRepo.FindByID(inviteeID); err != nil {
+		return nil, nil, utils.ErrUserNotFound
+	}
+
+	// Ne pas inviter quelqu'un qui a bloqué l'auteur ou que l'auteur a bloqué
+	blocked, err := s.friendshipRepo.IsBlocked(inviteeID, thread.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur vérification blocage: %w", err)
+	}
+	if !blocked {
+		blocked, err = s.friendshipRepo.IsBlocked(thread.UserID, inviteeID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("erreur vérification blocage: %w", err)
+		}
+	}
+	if blocked {
+		return nil, nil, fmt.Errorf("impossible d'inviter cet utilisateur")
+	}
+
+	invitation, err := s.invitationRepo.Invite(threadID, inviteeID, requesterID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return invitation, thread, nil
+}
+
+// RevokeInvitation retire l'accès d'un invité
+func (s *threadAccessService) RevokeInvitation(threadID, inviteeID, requesterID uint, isAdmin bool) error {
+	if _, err := s.findManagedThread(threadID, requesterID, isAdmin); err != nil {
+		return err
+	}
+
+	return s.invitationRepo.Revoke(threadID, inviteeID)
+}
+
+// GetInvitations liste les invités d'un thread
+func (s *threadAccessService) GetInvitations(threadID, requesterID uint, isAdmin bool) ([]*models.ThreadInvitation, error) {
+	if _, err := s.findManagedThread(threadID, requesterID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	return s.invitationRepo.FindByThreadID(threadID)
+}
+
+// findManagedThread récupère un thread et vérifie que l'utilisateur peut gérer ses accès
+func (s *threadAccessService) findManagedThread(threadID, requesterID uint, isAdmin bool) (*models.Thread, error) {
+	thread, err := s.threadRepo.FindByID(threadID)
+	if err != nil {
+		return nil, utils.ErrThreadNotFound
+	}
+
+	if !isAdmin && thread.UserID != requesterID {
+		return nil, utils.ErrUnauthorized
+	}
+
+	return thread, nil
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"log"
+	"time"
+)
+
+// StartThreadAutoArchiver lance en arrière-plan l'archivage périodique des threads inactifs
+func StartThreadAutoArchiver(threadService ThreadService, inactiveFor, interval time.Duration) {
+	if inactiveFor <= 0 || interval <= 0 {
+		log.Println("⏸️  Archivage automatique des threads désactivé")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			archived, err := threadService.ArchiveInactiveThreads(inactiveFor)
+			if err != nil {
+				log.Printf("❌ Erreur archivage automatique: %v", err)
+			} else if archived > 0 {
+				log.Printf("🗄️  %d thread(s) inactif(s) archivé(s)", archived)
+			}
+
+			<-ticker.C
+		}
+	}()
+
+	log.Printf("✅ Archivage automatique actif (inactivité > %s, vérification toutes les %s)", inactiveFor, interval)
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"log"
+	"time"
+)
+
+// StartScheduledThreadPublisher lance en arrière-plan la publication des threads programmés
+// onPublished est appelé pour chaque thread rendu visible (notifications)
+func StartScheduledThreadPublisher(threadService ThreadService, interval time.Duration, onPublished func(*ThreadResponseDTO)) {
+	if interval <= 0 {
+		log.Println("⏸️  Publication programmée des threads désactivée")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			published, err := threadService.PublishDueThreads()
+			if err != nil {
+				log.Printf("❌ Erreur publication programmée: %v", err)
+			}
+
+			for _, thread := range published {
+				log.Printf("📣 Thread programmé publié: %d (%s)", thread.ID, thread.Title)
+				if onPublished != nil {
+					onPublished(thread)
+				}
+			}
+
+			<-ticker.C
+		}
+	}()
+
+	log.Printf("✅ Publication programmée active (vérification toutes les %s)", interval)
+}
@@ -2,10 +2,15 @@ package services
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"rythmitbackend/internal/models"
 	"rythmitbackend/internal/repositories"
 	"rythmitbackend/internal/utils"
+	"rythmitbackend/pkg/markdown"
+	"sort"
 	"strings"
 	"time"
 )
@@ -21,6 +26,10 @@ type ThreadDTO struct {
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	Tags         []string  `json:"tags"`
+	IsPinned     bool      `json:"is_pinned"`
+	UnreadCount  int       `json:"unread_count"`
+	ShareCount   int       `json:"share_count"`
+	ViewCount    int       `json:"view_count"`
 }
 
 // ThreadService interface pour la logique métier des threads
@@ -36,15 +45,48 @@ type ThreadService interface {
 	SearchThreadsWithTags(query string, tags []string, params models.PaginationParams) (*PaginatedThreadsResponseDTO, error)
 	GetThreadsByTag(tagName string, params models.PaginationParams) (*PaginatedThreadsResponseDTO, error)
 	GetAllThreads() ([]ThreadDTO, error)
+	ForViewer(userID *uint) ThreadService
+	CheckThreadAction(id uint, action string, userID uint) error
+	CanViewThread(id uint, userID *uint) error
+	ArchiveInactiveThreads(inactiveFor time.Duration) (int64, error)
+	PublishDueThreads() ([]*ThreadResponseDTO, error)
+	PinThread(id uint, tagName string, expiresAt *time.Time, adminID uint) (*models.ThreadPin, error)
+	UnpinThread(id uint, tagName string) error
+	SetAnnouncement(id uint, announcement bool) error
+	GetAnnouncements() ([]*ThreadResponseDTO, error)
+	FindDuplicates(check DuplicateCheckDTO, userID uint, excludeID uint) ([]DuplicateThreadDTO, error)
+	MergeThreads(sourceID, targetID uint) (*ThreadResponseDTO, error)
 }
 
+// Actions soumises au cycle de vie d'un thread (voir CheckThreadAction)
+const (
+	ThreadActionComment = "comment"
+	ThreadActionVote    = "vote"
+	ThreadActionLike    = "like"
+	ThreadActionEdit    = "edit"
+)
+
+// maxAnnouncements nombre maximum d'annonces affichées simultanément sur l'accueil
+const maxAnnouncements = 3
+
+// Détection des doublons : threads récents comparés et score minimal d'un doublon probable
+const (
+	duplicateWindow          = 14 * 24 * time.Hour
+	duplicateCandidatesLimit = 200
+	duplicateThreshold       = 0.45
+	maxDuplicateResults      = 5
+)
+
 // DTOs pour les threads
 type CreateThreadDTO struct {
-	Title       string   `json:"title" validate:"required,min=1,max=200"`
-	Description string   `json:"description" validate:"required,min=1"`
-	ImageURL    *string  `json:"image_url" validate:"omitempty"`
-	Tags        []string `json:"tags" validate:"required,min=1,max=10"`
-	Visibility  string   `json:"visibility" validate:"oneof=public privé"`
+	Title       string         `json:"title" validate:"required,min=1,max=200"`
+	Description string         `json:"description" validate:"required,min=1"`
+	ImageURL    *string        `json:"image_url" validate:"omitempty"`
+	Tags        []string       `json:"tags" validate:"required,min=1,max=10"`
+	Visibility  string         `json:"visibility" validate:"oneof=public privé"`
+	Access      string         `json:"access" validate:"omitempty,oneof=invitations amis"`
+	PublishAt   *time.Time     `json:"publish_at,omitempty"` // publication programmée (nil = immédiate)
+	Poll        *CreatePollDTO `json:"poll,omitempty"`       // sondage optionnel créé avec le thread
 }
 
 type UpdateThreadDTO struct {
@@ -52,25 +94,59 @@ type UpdateThreadDTO struct {
 	Description string   `json:"description" validate:"required,min=1"`
 	ImageURL    *string  `json:"image_url" validate:"omitempty"`
 	Tags        []string `json:"tags" validate:"omitempty,max=10"`
-	State       string   `json:"state" validate:"oneof=ouvert fermé archivé"`
+	State       string   `json:"state" validate:"omitempty,oneof=ouvert fermé archivé"` // vide : état inchangé
 	Visibility  string   `json:"visibility" validate:"oneof=public privé"`
+	Access      string   `json:"access" validate:"omitempty,oneof=invitations amis"`
 }
 
 type ThreadResponseDTO struct {
-	ID           uint             `json:"id"`
-	Title        string           `json:"title"`
-	Description  string           `json:"description"`
-	ImageURL     *string          `json:"image_url"`
-	State        string           `json:"state"`
-	Visibility   string           `json:"visibility"`
-	CreatedAt    string           `json:"created_at"`
-	UpdatedAt    string           `json:"updated_at"`
-	Author       UserSummaryDTO   `json:"author"`
-	Tags         []TagResponseDTO `json:"tags"`
-	MessageCount int              `json:"message_count"`
-	FireCount    int              `json:"fire_count"`
-	SkipCount    int              `json:"skip_count"`
-	UserVote     *string          `json:"user_vote,omitempty"` // pour les threads avec votes
+	ID              uint                 `json:"id"`
+	Title           string               `json:"title"`
+	Description     string               `json:"description"`
+	DescriptionHTML string               `json:"description_html"` // rendu Markdown assaini
+	ImageURL        *string              `json:"image_url"`
+	State           string               `json:"state"`
+	Visibility      string               `json:"visibility"`
+	Access          string               `json:"access,omitempty"`
+	PublishAt       *string              `json:"publish_at,omitempty"`
+	IsPinned        bool                 `json:"is_pinned"`
+	IsAnnouncement  bool                 `json:"is_announcement"`
+	CreatedAt       string               `json:"created_at"`
+	UpdatedAt       string               `json:"updated_at"`
+	Author          UserSummaryDTO       `json:"author"`
+	Tags            []TagResponseDTO     `json:"tags"`
+	MessageCount    int                  `json:"message_count"`
+	FireCount       int                  `json:"fire_count"`
+	SkipCount       int                  `json:"skip_count"`
+	UserVote        *string              `json:"user_vote,omitempty"` // pour les threads avec votes
+	Poll            *PollResponseDTO     `json:"poll,omitempty"`
+	UnreadCount     int                  `json:"unread_count"` // commentaires non lus par l'utilisateur courant
+	ShareCount      int                  `json:"share_count"`
+	ViewCount       int                  `json:"view_count"` // vues écrites + vues en attente d'écriture
+	MergedIntoID    *uint                `json:"merged_into_id,omitempty"`
+	MusicEmbeds     []*models.MusicEmbed `json:"music_embeds,omitempty"` // liens musicaux reconnus dans la description
+	// Doublons probables détectés à la création, pour avertir l'auteur
+	PossibleDuplicates []DuplicateThreadDTO `json:"possible_duplicates,omitempty"`
+	// Sondage demandé à la création mais non enregistré
+	PollError string `json:"poll_error,omitempty"`
+}
+
+// DuplicateCheckDTO thread à comparer aux threads récents
+type DuplicateCheckDTO struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+}
+
+// DuplicateThreadDTO thread existant probablement identique
+type DuplicateThreadDTO struct {
+	ID         uint     `json:"id"`
+	Title      string   `json:"title"`
+	Author     string   `json:"author"`
+	CreatedAt  string   `json:"created_at"`
+	Score      float64  `json:"score"`
+	SharedTags []string `json:"shared_tags"`
+	SameTrack  bool     `json:"same_track"`
 }
 
 type TagResponseDTO struct {
@@ -105,19 +181,32 @@ type ThreadFilters struct {
 
 // threadService implémentation
 type threadService struct {
-	threadRepo  repositories.ThreadRepository
-	tagRepo     repositories.TagRepository
-	messageRepo repositories.MessageRepository
-	db          *sql.DB
+	threadRepo       repositories.ThreadRepository
+	tagRepo          repositories.TagRepository
+	messageRepo      repositories.MessageRepository
+	pollRepo         repositories.PollRepository
+	subscriptionRepo repositories.SubscriptionRepository
+	shareRepo        repositories.ShareRepository
+	annotationRepo   repositories.AnnotationRepository
+	mentionService   MentionService
+	embedService     EmbedService
+	db               *sql.DB
+	viewerID         *uint // utilisateur pour qui les listes sont filtrées (nil = anonyme)
 }
 
 // NewThreadService crée une nouvelle instance du service
 func NewThreadService(threadRepo repositories.ThreadRepository, tagRepo repositories.TagRepository, messageRepo repositories.MessageRepository, db *sql.DB) ThreadService {
 	return &threadService{
-		threadRepo:  threadRepo,
-		tagRepo:     tagRepo,
-		messageRepo: messageRepo,
-		db:          db,
+		threadRepo:       threadRepo,
+		tagRepo:          tagRepo,
+		messageRepo:      messageRepo,
+		pollRepo:         repositories.NewPollRepository(db),
+		subscriptionRepo: repositories.NewSubscriptionRepository(db),
+		shareRepo:        repositories.NewShareRepository(db),
+		annotationRepo:   repositories.NewAnnotationRepository(db),
+		mentionService:   NewMentionServiceWithDB(db),
+		embedService:     NewEmbedServiceWithDB(db),
+		db:               db,
 	}
 }
 
@@ -128,6 +217,13 @@ func (s *threadService) CreateThread(dto CreateThreadDTO, userID uint) (*ThreadR
 		return nil, fmt.Errorf("erreur validation: %v", validationErrors)
 	}
 
+	// Valider le sondage avant de créer quoi que ce soit
+	if dto.Poll != nil {
+		if err := validatePollDTO(dto.Poll); err != nil {
+			return nil, err
+		}
+	}
+
 	// Valeurs par défaut
 	if dto.Visibility == "" {
 		dto.Visibility = models.VisibilityPublic
@@ -140,9 +236,15 @@ func (s *threadService) CreateThread(dto CreateThreadDTO, userID uint) (*ThreadR
 		ImageURL:    dto.ImageURL,
 		State:       models.ThreadStateOpen,
 		Visibility:  dto.Visibility,
+		Access:      dto.Access,
 		UserID:      userID,
 	}
 
+	// Une date de publication future programme le thread au lieu de le publier
+	if dto.PublishAt != nil && dto.PublishAt.After(time.Now()) {
+		thread.PublishAt = dto.PublishAt
+	}
+
 	// Transaction pour créer le thread et ses tags
 	err := s.threadRepo.Transaction(func(tx *sql.Tx) error {
 		// Créer le thread
@@ -150,24 +252,10 @@ func (s *threadService) CreateThread(dto CreateThreadDTO, userID uint) (*ThreadR
 			return fmt.Errorf("erreur création thread: %w", err)
 		}
 
-		// Traiter les tags
-		var tagIDs []uint
-		for _, tagName := range dto.Tags {
-			tagName = strings.TrimSpace(tagName)
-			if tagName == "" {
-				continue
-			}
-
-			// Déterminer le type de tag basé sur le nom (heuristique simple)
-			tagType := determineTagType(tagName)
-
-			// FindOrCreate pour chaque tag
-			tag, err := s.tagRepo.FindOrCreate(tagName, tagType)
-			if err != nil {
-				return fmt.Errorf("erreur gestion tag '%s': %w", tagName, err)
-			}
-
-			tagIDs = append(tagIDs, tag.ID)
+		// Traiter les tags saisis et les #hashtags de la description
+		tagIDs, err := resolveTagIDs(s.tagRepo, mergeThreadTags(dto.Tags, nil, models.ExtractHashtags(thread.Description)))
+		if err != nil {
+			return err
 		}
 
 		// Attacher les tags au thread
@@ -184,8 +272,52 @@ func (s *threadService) CreateThread(dto CreateThreadDTO, userID uint) (*ThreadR
 		return nil, err
 	}
 
+	// Le sondage a sa propre transaction : le thread est déjà créé,
+	// un échec est signalé dans la réponse pour que l'auteur puisse le rajouter
+	var pollErr error
+	if dto.Poll != nil {
+		if pollErr = s.pollRepo.Create(newPollFromDTO(thread.ID, *dto.Poll)); pollErr != nil {
+			log.Printf("❌ Erreur création du sondage du thread %d: %v", thread.ID, pollErr)
+		}
+	}
+
+	// L'auteur suit automatiquement son thread ; le thread est déjà créé,
+	// un échec d'abonnement ne doit pas faire croire à un échec de création
+	if err := s.subscriptionRepo.AutoSubscribe(userID, thread.ID); err != nil {
+		log.Printf("❌ Erreur abonnement de l'auteur au thread %d: %v", thread.ID, err)
+	}
+
+	// Un thread programmé ne notifie ses mentions qu'à sa publication
+	if thread.PublishAt == nil {
+		s.recordThreadMentions(thread)
+	}
+	s.syncThreadEmbeds(thread)
+
 	// Récupérer le thread complet pour la réponse
-	return s.GetThread(thread.ID, &userID)
+	created, err := s.GetThread(thread.ID, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Signaler les doublons probables sans bloquer la création
+	tagNames := make([]string, 0, len(created.Tags))
+	for _, tag := range created.Tags {
+		tagNames = append(tagNames, tag.Name)
+	}
+	duplicates, err := s.FindDuplicates(DuplicateCheckDTO{
+		Title:       thread.Title,
+		Description: thread.Description,
+		Tags:        tagNames,
+	}, userID, thread.ID)
+	if err != nil {
+		log.Printf("❌ Erreur détection de doublons pour le thread %d: %v", thread.ID, err)
+	}
+	created.PossibleDuplicates = duplicates
+	if pollErr != nil {
+		created.PollError = "le sondage n'a pas pu être créé"
+	}
+
+	return created, nil
 }
 
 // GetThread récupère un thread par son ID
@@ -195,22 +327,356 @@ func (s *threadService) GetThread(id uint, userID *uint) (*ThreadResponseDTO, er
 		return nil, utils.ErrThreadNotFound
 	}
 
-	// Vérifier les permissions pour les threads privés
+	if err := s.checkCanView(thread, userID); err != nil {
+		return nil, err
+	}
+
+	// Les threads archivés restent consultables en lecture seule :
+	// les interactions sont bloquées par CheckThreadAction
+
+	dto := s.threadToDTO(thread)
+
+	shareCounts, err := s.shareRepo.CountByThreadIDs([]uint{thread.ID})
+	if err != nil {
+		return nil, fmt.Errorf("erreur comptage partages: %w", err)
+	}
+	dto.ShareCount = shareCounts[thread.ID]
+
+	// Joindre le sondage éventuel avec les résultats visibles par l'utilisateur
+	poll, err := s.pollRepo.FindByThreadID(thread.ID)
+	if err == nil {
+		var userVotes []uint
+		if userID != nil {
+			userVotes, _ = s.pollRepo.GetUserVotes(poll.ID, *userID)
+		}
+		dto.Poll = pollToDTO(poll, userVotes, time.Now())
+	} else if !errors.Is(err, utils.ErrPollNotFound) {
+		return nil, fmt.Errorf("erreur récupération sondage: %w", err)
+	}
+
+	embeds, err := s.embedService.GetEmbeds(models.EmbedTargetThread, []uint{thread.ID}, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération liens musicaux: %w", err)
+	}
+	dto.MusicEmbeds = embeds[thread.ID]
+
+	return dto, nil
+}
+
+// ForViewer retourne un service dont les listes et recherches incluent
+// les threads privés accessibles à l'utilisateur ; copie du service pour n'oublier aucune dépendance
+func (s *threadService) ForViewer(userID *uint) ThreadService {
+	c := *s
+	c.threadRepo = s.threadRepo.WithViewer(userID)
+	c.viewerID = userID
+	return &c
+}
+
+// CheckThreadAction vérifie que l'utilisateur voit le thread et que son état autorise l'action demandée ;
+// un thread qu'il ne peut pas consulter (privé, programmé) est introuvable pour lui
+func (s *threadService) CheckThreadAction(id uint, action string, userID uint) error {
+	thread, err := s.threadRepo.FindByID(id)
+	if err != nil {
+		return utils.ErrThreadNotFound
+	}
+
+	if err := s.checkCanView(thread, &userID); err != nil {
+		if errors.Is(err, utils.ErrUnauthorized) {
+			return utils.ErrThreadNotFound
+		}
+		return err
+	}
+
+	return checkStateAllows(thread.State, action)
+}
+
+// CanViewThread vérifie qu'un utilisateur (nil : visiteur) peut consulter le thread, sans le charger entièrement
+func (s *threadService) CanViewThread(id uint, userID *uint) error {
+	thread, err := s.threadRepo.FindByID(id)
+	if err != nil {
+		return utils.ErrThreadNotFound
+	}
+	return s.checkCanView(thread, userID)
+}
+
+// checkCanView vérifie qu'un utilisateur (nil : visiteur) peut consulter le thread
+func (s *threadService) checkCanView(thread *models.Thread, userID *uint) error {
+	// Un thread programmé n'existe que pour son auteur jusqu'à sa publication
+	if thread.PublishAt != nil && (userID == nil || *userID != thread.UserID) {
+		return utils.ErrThreadNotFound
+	}
+
+	// Vérifier les permissions pour les threads privés (auteur, invités, amis selon l'accès)
 	if thread.Visibility == models.VisibilityPrivate {
-		if userID == nil || (*userID != thread.UserID) {
-			return nil, utils.ErrUnauthorized
+		if userID == nil {
+			return utils.ErrUnauthorized
+		}
+		if *userID != thread.UserID {
+			allowed, err := s.threadRepo.CanView(thread.ID, *userID)
+			if err != nil {
+				return fmt.Errorf("erreur vérification accès: %w", err)
+			}
+			if !allowed {
+				return utils.ErrUnauthorized
+			}
+		}
+	}
+	return nil
+}
+
+// checkStateAllows applique les règles du cycle de vie :
+// fermé bloque les commentaires et les votes, archivé bloque tout
+func checkStateAllows(state, action string) error {
+	switch state {
+	case models.ThreadStateArchived:
+		return utils.ErrThreadArchived
+	case models.ThreadStateClosed:
+		if action == ThreadActionComment || action == ThreadActionVote {
+			return utils.ErrThreadClosed
 		}
 	}
+	return nil
+}
+
+// PublishDueThreads publie les threads programmés arrivés à échéance et les retourne ;
+// un thread en échec n'empêche pas la publication des suivants, les erreurs sont regroupées
+func (s *threadService) PublishDueThreads() ([]*ThreadResponseDTO, error) {
+	due, err := s.threadRepo.FindDueScheduled(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération threads programmés: %w", err)
+	}
+
+	var published []*ThreadResponseDTO
+	var errs []error
+	for _, thread := range due {
+		ok, err := s.threadRepo.Publish(thread.ID)
+		if err != nil {
+			log.Printf("❌ Erreur publication du thread programmé %d: %v", thread.ID, err)
+			errs = append(errs, fmt.Errorf("erreur publication thread %d: %w", thread.ID, err))
+			continue
+		}
+		if !ok {
+			// Déjà publié entre-temps (autre instance du serveur)
+			continue
+		}
+
+		thread.PublishAt = nil
+		thread.CreatedAt = time.Now()
+		s.recordThreadMentions(thread)
+		published = append(published, s.threadToDTO(thread))
+	}
+
+	return published, errors.Join(errs...)
+}
+
+// PinThread épingle un thread sur le fil global (tagName vide) ou sur un tag (admin)
+func (s *threadService) PinThread(id uint, tagName string, expiresAt *time.Time, adminID uint) (*models.ThreadPin, error) {
+	thread, err := s.threadRepo.FindByID(id)
+	if err != nil {
+		return nil, utils.ErrThreadNotFound
+	}
 
-	// Vérifier si le thread est archivé (sauf pour le propriétaire et admin)
 	if thread.State == models.ThreadStateArchived {
-		if userID == nil || (*userID != thread.UserID) {
-			// TODO: Vérifier si user est admin
-			return nil, utils.ErrThreadArchived
+		return nil, utils.ErrThreadArchived
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("la date d'expiration doit être dans le futur: %w", utils.ErrInvalidInput)
+	}
+
+	tagID, err := s.resolvePinScope(tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Un épinglage sur un tag n'a de sens que si le thread porte ce tag
+	if tagID != nil {
+		hasTag := false
+		for _, tag := range thread.Tags {
+			if tag.ID == *tagID {
+				hasTag = true
+				break
+			}
+		}
+		if !hasTag {
+			return nil, fmt.Errorf("le thread ne porte pas le tag '%s': %w", tagName, utils.ErrInvalidInput)
+		}
+	}
+
+	pin := &models.ThreadPin{
+		ThreadID:  id,
+		TagID:     tagID,
+		PinnedBy:  adminID,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := s.threadRepo.Pin(pin); err != nil {
+		return nil, fmt.Errorf("erreur épinglage: %w", err)
+	}
+
+	return pin, nil
+}
+
+// UnpinThread retire l'épinglage d'un thread sur le fil global (tagName vide) ou sur un tag (admin)
+func (s *threadService) UnpinThread(id uint, tagName string) error {
+	tagID, err := s.resolvePinScope(tagName)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.threadRepo.Unpin(id, tagID)
+	if err != nil {
+		return fmt.Errorf("erreur désépinglage: %w", err)
+	}
+
+	if !removed {
+		return utils.ErrThreadNotFound
+	}
+
+	return nil
+}
+
+// resolvePinScope convertit le nom de tag d'un épinglage en ID (nil = fil global)
+func (s *threadService) resolvePinScope(tagName string) (*uint, error) {
+	tagName = strings.TrimSpace(tagName)
+	if tagName == "" {
+		return nil, nil
+	}
+
+	tag, err := s.tagRepo.FindByName(tagName)
+	if err != nil {
+		return nil, utils.ErrTagNotFound
+	}
+
+	return &tag.ID, nil
+}
+
+// SetAnnouncement marque un thread comme annonce affichée en bannière sur l'accueil (admin)
+func (s *threadService) SetAnnouncement(id uint, announcement bool) error {
+	thread, err := s.threadRepo.FindByID(id)
+	if err != nil {
+		return utils.ErrThreadNotFound
+	}
+
+	// Une annonce doit être visible de tous
+	if announcement && (thread.Visibility == models.VisibilityPrivate || thread.PublishAt != nil) {
+		return fmt.Errorf("seul un thread public et publié peut devenir une annonce: %w", utils.ErrInvalidInput)
+	}
+
+	return s.threadRepo.SetAnnouncement(id, announcement)
+}
+
+// GetAnnouncements récupère les annonces à afficher en bannière
+func (s *threadService) GetAnnouncements() ([]*ThreadResponseDTO, error) {
+	threads, err := s.threadRepo.FindAnnouncements(maxAnnouncements)
+	if err != nil {
+		return nil, fmt.Errorf("erreur récupération annonces: %w", err)
+	}
+
+	announcements := make([]*ThreadResponseDTO, 0, len(threads))
+	for _, thread := range threads {
+		announcements = append(announcements, s.threadToDTO(thread))
+	}
+
+	return announcements, nil
+}
+
+// FindDuplicates compare un thread aux threads récents visibles par userID
+// et retourne les doublons probables, du plus au moins similaire
+func (s *threadService) FindDuplicates(check DuplicateCheckDTO, userID uint, excludeID uint) ([]DuplicateThreadDTO, error) {
+	if strings.TrimSpace(check.Title) == "" {
+		return []DuplicateThreadDTO{}, nil
+	}
+
+	candidates, err := s.threadRepo.WithViewer(&userID).FindRecent(time.Now().Add(-duplicateWindow), duplicateCandidatesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Les #hashtags deviennent des tags à la création : les comparer aussi
+	fingerprint := newThreadFingerprint(check.Title, check.Description, mergeThreadTags(check.Tags, nil, models.ExtractHashtags(check.Description)))
+
+	duplicates := []DuplicateThreadDTO{}
+	for _, candidate := range candidates {
+		if candidate.ID == excludeID {
+			continue
+		}
+
+		tagNames := make([]string, 0, len(candidate.Tags))
+		for _, tag := range candidate.Tags {
+			tagNames = append(tagNames, tag.Name)
+		}
+
+		score, sharedTags, sameTrack := fingerprint.similarity(newThreadFingerprint(candidate.Title, candidate.Description, tagNames))
+		if score < duplicateThreshold {
+			continue
 		}
+
+		duplicates = append(duplicates, DuplicateThreadDTO{
+			ID:         candidate.ID,
+			Title:      candidate.Title,
+			Author:     candidate.Author.Username,
+			CreatedAt:  candidate.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			Score:      math.Round(score*100) / 100,
+			SharedTags: sharedTags,
+			SameTrack:  sameTrack,
+		})
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Score > duplicates[j].Score
+	})
+	if len(duplicates) > maxDuplicateResults {
+		duplicates = duplicates[:maxDuplicateResults]
 	}
 
-	return s.threadToDTO(thread), nil
+	return duplicates, nil
+}
+
+// MergeThreads fusionne un doublon dans le thread conservé (modération) :
+// commentaires et likes sont déplacés, le doublon est archivé et redirige vers la cible
+func (s *threadService) MergeThreads(sourceID, targetID uint) (*ThreadResponseDTO, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("un thread ne peut pas être fusionné avec lui-même: %w", utils.ErrInvalidInput)
+	}
+
+	source, err := s.threadRepo.FindByID(sourceID)
+	if err != nil {
+		return nil, utils.ErrThreadNotFound
+	}
+	target, err := s.threadRepo.FindByID(targetID)
+	if err != nil {
+		return nil, utils.ErrThreadNotFound
+	}
+
+	if source.MergedIntoID != nil || target.MergedIntoID != nil {
+		return nil, fmt.Errorf("un thread déjà fusionné ne peut pas être fusionné à nouveau: %w", utils.ErrInvalidInput)
+	}
+	if target.State == models.ThreadStateArchived {
+		return nil, utils.ErrThreadArchived
+	}
+
+	if err := s.threadRepo.Merge(sourceID, targetID); err != nil {
+		return nil, fmt.Errorf("erreur fusion des threads: %w", err)
+	}
+
+	log.Printf("🔀 Thread %d fusionné dans le thread %d", sourceID, targetID)
+	return s.GetThread(targetID, &target.UserID)
+}
+
+// ArchiveInactiveThreads archive les threads sans activité depuis la durée donnée
+func (s *threadService) ArchiveInactiveThreads(inactiveFor time.Duration) (int64, error) {
+	if inactiveFor <= 0 {
+		return 0, nil
+	}
+
+	cutoff := time.Now().Add(-inactiveFor)
+	archived, err := s.threadRepo.ArchiveInactive(cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("erreur archivage automatique: %w", err)
+	}
+
+	return archived, nil
 }
 
 // ValidatePagination valide et normalise les paramètres de pagination
@@ -265,6 +731,14 @@ func (s *threadService) GetPublicThreads(params models.PaginationParams, filters
 		threadDTOs = append(threadDTOs, *s.threadToDTO(thread))
 	}
 
+	if err := s.attachUnreadCounts(threadDTOs); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachShareCounts(threadDTOs); err != nil {
+		return nil, err
+	}
+
 	return &PaginatedThreadsResponseDTO{
 		Threads:    threadDTOs,
 		Pagination: s.buildPaginationInfo(params, total),
@@ -283,6 +757,9 @@ func (s *threadService) GetUserThreads(userID uint, params models.PaginationPara
 	// Convertir en DTOs
 	var threadDTOs []ThreadResponseDTO
 	for _, thread := range threads {
+		if !s.viewerCanList(thread) {
+			continue
+		}
 		threadDTOs = append(threadDTOs, *s.threadToDTO(thread))
 	}
 
@@ -300,6 +777,14 @@ func (s *threadService) GetUserThreads(userID uint, params models.PaginationPara
 		threadDTOs = threadDTOs[start:end]
 	}
 
+	if err := s.attachUnreadCounts(threadDTOs); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachShareCounts(threadDTOs); err != nil {
+		return nil, err
+	}
+
 	return &PaginatedThreadsResponseDTO{
 		Threads:    threadDTOs,
 		Pagination: s.buildPaginationInfo(params, total),
@@ -324,51 +809,140 @@ func (s *threadService) UpdateThread(id uint, dto UpdateThreadDTO, userID uint,
 		return utils.ErrUnauthorized
 	}
 
+	// Un thread archivé est en lecture seule : seul un admin peut le désarchiver,
+	// en indiquant explicitement le nouvel état (ouvert ou fermé)
+	if thread.State == models.ThreadStateArchived {
+		if !isAdmin || dto.State == models.ThreadStateArchived {
+			return utils.ErrThreadArchived
+		}
+		if dto.State == "" {
+			return fmt.Errorf("état ouvert ou fermé requis pour désarchiver le thread: %w", utils.ErrInvalidInput)
+		}
+	}
+
+	// Hashtags avant modification, pour retirer ceux supprimés du texte
+	previousHashtags := models.ExtractHashtags(thread.Description)
+	previousDescription := thread.Description
+
 	// Transaction pour mettre à jour le thread et ses tags
-	return s.threadRepo.Transaction(func(tx *sql.Tx) error {
+	err = s.threadRepo.Transaction(func(tx *sql.Tx) error {
 		// Mettre à jour les champs du thread
 		thread.Title = strings.TrimSpace(dto.Title)
 		thread.Description = strings.TrimSpace(dto.Description)
 		thread.ImageURL = dto.ImageURL
-		thread.State = dto.State
+		if dto.State != "" {
+			thread.State = dto.State
+		}
 		thread.Visibility = dto.Visibility
+		if dto.Access != "" {
+			thread.Access = dto.Access
+		}
 
 		// Mettre à jour le thread
 		if err := s.threadRepo.Update(thread); err != nil {
 			return fmt.Errorf("erreur mise à jour thread: %w", err)
 		}
 
-		// Traiter les tags si fournis
-		if len(dto.Tags) > 0 {
-			var tagIDs []uint
-			for _, tagName := range dto.Tags {
-				tagName = strings.TrimSpace(tagName)
-				if tagName == "" {
-					continue
-				}
-
-				// Déterminer le type de tag
-				tagType := determineTagType(tagName)
+		// Tags fournis, sinon tags actuels, synchronisés avec les #hashtags du texte
+		tagNames := dto.Tags
+		if len(tagNames) == 0 {
+			for _, tag := range thread.Tags {
+				tagNames = append(tagNames, tag.Name)
+			}
+		}
 
-				// FindOrCreate pour chaque tag
-				tag, err := s.tagRepo.FindOrCreate(tagName, tagType)
-				if err != nil {
-					return fmt.Errorf("erreur gestion tag '%s': %w", tagName, err)
-				}
+		hashtags := models.ExtractHashtags(thread.Description)
+		tagIDs, err := resolveTagIDs(s.tagRepo, mergeThreadTags(tagNames, removedHashtags(previousHashtags, hashtags), hashtags))
+		if err != nil {
+			return err
+		}
 
-				tagIDs = append(tagIDs, tag.ID)
+		// Mettre à jour les tags du thread
+		if len(tagIDs) > 0 {
+			if err := s.threadRepo.AttachTags(thread.ID, tagIDs); err != nil {
+				return fmt.Errorf("erreur mise à jour tags: %w", err)
 			}
-
-			// Mettre à jour les tags du thread
-			if len(tagIDs) > 0 {
-				if err := s.threadRepo.AttachTags(thread.ID, tagIDs); err != nil {
-					return fmt.Errorf("erreur mise à jour tags: %w", err)
-				}
+		} else if len(thread.Tags) > 0 {
+			if err := s.threadRepo.DetachTags(thread.ID); err != nil {
+				return fmt.Errorf("erreur mise à jour tags: %w", err)
 			}
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// Réancrer les annotations et relire les liens musicaux du nouveau texte ;
+	// un échec ne doit pas annuler la modification
+	if thread.Description != previousDescription {
+		if err := reanchorThreadAnnotations(s.annotationRepo, thread.ID); err != nil {
+			log.Printf("❌ Erreur réancrage des annotations du thread %d: %v", thread.ID, err)
+		}
+		s.syncThreadEmbeds(thread)
+	}
+
+	return nil
+}
+
+// resolveTagIDs retrouve ou crée les tags nommés et retourne leurs IDs
+func resolveTagIDs(tagRepo repositories.TagRepository, tagNames []string) ([]uint, error) {
+	var tagIDs []uint
+	for _, tagName := range tagNames {
+		// Déterminer le type de tag basé sur le nom (heuristique simple)
+		tagType := determineTagType(tagName)
+
+		// FindOrCreate pour chaque tag
+		tag, err := tagRepo.FindOrCreate(tagName, tagType)
+		if err != nil {
+			return nil, fmt.Errorf("erreur gestion tag '%s': %w", tagName, err)
+		}
+
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	return tagIDs, nil
+}
+
+// mergeThreadTags combine les tags saisis et les hashtags du texte, sans doublon
+// (insensible à la casse). Les tags de removed, hashtags retirés du texte, sont écartés.
+func mergeThreadTags(tags []string, removed []string, hashtags []string) []string {
+	excluded := make(map[string]bool, len(removed))
+	for _, name := range removed {
+		excluded[strings.ToLower(name)] = true
+	}
+
+	var merged []string
+	seen := make(map[string]bool)
+	for _, name := range append(append([]string{}, tags...), hashtags...) {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] || excluded[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, name)
+	}
+
+	return merged
+}
+
+// removedHashtags retourne les hashtags présents avant une modification mais plus après
+func removedHashtags(previous []string, current []string) []string {
+	kept := make(map[string]bool, len(current))
+	for _, hashtag := range current {
+		kept[hashtag] = true
+	}
+
+	var removed []string
+	for _, hashtag := range previous {
+		if !kept[hashtag] {
+			removed = append(removed, hashtag)
+		}
+	}
+
+	return removed
 }
 
 // DeleteThread supprime un thread
@@ -405,6 +979,11 @@ func (s *threadService) ChangeThreadState(id uint, state string, userID uint, is
 		return utils.ErrUnauthorized
 	}
 
+	// Seul un admin peut sortir un thread de l'archive
+	if thread.State == models.ThreadStateArchived && !isAdmin {
+		return utils.ErrThreadArchived
+	}
+
 	return s.threadRepo.UpdateState(id, state)
 }
 
@@ -496,15 +1075,27 @@ func (s *threadService) GetThreadsByTag(tagName string, params models.Pagination
 
 // threadToDTO convertit un thread en DTO
 func (s *threadService) threadToDTO(thread *models.Thread) *ThreadResponseDTO {
+	return newThreadResponseDTO(thread)
+}
+
+// newThreadResponseDTO convertit un thread en DTO sans dépendre du service
+// (utilisé aussi par les favoris et les collections)
+func newThreadResponseDTO(thread *models.Thread) *ThreadResponseDTO {
 	dto := &ThreadResponseDTO{
-		ID:          thread.ID,
-		Title:       thread.Title,
-		Description: thread.Description,
-		ImageURL:    thread.ImageURL,
-		State:       thread.State,
-		Visibility:  thread.Visibility,
-		CreatedAt:   thread.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:   thread.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		ID:              thread.ID,
+		Title:           thread.Title,
+		Description:     thread.Description,
+		DescriptionHTML: thread.DescriptionHTML,
+		ImageURL:        thread.ImageURL,
+		State:           thread.State,
+		Visibility:      thread.Visibility,
+		Access:          thread.Access,
+		IsPinned:        thread.IsPinned,
+		IsAnnouncement:  thread.IsAnnouncement,
+		MergedIntoID:    thread.MergedIntoID,
+		ViewCount:       thread.ViewCount + pendingThreadViews(thread.ID),
+		CreatedAt:       thread.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:       thread.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 		Author: UserSummaryDTO{
 			ID:         thread.Author.ID,
 			Username:   thread.Author.Username,
@@ -516,6 +1107,16 @@ func (s *threadService) threadToDTO(thread *models.Thread) *ThreadResponseDTO {
 		SkipCount:    thread.SkipCount,
 	}
 
+	if thread.PublishAt != nil {
+		publishAt := thread.PublishAt.Format("2006-01-02T15:04:05Z")
+		dto.PublishAt = &publishAt
+	}
+
+	// Threads antérieurs au cache de rendu et jamais réenregistrés
+	if dto.DescriptionHTML == "" {
+		dto.DescriptionHTML = markdown.Render(thread.Description)
+	}
+
 	// Convertir les tags
 	for _, tag := range thread.Tags {
 		dto.Tags = append(dto.Tags, TagResponseDTO{
@@ -528,6 +1129,91 @@ func (s *threadService) threadToDTO(thread *models.Thread) *ThreadResponseDTO {
 	return dto
 }
 
+// attachUnreadCounts renseigne les commentaires non lus de l'utilisateur courant
+func (s *threadService) attachUnreadCounts(threads []ThreadResponseDTO) error {
+	if s.viewerID == nil || len(threads) == 0 {
+		return nil
+	}
+
+	threadIDs := make([]uint, len(threads))
+	for i, thread := range threads {
+		threadIDs[i] = thread.ID
+	}
+
+	counts, err := s.subscriptionRepo.CountUnread(*s.viewerID, threadIDs)
+	if err != nil {
+		return fmt.Errorf("erreur comptage non lus: %w", err)
+	}
+
+	for i := range threads {
+		threads[i].UnreadCount = counts[threads[i].ID]
+	}
+
+	return nil
+}
+
+// recordThreadMentions enregistre et notifie les mentions du titre et de la description d'un thread publié
+func (s *threadService) recordThreadMentions(thread *models.Thread) {
+	_, err := s.mentionService.RecordMentions(MentionSource{
+		Type:     models.MentionSourceThread,
+		ID:       thread.ID,
+		ThreadID: &thread.ID,
+		AuthorID: thread.UserID,
+		Content:  thread.Title + "\n" + thread.Description,
+	})
+	if err != nil {
+		// Les mentions ne doivent pas faire échouer la publication
+		log.Printf("❌ Erreur mentions du thread %d: %v", thread.ID, err)
+	}
+}
+
+// syncThreadEmbeds enregistre les liens musicaux de la description d'un thread
+func (s *threadService) syncThreadEmbeds(thread *models.Thread) {
+	if _, err := s.embedService.SyncEmbeds(models.EmbedTargetThread, thread.ID, thread.Description); err != nil {
+		// Les liens musicaux ne doivent pas faire échouer l'enregistrement du thread
+		log.Printf("❌ Erreur liens musicaux du thread %d: %v", thread.ID, err)
+	}
+}
+
+// attachShareCounts renseigne le nombre de partages de chaque thread
+func (s *threadService) attachShareCounts(threads []ThreadResponseDTO) error {
+	if len(threads) == 0 {
+		return nil
+	}
+
+	threadIDs := make([]uint, len(threads))
+	for i, thread := range threads {
+		threadIDs[i] = thread.ID
+	}
+
+	counts, err := s.shareRepo.CountByThreadIDs(threadIDs)
+	if err != nil {
+		return fmt.Errorf("erreur comptage partages: %w", err)
+	}
+
+	for i := range threads {
+		threads[i].ShareCount = counts[threads[i].ID]
+	}
+
+	return nil
+}
+
+// viewerCanList vérifie si l'utilisateur courant du service peut voir un thread dans une liste
+func (s *threadService) viewerCanList(thread *models.Thread) bool {
+	isOwner := s.viewerID != nil && *s.viewerID == thread.UserID
+	if thread.PublishAt != nil {
+		return isOwner
+	}
+	if thread.Visibility != models.VisibilityPrivate || isOwner {
+		return true
+	}
+	if s.viewerID == nil {
+		return false
+	}
+	allowed, err := s.threadRepo.CanView(thread.ID, *s.viewerID)
+	return err == nil && allowed
+}
+
 // buildPaginationInfo construit les infos de pagination
 func (s *threadService) buildPaginationInfo(params models.PaginationParams, total int64) PaginationInfo {
 	totalPages := int(total) / params.PerPage
@@ -587,6 +1273,11 @@ func (s *threadService) GetAllThreads() ([]ThreadDTO, error) {
 
 	var dtos []ThreadDTO
 	for _, thread := range threads {
+		// FindAll ne filtre pas la visibilité : écarter les threads privés ou programmés inaccessibles
+		if !s.viewerCanList(thread) {
+			continue
+		}
+
 		// Get tags from the thread's Tags field since they should be preloaded
 		tagNames := make([]string, len(thread.Tags))
 		for i, tag := range thread.Tags {
@@ -611,9 +1302,22 @@ func (s *threadService) GetAllThreads() ([]ThreadDTO, error) {
 			CreatedAt:    thread.CreatedAt,
 			UpdatedAt:    thread.UpdatedAt,
 			Tags:         tagNames,
+			ViewCount:    thread.ViewCount + pendingThreadViews(thread.ID),
 		}
 		dtos = append(dtos, dto)
 	}
 
+	threadIDs := make([]uint, len(dtos))
+	for i, dto := range dtos {
+		threadIDs[i] = dto.ID
+	}
+	shareCounts, err := s.shareRepo.CountByThreadIDs(threadIDs)
+	if err != nil {
+		return nil, fmt.Errorf("erreur comptage partages: %w", err)
+	}
+	for i := range dtos {
+		dtos[i].ShareCount = shareCounts[dtos[i].ID]
+	}
+
 	return dtos, nil
 }
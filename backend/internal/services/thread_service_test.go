@@ -1,12 +1,16 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"rythmitbackend/configs"
 	"rythmitbackend/internal/models"
 	"rythmitbackend/internal/repositories"
+	"rythmitbackend/internal/utils"
 	"rythmitbackend/pkg/database"
+	"strings"
 	"testing"
+	"time"
 )
 
 // DefaultPagination returns default pagination parameters for testing
@@ -571,3 +575,190 @@ func TestThreadService_EdgeCases(t *testing.T) {
 		t.Logf("✅ Thread %d supprimé avec succès", threadID)
 	})
 }
+
+func TestCheckStateAllows(t *testing.T) {
+	cases := []struct {
+		state   string
+		action  string
+		wantErr error
+	}{
+		{models.ThreadStateOpen, ThreadActionComment, nil},
+		{models.ThreadStateOpen, ThreadActionLike, nil},
+		{models.ThreadStateClosed, ThreadActionComment, utils.ErrThreadClosed},
+		{models.ThreadStateClosed, ThreadActionVote, utils.ErrThreadClosed},
+		{models.ThreadStateClosed, ThreadActionLike, nil},
+		{models.ThreadStateArchived, ThreadActionLike, utils.ErrThreadArchived},
+		{models.ThreadStateArchived, ThreadActionEdit, utils.ErrThreadArchived},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%s/%s", c.state, c.action), func(t *testing.T) {
+			if err := checkStateAllows(c.state, c.action); err != c.wantErr {
+				t.Errorf("Attendu: %v, Obtenu: %v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+// stubThreadRepository thread unique en mémoire ; les méthodes non surchargées ne sont pas utilisées
+type stubThreadRepository struct {
+	repositories.ThreadRepository
+	thread  *models.Thread
+	viewers map[uint]bool
+}
+
+func (r *stubThreadRepository) FindByID(id uint) (*models.Thread, error) {
+	if r.thread == nil || r.thread.ID != id {
+		return nil, utils.ErrThreadNotFound
+	}
+	return r.thread, nil
+}
+
+func (r *stubThreadRepository) CanView(threadID, userID uint) (bool, error) {
+	return r.viewers[userID], nil
+}
+
+func TestCheckThreadAction(t *testing.T) {
+	publishAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		visibility string
+		state      string
+		publishAt  *time.Time
+		userID     uint
+		wantErr    error
+	}{
+		{"public ouvert", models.VisibilityPublic, models.ThreadStateOpen, nil, 5, nil},
+		{"privé, invité", models.VisibilityPrivate, models.ThreadStateOpen, nil, 4, nil},
+		{"privé, auteur", models.VisibilityPrivate, models.ThreadStateOpen, nil, 1, nil},
+		{"privé, non invité", models.VisibilityPrivate, models.ThreadStateOpen, nil, 5, utils.ErrThreadNotFound},
+		{"programmé, autre utilisateur", models.VisibilityPublic, models.ThreadStateOpen, &publishAt, 5, utils.ErrThreadNotFound},
+		{"programmé, auteur", models.VisibilityPublic, models.ThreadStateOpen, &publishAt, 1, nil},
+		{"privé fermé, invité", models.VisibilityPrivate, models.ThreadStateClosed, nil, 4, utils.ErrThreadClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thread := &models.Thread{Visibility: tt.visibility, State: tt.state, PublishAt: tt.publishAt, UserID: 1}
+			thread.ID = 9
+			service := &threadService{threadRepo: &stubThreadRepository{thread: thread, viewers: map[uint]bool{4: true}}}
+
+			if err := service.CheckThreadAction(9, ThreadActionComment, tt.userID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Erreur attendue: %v, Obtenue: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUpdateArchivedThread(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   string
+		isAdmin bool
+		wantErr error
+	}{
+		{"auteur", models.ThreadStateOpen, false, utils.ErrThreadArchived},
+		{"admin, reste archivé", models.ThreadStateArchived, true, utils.ErrThreadArchived},
+		{"admin, état manquant", "", true, utils.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thread := &models.Thread{Title: "Archives", State: models.ThreadStateArchived, Visibility: models.VisibilityPublic, UserID: 1}
+			thread.ID = 9
+			service := &threadService{threadRepo: &stubThreadRepository{thread: thread}}
+
+			dto := UpdateThreadDTO{Title: "Archives", Description: "Texte", State: tt.state, Visibility: models.VisibilityPublic}
+			if err := service.UpdateThread(9, dto, 1, tt.isAdmin); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Erreur attendue: %v, Obtenue: %v", tt.wantErr, err)
+			}
+			if thread.State != models.ThreadStateArchived {
+				t.Errorf("L'état ne doit pas changer, Obtenu: %q", thread.State)
+			}
+		})
+	}
+}
+
+// viewerThreadRepository note l'utilisateur transmis par WithViewer
+type viewerThreadRepository struct {
+	stubThreadRepository
+	viewerID *uint
+}
+
+func (r *viewerThreadRepository) WithViewer(viewerID *uint) repositories.ThreadRepository {
+	return &viewerThreadRepository{stubThreadRepository: r.stubThreadRepository, viewerID: viewerID}
+}
+
+func TestForViewerKeepsDependencies(t *testing.T) {
+	viewerID := uint(4)
+	service := &threadService{
+		threadRepo:     &viewerThreadRepository{},
+		annotationRepo: repositories.NewAnnotationRepository(nil),
+		embedService:   NewEmbedService(nil, nil, nil, nil),
+		mentionService: NewMentionService(nil, nil, nil, nil),
+	}
+
+	scoped := service.ForViewer(&viewerID).(*threadService)
+	if repo := scoped.threadRepo.(*viewerThreadRepository); repo.viewerID != &viewerID {
+		t.Errorf("Le repository doit être filtré pour l'utilisateur %d", viewerID)
+	}
+	if scoped.viewerID != &viewerID {
+		t.Errorf("viewerID attendu: %d, Obtenu: %v", viewerID, scoped.viewerID)
+	}
+	if scoped.annotationRepo == nil || scoped.embedService == nil || scoped.mentionService == nil {
+		t.Errorf("Le service filtré doit garder toutes ses dépendances: %+v", scoped)
+	}
+}
+
+// scheduledThreadRepository threads programmés en mémoire dont la publication peut échouer
+type scheduledThreadRepository struct {
+	stubThreadRepository
+	due       []*models.Thread
+	failing   map[uint]bool
+	published []uint
+}
+
+func (r *scheduledThreadRepository) FindDueScheduled(now time.Time) ([]*models.Thread, error) {
+	return r.due, nil
+}
+
+func (r *scheduledThreadRepository) Publish(id uint) (bool, error) {
+	if r.failing[id] {
+		return false, errors.New("verrou expiré")
+	}
+	r.published = append(r.published, id)
+	return true, nil
+}
+
+func TestPublishDueThreadsContinuesAfterFailure(t *testing.T) {
+	repo := &scheduledThreadRepository{failing: map[uint]bool{1: true}}
+	for _, id := range []uint{1, 2, 3} {
+		thread := &models.Thread{Title: "Programmé", Author: &models.User{}}
+		thread.ID = id
+		repo.due = append(repo.due, thread)
+	}
+	service := &threadService{threadRepo: repo, mentionService: NewMentionService(nil, nil, nil, nil)}
+
+	published, err := service.PublishDueThreads()
+	if err == nil || !strings.Contains(err.Error(), "thread 1") {
+		t.Errorf("L'échec du thread 1 doit être signalé, Obtenu: %v", err)
+	}
+	if len(published) != 2 || published[0].ID != 2 || published[1].ID != 3 {
+		t.Errorf("Threads publiés attendus: [2 3], Obtenus: %+v", published)
+	}
+}
+
+func TestMergeThreadTagsWithHashtags(t *testing.T) {
+	previous := models.ExtractHashtags("Nouveau son #Drill #uk, voir https://example.com/page#intro et #1")
+	if fmt.Sprint(previous) != "[drill uk]" {
+		t.Fatalf("Hashtags attendus: [drill uk], Obtenu: %v", previous)
+	}
+
+	current := models.ExtractHashtags("Nouveau son #drill #grime")
+	merged := mergeThreadTags([]string{"rap", "UK", "Drill"}, removedHashtags(previous, current), current)
+
+	if fmt.Sprint(merged) != "[rap Drill grime]" {
+		t.Errorf("Tags attendus: [rap Drill grime], Obtenu: %v", merged)
+	}
+}
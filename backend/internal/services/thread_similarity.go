@@ -0,0 +1,158 @@
+package services
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Poids des critères de similarité entre deux threads (total = 1)
+const (
+	titleSimilarityWeight = 0.5
+	tagSimilarityWeight   = 0.2
+	trackSimilarityWeight = 0.3
+)
+
+// titleShingleSize taille des n-grammes de caractères comparés entre deux titres
+const titleShingleSize = 3
+
+// musicHosts plateformes dont les liens identifient un morceau ou un album
+var musicHosts = map[string]bool{
+	"open.spotify.com":  true,
+	"youtube.com":       true,
+	"music.youtube.com": true,
+	"youtu.be":          true,
+	"soundcloud.com":    true,
+	"deezer.com":        true,
+	"music.apple.com":   true,
+}
+
+// linkRegex repère les URLs d'un texte
+var linkRegex = regexp.MustCompile(`https?://[^\s<>()\[\]"']+`)
+
+// threadFingerprint éléments comparés pour détecter un doublon
+type threadFingerprint struct {
+	shingles map[string]bool
+	tags     map[string]bool
+	tracks   map[string]bool
+}
+
+// newThreadFingerprint calcule l'empreinte d'un thread à partir de son titre, sa description et ses tags
+func newThreadFingerprint(title, description string, tags []string) threadFingerprint {
+	fingerprint := threadFingerprint{
+		shingles: titleShingles(title),
+		tags:     make(map[string]bool, len(tags)),
+		tracks:   make(map[string]bool),
+	}
+
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			fingerprint.tags[tag] = true
+		}
+	}
+	for _, track := range extractTrackKeys(description) {
+		fingerprint.tracks[track] = true
+	}
+
+	return fingerprint
+}
+
+// similarity retourne un score entre 0 et 1, les tags partagés et si un même morceau est lié
+func (f threadFingerprint) similarity(other threadFingerprint) (float64, []string, bool) {
+	var sharedTags []string
+	for tag := range f.tags {
+		if other.tags[tag] {
+			sharedTags = append(sharedTags, tag)
+		}
+	}
+
+	sameTrack := false
+	for track := range f.tracks {
+		if other.tracks[track] {
+			sameTrack = true
+			break
+		}
+	}
+
+	score := titleSimilarityWeight*jaccard(f.shingles, other.shingles) +
+		tagSimilarityWeight*jaccard(f.tags, other.tags)
+	if sameTrack {
+		score += trackSimilarityWeight
+	}
+
+	return score, sharedTags, sameTrack
+}
+
+// titleShingles découpe un titre normalisé en n-grammes de caractères
+func titleShingles(title string) map[string]bool {
+	normalized := strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+
+	shingles := make(map[string]bool)
+	runes := []rune(normalized)
+	if len(runes) == 0 {
+		return shingles
+	}
+	if len(runes) <= titleShingleSize {
+		shingles[normalized] = true
+		return shingles
+	}
+
+	for i := 0; i+titleShingleSize <= len(runes); i++ {
+		shingles[string(runes[i:i+titleShingleSize])] = true
+	}
+
+	return shingles
+}
+
+// jaccard indice de Jaccard de deux ensembles (0 si les deux sont vides)
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+
+	intersection := 0
+	for key := range a {
+		if b[key] {
+			intersection++
+		}
+	}
+
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+// extractTrackKeys retourne une clé normalisée pour chaque lien musical d'un texte
+func extractTrackKeys(text string) []string {
+	var keys []string
+
+	for _, link := range linkRegex.FindAllString(text, -1) {
+		parsed, err := url.Parse(strings.TrimRight(link, ".,;:!?"))
+		if err != nil {
+			continue
+		}
+
+		// Les pages Bandcamp sont hébergées sur le sous-domaine de l'artiste
+		host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+		if !musicHosts[host] && !strings.HasSuffix(host, ".bandcamp.com") {
+			continue
+		}
+
+		// Une même vidéo YouTube a plusieurs formes d'URL
+		switch host {
+		case "youtube.com", "music.youtube.com":
+			if id := parsed.Query().Get("v"); id != "" {
+				keys = append(keys, "youtube:"+id)
+				continue
+			}
+		case "youtu.be":
+			keys = append(keys, "youtube:"+strings.Trim(parsed.Path, "/"))
+			continue
+		}
+
+		keys = append(keys, host+strings.TrimRight(parsed.Path, "/"))
+	}
+
+	return keys
+}
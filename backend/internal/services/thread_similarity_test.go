@@ -0,0 +1,43 @@
+package services
+
+import "testing"
+
+func TestThreadFingerprintSimilarity(t *testing.T) {
+	original := newThreadFingerprint(
+		"Le nouvel album de Kendrick",
+		"Écoutez ça https://open.spotify.com/album/4eLPsYPBmXABThSJ821sqY",
+		[]string{"rap", "kendrick lamar"},
+	)
+
+	cases := []struct {
+		name        string
+		title       string
+		description string
+		tags        []string
+		duplicate   bool
+		sameTrack   bool
+	}{
+		{"Même titre reformulé", "Nouvel album de Kendrick !", "", []string{"rap"}, true, false},
+		{"Même lien, autre titre", "Enfin sorti", "https://open.spotify.com/album/4eLPsYPBmXABThSJ821sqY?si=abc", []string{"rap", "kendrick lamar"}, true, true},
+		{"Sujet différent", "Meilleurs festivals de jazz", "", []string{"jazz"}, false, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			score, _, sameTrack := original.similarity(newThreadFingerprint(c.title, c.description, c.tags))
+			if (score >= duplicateThreshold) != c.duplicate {
+				t.Errorf("Score %.2f, doublon attendu: %v", score, c.duplicate)
+			}
+			if sameTrack != c.sameTrack {
+				t.Errorf("Même morceau attendu: %v, Obtenu: %v", c.sameTrack, sameTrack)
+			}
+		})
+	}
+}
+
+func TestExtractTrackKeysNormalizesYouTube(t *testing.T) {
+	keys := extractTrackKeys("https://www.youtube.com/watch?v=abc123&t=10 et https://youtu.be/abc123")
+	if len(keys) != 2 || keys[0] != "youtube:abc123" || keys[1] != keys[0] {
+		t.Errorf("Clés YouTube inattendues: %v", keys)
+	}
+}
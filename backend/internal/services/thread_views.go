@@ -0,0 +1,190 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// botUserAgentMarkers fragments d'user-agent des robots, aperçus de liens et clients HTTP
+var botUserAgentMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "preview", "facebookexternalhit", "headless",
+	"curl", "wget", "python-requests", "python-urllib", "go-http-client", "scrapy", "java/",
+}
+
+// ThreadViewStore destination des vues accumulées
+type ThreadViewStore interface {
+	IncrementViewCounts(counts map[uint]int) error
+}
+
+// ThreadViewCounter déduplique les vues des threads et les accumule en mémoire
+// pour les écrire par lots plutôt qu'à chaque affichage
+type ThreadViewCounter struct {
+	mu      sync.Mutex
+	store   ThreadViewStore
+	window  time.Duration
+	seen    map[string]time.Time // "threadID:visiteur" → dernière vue comptée
+	pending map[uint]int
+	now     func() time.Time
+}
+
+// threadViews compteur global utilisé par les handlers (nil tant qu'il n'est pas démarré)
+var threadViews *ThreadViewCounter
+
+// threadViewsStop arrête l'écriture périodique des lots (voir StopThreadViewCounter)
+var threadViewsStop chan struct{}
+
+// NewThreadViewCounter crée un compteur ; un même visiteur n'est compté qu'une fois par fenêtre
+func NewThreadViewCounter(store ThreadViewStore, window time.Duration) *ThreadViewCounter {
+	return &ThreadViewCounter{
+		store:   store,
+		window:  window,
+		seen:    make(map[string]time.Time),
+		pending: make(map[uint]int),
+		now:     time.Now,
+	}
+}
+
+// RecordView compte une vue sauf pour un robot ou un visiteur déjà compté dans la fenêtre
+// viewerKey identifie l'utilisateur connecté ou la session anonyme
+func (c *ThreadViewCounter) RecordView(threadID uint, viewerKey, userAgent string) bool {
+	if viewerKey == "" || IsBotUserAgent(userAgent) {
+		return false
+	}
+
+	key := fmt.Sprintf("%d:%s", threadID, viewerKey)
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if last, ok := c.seen[key]; ok && now.Sub(last) < c.window {
+		return false
+	}
+	c.seen[key] = now
+	c.pending[threadID]++
+
+	return true
+}
+
+// Pending nombre de vues d'un thread pas encore écrites en base
+func (c *ThreadViewCounter) Pending(threadID uint) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pending[threadID]
+}
+
+// Flush écrit les vues accumulées et oublie les visiteurs sortis de la fenêtre
+// En cas d'échec, les vues sont remises en attente pour le prochain lot
+func (c *ThreadViewCounter) Flush() error {
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = make(map[uint]int)
+
+	now := c.now()
+	for key, last := range c.seen {
+		if now.Sub(last) >= c.window {
+			delete(c.seen, key)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := c.store.IncrementViewCounts(batch); err != nil {
+		c.mu.Lock()
+		for threadID, views := range batch {
+			c.pending[threadID] += views
+		}
+		c.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// IsBotUserAgent indique si un user-agent est vide ou appartient à un robot connu
+func IsBotUserAgent(userAgent string) bool {
+	userAgent = strings.ToLower(strings.TrimSpace(userAgent))
+	if userAgent == "" {
+		return true
+	}
+
+	for _, marker := range botUserAgentMarkers {
+		if strings.Contains(userAgent, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StartThreadViewCounter installe le compteur de vues global et lance l'écriture périodique des lots
+func StartThreadViewCounter(store ThreadViewStore, window, interval time.Duration) {
+	if interval <= 0 {
+		log.Println("⏸️  Compteur de vues des threads désactivé")
+		return
+	}
+
+	counter := NewThreadViewCounter(store, window)
+	threadViews = counter
+	stop := make(chan struct{})
+	threadViewsStop = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := counter.Flush(); err != nil {
+					log.Printf("❌ Erreur écriture des vues des threads: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	log.Printf("✅ Compteur de vues actif (déduplication %s, écriture toutes les %s)", window, interval)
+}
+
+// StopThreadViewCounter arrête l'écriture périodique et écrit les vues encore en mémoire ;
+// appelé à l'arrêt du serveur, une fois les requêtes terminées, pour ne perdre aucune vue
+func StopThreadViewCounter() {
+	if threadViews == nil {
+		return
+	}
+	if threadViewsStop != nil {
+		close(threadViewsStop)
+		threadViewsStop = nil
+	}
+
+	if err := threadViews.Flush(); err != nil {
+		log.Printf("❌ Erreur écriture des dernières vues des threads: %v", err)
+		return
+	}
+	log.Println("✅ Vues des threads en attente écrites")
+}
+
+// RecordThreadView enregistre une vue sur le compteur global (sans effet s'il n'est pas démarré)
+func RecordThreadView(threadID uint, viewerKey, userAgent string) bool {
+	if threadViews == nil {
+		return false
+	}
+	return threadViews.RecordView(threadID, viewerKey, userAgent)
+}
+
+// pendingThreadViews vues d'un thread pas encore écrites, ajoutées au compteur affiché
+func pendingThreadViews(threadID uint) int {
+	if threadViews == nil {
+		return 0
+	}
+	return threadViews.Pending(threadID)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeViewStore conserve les lots écrits (ou échoue si err est défini)
+type fakeViewStore struct {
+	counts map[uint]int
+	err    error
+}
+
+func (f *fakeViewStore) IncrementViewCounts(counts map[uint]int) error {
+	if f.err != nil {
+		return f.err
+	}
+	for threadID, views := range counts {
+		f.counts[threadID] += views
+	}
+	return nil
+}
+
+const browserUserAgent = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/120.0 Safari/537.36"
+
+func TestThreadViewCounterDeduplicates(t *testing.T) {
+	store := &fakeViewStore{counts: make(map[uint]int)}
+	counter := NewThreadViewCounter(store, 30*time.Minute)
+	now := time.Now()
+	counter.now = func() time.Time { return now }
+
+	if !counter.RecordView(1, "u:1", browserUserAgent) {
+		t.Fatal("La première vue devrait être comptée")
+	}
+	if counter.RecordView(1, "u:1", browserUserAgent) {
+		t.Error("Une vue répétée dans la fenêtre ne devrait pas être comptée")
+	}
+	if !counter.RecordView(1, "u:2", browserUserAgent) {
+		t.Error("Un autre visiteur devrait être compté")
+	}
+	if counter.RecordView(1, "a:ff", "Googlebot/2.1 (+http://www.google.com/bot.html)") {
+		t.Error("Un robot ne devrait pas être compté")
+	}
+
+	now = now.Add(31 * time.Minute)
+	if !counter.RecordView(1, "u:1", browserUserAgent) {
+		t.Error("Une vue après la fenêtre devrait être comptée")
+	}
+
+	if err := counter.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if store.counts[1] != 3 {
+		t.Errorf("Vues attendues: 3, Obtenu: %d", store.counts[1])
+	}
+	if counter.Pending(1) != 0 {
+		t.Errorf("Aucune vue ne devrait rester en attente, Obtenu: %d", counter.Pending(1))
+	}
+}
+
+func TestThreadViewCounterKeepsViewsOnFlushError(t *testing.T) {
+	store := &fakeViewStore{counts: make(map[uint]int), err: errors.New("base indisponible")}
+	counter := NewThreadViewCounter(store, time.Minute)
+
+	counter.RecordView(7, "u:1", browserUserAgent)
+	if err := counter.Flush(); err == nil {
+		t.Fatal("Une erreur d'écriture était attendue")
+	}
+	if counter.Pending(7) != 1 {
+		t.Errorf("La vue devrait rester en attente, Obtenu: %d", counter.Pending(7))
+	}
+
+	store.err = nil
+	if err := counter.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if store.counts[7] != 1 {
+		t.Errorf("Vues attendues: 1, Obtenu: %d", store.counts[7])
+	}
+}
+
+func TestStopThreadViewCounterFlushesPendingViews(t *testing.T) {
+	store := &fakeViewStore{counts: make(map[uint]int)}
+	StartThreadViewCounter(store, time.Minute, time.Hour)
+	defer func() { threadViews = nil }()
+
+	RecordThreadView(5, "u:1", browserUserAgent)
+	StopThreadViewCounter()
+
+	if store.counts[5] != 1 {
+		t.Errorf("Les vues en attente devraient être écrites à l'arrêt, Obtenu: %d", store.counts[5])
+	}
+}
+
+func TestIsBotUserAgent(t *testing.T) {
+	cases := map[string]bool{
+		"":                        true,
+		browserUserAgent:          false,
+		"curl/8.4.0":              true,
+		"Discordbot/2.0":          true,
+		"facebookexternalhit/1.1": true,
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Safari/604.1": false,
+	}
+
+	for userAgent, expected := range cases {
+		if got := IsBotUserAgent(userAgent); got != expected {
+			t.Errorf("IsBotUserAgent(%q) = %v, attendu %v", userAgent, got, expected)
+		}
+	}
+}
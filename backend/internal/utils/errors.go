@@ -23,19 +23,56 @@ var (
 	ErrUsernameTaken   = errors.New("nom d'utilisateur déjà pris")
 
 	// Erreurs de threads
-	ErrThreadNotFound = errors.New("thread non trouvé")
-	ErrThreadClosed   = errors.New("ce thread est fermé")
-	ErrThreadArchived = errors.New("ce thread est archivé")
+	ErrThreadNotFound      = errors.New("thread non trouvé")
+	ErrThreadClosed        = errors.New("ce thread est fermé")
+	ErrThreadArchived      = errors.New("ce thread est archivé")
+	ErrDraftNotFound       = errors.New("brouillon non trouvé")
+	ErrTagNotFound         = errors.New("tag non trouvé")
+	ErrAlreadyReposted     = errors.New("vous avez déjà republié ce thread")
+	ErrThreadNotRepostable = errors.New("seuls les threads publics peuvent être republiés")
+
+	// Erreurs de collections
+	ErrCollectionNotFound     = errors.New("collection non trouvée")
+	ErrCollectionNameTaken    = errors.New("vous avez déjà une collection avec ce nom")
+	ErrCollectionItemExists   = errors.New("ce thread est déjà dans la collection")
+	ErrCollectionItemNotFound = errors.New("ce thread n'est pas dans la collection")
 
 	// Erreurs de messages
 	ErrMessageNotFound = errors.New("message non trouvé")
 	ErrAlreadyVoted    = errors.New("vous avez déjà voté pour ce message")
+	ErrReactionInvalid = errors.New("cette réaction n'est pas disponible")
 
 	// Erreurs de battles
 	ErrBattleNotFound     = errors.New("battle non trouvée")
 	ErrBattleEnded        = errors.New("cette battle est terminée")
 	ErrAlreadyVotedBattle = errors.New("vous avez déjà voté dans cette battle")
 
+	// Erreurs de sondages
+	ErrPollNotFound     = errors.New("sondage non trouvé")
+	ErrPollClosed       = errors.New("ce sondage est clôturé")
+	ErrAlreadyVotedPoll = errors.New("vous avez déjà voté à ce sondage")
+
+	// Erreurs de critiques
+	ErrReviewNotFound = errors.New("critique non trouvée")
+
+	// Erreurs d'annotations
+	ErrAnnotationNotFound = errors.New("annotation non trouvée")
+	ErrAnnotationOutdated = errors.New("le texte du thread a été modifié depuis la sélection")
+
+	// Erreurs du catalogue musical
+	ErrCatalogEntryNotFound = errors.New("entrée du catalogue non trouvée")
+	ErrCatalogLinkTaken     = errors.New("ce lien est déjà rattaché à une autre entrée du catalogue")
+
+	// Erreurs des fichiers audio
+	ErrAudioNotFound    = errors.New("fichier audio non trouvé")
+	ErrAudioTooLarge    = errors.New("fichier audio trop volumineux")
+	ErrAudioUnsupported = errors.New("format audio non pris en charge (MP3, OGG, FLAC ou WAV)")
+
+	// Erreurs de l'historique d'écoute et du scrobbling
+	ErrListenTooOld          = errors.New("écoute trop ancienne")
+	ErrListenTooNew          = errors.New("écoute datée dans le futur")
+	ErrScrobbleTokenNotFound = errors.New("jeton de scrobbling non trouvé")
+
 	// Erreurs système
 	ErrDatabaseConnection = errors.New("erreur de connexion à la base de données")
 	ErrInternalServer     = errors.New("erreur interne du serveur")
@@ -101,13 +138,35 @@ func HandleError(w http.ResponseWriter, err error) {
 	case errors.Is(err, ErrThreadNotFound):
 		NotFound(w, "Thread non trouvé")
 	case errors.Is(err, ErrThreadClosed):
-		Forbidden(w, "Ce thread est fermé aux nouveaux messages")
+		Forbidden(w, "Ce thread est fermé aux nouveaux messages et votes")
 	case errors.Is(err, ErrThreadArchived):
-		Forbidden(w, "Ce thread est archivé")
+		Forbidden(w, "Ce thread est archivé et consultable en lecture seule")
+	case errors.Is(err, ErrDraftNotFound):
+		NotFound(w, "Brouillon non trouvé")
+	case errors.Is(err, ErrTagNotFound):
+		NotFound(w, "Tag non trouvé")
+	case errors.Is(err, ErrAlreadyReposted):
+		BadRequest(w, "Vous avez déjà republié ce thread")
+	case errors.Is(err, ErrThreadNotRepostable):
+		BadRequest(w, "Seuls les threads publics peuvent être republiés")
+	case errors.Is(err, ErrCollectionNotFound):
+		NotFound(w, "Collection non trouvée")
+	case errors.Is(err, ErrCollectionNameTaken):
+		BadRequest(w, "Vous avez déjà une collection avec ce nom")
+	case errors.Is(err, ErrCollectionItemExists):
+		BadRequest(w, "Ce thread est déjà dans la collection")
+	case errors.Is(err, ErrCollectionItemNotFound):
+		NotFound(w, "Ce thread n'est pas dans la collection")
 	case errors.Is(err, ErrAlreadyVoted):
 		BadRequest(w, "Vous avez déjà voté pour ce message")
 	case errors.Is(err, ErrBattleEnded):
 		BadRequest(w, "Cette battle est terminée")
+	case errors.Is(err, ErrPollNotFound):
+		NotFound(w, "Sondage non trouvé")
+	case errors.Is(err, ErrPollClosed):
+		BadRequest(w, "Ce sondage est clôturé")
+	case errors.Is(err, ErrAlreadyVotedPoll):
+		BadRequest(w, "Vous avez déjà voté à ce sondage")
 	default:
 		// Log l'erreur réelle pour debug
 		// TODO: Ajouter un logger
@@ -17,6 +17,7 @@ var (
 	usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9_]{3,30}$`)
 	emailRegex    = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	urlRegex      = regexp.MustCompile(`^(https?:\/\/)?([\da-z\.-]+)\.([a-z\.]{2,6})([\/\w \.-]*)*\/?$`)
+	htmlTagRegex  = regexp.MustCompile(`<\s*/?\s*[a-zA-Z!?]`)
 )
 
 func init() {
@@ -126,10 +127,11 @@ func validateURL(fl validator.FieldLevel) bool {
 	return urlRegex.MatchString(url)
 }
 
-// validateNoHTML vérifie qu'une chaîne ne contient pas de HTML
+// validateNoHTML vérifie qu'une chaîne ne contient pas de balise HTML.
+// Les chevrons isolés restent permis (citations Markdown "> ", comparaisons "a < b").
 func validateNoHTML(fl validator.FieldLevel) bool {
 	str := fl.Field().String()
-	return !strings.ContainsAny(str, "<>")
+	return !htmlTagRegex.MatchString(str)
 }
 
 // validateAlphaNumSpace vérifie qu'une chaîne ne contient que des lettres, chiffres et espaces
@@ -0,0 +1,18 @@
+-- Migration: Contrôle d'accès des threads privés
+-- Un thread privé est visible par son auteur et ses invités,
+-- et par les amis de l'auteur si l'accès est 'amis'
+
+ALTER TABLE threads ADD COLUMN access ENUM('invitations', 'amis') NOT NULL DEFAULT 'invitations' AFTER visibility;
+
+CREATE TABLE IF NOT EXISTS thread_invitations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    thread_id INT NOT NULL,
+    user_id INT NOT NULL,
+    invited_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_thread_invitation (thread_id, user_id),
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_thread_invitations_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,21 @@
+-- Migration: Brouillons et publication programmée des threads
+-- publish_at non NULL = thread programmé, invisible jusqu'à sa publication
+
+ALTER TABLE threads ADD COLUMN publish_at TIMESTAMP NULL DEFAULT NULL AFTER access;
+
+CREATE INDEX idx_threads_publish_at ON threads (publish_at);
+
+CREATE TABLE IF NOT EXISTS thread_drafts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    title VARCHAR(200) NOT NULL DEFAULT '',
+    desc_ MEDIUMTEXT NOT NULL,
+    image_url VARCHAR(500) NULL,
+    tags VARCHAR(500) NOT NULL DEFAULT '',
+    visibility ENUM('public', 'privé') DEFAULT 'public',
+    publish_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_thread_drafts_user (user_id, updated_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
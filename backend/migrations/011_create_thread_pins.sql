@@ -0,0 +1,18 @@
+-- Migration: Threads épinglés et annonces
+-- tag_id NULL = épinglé sur le fil global, sinon uniquement sur la page du tag
+
+ALTER TABLE threads ADD COLUMN is_announcement BOOLEAN NOT NULL DEFAULT FALSE AFTER publish_at;
+
+CREATE TABLE IF NOT EXISTS thread_pins (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    thread_id INT NOT NULL,
+    tag_id INT NULL,
+    pinned_by INT NOT NULL,
+    expires_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE,
+    FOREIGN KEY (pinned_by) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_thread_pins_scope (tag_id, expires_at),
+    INDEX idx_thread_pins_thread (thread_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,42 @@
+-- Migration: Sondages intégrés aux threads
+-- Un thread porte au plus un sondage, un bulletin par utilisateur (thread_poll_ballots)
+
+CREATE TABLE IF NOT EXISTS thread_polls (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    thread_id INT NOT NULL UNIQUE,
+    question VARCHAR(300) NOT NULL,
+    multiple_choice BOOLEAN NOT NULL DEFAULT FALSE,
+    hide_results BOOLEAN NOT NULL DEFAULT FALSE,
+    closes_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS thread_poll_options (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    poll_id INT NOT NULL,
+    label VARCHAR(200) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (poll_id) REFERENCES thread_polls(id) ON DELETE CASCADE,
+    INDEX idx_thread_poll_options_poll (poll_id, position)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS thread_poll_ballots (
+    poll_id INT NOT NULL,
+    user_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, user_id),
+    FOREIGN KEY (poll_id) REFERENCES thread_polls(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS thread_poll_votes (
+    poll_id INT NOT NULL,
+    option_id INT NOT NULL,
+    user_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (option_id, user_id),
+    FOREIGN KEY (poll_id, user_id) REFERENCES thread_poll_ballots(poll_id, user_id) ON DELETE CASCADE,
+    FOREIGN KEY (option_id) REFERENCES thread_poll_options(id) ON DELETE CASCADE,
+    INDEX idx_thread_poll_votes_poll (poll_id, user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
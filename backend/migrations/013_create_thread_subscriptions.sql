@@ -0,0 +1,24 @@
+-- Migration: Abonnements aux threads et suivi de lecture
+-- subscribed = FALSE conserve un désabonnement explicite (pas de réabonnement automatique)
+
+CREATE TABLE IF NOT EXISTS thread_subscriptions (
+    user_id INT NOT NULL,
+    thread_id INT NOT NULL,
+    subscribed BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, thread_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE,
+    INDEX idx_thread_subscriptions_thread (thread_id, subscribed)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS thread_reads (
+    user_id INT NOT NULL,
+    thread_id INT NOT NULL,
+    last_read_message_id INT NOT NULL DEFAULT 0,
+    read_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, thread_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,18 @@
+-- Migration: Partages de threads (repost sur le profil ou envoi en message privé)
+-- repost_user_id n'est renseigné que pour les reposts : un seul repost par utilisateur et par thread
+
+CREATE TABLE IF NOT EXISTS thread_shares (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    thread_id INT NOT NULL,
+    user_id INT NOT NULL,
+    kind ENUM('repost', 'message') NOT NULL,
+    comment VARCHAR(500) NULL,
+    recipient_id INT NULL,
+    repost_user_id INT AS (IF(kind = 'repost', user_id, NULL)) STORED,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (recipient_id) REFERENCES users(id) ON DELETE SET NULL,
+    UNIQUE KEY uk_thread_shares_repost (thread_id, repost_user_id),
+    INDEX idx_thread_shares_user (user_id, kind, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
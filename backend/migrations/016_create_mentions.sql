@@ -0,0 +1,17 @@
+-- Migration: Mentions @utilisateur dans les threads, commentaires et messages privés
+-- source_id référence threads.id, messages.id ou direct_messages.id selon source_type
+
+CREATE TABLE IF NOT EXISTS mentions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    mentioned_user_id INT NOT NULL,
+    author_id INT NOT NULL,
+    source_type ENUM('thread', 'comment', 'message') NOT NULL,
+    source_id INT NOT NULL,
+    thread_id INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (mentioned_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_mentions_source_user (source_type, source_id, mentioned_user_id),
+    INDEX idx_mentions_user (mentioned_user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
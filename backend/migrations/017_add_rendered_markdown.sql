@@ -0,0 +1,6 @@
+-- Migration: Cache du rendu Markdown des threads et commentaires
+-- NULL = rendu à recalculer (contenus antérieurs au support Markdown)
+
+ALTER TABLE threads ADD COLUMN desc_html MEDIUMTEXT NULL AFTER desc_;
+
+ALTER TABLE messages ADD COLUMN content_html MEDIUMTEXT NULL AFTER content;
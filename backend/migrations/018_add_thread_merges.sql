@@ -0,0 +1,6 @@
+-- Migration: Fusion des threads en double
+-- merged_into_id non NULL = thread archivé dont les commentaires et likes ont été déplacés
+
+ALTER TABLE threads ADD COLUMN merged_into_id INT NULL AFTER is_announcement;
+
+ALTER TABLE threads ADD CONSTRAINT fk_threads_merged_into FOREIGN KEY (merged_into_id) REFERENCES threads(id) ON DELETE SET NULL;
@@ -0,0 +1,17 @@
+-- Migration: Journal des actions de modération sur les threads
+-- details contient les paramètres de l'action au format JSON (commentaires déplacés, tags...)
+
+CREATE TABLE IF NOT EXISTS moderation_actions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    moderator_id INT NOT NULL,
+    action ENUM('split', 'move', 'retag', 'merge') NOT NULL,
+    thread_id INT NOT NULL,
+    target_thread_id INT NULL,
+    details TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (moderator_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE,
+    FOREIGN KEY (target_thread_id) REFERENCES threads(id) ON DELETE SET NULL,
+    INDEX idx_moderation_actions_thread (thread_id, created_at),
+    INDEX idx_moderation_actions_target (target_thread_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,6 @@
+-- Migration: Compteur de vues des threads
+-- Les vues sont dédupliquées et regroupées en mémoire puis ajoutées par lots
+
+ALTER TABLE threads ADD COLUMN view_count INT NOT NULL DEFAULT 0 AFTER merged_into_id;
+
+CREATE INDEX idx_threads_view_count ON threads (view_count);
@@ -0,0 +1,16 @@
+-- Migration: Réactions emoji sur les commentaires et les messages privés
+-- target_id référence messages.id ou direct_messages.id selon target_type
+-- Les votes Fire/Skip (message_votes) restent la seule base du score de popularité
+-- emoji en collation binaire : en unicode_ci, des emojis différents sont considérés égaux
+
+CREATE TABLE IF NOT EXISTS reactions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    target_type ENUM('comment', 'message') NOT NULL,
+    target_id INT NOT NULL,
+    emoji VARCHAR(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_reactions_user_target_emoji (user_id, target_type, target_id, emoji),
+    INDEX idx_reactions_target (target_type, target_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,24 @@
+-- Migration: Critiques d'artistes, d'albums et de morceaux
+-- Un thread porte au plus une critique, liée à un tag artiste, album ou morceau (note de 0 à 10)
+
+ALTER TABLE tags MODIFY COLUMN type ENUM('genre', 'artist', 'album', 'track') DEFAULT 'genre';
+
+CREATE TABLE IF NOT EXISTS thread_reviews (
+    thread_id INT PRIMARY KEY,
+    tag_id INT NOT NULL,
+    score DECIMAL(3,1) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE,
+    INDEX idx_thread_reviews_tag_score (tag_id, score)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS thread_review_tracks (
+    thread_id INT NOT NULL,
+    position INT NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    score DECIMAL(3,1) NOT NULL,
+    PRIMARY KEY (thread_id, position),
+    FOREIGN KEY (thread_id) REFERENCES thread_reviews(thread_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,36 @@
+-- Migration: Annotations de passages de la description d'un thread (style Genius)
+-- desc_revision est incrémenté à chaque modification du texte de la description
+-- Les offsets sont comptés en caractères (points de code) dans le texte brut de la révision indiquée
+-- Une annotation dont le passage a disparu après une modification est marquée stale
+
+ALTER TABLE threads ADD COLUMN desc_revision INT UNSIGNED NOT NULL DEFAULT 1 AFTER desc_html;
+
+CREATE TABLE IF NOT EXISTS thread_annotations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    thread_id INT NOT NULL,
+    user_id INT NOT NULL,
+    start_offset INT UNSIGNED NOT NULL,
+    end_offset INT UNSIGNED NOT NULL,
+    quote TEXT NOT NULL,
+    body TEXT NOT NULL,
+    revision INT UNSIGNED NOT NULL,
+    stale BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (thread_id) REFERENCES threads(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_annotations_thread (thread_id, start_offset)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Votes Fire/Skip sur les annotations (un vote neutre supprime la ligne)
+CREATE TABLE IF NOT EXISTS annotation_votes (
+    user_id INT NOT NULL,
+    annotation_id INT NOT NULL,
+    state ENUM('fire', 'skip') NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, annotation_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (annotation_id) REFERENCES thread_annotations(id) ON DELETE CASCADE,
+    INDEX idx_annotation_votes_annotation (annotation_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
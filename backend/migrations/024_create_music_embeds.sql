@@ -0,0 +1,18 @@
+-- Migration: Liens musicaux reconnus (embeds structurés) des threads, commentaires et options de battle
+-- target_id référence threads.id, messages.id ou battle_options.id selon target_type
+-- url est l'URL canonique du lien, sans paramètres de suivi
+
+CREATE TABLE IF NOT EXISTS music_embeds (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    target_type ENUM('thread', 'comment', 'battle_option') NOT NULL,
+    target_id INT NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    platform ENUM('youtube', 'youtube_music', 'spotify', 'deezer', 'apple_music', 'soundcloud', 'bandcamp', 'tidal') NOT NULL,
+    entity_type ENUM('track', 'album', 'artist', 'playlist') NOT NULL,
+    entity_id VARCHAR(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_music_embeds_target_entity (target_type, target_id, platform, entity_type, entity_id),
+    INDEX idx_music_embeds_target (target_type, target_id, position),
+    INDEX idx_music_embeds_entity (platform, entity_type, entity_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
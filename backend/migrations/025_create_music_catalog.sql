@@ -0,0 +1,64 @@
+-- Migration: Catalogue musical commun à toutes les plateformes
+-- Un morceau, un album ou un artiste du catalogue regroupe ses liens Spotify, YouTube, Deezer, etc.
+-- catalog_links associe un lien reconnu (plateforme, type, identifiant : voir music_embeds) à une entrée
+-- matched_by : lien manuel, rapprochement par ISRC (ou UPC pour un album) ou par titre et artiste
+-- match_key : titre ou nom normalisé (minuscules, sans ponctuation ni mentions entre parenthèses)
+
+CREATE TABLE IF NOT EXISTS catalog_artists (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    match_key VARCHAR(200) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_catalog_artists_match_key (match_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS catalog_albums (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    artist_id INT NOT NULL,
+    title VARCHAR(300) NOT NULL,
+    match_key VARCHAR(300) NOT NULL,
+    upc VARCHAR(20) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (artist_id) REFERENCES catalog_artists(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_catalog_albums_upc (upc),
+    INDEX idx_catalog_albums_match (artist_id, match_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS catalog_tracks (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    artist_id INT NOT NULL,
+    album_id INT NULL,
+    title VARCHAR(300) NOT NULL,
+    match_key VARCHAR(300) NOT NULL,
+    isrc CHAR(12) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (artist_id) REFERENCES catalog_artists(id) ON DELETE CASCADE,
+    FOREIGN KEY (album_id) REFERENCES catalog_albums(id) ON DELETE SET NULL,
+    UNIQUE KEY uk_catalog_tracks_isrc (isrc),
+    INDEX idx_catalog_tracks_match (artist_id, match_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS catalog_links (
+    platform ENUM('youtube', 'youtube_music', 'spotify', 'deezer', 'apple_music', 'soundcloud', 'bandcamp', 'tidal') NOT NULL,
+    entity_type ENUM('track', 'album', 'artist') NOT NULL,
+    entity_id VARCHAR(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,
+    catalog_id INT NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    matched_by ENUM('manual', 'isrc', 'title_artist') NOT NULL DEFAULT 'manual',
+    created_by INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (platform, entity_type, entity_id),
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+    INDEX idx_catalog_links_entry (entity_type, catalog_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Plateforme préférée de chaque utilisateur pour ouvrir les liens du catalogue
+CREATE TABLE IF NOT EXISTS user_platform_preferences (
+    user_id INT PRIMARY KEY,
+    platform ENUM('youtube', 'youtube_music', 'spotify', 'deezer', 'apple_music', 'soundcloud', 'bandcamp', 'tidal') NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,24 @@
+-- Migration: Cache des métadonnées des liens musicaux (titre, artiste, album, durée, pochette)
+-- Une ligne par entité (plateforme, type, identifiant : voir music_embeds), remplie par un worker
+-- status : pending (à récupérer), ok, not_found (inconnu du fournisseur) ou error (nouvel essai plus tard)
+-- next_attempt_at : prochaine récupération (rafraîchissement d'une ligne ok, nouvel essai après erreur)
+
+CREATE TABLE IF NOT EXISTS music_metadata (
+    platform ENUM('youtube', 'youtube_music', 'spotify', 'deezer', 'apple_music', 'soundcloud', 'bandcamp', 'tidal') NOT NULL,
+    entity_type ENUM('track', 'album', 'artist', 'playlist') NOT NULL,
+    entity_id VARCHAR(200) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    status ENUM('pending', 'ok', 'not_found', 'error') NOT NULL DEFAULT 'pending',
+    title VARCHAR(300) NULL,
+    artist VARCHAR(200) NULL,
+    album VARCHAR(300) NULL,
+    duration_ms INT UNSIGNED NULL,
+    artwork_url VARCHAR(500) NULL,
+    provider VARCHAR(30) NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    fetched_at TIMESTAMP NULL,
+    next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (platform, entity_type, entity_id),
+    INDEX idx_music_metadata_due (next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,26 @@
+-- Migration: Fichiers audio envoyés par les utilisateurs (morceaux des battles)
+-- Le format est reconnu d'après le contenu du fichier, stocké sous uploads/audio/<file_name>
+-- title, artist, album : tags ID3 / Vorbis / INFO lus à l'envoi, proposés pour préremplir l'option
+-- battle_options.audio_id : morceau envoyé joué par l'option (music_url pointe alors vers le fichier)
+
+CREATE TABLE IF NOT EXISTS audio_uploads (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    file_name VARCHAR(100) NOT NULL,
+    original_name VARCHAR(255) NOT NULL,
+    format ENUM('mp3', 'ogg', 'flac', 'wav') NOT NULL,
+    size_bytes BIGINT UNSIGNED NOT NULL,
+    duration_ms INT UNSIGNED NOT NULL,
+    title VARCHAR(300) NULL,
+    artist VARCHAR(200) NULL,
+    album VARCHAR(300) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_audio_uploads_file_name (file_name),
+    INDEX idx_audio_uploads_user (user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE battle_options ADD COLUMN audio_id INT NULL;
+
+ALTER TABLE battle_options ADD CONSTRAINT fk_battle_options_audio
+    FOREIGN KEY (audio_id) REFERENCES audio_uploads(id) ON DELETE SET NULL;
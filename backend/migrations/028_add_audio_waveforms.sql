@@ -0,0 +1,13 @@
+-- Migration: Forme d'onde et durée exacte des fichiers audio envoyés
+-- Calculées par un worker après l'envoi (WAV décodés, enveloppe MP3 estimée d'après les trames)
+-- waveform_status : pending (à analyser), ok, unsupported (OGG, FLAC) ou error (nouvel essai limité)
+-- waveform : crêtes entre 0 et 1 au format JSON
+
+ALTER TABLE audio_uploads
+    ADD COLUMN waveform_status ENUM('pending', 'ok', 'unsupported', 'error') NOT NULL DEFAULT 'pending',
+    ADD COLUMN waveform TEXT NULL,
+    ADD COLUMN waveform_estimated BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN analysis_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN analyzed_at TIMESTAMP NULL;
+
+CREATE INDEX idx_audio_uploads_waveform ON audio_uploads (waveform_status, analysis_attempts);
@@ -0,0 +1,8 @@
+-- Migration: Lecture des fichiers audio par l'API
+-- Les fichiers de uploads/audio ne sont plus servis en statique : la lecture passe par
+-- /api/v1/audio/{id}/stream (requêtes Range, contrôle d'accès, URL signées)
+-- Les options de battle déjà créées pointent vers la nouvelle URL
+
+UPDATE battle_options
+SET music_url = CONCAT('/api/v1/audio/', audio_id, '/stream')
+WHERE audio_id IS NOT NULL;
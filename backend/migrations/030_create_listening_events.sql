@@ -0,0 +1,20 @@
+-- Migration: Historique d'écoute des utilisateurs
+-- Chaque activité « listening » est conservée (écoutes récentes du profil)
+-- expires_at : fin prévue de l'écoute (début + durée du morceau), l'écoute la plus récente
+-- non expirée est le « en écoute » de l'utilisateur (activité affichée aux amis)
+-- Une nouvelle écoute termine la précédente (expires_at ramené à son début)
+
+CREATE TABLE IF NOT EXISTS listening_events (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    track_name VARCHAR(300) NOT NULL,
+    artist VARCHAR(200) NOT NULL,
+    album VARCHAR(300) NULL,
+    duration_ms INT UNSIGNED NOT NULL,
+    music_url VARCHAR(500) NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_listening_events_user (user_id, started_at),
+    INDEX idx_listening_events_now_playing (user_id, expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
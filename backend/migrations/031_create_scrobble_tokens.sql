@@ -0,0 +1,23 @@
+-- Migration: API de scrobbling compatible Last.fm et ListenBrainz
+-- scrobble_tokens : jetons personnels des scrobblers (clé de session Last.fm, jeton ListenBrainz),
+-- seule l'empreinte SHA-256 est conservée, token_prefix permet de les reconnaître dans la liste
+-- listening_events.in_history : FALSE pour un « en écoute » signalé par un scrobbler, qui n'entre
+-- dans l'historique qu'une fois scrobblé
+-- uk_listening_events_listen : un scrobble renvoyé par un client (nouvel essai) n'est enregistré qu'une fois
+
+CREATE TABLE IF NOT EXISTS scrobble_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    token_prefix CHAR(6) NOT NULL,
+    last_used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_scrobble_tokens_hash (token_hash),
+    INDEX idx_scrobble_tokens_user (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE listening_events
+    ADD COLUMN in_history BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD UNIQUE KEY uk_listening_events_listen (user_id, started_at, in_history, track_name(191));
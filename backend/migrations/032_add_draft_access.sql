@@ -0,0 +1,4 @@
+-- Migration: Niveau d'accès des brouillons de threads privés
+-- Conservé à la publication, comme threads.access (invitations : auteur et invités, amis : aussi les amis de l'auteur)
+
+ALTER TABLE thread_drafts ADD COLUMN access ENUM('invitations', 'amis') NOT NULL DEFAULT 'invitations' AFTER visibility;
@@ -0,0 +1,7 @@
+-- Migration: Clé d'unicité des écoutes avec l'artiste
+-- Un scrobble renvoyé (nouvel essai après une erreur) est reconnu par l'utilisateur, le début de
+-- l'écoute, le titre et l'artiste : deux morceaux homonymes écoutés à la même seconde restent distincts
+
+ALTER TABLE listening_events
+    DROP INDEX uk_listening_events_listen,
+    ADD UNIQUE KEY uk_listening_events_listen (user_id, started_at, in_history, track_name(191), artist(191));
@@ -0,0 +1,6 @@
+-- Migration: Liens vers les fichiers audio envoyés dans les embeds
+-- platform 'upload' : lien vers /api/v1/audio/{id}, entity_id est l'ID du fichier (audio_uploads.id),
+-- la durée et la forme d'onde sont lues dans audio_uploads et non dans music_metadata
+
+ALTER TABLE music_embeds
+    MODIFY platform ENUM('youtube', 'youtube_music', 'spotify', 'deezer', 'apple_music', 'soundcloud', 'bandcamp', 'tidal', 'upload') NOT NULL;
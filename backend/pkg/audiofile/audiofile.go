@@ -0,0 +1,208 @@
+// Package audiofile reconnaît les fichiers audio envoyés par les utilisateurs.
+//
+// Probe identifie le format d'après le contenu du fichier (et non son extension
+// ni le Content-Type annoncé), calcule la durée et lit les tags de titre,
+// d'artiste et d'album, sans décoder l'audio.
+//
+// Formats reconnus :
+//
+//	MP3 (ID3v2, ID3v1, en-têtes Xing/Info/VBRI), Ogg Vorbis et Opus, FLAC, WAV (PCM)
+package audiofile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Formats reconnus
+const (
+	FormatMP3  = "mp3"
+	FormatOGG  = "ogg"
+	FormatFLAC = "flac"
+	FormatWAV  = "wav"
+)
+
+// ErrUnsupported le contenu n'est pas un fichier audio reconnu
+var ErrUnsupported = errors.New("format audio non reconnu")
+
+// maxTagSize taille maximale lue pour les tags (les pochettes intégrées au-delà sont ignorées)
+const maxTagSize = 1 << 20
+
+// Info décrit un fichier audio
+type Info struct {
+	Format     string
+	Duration   time.Duration
+	SampleRate int
+	Channels   int
+	Title      string
+	Artist     string
+	Album      string
+}
+
+// MimeType type MIME servi pour un format
+func MimeType(format string) string {
+	switch format {
+	case FormatMP3:
+		return "audio/mpeg"
+	case FormatOGG:
+		return "audio/ogg"
+	case FormatFLAC:
+		return "audio/flac"
+	case FormatWAV:
+		return "audio/wav"
+	}
+	return "application/octet-stream"
+}
+
+// Sniff reconnaît le format d'après les premiers octets du fichier ("" si inconnu) ;
+// un tag ID3v2 annonce un MP3 même s'il précède un flux FLAC (voir Probe)
+func Sniff(header []byte) string {
+	switch {
+	case len(header) >= 3 && string(header[:3]) == "ID3":
+		return FormatMP3
+	case len(header) >= 4 && string(header[:4]) == "OggS":
+		return FormatOGG
+	case len(header) >= 4 && string(header[:4]) == "fLaC":
+		return FormatFLAC
+	case len(header) >= 12 && string(header[:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		return FormatWAV
+	case len(header) >= 4:
+		if _, ok := parseMPEGHeader(header[:4]); ok {
+			return FormatMP3
+		}
+	}
+	return ""
+}
+
+// Probe analyse un fichier audio complet
+func Probe(r io.ReadSeeker) (*Info, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 12)
+	if _, err := readAt(r, header, 0); err != nil {
+		return nil, ErrUnsupported
+	}
+
+	info := &Info{}
+	switch Sniff(header) {
+	case FormatMP3:
+		err = probeMP3(r, size, info)
+	case FormatOGG:
+		err = probeOGG(r, size, info)
+	case FormatFLAC:
+		err = probeFLAC(r, 0, info)
+	case FormatWAV:
+		err = probeWAV(r, size, info)
+	default:
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("fichier audio tronqué: %w", ErrUnsupported)
+		}
+		return nil, err
+	}
+
+	if info.Duration <= 0 {
+		return nil, fmt.Errorf("durée introuvable: %w", ErrUnsupported)
+	}
+	return info, nil
+}
+
+// readAt lit len(buf) octets à partir de offset
+func readAt(r io.ReadSeeker, buf []byte, offset int64) (int, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(r, buf)
+}
+
+// readBlock lit size octets à offset, en se limitant à maxTagSize
+func readBlock(r io.ReadSeeker, offset, size int64) ([]byte, error) {
+	if size > maxTagSize {
+		size = maxTagSize
+	}
+	buf := make([]byte, size)
+	n, err := readAt(r, buf, offset)
+	if err == io.ErrUnexpectedEOF {
+		return buf[:n], nil
+	}
+	return buf, err
+}
+
+// setTag renseigne un tag encore vide avec une valeur nettoyée
+func setTag(field *string, value string) {
+	if *field != "" {
+		return
+	}
+	*field = cleanText(value)
+}
+
+// cleanText retire les octets nuls et les espaces superflus, et remplace l'UTF-8 invalide
+func cleanText(value string) string {
+	value = strings.ToValidUTF8(value, string(utf8.RuneError))
+	return strings.TrimSpace(strings.Trim(value, "\x00"))
+}
+
+// latin1 convertit un texte ISO-8859-1 en UTF-8, jusqu'au premier octet nul
+func latin1(b []byte) string {
+	runes := make([]rune, 0, len(b))
+	for _, c := range b {
+		if c == 0 {
+			break
+		}
+		runes = append(runes, rune(c))
+	}
+	return string(runes)
+}
+
+// parseVorbisComments lit un bloc de commentaires Vorbis (Ogg Vorbis, Opus, FLAC)
+func parseVorbisComments(data []byte, info *Info) {
+	if len(data) < 8 {
+		return
+	}
+	vendorLen := int(binary.LittleEndian.Uint32(data))
+	pos := 4 + vendorLen
+	if pos+4 > len(data) || vendorLen < 0 {
+		return
+	}
+	count := int(binary.LittleEndian.Uint32(data[pos:]))
+	pos += 4
+
+	for i := 0; i < count && pos+4 <= len(data); i++ {
+		length := int(binary.LittleEndian.Uint32(data[pos:]))
+		pos += 4
+		if length < 0 || pos+length > len(data) {
+			return
+		}
+		key, value, ok := strings.Cut(string(data[pos:pos+length]), "=")
+		pos += length
+		if !ok {
+			continue
+		}
+		switch strings.ToUpper(key) {
+		case "TITLE":
+			setTag(&info.Title, value)
+		case "ARTIST":
+			setTag(&info.Artist, value)
+		case "ALBUM":
+			setTag(&info.Album, value)
+		}
+	}
+}
+
+// samplesDuration durée de samples échantillons à sampleRate Hz
+func samplesDuration(samples int64, sampleRate int) time.Duration {
+	if sampleRate <= 0 || samples <= 0 {
+		return 0
+	}
+	return time.Duration(float64(samples) / float64(sampleRate) * float64(time.Second))
+}
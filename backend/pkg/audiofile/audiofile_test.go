@@ -0,0 +1,356 @@
+package audiofile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// wavFile construit un WAV PCM 16 bits stéréo 44,1 kHz de la durée demandée, avec tags INFO
+func wavFile(seconds int, title, artist string) []byte {
+	var info bytes.Buffer
+	info.WriteString("INFO")
+	for _, tag := range [][2]string{{"INAM", title}, {"IART", artist}} {
+		value := append([]byte(tag[1]), 0)
+		info.WriteString(tag[0])
+		binary.Write(&info, binary.LittleEndian, uint32(len(value)))
+		info.Write(value)
+		if len(value)%2 == 1 {
+			info.WriteByte(0)
+		}
+	}
+
+	var chunks bytes.Buffer
+	chunks.WriteString("WAVE")
+	chunks.WriteString("fmt ")
+	binary.Write(&chunks, binary.LittleEndian, []uint32{16})
+	binary.Write(&chunks, binary.LittleEndian, []uint16{1, 2})
+	binary.Write(&chunks, binary.LittleEndian, []uint32{44100, 44100 * 4})
+	binary.Write(&chunks, binary.LittleEndian, []uint16{4, 16})
+	chunks.WriteString("LIST")
+	binary.Write(&chunks, binary.LittleEndian, uint32(info.Len()))
+	chunks.Write(info.Bytes())
+	chunks.WriteString("data")
+	binary.Write(&chunks, binary.LittleEndian, uint32(seconds*44100*4))
+	chunks.Write(make([]byte, seconds*44100*4))
+
+	var file bytes.Buffer
+	file.WriteString("RIFF")
+	binary.Write(&file, binary.LittleEndian, uint32(chunks.Len()))
+	file.Write(chunks.Bytes())
+	return file.Bytes()
+}
+
+// vorbisComments construit un bloc de commentaires Vorbis
+func vorbisComments(comments ...string) []byte {
+	var block bytes.Buffer
+	binary.Write(&block, binary.LittleEndian, uint32(4))
+	block.WriteString("test")
+	binary.Write(&block, binary.LittleEndian, uint32(len(comments)))
+	for _, comment := range comments {
+		binary.Write(&block, binary.LittleEndian, uint32(len(comment)))
+		block.WriteString(comment)
+	}
+	return block.Bytes()
+}
+
+// flacFile construit un FLAC (métadonnées seules) de samples échantillons à 44,1 kHz
+func flacFile(samples int64, comments ...string) []byte {
+	rate := 44100
+	streamInfo := make([]byte, 34)
+	streamInfo[10] = byte(rate >> 12)
+	streamInfo[11] = byte(rate >> 4)
+	streamInfo[12] = byte(rate<<4) | 1<<1 // stéréo
+	streamInfo[13] = byte(samples>>32) & 0x0F
+	binary.BigEndian.PutUint32(streamInfo[14:], uint32(samples))
+
+	block := vorbisComments(comments...)
+	var file bytes.Buffer
+	file.WriteString("fLaC")
+	file.Write([]byte{0x00, 0, 0, 34})
+	file.Write(streamInfo)
+	file.Write([]byte{0x80 | 4, byte(len(block) >> 16), byte(len(block) >> 8), byte(len(block))})
+	file.Write(block)
+	return file.Bytes()
+}
+
+// mp3Frame construit une trame MPEG1 Layer III 128 kbit/s 44,1 kHz stéréo (417 octets)
+func mp3Frame(payload []byte) []byte {
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	copy(frame[4:], payload)
+	return frame
+}
+
+// id3v23 construit un tag ID3v2.3 (titre en ISO-8859-1, artiste en UTF-16)
+func id3v23(title, artist string) []byte {
+	var frames bytes.Buffer
+	titleFrame := append([]byte{0}, []byte(title)...)
+	frames.WriteString("TIT2")
+	binary.Write(&frames, binary.BigEndian, uint32(len(titleFrame)))
+	frames.Write([]byte{0, 0})
+	frames.Write(titleFrame)
+
+	artistFrame := []byte{1, 0xFF, 0xFE}
+	for _, r := range artist {
+		artistFrame = binary.LittleEndian.AppendUint16(artistFrame, uint16(r))
+	}
+	frames.WriteString("TPE1")
+	binary.Write(&frames, binary.BigEndian, uint32(len(artistFrame)))
+	frames.Write([]byte{0, 0})
+	frames.Write(artistFrame)
+	frames.Write(make([]byte, 32)) // remplissage
+
+	size := frames.Len()
+	tag := []byte{'I', 'D', '3', 3, 0, 0, byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+	return append(tag, frames.Bytes()...)
+}
+
+// oggPage construit une page Ogg contenant des paquets complets
+func oggPage(granule int64, packets ...[]byte) []byte {
+	var lacing, body []byte
+	for _, packet := range packets {
+		n := len(packet)
+		for ; n >= 255; n -= 255 {
+			lacing = append(lacing, 255)
+		}
+		lacing = append(lacing, byte(n))
+		body = append(body, packet...)
+	}
+
+	page := []byte("OggS")
+	page = append(page, 0, 0)
+	page = binary.LittleEndian.AppendUint64(page, uint64(granule))
+	page = binary.LittleEndian.AppendUint32(page, 42)
+	page = append(page, make([]byte, 8)...) // numéro de page et CRC (non vérifiés)
+	page = append(page, byte(len(lacing)))
+	page = append(page, lacing...)
+	return append(page, body...)
+}
+
+// oggVorbisFile construit un Ogg Vorbis (en-têtes seuls) de samples échantillons à 44,1 kHz
+func oggVorbisFile(samples int64, comments ...string) []byte {
+	id := append([]byte("\x01vorbis"), 0, 0, 0, 0, 2)
+	id = binary.LittleEndian.AppendUint32(id, 44100)
+	id = append(id, make([]byte, 14)...)
+	comment := append([]byte("\x03vorbis"), vorbisComments(comments...)...)
+
+	var file []byte
+	file = append(file, oggPage(0, id)...)
+	file = append(file, oggPage(0, comment)...)
+	file = append(file, oggPage(samples/2, make([]byte, 300))...)
+	return append(file, oggPage(samples, make([]byte, 300))...)
+}
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{"tag ID3", []byte("ID3\x03\x00\x00\x00\x00\x00\x00"), FormatMP3},
+		{"trame MPEG", []byte{0xFF, 0xFB, 0x90, 0x00}, FormatMP3},
+		{"Ogg", []byte("OggS\x00\x02"), FormatOGG},
+		{"FLAC", []byte("fLaC\x00\x00\x00\x22"), FormatFLAC},
+		{"WAV", []byte("RIFF\x24\x00\x00\x00WAVE"), FormatWAV},
+		{"AVI", []byte("RIFF\x24\x00\x00\x00AVI "), ""},
+		{"PNG", []byte("\x89PNG\r\n\x1a\n"), ""},
+		{"trame MPEG Layer I", []byte{0xFF, 0xFF, 0x90, 0x00}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sniff(tt.header); got != tt.want {
+				t.Errorf("Attendu: %q, Obtenu: %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProbe(t *testing.T) {
+	var cbr []byte
+	cbr = append(cbr, id3v23("Midnight City", "M83")...)
+	for i := 0; i < 100; i++ {
+		cbr = append(cbr, mp3Frame(nil)...)
+	}
+
+	xingHeader := append([]byte("Xing"), 0, 0, 0, 1)
+	xingHeader = binary.BigEndian.AppendUint32(xingHeader, 1000)
+	vbr := mp3Frame(append(make([]byte, 32), xingHeader...))
+	for i := 0; i < 10; i++ {
+		vbr = append(vbr, mp3Frame(nil)...)
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		format   string
+		duration time.Duration
+		title    string
+		artist   string
+	}{
+		{"WAV avec tags INFO", wavFile(2, "Tessellate", "alt-J"), FormatWAV, 2 * time.Second, "Tessellate", "alt-J"},
+		{"FLAC avec commentaires", flacFile(3*44100, "TITLE=Bloodflood", "artist=alt-J"), FormatFLAC, 3 * time.Second, "Bloodflood", "alt-J"},
+		{"FLAC précédé d'ID3", append(id3v23("Teardrop", "Massive Attack"), flacFile(44100)...), FormatFLAC, time.Second, "Teardrop", "Massive Attack"},
+		{"MP3 débit constant avec ID3v2", cbr, FormatMP3, 2606 * time.Millisecond, "Midnight City", "M83"},
+		{"MP3 avec en-tête Xing", vbr, FormatMP3, 26122 * time.Millisecond, "", ""},
+		{"Ogg Vorbis", oggVorbisFile(5*44100, "TITLE=Réveil", "ARTIST=Étienne"), FormatOGG, 5 * time.Second, "Réveil", "Étienne"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Probe(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Erreur inattendue: %v", err)
+			}
+			if info.Format != tt.format {
+				t.Errorf("Format attendu: %s, Obtenu: %s", tt.format, info.Format)
+			}
+			if diff := info.Duration - tt.duration; diff < -time.Millisecond || diff > time.Millisecond {
+				t.Errorf("Durée attendue: %s, Obtenue: %s", tt.duration, info.Duration)
+			}
+			if info.Title != tt.title || info.Artist != tt.artist {
+				t.Errorf("Tags attendus: %q / %q, Obtenus: %q / %q", tt.title, tt.artist, info.Title, info.Artist)
+			}
+		})
+	}
+}
+
+func TestProbeRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"image PNG", append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)},
+		{"fichier vide", nil},
+		{"WAV sans données", wavFile(0, "", "")[:36]},
+		{"MP3 sans trame", append(id3v23("Titre", "Artiste"), make([]byte, 512)...)},
+		{"FLAC tronqué", flacFile(44100)[:20]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Probe(bytes.NewReader(tt.data)); !errors.Is(err, ErrUnsupported) {
+				t.Errorf("Attendu: ErrUnsupported, Obtenu: %v", err)
+			}
+		})
+	}
+}
+
+// wavSamples construit un WAV PCM 16 bits mono 8 kHz à partir d'échantillons
+func wavSamples(samples []int16) []byte {
+	var chunks bytes.Buffer
+	chunks.WriteString("WAVEfmt ")
+	binary.Write(&chunks, binary.LittleEndian, []uint32{16})
+	binary.Write(&chunks, binary.LittleEndian, []uint16{1, 1})
+	binary.Write(&chunks, binary.LittleEndian, []uint32{8000, 16000})
+	binary.Write(&chunks, binary.LittleEndian, []uint16{2, 16})
+	chunks.WriteString("data")
+	binary.Write(&chunks, binary.LittleEndian, uint32(len(samples)*2))
+	binary.Write(&chunks, binary.LittleEndian, samples)
+
+	var file bytes.Buffer
+	file.WriteString("RIFF")
+	binary.Write(&file, binary.LittleEndian, uint32(chunks.Len()))
+	file.Write(chunks.Bytes())
+	return file.Bytes()
+}
+
+// mp3GainFrame construit une trame MPEG1 stéréo dont les quatre granules ont le gain global donné
+// (gain négatif : granules sans données, donc silencieux)
+func mp3GainFrame(gain int) []byte {
+	var bits []byte
+	write := func(value, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, byte(value>>i)&1)
+		}
+	}
+	write(0, 9+3+8) // main_data_begin, bits privés, scfsi
+	for granule := 0; granule < 4; granule++ {
+		if gain < 0 {
+			write(0, 59)
+			continue
+		}
+		write(100, 12) // part2_3_length
+		write(10, 9)   // big_values
+		write(gain, 8)
+		write(0, 30)
+	}
+
+	side := make([]byte, 32)
+	for i, bit := range bits {
+		side[i/8] |= bit << (7 - i%8)
+	}
+	return mp3Frame(side)
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Run("WAV décodé", func(t *testing.T) {
+		samples := make([]int16, 16000)
+		for i := 8000; i < len(samples); i++ {
+			samples[i] = 16384
+			if i%2 == 1 {
+				samples[i] = -16384
+			}
+		}
+		samples[12000] = -32768
+
+		analysis, err := Analyze(bytes.NewReader(wavSamples(samples)), 4)
+		if err != nil {
+			t.Fatalf("Erreur inattendue: %v", err)
+		}
+		want := []float64{0, 0, 0.5, 1}
+		for i, peak := range analysis.Peaks {
+			if peak != want[i] {
+				t.Errorf("Crêtes attendues: %v, Obtenues: %v", want, analysis.Peaks)
+				break
+			}
+		}
+		if analysis.Duration != 2*time.Second || analysis.Estimated {
+			t.Errorf("Attendu: 2s décodées, Obtenu: %s (estimée: %v)", analysis.Duration, analysis.Estimated)
+		}
+	})
+
+	t.Run("MP3 estimé d'après les gains", func(t *testing.T) {
+		xingHeader := append([]byte("Xing"), 0, 0, 0, 1)
+		xingHeader = binary.BigEndian.AppendUint32(xingHeader, 40)
+		data := id3v23("Titre", "Artiste")
+		data = append(data, mp3Frame(append(make([]byte, 32), xingHeader...))...)
+		for i := 0; i < 40; i++ {
+			switch {
+			case i < 20:
+				data = append(data, mp3GainFrame(-1)...)
+			case i < 30:
+				data = append(data, mp3GainFrame(202)...)
+			default:
+				data = append(data, mp3GainFrame(210)...)
+			}
+		}
+
+		analysis, err := Analyze(bytes.NewReader(data), 4)
+		if err != nil {
+			t.Fatalf("Erreur inattendue: %v", err)
+		}
+		want := []float64{0, 0, 0.25, 1}
+		for i, peak := range analysis.Peaks {
+			if math.Abs(peak-want[i]) > 1e-9 {
+				t.Errorf("Crêtes attendues: %v, Obtenues: %v", want, analysis.Peaks)
+				break
+			}
+		}
+		if wantDuration := samplesDuration(40*1152, 44100); analysis.Duration != wantDuration || !analysis.Estimated {
+			t.Errorf("Attendu: %s estimée, Obtenu: %s (estimée: %v)", wantDuration, analysis.Duration, analysis.Estimated)
+		}
+	})
+
+	t.Run("formats sans forme d'onde", func(t *testing.T) {
+		for _, data := range [][]byte{flacFile(44100), oggVorbisFile(44100)} {
+			if _, err := Analyze(bytes.NewReader(data), 4); !errors.Is(err, ErrUnsupported) {
+				t.Errorf("Attendu: ErrUnsupported, Obtenu: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package audiofile
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Types de blocs de métadonnées FLAC utilisés
+const (
+	flacStreamInfo    = 0
+	flacVorbisComment = 4
+)
+
+// probeFLAC lit les blocs STREAMINFO et VORBIS_COMMENT d'un flux FLAC commençant à start
+func probeFLAC(r io.ReadSeeker, start int64, info *Info) error {
+	info.Format = FormatFLAC
+	header := make([]byte, 4)
+
+	for offset := start + 4; ; {
+		if _, err := readAt(r, header, offset); err != nil {
+			return err
+		}
+		last := header[0]&0x80 != 0
+		length := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+		offset += 4
+
+		switch header[0] & 0x7F {
+		case flacStreamInfo:
+			block, err := readBlock(r, offset, length)
+			if err != nil {
+				return err
+			}
+			if len(block) < 18 {
+				return fmt.Errorf("STREAMINFO FLAC invalide: %w", ErrUnsupported)
+			}
+			info.SampleRate = int(block[10])<<12 | int(block[11])<<4 | int(block[12])>>4
+			info.Channels = int((block[12]>>1)&0x07) + 1
+			samples := int64(block[13]&0x0F)<<32 | int64(binary.BigEndian.Uint32(block[14:]))
+			info.Duration = samplesDuration(samples, info.SampleRate)
+		case flacVorbisComment:
+			block, err := readBlock(r, offset, length)
+			if err != nil {
+				return err
+			}
+			parseVorbisComments(block, info)
+		}
+
+		offset += length
+		if last {
+			break
+		}
+	}
+
+	if info.SampleRate == 0 {
+		return fmt.Errorf("STREAMINFO FLAC manquant: %w", ErrUnsupported)
+	}
+	return nil
+}
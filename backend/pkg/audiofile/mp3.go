@@ -0,0 +1,272 @@
+package audiofile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+	"unicode/utf16"
+)
+
+// maxSyncSearch distance maximale parcourue pour trouver la première trame MPEG
+const maxSyncSearch = 64 << 10
+
+// Débits (kbit/s) des trames MPEG Layer III selon l'index d'en-tête
+var (
+	mpeg1Bitrates = [15]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320}
+	mpeg2Bitrates = [15]int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160}
+	mpegRates     = [3]int{44100, 48000, 32000}
+)
+
+// mpegFrame en-tête d'une trame MPEG Layer III
+type mpegFrame struct {
+	mpeg1      bool
+	bitrate    int // kbit/s
+	sampleRate int
+	channels   int
+	length     int // octets, en-tête compris
+}
+
+// samples nombre d'échantillons par trame
+func (f mpegFrame) samples() int {
+	if f.mpeg1 {
+		return 1152
+	}
+	return 576
+}
+
+// sideInfoSize taille des informations annexes qui suivent l'en-tête
+func (f mpegFrame) sideInfoSize() int {
+	switch {
+	case f.mpeg1 && f.channels == 1:
+		return 17
+	case f.mpeg1:
+		return 32
+	case f.channels == 1:
+		return 9
+	}
+	return 17
+}
+
+// parseMPEGHeader décode l'en-tête de 4 octets d'une trame MPEG Layer III
+func parseMPEGHeader(b []byte) (mpegFrame, bool) {
+	if b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return mpegFrame{}, false
+	}
+	version := (b[1] >> 3) & 0x03 // 3 = MPEG1, 2 = MPEG2, 0 = MPEG2.5
+	layer := (b[1] >> 1) & 0x03   // 1 = Layer III
+	bitrateIndex := int(b[2] >> 4)
+	rateIndex := int((b[2] >> 2) & 0x03)
+	if version == 1 || layer != 1 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return mpegFrame{}, false
+	}
+
+	frame := mpegFrame{mpeg1: version == 3, sampleRate: mpegRates[rateIndex], channels: 2}
+	if frame.mpeg1 {
+		frame.bitrate = mpeg1Bitrates[bitrateIndex]
+	} else {
+		frame.bitrate = mpeg2Bitrates[bitrateIndex]
+		frame.sampleRate /= 2
+		if version == 0 {
+			frame.sampleRate /= 2
+		}
+	}
+	if b[3]>>6 == 3 {
+		frame.channels = 1
+	}
+
+	padding := int((b[2] >> 1) & 0x01)
+	frame.length = frame.samples()/8*frame.bitrate*1000/frame.sampleRate + padding
+	return frame, true
+}
+
+// probeMP3 lit les tags ID3 puis la durée (en-tête Xing/Info/VBRI ou débit constant)
+func probeMP3(r io.ReadSeeker, size int64, info *Info) error {
+	var audioStart int64
+	header := make([]byte, 10)
+	if _, err := readAt(r, header, 0); err == nil && string(header[:3]) == "ID3" {
+		tagSize := syncsafe(header[6:10])
+		audioStart = 10 + tagSize
+		if header[5]&0x10 != 0 { // pied de tag
+			audioStart += 10
+		}
+		body, err := readBlock(r, 10, tagSize)
+		if err != nil {
+			return err
+		}
+		parseID3v2(body, header[3], info)
+
+		// FLAC précédé d'un tag ID3 (rare mais valide)
+		magic := make([]byte, 4)
+		if _, err := readAt(r, magic, audioStart); err == nil && string(magic) == "fLaC" {
+			return probeFLAC(r, audioStart, info)
+		}
+	}
+	info.Format = FormatMP3
+
+	// ID3v1 en fin de fichier, si l'ID3v2 n'a pas tout renseigné
+	audioEnd := size
+	if size >= 128 {
+		tag := make([]byte, 128)
+		if _, err := readAt(r, tag, size-128); err == nil && string(tag[:3]) == "TAG" {
+			audioEnd -= 128
+			setTag(&info.Title, latin1(tag[3:33]))
+			setTag(&info.Artist, latin1(tag[33:63]))
+			setTag(&info.Album, latin1(tag[63:93]))
+		}
+	}
+
+	frameStart, frame, err := findMPEGFrame(r, audioStart, audioEnd)
+	if err != nil {
+		return err
+	}
+	info.SampleRate = frame.sampleRate
+	info.Channels = frame.channels
+
+	// Nombre de trames annoncé par un encodeur VBR (Xing, Info ou VBRI)
+	first, err := readBlock(r, frameStart, int64(frame.length))
+	if err != nil {
+		return err
+	}
+	if frames := vbrFrameCount(first, frame); frames > 0 {
+		info.Duration = samplesDuration(frames*int64(frame.samples()), frame.sampleRate)
+		return nil
+	}
+
+	// Débit constant : la durée se déduit de la taille du flux
+	info.Duration = time.Duration((audioEnd - frameStart) * 8 * int64(time.Millisecond) / int64(frame.bitrate))
+	return nil
+}
+
+// findMPEGFrame cherche la première trame valide, confirmée par la trame suivante
+func findMPEGFrame(r io.ReadSeeker, start, end int64) (int64, mpegFrame, error) {
+	limit := end - start
+	if limit > maxSyncSearch {
+		limit = maxSyncSearch
+	}
+	if limit < 4 {
+		return 0, mpegFrame{}, ErrUnsupported
+	}
+	buf := make([]byte, limit)
+	n, err := readAt(r, buf, start)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return 0, mpegFrame{}, err
+	}
+	buf = buf[:n]
+
+	next := make([]byte, 4)
+	for i := 0; i+4 <= len(buf); i++ {
+		frame, ok := parseMPEGHeader(buf[i : i+4])
+		if !ok {
+			continue
+		}
+		offset := start + int64(i)
+		following := offset + int64(frame.length)
+		if following+4 > end {
+			return offset, frame, nil // trame unique
+		}
+		if _, err := readAt(r, next, following); err == nil {
+			if _, ok := parseMPEGHeader(next); ok {
+				return offset, frame, nil
+			}
+		}
+	}
+	return 0, mpegFrame{}, ErrUnsupported
+}
+
+// vbrFrameCount nombre de trames annoncé par l'en-tête Xing/Info ou VBRI de la première trame (0 si absent)
+func vbrFrameCount(data []byte, frame mpegFrame) int64 {
+	xing := 4 + frame.sideInfoSize()
+	if len(data) >= xing+12 {
+		tag := string(data[xing : xing+4])
+		flags := binary.BigEndian.Uint32(data[xing+4:])
+		if (tag == "Xing" || tag == "Info") && flags&0x01 != 0 {
+			return int64(binary.BigEndian.Uint32(data[xing+8:]))
+		}
+	}
+	if len(data) >= 36+18 && string(data[36:40]) == "VBRI" {
+		return int64(binary.BigEndian.Uint32(data[36+14:]))
+	}
+	return 0
+}
+
+// syncsafe décode un entier ID3 sur 4 octets de 7 bits
+func syncsafe(b []byte) int64 {
+	return int64(b[0]&0x7F)<<21 | int64(b[1]&0x7F)<<14 | int64(b[2]&0x7F)<<7 | int64(b[3]&0x7F)
+}
+
+// parseID3v2 lit les cadres de titre, d'artiste et d'album d'un tag ID3v2.2, 2.3 ou 2.4
+func parseID3v2(body []byte, version byte, info *Info) {
+	idSize, headerSize := 4, 10
+	if version == 2 {
+		idSize, headerSize = 3, 6
+	}
+
+	for pos := 0; pos+headerSize <= len(body); {
+		id := string(body[pos : pos+idSize])
+		if id[0] == 0 {
+			return // remplissage
+		}
+
+		var frameSize int64
+		switch version {
+		case 2:
+			frameSize = int64(body[pos+3])<<16 | int64(body[pos+4])<<8 | int64(body[pos+5])
+		case 4:
+			frameSize = syncsafe(body[pos+4 : pos+8])
+		default:
+			frameSize = int64(binary.BigEndian.Uint32(body[pos+4:]))
+		}
+		pos += headerSize
+		if frameSize <= 0 || int64(pos)+frameSize > int64(len(body)) {
+			return
+		}
+		frame := body[pos : pos+int(frameSize)]
+		pos += int(frameSize)
+
+		switch id {
+		case "TIT2", "TT2":
+			setTag(&info.Title, id3Text(frame))
+		case "TPE1", "TP1":
+			setTag(&info.Artist, id3Text(frame))
+		case "TALB", "TAL":
+			setTag(&info.Album, id3Text(frame))
+		}
+	}
+}
+
+// id3Text décode un cadre texte ID3 (octet d'encodage puis texte)
+func id3Text(frame []byte) string {
+	if len(frame) < 2 {
+		return ""
+	}
+	text := frame[1:]
+	switch frame[0] {
+	case 0: // ISO-8859-1
+		return latin1(text)
+	case 1, 2: // UTF-16 avec BOM, UTF-16BE
+		order := binary.ByteOrder(binary.BigEndian)
+		if frame[0] == 1 && len(text) >= 2 {
+			if text[0] == 0xFF && text[1] == 0xFE {
+				order = binary.LittleEndian
+			}
+			if (text[0] == 0xFF && text[1] == 0xFE) || (text[0] == 0xFE && text[1] == 0xFF) {
+				text = text[2:]
+			}
+		}
+		units := make([]uint16, 0, len(text)/2)
+		for i := 0; i+1 < len(text); i += 2 {
+			unit := order.Uint16(text[i:])
+			if unit == 0 {
+				break
+			}
+			units = append(units, unit)
+		}
+		return string(utf16.Decode(units))
+	default: // UTF-8
+		if end := bytes.IndexByte(text, 0); end >= 0 {
+			text = text[:end]
+		}
+		return string(text)
+	}
+}
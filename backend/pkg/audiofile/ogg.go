@@ -0,0 +1,139 @@
+package audiofile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// oggTailSize octets relus en fin de fichier pour trouver la dernière page (une page fait au plus 65 307 octets)
+const oggTailSize = 1 << 17
+
+// probeOGG lit les en-têtes Vorbis ou Opus du premier flux puis la position de la dernière page
+func probeOGG(r io.ReadSeeker, size int64, info *Info) error {
+	info.Format = FormatOGG
+	packets, serial, err := oggHeaderPackets(r, 2)
+	if err != nil {
+		return err
+	}
+
+	var preSkip int64
+	id := packets[0]
+	switch {
+	case len(id) >= 16 && string(id[:7]) == "\x01vorbis":
+		info.Channels = int(id[11])
+		info.SampleRate = int(binary.LittleEndian.Uint32(id[12:]))
+	case len(id) >= 19 && string(id[:8]) == "OpusHead":
+		// Les positions Opus sont toujours exprimées à 48 kHz
+		info.Channels = int(id[9])
+		info.SampleRate = 48000
+		preSkip = int64(binary.LittleEndian.Uint16(id[10:]))
+	default:
+		return fmt.Errorf("codec Ogg non pris en charge: %w", ErrUnsupported)
+	}
+
+	if len(packets) > 1 {
+		comments := packets[1]
+		switch {
+		case bytes.HasPrefix(comments, []byte("\x03vorbis")):
+			parseVorbisComments(comments[7:], info)
+		case bytes.HasPrefix(comments, []byte("OpusTags")):
+			parseVorbisComments(comments[8:], info)
+		}
+	}
+
+	granule, err := lastOggGranule(r, size, serial)
+	if err != nil {
+		return err
+	}
+	info.Duration = samplesDuration(granule-preSkip, info.SampleRate)
+	return nil
+}
+
+// oggHeaderPackets reconstitue les count premiers paquets du premier flux logique
+func oggHeaderPackets(r io.ReadSeeker, count int) ([][]byte, uint32, error) {
+	var packets [][]byte
+	var current []byte
+	var serial uint32
+	header := make([]byte, 27)
+
+	for offset := int64(0); len(packets) < count; {
+		if _, err := readAt(r, header, offset); err != nil {
+			if len(packets) > 0 {
+				return packets, serial, nil
+			}
+			return nil, 0, err
+		}
+		if string(header[:4]) != "OggS" {
+			return nil, 0, fmt.Errorf("page Ogg invalide: %w", ErrUnsupported)
+		}
+
+		pageSerial := binary.LittleEndian.Uint32(header[14:])
+		if offset == 0 {
+			serial = pageSerial
+		}
+		lacing := make([]byte, header[26])
+		if _, err := readAt(r, lacing, offset+27); err != nil {
+			return nil, 0, err
+		}
+		var bodySize int64
+		for _, l := range lacing {
+			bodySize += int64(l)
+		}
+		bodyStart := offset + 27 + int64(len(lacing))
+		offset = bodyStart + bodySize
+
+		if pageSerial != serial {
+			continue
+		}
+		body, err := readBlock(r, bodyStart, bodySize)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		pos := 0
+		for _, l := range lacing {
+			end := pos + int(l)
+			if end > len(body) {
+				return nil, 0, io.ErrUnexpectedEOF
+			}
+			// Les paquets démesurés (pochette intégrée) sont tronqués
+			if len(current) < maxTagSize {
+				current = append(current, body[pos:end]...)
+			}
+			pos = end
+			if l < 255 {
+				packets = append(packets, current)
+				current = nil
+				if len(packets) == count {
+					break
+				}
+			}
+		}
+	}
+
+	return packets, serial, nil
+}
+
+// lastOggGranule position (en échantillons) de la dernière page du flux serial
+func lastOggGranule(r io.ReadSeeker, size int64, serial uint32) (int64, error) {
+	start := size - oggTailSize
+	if start < 0 {
+		start = 0
+	}
+	tail, err := readBlock(r, start, size-start)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if i+27 > len(tail) || binary.LittleEndian.Uint32(tail[i+14:]) != serial {
+			continue
+		}
+		if granule := int64(binary.LittleEndian.Uint64(tail[i+6:])); granule > 0 {
+			return granule, nil
+		}
+	}
+	return 0, fmt.Errorf("fin du flux Ogg introuvable: %w", ErrUnsupported)
+}
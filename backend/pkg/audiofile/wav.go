@@ -0,0 +1,142 @@
+package audiofile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+)
+
+// Formats d'échantillons WAV acceptés (les WAV compressés sont refusés)
+const (
+	wavPCM        = 0x0001
+	wavFloat      = 0x0003
+	wavExtensible = 0xFFFE
+)
+
+// wavLayout organisation d'un fichier WAV : format des échantillons et position des données
+type wavLayout struct {
+	format        uint16
+	channels      int
+	sampleRate    int
+	byteRate      int64
+	blockAlign    int
+	bitsPerSample int
+	dataStart     int64
+	dataSize      int64
+}
+
+// probeWAV lit le format, la durée et les tags LIST/INFO
+func probeWAV(r io.ReadSeeker, size int64, info *Info) error {
+	info.Format = FormatWAV
+	layout, err := readWAVLayout(r, size, info)
+	if err != nil {
+		return err
+	}
+
+	info.Channels = layout.channels
+	info.SampleRate = layout.sampleRate
+	info.Duration = time.Duration(float64(layout.dataSize) / float64(layout.byteRate) * float64(time.Second))
+	return nil
+}
+
+// readWAVLayout parcourt les blocs RIFF : format, données et, si info n'est pas nil, tags LIST/INFO
+func readWAVLayout(r io.ReadSeeker, size int64, info *Info) (*wavLayout, error) {
+	layout := &wavLayout{dataSize: -1}
+	header := make([]byte, 8)
+
+	for offset := int64(12); offset+8 <= size; {
+		if _, err := readAt(r, header, offset); err != nil {
+			return nil, err
+		}
+		chunkSize := int64(binary.LittleEndian.Uint32(header[4:]))
+		body := offset + 8
+
+		switch string(header[:4]) {
+		case "fmt ":
+			block, err := readBlock(r, body, chunkSize)
+			if err != nil {
+				return nil, err
+			}
+			if len(block) < 16 {
+				return nil, fmt.Errorf("bloc fmt WAV invalide: %w", ErrUnsupported)
+			}
+			layout.format = binary.LittleEndian.Uint16(block)
+			switch layout.format {
+			case wavPCM, wavFloat, wavExtensible:
+			default:
+				return nil, fmt.Errorf("WAV compressé non pris en charge: %w", ErrUnsupported)
+			}
+			layout.channels = int(binary.LittleEndian.Uint16(block[2:]))
+			layout.sampleRate = int(binary.LittleEndian.Uint32(block[4:]))
+			layout.byteRate = int64(binary.LittleEndian.Uint32(block[8:]))
+			layout.blockAlign = int(binary.LittleEndian.Uint16(block[12:]))
+			layout.bitsPerSample = int(binary.LittleEndian.Uint16(block[14:]))
+			// WAVE_FORMAT_EXTENSIBLE : le vrai format est au début du sous-format
+			if layout.format == wavExtensible && len(block) >= 26 {
+				layout.format = binary.LittleEndian.Uint16(block[24:])
+			}
+		case "data":
+			// Taille absente ou fausse (enregistrement interrompu) : les données vont jusqu'à la fin
+			layout.dataStart = body
+			layout.dataSize = chunkSize
+			if body+chunkSize > size {
+				layout.dataSize = size - body
+			}
+		case "LIST":
+			if info == nil {
+				break
+			}
+			block, err := readBlock(r, body, chunkSize)
+			if err != nil {
+				return nil, err
+			}
+			if len(block) >= 4 && string(block[:4]) == "INFO" {
+				parseRIFFInfo(block[4:], info)
+			}
+		}
+
+		offset = body + chunkSize + chunkSize%2
+	}
+
+	if layout.byteRate == 0 || layout.dataSize < 0 {
+		return nil, fmt.Errorf("blocs fmt ou data WAV manquants: %w", ErrUnsupported)
+	}
+	return layout, nil
+}
+
+// parseRIFFInfo lit les tags INAM (titre), IART (artiste) et IPRD (album) d'une liste INFO
+func parseRIFFInfo(data []byte, info *Info) {
+	for pos := 0; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		length := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		pos += 8
+		if length < 0 || pos+length > len(data) {
+			return
+		}
+		value := riffText(data[pos : pos+length])
+		pos += length + length%2
+
+		switch id {
+		case "INAM":
+			setTag(&info.Title, value)
+		case "IART":
+			setTag(&info.Artist, value)
+		case "IPRD":
+			setTag(&info.Album, value)
+		}
+	}
+}
+
+// riffText décode une valeur INFO : UTF-8 si elle est valide, ISO-8859-1 sinon
+func riffText(b []byte) string {
+	if end := bytes.IndexByte(b, 0); end >= 0 {
+		b = b[:end]
+	}
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	return latin1(b)
+}
@@ -0,0 +1,313 @@
+package audiofile
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Analysis forme d'onde et durée exacte d'un fichier audio, calculées sur tout le flux
+type Analysis struct {
+	Duration  time.Duration
+	Peaks     []float64 // crêtes entre 0 et 1, une par tranche de durée égale
+	Estimated bool      // enveloppe estimée sans décodage des échantillons (MP3)
+}
+
+// Analyze parcourt tout le flux audio et calcule points crêtes.
+//
+// Les WAV sont décodés échantillon par échantillon. Les MP3 ne sont pas décodés : chaque trame est lue
+// et le niveau de chaque granule est estimé d'après son gain global (pas de quantification choisi par
+// l'encodeur), ce qui donne une enveloppe fidèle à l'allure du morceau et une durée exacte.
+// Les autres formats retournent ErrUnsupported.
+func Analyze(r io.ReadSeeker, points int) (*Analysis, error) {
+	if points <= 0 {
+		return nil, fmt.Errorf("nombre de crêtes invalide: %d", points)
+	}
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 12)
+	if _, err := readAt(r, header, 0); err != nil {
+		return nil, ErrUnsupported
+	}
+
+	var analysis *Analysis
+	switch Sniff(header) {
+	case FormatWAV:
+		analysis, err = analyzeWAV(r, size, points)
+	case FormatMP3:
+		analysis, err = analyzeMP3(r, size, points)
+	default:
+		return nil, fmt.Errorf("forme d'onde indisponible pour ce format: %w", ErrUnsupported)
+	}
+	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("fichier audio tronqué: %w", ErrUnsupported)
+		}
+		return nil, err
+	}
+	return analysis, nil
+}
+
+// peakBuckets répartit total unités (échantillons, granules) en tranches et garde la crête de chacune
+type peakBuckets struct {
+	peaks []float64
+	total int64
+}
+
+// newPeakBuckets prépare points tranches (moins si le flux est plus court)
+func newPeakBuckets(points int, total int64) *peakBuckets {
+	if total < int64(points) {
+		points = int(total)
+	}
+	return &peakBuckets{peaks: make([]float64, points), total: total}
+}
+
+// add retient value pour l'unité index si elle dépasse la crête de sa tranche
+func (b *peakBuckets) add(index int64, value float64) {
+	i := index * int64(len(b.peaks)) / b.total
+	if value > b.peaks[i] {
+		b.peaks[i] = value
+	}
+}
+
+// analyzeWAV décode les échantillons PCM ou flottants et garde la crête de chaque tranche
+func analyzeWAV(r io.ReadSeeker, size int64, points int) (*Analysis, error) {
+	layout, err := readWAVLayout(r, size, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sampleSize := (layout.bitsPerSample + 7) / 8
+	decode := wavSampleDecoder(layout.format, sampleSize)
+	if decode == nil || layout.channels <= 0 || layout.sampleRate <= 0 || layout.blockAlign < layout.channels*sampleSize {
+		return nil, fmt.Errorf("échantillons WAV non pris en charge (%d bits): %w", layout.bitsPerSample, ErrUnsupported)
+	}
+
+	frames := layout.dataSize / int64(layout.blockAlign)
+	if frames == 0 {
+		return nil, fmt.Errorf("WAV sans échantillons: %w", ErrUnsupported)
+	}
+	buckets := newPeakBuckets(points, frames)
+
+	if _, err := r.Seek(layout.dataStart, io.SeekStart); err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReaderSize(r, 64<<10)
+	block := make([]byte, layout.blockAlign)
+	for frame := int64(0); frame < frames; frame++ {
+		if _, err := io.ReadFull(reader, block); err != nil {
+			return nil, err
+		}
+		peak := 0.0
+		for ch := 0; ch < layout.channels; ch++ {
+			if value := decode(block[ch*sampleSize:]); value > peak {
+				peak = value
+			}
+		}
+		buckets.add(frame, peak)
+	}
+
+	return &Analysis{
+		Duration: samplesDuration(frames, layout.sampleRate),
+		Peaks:    buckets.peaks,
+	}, nil
+}
+
+// wavSampleDecoder retourne la fonction donnant l'amplitude (0 à 1) d'un échantillon, nil si non pris en charge
+func wavSampleDecoder(format uint16, sampleSize int) func([]byte) float64 {
+	switch {
+	case format == wavPCM && sampleSize == 1: // 8 bits non signé
+		return func(b []byte) float64 { return math.Abs(float64(int(b[0])-128)) / 128 }
+	case format == wavPCM && sampleSize == 2:
+		return func(b []byte) float64 { return math.Abs(float64(int16(binary.LittleEndian.Uint16(b)))) / 32768 }
+	case format == wavPCM && sampleSize == 3:
+		return func(b []byte) float64 {
+			value := int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+			return math.Abs(float64(value)) / 8388608
+		}
+	case format == wavPCM && sampleSize == 4:
+		return func(b []byte) float64 { return math.Abs(float64(int32(binary.LittleEndian.Uint32(b)))) / 2147483648 }
+	case format == wavFloat && sampleSize == 4:
+		return func(b []byte) float64 {
+			return math.Min(math.Abs(float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))), 1)
+		}
+	case format == wavFloat && sampleSize == 8:
+		return func(b []byte) float64 {
+			return math.Min(math.Abs(math.Float64frombits(binary.LittleEndian.Uint64(b))), 1)
+		}
+	}
+	return nil
+}
+
+// analyzeMP3 lit toutes les trames, estime le niveau de chaque granule et compte les échantillons
+func analyzeMP3(r io.ReadSeeker, size int64, points int) (*Analysis, error) {
+	audioStart, err := skipID3v2(r)
+	if err != nil {
+		return nil, err
+	}
+	audioEnd := size
+	tag := make([]byte, 3)
+	if size >= 128 {
+		if _, err := readAt(r, tag, size-128); err == nil && string(tag) == "TAG" {
+			audioEnd -= 128
+		}
+	}
+
+	frameStart, first, err := findMPEGFrame(r, audioStart, audioEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(frameStart, io.SeekStart); err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReaderSize(io.LimitReader(r, audioEnd-frameStart), 64<<10)
+
+	var levels []float64
+	var frames int64
+	data := make([]byte, 0, 1441) // plus grande trame Layer III possible
+	for index := 0; ; {
+		header, err := reader.Peek(4)
+		if err != nil {
+			break // fin du flux
+		}
+		frame, ok := parseMPEGHeader(header)
+		if !ok || frame.mpeg1 != first.mpeg1 || frame.sampleRate != first.sampleRate {
+			reader.Discard(1) // octets parasites : resynchronisation
+			continue
+		}
+		data = data[:frame.length]
+		if _, err := io.ReadFull(reader, data); err != nil {
+			break // dernière trame incomplète
+		}
+
+		// La trame d'en-tête VBR (Xing, Info, VBRI) ne contient pas d'audio
+		if index == 0 && isVBRHeader(data, frame) {
+			index++
+			continue
+		}
+		index++
+		frames++
+		levels = append(levels, granuleLevels(data, frame)...)
+	}
+
+	if frames == 0 || len(levels) == 0 {
+		return nil, fmt.Errorf("aucune trame MP3 lisible: %w", ErrUnsupported)
+	}
+
+	buckets := newPeakBuckets(points, int64(len(levels)))
+	loudest := 0.0
+	for i, level := range levels {
+		buckets.add(int64(i), level)
+		loudest = math.Max(loudest, level)
+	}
+	if loudest > 0 {
+		for i := range buckets.peaks {
+			buckets.peaks[i] /= loudest
+		}
+	}
+
+	return &Analysis{
+		Duration:  samplesDuration(frames*int64(first.samples()), first.sampleRate),
+		Peaks:     buckets.peaks,
+		Estimated: true,
+	}, nil
+}
+
+// skipID3v2 retourne la position du flux audio après un éventuel tag ID3v2
+func skipID3v2(r io.ReadSeeker) (int64, error) {
+	header := make([]byte, 10)
+	if _, err := readAt(r, header, 0); err != nil {
+		return 0, err
+	}
+	if string(header[:3]) != "ID3" {
+		return 0, nil
+	}
+	start := 10 + syncsafe(header[6:10])
+	if header[5]&0x10 != 0 { // pied de tag
+		start += 10
+	}
+	return start, nil
+}
+
+// isVBRHeader indique si la trame porte un en-tête Xing, Info ou VBRI
+func isVBRHeader(data []byte, frame mpegFrame) bool {
+	xing := 4 + frame.sideInfoSize()
+	if len(data) >= xing+4 {
+		if tag := string(data[xing : xing+4]); tag == "Xing" || tag == "Info" {
+			return true
+		}
+	}
+	return len(data) >= 40 && string(data[36:40]) == "VBRI"
+}
+
+// granuleLevels estime le niveau de chaque granule d'une trame d'après ses informations annexes :
+// l'amplitude restituée est proportionnelle à 2^((global_gain - 210) / 4) ; un granule sans
+// données spectrales (part2_3_length nul) est silencieux
+func granuleLevels(data []byte, frame mpegFrame) []float64 {
+	offset := 4
+	if data[1]&0x01 == 0 { // CRC après l'en-tête
+		offset += 2
+	}
+	if len(data) < offset+frame.sideInfoSize() {
+		return nil
+	}
+	bits := &bitReader{data: data[offset : offset+frame.sideInfoSize()]}
+
+	granules, skip := 1, 34 // MPEG2/2.5 : un granule, 63 bits par canal
+	if frame.mpeg1 {
+		granules, skip = 2, 30 // MPEG1 : deux granules, 59 bits par canal
+		bits.skip(9)           // main_data_begin
+		if frame.channels == 1 {
+			bits.skip(5 + 4) // bits privés, scfsi
+		} else {
+			bits.skip(3 + 8)
+		}
+	} else {
+		bits.skip(8) // main_data_begin
+		bits.skip(frame.channels)
+	}
+
+	levels := make([]float64, granules)
+	for gr := 0; gr < granules; gr++ {
+		for ch := 0; ch < frame.channels; ch++ {
+			part23 := bits.read(12)
+			bits.skip(9) // big_values
+			gain := bits.read(8)
+			bits.skip(skip)
+			if part23 == 0 {
+				continue
+			}
+			levels[gr] = math.Max(levels[gr], math.Exp2((float64(gain)-210)/4))
+		}
+	}
+	return levels
+}
+
+// bitReader lit des champs de bits, poids fort en premier
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+// read lit n bits (0 au-delà des données)
+func (b *bitReader) read(n int) int {
+	value := 0
+	for i := 0; i < n; i++ {
+		value <<= 1
+		if byteIndex := b.pos / 8; byteIndex < len(b.data) {
+			value |= int(b.data[byteIndex]>>(7-b.pos%8)) & 1
+		}
+		b.pos++
+	}
+	return value
+}
+
+// skip saute n bits
+func (b *bitReader) skip(n int) {
+	b.pos += n
+}
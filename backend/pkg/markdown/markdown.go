@@ -0,0 +1,232 @@
+// Package markdown rend le Markdown des threads et commentaires en HTML sûr.
+//
+// Le texte source est toujours échappé : le HTML brut n'est jamais recopié.
+// Seules les balises de la liste autorisée ci-dessous sont produites par le rendu :
+//
+//	p, br, strong, em, del, code, pre, blockquote, ul, ol, li,
+//	a (href http, https, mailto ou chemin relatif), span.spoiler
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxQuoteDepth profondeur maximale des citations imbriquées
+const maxQuoteDepth = 5
+
+// allowedSchemes schémas d'URL autorisés dans les liens
+var allowedSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+var (
+	linkRegex        = regexp.MustCompile(`^\[([^\[\]\n]+)\]\(([^()\s]+)\)`)
+	unorderedRegex   = regexp.MustCompile(`^ {0,3}[-*+] +(.*)$`)
+	orderedRegex     = regexp.MustCompile(`^ {0,3}\d{1,9}[.)] +(.*)$`)
+	quoteRegex       = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	fenceRegex       = regexp.MustCompile("^ {0,3}```")
+	strongRegex      = regexp.MustCompile(`\*\*([^*\n]+?)\*\*`)
+	strongUnderRegex = regexp.MustCompile(`(^|[^a-zA-Z0-9_])__([^_\n]+?)__($|[^a-zA-Z0-9_])`)
+	emRegex          = regexp.MustCompile(`\*([^*\n]+?)\*`)
+	emUnderRegex     = regexp.MustCompile(`(^|[^a-zA-Z0-9_])_([^_\n]+?)_($|[^a-zA-Z0-9_])`)
+	strikeRegex      = regexp.MustCompile(`~~([^~\n]+?)~~`)
+	spoilerRegex     = regexp.MustCompile(`\|\|([^|\n]+?)\|\|`)
+)
+
+// Render convertit un contenu Markdown en HTML assaini
+func Render(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	return renderBlocks(strings.Split(source, "\n"), 0)
+}
+
+// renderBlocks rend une suite de lignes en blocs (paragraphes, listes, citations, code)
+func renderBlocks(lines []string, depth int) string {
+	var blocks []string
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fenceRegex.MatchString(line):
+			var code []string
+			i++
+			for i < len(lines) && !fenceRegex.MatchString(lines[i]) {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // fermeture ``` (ou fin du contenu)
+			blocks = append(blocks, "<pre><code>"+html.EscapeString(strings.Join(code, "\n"))+"</code></pre>")
+
+		case quoteRegex.MatchString(line) && depth < maxQuoteDepth:
+			var quoted []string
+			for i < len(lines) && quoteRegex.MatchString(lines[i]) {
+				quoted = append(quoted, quoteRegex.FindStringSubmatch(lines[i])[1])
+				i++
+			}
+			blocks = append(blocks, "<blockquote>"+renderBlocks(quoted, depth+1)+"</blockquote>")
+
+		case unorderedRegex.MatchString(line):
+			var items []string
+			for i < len(lines) && unorderedRegex.MatchString(lines[i]) {
+				items = append(items, "<li>"+renderInline(unorderedRegex.FindStringSubmatch(lines[i])[1])+"</li>")
+				i++
+			}
+			blocks = append(blocks, "<ul>"+strings.Join(items, "")+"</ul>")
+
+		case orderedRegex.MatchString(line):
+			var items []string
+			for i < len(lines) && orderedRegex.MatchString(lines[i]) {
+				items = append(items, "<li>"+renderInline(orderedRegex.FindStringSubmatch(lines[i])[1])+"</li>")
+				i++
+			}
+			blocks = append(blocks, "<ol>"+strings.Join(items, "")+"</ol>")
+
+		default:
+			var paragraph []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(paragraph) == 0 || !startsBlock(lines[i], depth)) {
+				paragraph = append(paragraph, renderInline(strings.TrimSpace(lines[i])))
+				i++
+			}
+			blocks = append(blocks, "<p>"+strings.Join(paragraph, "<br>")+"</p>")
+		}
+	}
+
+	return strings.Join(blocks, "\n")
+}
+
+// startsBlock indique si une ligne ouvre un bloc autre qu'un paragraphe
+func startsBlock(line string, depth int) bool {
+	return fenceRegex.MatchString(line) ||
+		(quoteRegex.MatchString(line) && depth < maxQuoteDepth) ||
+		unorderedRegex.MatchString(line) ||
+		orderedRegex.MatchString(line)
+}
+
+// renderInline rend le Markdown d'une ligne : code, liens puis mise en forme
+func renderInline(text string) string {
+	var b strings.Builder
+
+	for len(text) > 0 {
+		i := strings.IndexAny(text, "`[")
+		if i < 0 {
+			b.WriteString(renderEmphasis(html.EscapeString(text)))
+			break
+		}
+
+		b.WriteString(renderEmphasis(html.EscapeString(text[:i])))
+		text = text[i:]
+
+		if text[0] == '`' {
+			if end := strings.IndexByte(text[1:], '`'); end > 0 {
+				b.WriteString("<code>" + html.EscapeString(text[1:end+1]) + "</code>")
+				text = text[end+2:]
+				continue
+			}
+		} else if match := linkRegex.FindStringSubmatch(text); match != nil && isSafeURL(match[2]) {
+			b.WriteString(`<a href="` + html.EscapeString(match[2]) + `" rel="nofollow noopener noreferrer" target="_blank">`)
+			b.WriteString(renderEmphasis(html.EscapeString(match[1])))
+			b.WriteString("</a>")
+			text = text[len(match[0]):]
+			continue
+		}
+
+		// Caractère littéral
+		b.WriteString(html.EscapeString(text[:1]))
+		text = text[1:]
+	}
+
+	return b.String()
+}
+
+// renderEmphasis applique gras, italique, barré et spoiler sur un texte déjà échappé
+func renderEmphasis(escaped string) string {
+	escaped = strongRegex.ReplaceAllString(escaped, "<strong>$1</strong>")
+	escaped = strongUnderRegex.ReplaceAllString(escaped, "$1<strong>$2</strong>$3")
+	escaped = emRegex.ReplaceAllString(escaped, "<em>$1</em>")
+	escaped = emUnderRegex.ReplaceAllString(escaped, "$1<em>$2</em>$3")
+	escaped = strikeRegex.ReplaceAllString(escaped, "<del>$1</del>")
+	escaped = spoilerRegex.ReplaceAllString(escaped, `<span class="spoiler">$1</span>`)
+	return escaped
+}
+
+// isSafeURL vérifie qu'un lien pointe vers un schéma autorisé ou un chemin du site
+func isSafeURL(raw string) bool {
+	if strings.HasPrefix(raw, "/") {
+		// Les navigateurs lisent « \ » comme « / » et ignorent tabulations et retours à la ligne :
+		// « /\evil.example » deviendrait le lien externe « //evil.example »
+		if strings.ContainsAny(raw, "\\\t\r\n") {
+			return false
+		}
+		return !strings.HasPrefix(raw, "//")
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+
+	return allowedSchemes[strings.ToLower(parsed.Scheme)]
+}
+
+// MapText applique fn au texte d'un HTML produit par Render, hors balises,
+// liens et blocs de code. fn reçoit et retourne du texte échappé.
+func MapText(rendered string, fn func(text string) string) string {
+	var b strings.Builder
+	skipDepth := 0
+
+	for len(rendered) > 0 {
+		start := strings.IndexByte(rendered, '<')
+		if start < 0 {
+			start = len(rendered)
+		}
+
+		if text := rendered[:start]; text != "" {
+			if skipDepth == 0 {
+				b.WriteString(fn(text))
+			} else {
+				b.WriteString(text)
+			}
+		}
+		if start == len(rendered) {
+			break
+		}
+
+		end := strings.IndexByte(rendered[start:], '>')
+		if end < 0 {
+			b.WriteString(rendered[start:])
+			break
+		}
+
+		tag := rendered[start : start+end+1]
+		switch tagName(tag) {
+		case "a", "code", "pre":
+			if strings.HasPrefix(tag, "</") {
+				skipDepth--
+			} else {
+				skipDepth++
+			}
+		}
+
+		b.WriteString(tag)
+		rendered = rendered[start+end+1:]
+	}
+
+	return b.String()
+}
+
+// tagName extrait le nom d'une balise ouvrante ou fermante
+func tagName(tag string) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(tag, "<"), "/")
+	if i := strings.IndexAny(name, " >/"); i >= 0 {
+		name = name[:i]
+	}
+	return strings.ToLower(name)
+}
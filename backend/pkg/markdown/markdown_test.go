@@ -0,0 +1,70 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"Texte simple", "Hello", "<p>Hello</p>"},
+		{"Gras et italique", "**fort** et *doux* et _aussi_", "<p><strong>fort</strong> et <em>doux</em> et <em>aussi</em></p>"},
+		{"Underscore dans un mot", "snake_case_name", "<p>snake_case_name</p>"},
+		{"Spoiler", "La fin : ||il meurt||", `<p>La fin : <span class="spoiler">il meurt</span></p>`},
+		{"Barré", "~~faux~~", "<p><del>faux</del></p>"},
+		{"Code en ligne", "`**pas gras**`", "<p><code>**pas gras**</code></p>"},
+		{"Retour à la ligne", "ligne 1\nligne 2", "<p>ligne 1<br>ligne 2</p>"},
+		{"Paragraphes", "un\n\ndeux", "<p>un</p>\n<p>deux</p>"},
+		{"Liste à puces", "- a\n- b", "<ul><li>a</li><li>b</li></ul>"},
+		{"Liste numérotée", "1. a\n2. b", "<ol><li>a</li><li>b</li></ol>"},
+		{"Citation", "> cité\n> encore", "<blockquote><p>cité<br>encore</p></blockquote>"},
+		{"Bloc de code", "```\n<b>x</b>\n```", "<pre><code>&lt;b&gt;x&lt;/b&gt;</code></pre>"},
+		{"Lien", "[site](https://example.com)", `<p><a href="https://example.com" rel="nofollow noopener noreferrer" target="_blank">site</a></p>`},
+		{"Lien relatif", "[thread](/thread/1)", `<p><a href="/thread/1" rel="nofollow noopener noreferrer" target="_blank">thread</a></p>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.source); got != tt.expected {
+				t.Errorf("Render(%q) = %q, attendu %q", tt.source, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRender_Sanitization(t *testing.T) {
+	sources := []string{
+		"<script>alert(1)</script>",
+		`<img src=x onerror="alert(1)">`,
+		"[clic](javascript:alert(1))",
+		"[clic](//evil.example)",
+		`[clic](/\evil.example)`,
+		`[clic](https://example.com" onclick="alert(1))`,
+		"**<b>gras</b>**",
+	}
+
+	for _, source := range sources {
+		rendered := Render(source)
+		for _, forbidden := range []string{"<script", "<img", "<b>", "href=\"javascript:", "href=\"//", `href="/\`, "onclick=\""} {
+			if strings.Contains(rendered, forbidden) {
+				t.Errorf("Render(%q) = %q contient %q", source, rendered, forbidden)
+			}
+		}
+	}
+}
+
+func TestMapText(t *testing.T) {
+	rendered := Render("salut @bob, `@code` et [@lien](https://example.com/@bob)")
+	got := MapText(rendered, func(text string) string {
+		return strings.ReplaceAll(text, "@bob", "[BOB]")
+	})
+
+	expected := `<p>salut [BOB], <code>@code</code> et <a href="https://example.com/@bob" rel="nofollow noopener noreferrer" target="_blank">@lien</a></p>`
+	if got != expected {
+		t.Errorf("MapText = %q, attendu %q", got, expected)
+	}
+}
@@ -89,8 +89,9 @@ func appliedMigrations(db *sql.DB) (map[string]bool, error) {
 }
 
 // applyFile executes every statement in a SQL file.
-// Lines starting with USE or CREATE DATABASE are skipped since Railway
-// already provides the target database via the connection string.
+// Comment lines are dropped, and lines starting with USE or CREATE DATABASE
+// are skipped since Railway already provides the target database via the
+// connection string.
 // Errors for duplicate columns/keys are tolerated so re-running is safe.
 func applyFile(db *sql.DB, path string) error {
 	raw, err := os.ReadFile(path)
@@ -98,11 +99,28 @@ func applyFile(db *sql.DB, path string) error {
 		return err
 	}
 
-	// Remove lines that would conflict with Railway's DB context
+	for _, stmt := range splitStatements(string(raw)) {
+		if _, err := db.Exec(stmt); err != nil {
+			if isIdempotentError(err) {
+				log.Printf("⚠️  Ignoré (déjà appliqué): %v", err)
+				continue
+			}
+			return fmt.Errorf("executing statement: %w\nSQL: %s", err, stmt)
+		}
+	}
+	return nil
+}
+
+// splitStatements returns the statements of a SQL file, without comment
+// lines or statements that conflict with Railway's DB context.
+func splitStatements(raw string) []string {
+	// Remove lines that would conflict with Railway's DB context, and comment
+	// lines, which may contain semicolons that would break the split below
 	var filteredLines []string
-	for _, line := range strings.Split(string(raw), "\n") {
+	for _, line := range strings.Split(raw, "\n") {
 		trimmed := strings.TrimSpace(strings.ToUpper(line))
-		if strings.HasPrefix(trimmed, "USE ") ||
+		if strings.HasPrefix(trimmed, "--") ||
+			strings.HasPrefix(trimmed, "USE ") ||
 			strings.HasPrefix(trimmed, "CREATE DATABASE") ||
 			strings.HasPrefix(trimmed, "DROP DATABASE") {
 			continue
@@ -111,23 +129,28 @@ func applyFile(db *sql.DB, path string) error {
 	}
 	content := strings.Join(filteredLines, "\n")
 
-	// Split on semicolons and run each statement individually
-	stmts := strings.Split(content, ";")
-	for _, stmt := range stmts {
+	// Split on semicolons and keep each statement that is not only a
+	// trailing comment (e.g. "VALUES (1, 2);  -- note" at the end of a file)
+	var stmts []string
+	for _, stmt := range strings.Split(content, ";") {
 		stmt = strings.TrimSpace(stmt)
-		if stmt == "" || stmt == "--" {
+		if isCommentOnly(stmt) {
 			continue
 		}
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
 
-		if _, err := db.Exec(stmt); err != nil {
-			if isIdempotentError(err) {
-				log.Printf("⚠️  Ignoré (déjà appliqué): %v", err)
-				continue
-			}
-			return fmt.Errorf("executing statement: %w\nSQL: %s", err, stmt)
+// isCommentOnly returns true when a statement is empty or made of comments.
+func isCommentOnly(stmt string) bool {
+	for _, line := range strings.Split(stmt, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
 		}
 	}
-	return nil
+	return true
 }
 
 // isIdempotentError returns true for MySQL errors that mean the schema
@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{
+			"point-virgule dans un commentaire",
+			"-- Un sondage ; un bulletin\nCREATE TABLE a (id INT);\n",
+			[]string{"CREATE TABLE a (id INT)"},
+		},
+		{
+			"commentaire en fin de fichier",
+			"INSERT INTO a VALUES\n(1),  -- premier\n(2);  -- second",
+			[]string{"INSERT INTO a VALUES\n(1),  -- premier\n(2)"},
+		},
+		{
+			"base de données ignorée",
+			"CREATE DATABASE x;\nUSE x;\nDROP TABLE a;",
+			[]string{"DROP TABLE a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitStatements(tt.raw)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Instructions attendues: %q, Obtenues: %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestMigrationFilesSplit vérifie qu'aucune migration ne produit un fragment
+// commençant au milieu d'un commentaire (point-virgule dans un commentaire)
+func TestMigrationFilesSplit(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "migrations", "*.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Migrations introuvables: %v", err)
+	}
+
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Lecture de %s: %v", file, err)
+		}
+		for _, line := range strings.Split(string(raw), "\n") {
+			if _, comment, ok := strings.Cut(line, "--"); ok && strings.Contains(comment, ";") {
+				t.Errorf("%s: point-virgule dans un commentaire: %q", filepath.Base(file), line)
+			}
+		}
+	}
+}
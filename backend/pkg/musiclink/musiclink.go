@@ -0,0 +1,404 @@
+// Package musiclink reconnaît les liens des plateformes de streaming musical.
+//
+// Parse identifie la plateforme d'une URL, le type d'entité (morceau, album,
+// artiste ou playlist) et son identifiant, puis reconstruit une URL canonique
+// débarrassée des paramètres de suivi (si, utm_*, feature, ...).
+//
+// Plateformes reconnues :
+//
+//	YouTube, YouTube Music, Spotify, Deezer, Apple Music, SoundCloud, Bandcamp, Tidal
+package musiclink
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Plateformes reconnues
+const (
+	PlatformYouTube      = "youtube"
+	PlatformYouTubeMusic = "youtube_music"
+	PlatformSpotify      = "spotify"
+	PlatformDeezer       = "deezer"
+	PlatformAppleMusic   = "apple_music"
+	PlatformSoundCloud   = "soundcloud"
+	PlatformBandcamp     = "bandcamp"
+	PlatformTidal        = "tidal"
+)
+
+// Platforms liste des plateformes reconnues
+var Platforms = []string{
+	PlatformYouTube, PlatformYouTubeMusic, PlatformSpotify, PlatformDeezer,
+	PlatformAppleMusic, PlatformSoundCloud, PlatformBandcamp, PlatformTidal,
+}
+
+// Types d'entités musicales
+const (
+	TypeTrack    = "track"
+	TypeAlbum    = "album"
+	TypeArtist   = "artist"
+	TypePlaylist = "playlist"
+)
+
+// maxLinksPerText nombre maximum de liens extraits d'un même texte
+const maxLinksPerText = 20
+
+var (
+	// ErrUnsupported l'URL n'est pas un lien musical reconnu
+	ErrUnsupported = errors.New("lien musical non reconnu")
+
+	// linkRegex repère les URLs d'un texte
+	linkRegex = regexp.MustCompile(`https?://[^\s<>()\[\]"']+`)
+
+	// Identifiants des plateformes
+	youtubeIDRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	spotifyIDRegex = regexp.MustCompile(`^[A-Za-z0-9]{22}$`)
+	numericIDRegex = regexp.MustCompile(`^[0-9]+$`)
+	appleIDRegex   = regexp.MustCompile(`^(pl\.)?[A-Za-z0-9-]+$`)
+	tidalIDRegex   = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	slugRegex      = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	countryRegex   = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+// soundCloudReserved premiers segments de chemin SoundCloud qui ne sont pas des profils
+var soundCloudReserved = map[string]bool{
+	"discover": true, "search": true, "stream": true, "upload": true, "you": true,
+	"charts": true, "pages": true, "settings": true, "messages": true, "notifications": true,
+}
+
+// Link lien musical reconnu
+type Link struct {
+	Platform string `json:"platform"`
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	URL      string `json:"url"` // URL canonique, sans paramètres de suivi
+}
+
+// Key identifie l'entité d'un lien, quelle que soit la forme de l'URL d'origine
+func (l *Link) Key() string {
+	return l.Platform + ":" + l.Type + ":" + l.ID
+}
+
+// IsPlatform indique si une plateforme est reconnue
+func IsPlatform(platform string) bool {
+	for _, p := range Platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse reconnaît un lien musical ; les URIs Spotify (spotify:track:ID) sont acceptées
+func Parse(raw string) (*Link, error) {
+	raw = strings.TrimRight(strings.TrimSpace(raw), ".,;:!?")
+
+	if strings.HasPrefix(raw, "spotify:") {
+		parts := strings.Split(raw, ":")
+		if len(parts) != 3 {
+			return nil, ErrUnsupported
+		}
+		return spotifyLink(parts[1], parts[2])
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, ErrUnsupported
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "www."), "m.")
+	segments := pathSegments(parsed.Path)
+
+	switch {
+	case host == "youtube.com" || host == "youtu.be" || host == "youtube-nocookie.com":
+		return parseYouTube(host, segments, parsed.Query())
+	case host == "music.youtube.com":
+		return parseYouTubeMusic(segments, parsed.Query())
+	case host == "open.spotify.com" || host == "play.spotify.com":
+		return parseSpotify(segments)
+	case host == "deezer.com":
+		return parseDeezer(segments)
+	case host == "music.apple.com" || host == "itunes.apple.com":
+		return parseAppleMusic(segments, parsed.Query())
+	case host == "soundcloud.com":
+		return parseSoundCloud(segments)
+	case strings.HasSuffix(host, ".bandcamp.com"):
+		return parseBandcamp(strings.TrimSuffix(host, ".bandcamp.com"), segments)
+	case host == "tidal.com" || host == "listen.tidal.com":
+		return parseTidal(segments)
+	}
+
+	return nil, ErrUnsupported
+}
+
+// Extract retourne les liens musicaux reconnus d'un texte, sans doublons, dans l'ordre d'apparition
+func Extract(text string) []*Link {
+	var links []*Link
+	seen := make(map[string]bool)
+
+	for _, raw := range linkRegex.FindAllString(text, -1) {
+		link, err := Parse(raw)
+		if err != nil || seen[link.Key()] {
+			continue
+		}
+
+		seen[link.Key()] = true
+		links = append(links, link)
+		if len(links) == maxLinksPerText {
+			break
+		}
+	}
+
+	return links
+}
+
+// parseYouTube watch?v=, youtu.be/ID, shorts/, embed/, live/, playlist?list=, channel/ et @pseudo
+func parseYouTube(host string, segments []string, query url.Values) (*Link, error) {
+	if host == "youtu.be" {
+		if len(segments) == 1 && youtubeIDRegex.MatchString(segments[0]) {
+			return youtubeVideo(PlatformYouTube, segments[0]), nil
+		}
+		return nil, ErrUnsupported
+	}
+
+	if len(segments) == 0 {
+		return nil, ErrUnsupported
+	}
+
+	switch segments[0] {
+	case "watch":
+		if id := query.Get("v"); youtubeIDRegex.MatchString(id) {
+			return youtubeVideo(PlatformYouTube, id), nil
+		}
+	case "shorts", "embed", "live", "v":
+		if len(segments) == 2 && youtubeIDRegex.MatchString(segments[1]) {
+			return youtubeVideo(PlatformYouTube, segments[1]), nil
+		}
+	case "playlist":
+		if list := query.Get("list"); slugRegex.MatchString(list) {
+			return &Link{PlatformYouTube, TypePlaylist, list, "https://www.youtube.com/playlist?list=" + list}, nil
+		}
+	case "channel":
+		if len(segments) >= 2 && slugRegex.MatchString(segments[1]) {
+			return &Link{PlatformYouTube, TypeArtist, segments[1], "https://www.youtube.com/channel/" + segments[1]}, nil
+		}
+	default:
+		if handle := strings.TrimPrefix(segments[0], "@"); handle != segments[0] && slugOrDot(handle) {
+			return &Link{PlatformYouTube, TypeArtist, "@" + handle, "https://www.youtube.com/@" + handle}, nil
+		}
+	}
+
+	return nil, ErrUnsupported
+}
+
+// parseYouTubeMusic watch?v=, playlist?list= (OLAK5uy_ = album), browse/MPREb_ (album) et channel/
+func parseYouTubeMusic(segments []string, query url.Values) (*Link, error) {
+	if len(segments) == 0 {
+		return nil, ErrUnsupported
+	}
+
+	switch segments[0] {
+	case "watch":
+		if id := query.Get("v"); youtubeIDRegex.MatchString(id) {
+			return youtubeVideo(PlatformYouTubeMusic, id), nil
+		}
+	case "playlist":
+		if list := query.Get("list"); slugRegex.MatchString(list) {
+			entityType := TypePlaylist
+			if strings.HasPrefix(list, "OLAK5uy_") {
+				entityType = TypeAlbum
+			}
+			return &Link{PlatformYouTubeMusic, entityType, list, "https://music.youtube.com/playlist?list=" + list}, nil
+		}
+	case "browse":
+		if len(segments) == 2 && strings.HasPrefix(segments[1], "MPREb_") && slugRegex.MatchString(segments[1]) {
+			return &Link{PlatformYouTubeMusic, TypeAlbum, segments[1], "https://music.youtube.com/browse/" + segments[1]}, nil
+		}
+	case "channel":
+		if len(segments) == 2 && slugRegex.MatchString(segments[1]) {
+			return &Link{PlatformYouTubeMusic, TypeArtist, segments[1], "https://music.youtube.com/channel/" + segments[1]}, nil
+		}
+	}
+
+	return nil, ErrUnsupported
+}
+
+// youtubeVideo lien vers une vidéo, considérée comme un morceau
+func youtubeVideo(platform, id string) *Link {
+	canonical := "https://www.youtube.com/watch?v=" + id
+	if platform == PlatformYouTubeMusic {
+		canonical = "https://music.youtube.com/watch?v=" + id
+	}
+	return &Link{platform, TypeTrack, id, canonical}
+}
+
+// parseSpotify open.spotify.com/[intl-xx/][embed/]{type}/{id}
+func parseSpotify(segments []string) (*Link, error) {
+	if len(segments) > 0 && strings.HasPrefix(segments[0], "intl-") {
+		segments = segments[1:]
+	}
+	if len(segments) > 0 && segments[0] == "embed" {
+		segments = segments[1:]
+	}
+	if len(segments) < 2 {
+		return nil, ErrUnsupported
+	}
+	return spotifyLink(segments[0], segments[1])
+}
+
+// spotifyLink lien Spotify à partir du type et de l'identifiant
+func spotifyLink(entityType, id string) (*Link, error) {
+	switch entityType {
+	case TypeTrack, TypeAlbum, TypeArtist, TypePlaylist:
+	default:
+		return nil, ErrUnsupported
+	}
+	if !spotifyIDRegex.MatchString(id) {
+		return nil, ErrUnsupported
+	}
+	return &Link{PlatformSpotify, entityType, id, "https://open.spotify.com/" + entityType + "/" + id}, nil
+}
+
+// parseDeezer deezer.com/[pays/]{type}/{id}
+func parseDeezer(segments []string) (*Link, error) {
+	if len(segments) > 0 && countryRegex.MatchString(segments[0]) {
+		segments = segments[1:]
+	}
+	if len(segments) != 2 || !numericIDRegex.MatchString(segments[1]) {
+		return nil, ErrUnsupported
+	}
+
+	switch segments[0] {
+	case TypeTrack, TypeAlbum, TypeArtist, TypePlaylist:
+		return &Link{PlatformDeezer, segments[0], segments[1], "https://www.deezer.com/" + segments[0] + "/" + segments[1]}, nil
+	}
+
+	return nil, ErrUnsupported
+}
+
+// parseAppleMusic music.apple.com/{pays}/{type}/[nom/]{id} ; un album avec ?i= désigne un morceau
+func parseAppleMusic(segments []string, query url.Values) (*Link, error) {
+	country := "us"
+	if len(segments) > 0 && countryRegex.MatchString(segments[0]) {
+		country, segments = segments[0], segments[1:]
+	}
+	if len(segments) < 2 || len(segments) > 3 {
+		return nil, ErrUnsupported
+	}
+
+	id := segments[len(segments)-1]
+	if !appleIDRegex.MatchString(id) {
+		return nil, ErrUnsupported
+	}
+
+	var entityType string
+	switch segments[0] {
+	case "song":
+		entityType = TypeTrack
+	case "album":
+		entityType = TypeAlbum
+		if trackID := query.Get("i"); numericIDRegex.MatchString(trackID) {
+			entityType, id = TypeTrack, trackID
+		}
+	case "artist":
+		entityType = TypeArtist
+	case "playlist":
+		entityType = TypePlaylist
+	default:
+		return nil, ErrUnsupported
+	}
+
+	// Les identifiants numériques désignent un catalogue, les playlists commencent par pl.
+	if (entityType == TypePlaylist) != strings.HasPrefix(id, "pl.") || (entityType != TypePlaylist && !numericIDRegex.MatchString(id)) {
+		return nil, ErrUnsupported
+	}
+
+	path := map[string]string{TypeTrack: "song", TypeAlbum: "album", TypeArtist: "artist", TypePlaylist: "playlist"}[entityType]
+	return &Link{PlatformAppleMusic, entityType, id, "https://music.apple.com/" + country + "/" + path + "/" + id}, nil
+}
+
+// parseSoundCloud soundcloud.com/{profil}[/{morceau}|/sets/{playlist}]
+func parseSoundCloud(segments []string) (*Link, error) {
+	if len(segments) == 0 || soundCloudReserved[segments[0]] {
+		return nil, ErrUnsupported
+	}
+	for _, segment := range segments {
+		if !slugRegex.MatchString(segment) {
+			return nil, ErrUnsupported
+		}
+	}
+
+	var link *Link
+	switch {
+	case len(segments) == 1:
+		link = &Link{PlatformSoundCloud, TypeArtist, segments[0], ""}
+	case len(segments) == 2 && segments[1] != "sets" && segments[1] != "tracks" && segments[1] != "albums":
+		link = &Link{PlatformSoundCloud, TypeTrack, segments[0] + "/" + segments[1], ""}
+	case len(segments) == 3 && segments[1] == "sets":
+		link = &Link{PlatformSoundCloud, TypePlaylist, segments[0] + "/sets/" + segments[2], ""}
+	default:
+		return nil, ErrUnsupported
+	}
+
+	link.ID = strings.ToLower(link.ID)
+	link.URL = "https://soundcloud.com/" + link.ID
+	return link, nil
+}
+
+// parseBandcamp {artiste}.bandcamp.com[/track/{nom}|/album/{nom}]
+func parseBandcamp(artist string, segments []string) (*Link, error) {
+	if !slugRegex.MatchString(artist) {
+		return nil, ErrUnsupported
+	}
+	base := "https://" + artist + ".bandcamp.com"
+
+	switch {
+	case len(segments) == 0 || (len(segments) == 1 && segments[0] == "music"):
+		return &Link{PlatformBandcamp, TypeArtist, artist, base}, nil
+	case len(segments) == 2 && (segments[0] == TypeTrack || segments[0] == TypeAlbum) && slugRegex.MatchString(segments[1]):
+		return &Link{PlatformBandcamp, segments[0], artist + "/" + segments[1], base + "/" + segments[0] + "/" + segments[1]}, nil
+	}
+
+	return nil, ErrUnsupported
+}
+
+// parseTidal tidal.com/[browse/]{type}/{id}
+func parseTidal(segments []string) (*Link, error) {
+	if len(segments) > 0 && segments[0] == "browse" {
+		segments = segments[1:]
+	}
+	if len(segments) < 2 || !tidalIDRegex.MatchString(segments[1]) {
+		return nil, ErrUnsupported
+	}
+
+	switch segments[0] {
+	case TypeTrack, TypeAlbum, TypeArtist:
+		if !numericIDRegex.MatchString(segments[1]) {
+			return nil, ErrUnsupported
+		}
+	case TypePlaylist:
+	default:
+		return nil, ErrUnsupported
+	}
+
+	return &Link{PlatformTidal, segments[0], segments[1], "https://tidal.com/browse/" + segments[0] + "/" + segments[1]}, nil
+}
+
+// pathSegments segments non vides d'un chemin d'URL
+func pathSegments(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// slugOrDot identifiant composé de lettres, chiffres, tirets, soulignés ou points
+func slugOrDot(value string) bool {
+	return value != "" && slugRegex.MatchString(strings.ReplaceAll(value, ".", "_"))
+}
@@ -0,0 +1,82 @@
+package musiclink
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		platform string
+		kind     string
+		id       string
+		url      string
+	}{
+		{"YouTube watch", "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=42s&feature=share", PlatformYouTube, TypeTrack, "dQw4w9WgXcQ", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"YouTube court", "https://youtu.be/dQw4w9WgXcQ?si=tracking", PlatformYouTube, TypeTrack, "dQw4w9WgXcQ", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"YouTube shorts mobile", "https://m.youtube.com/shorts/dQw4w9WgXcQ", PlatformYouTube, TypeTrack, "dQw4w9WgXcQ", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"YouTube playlist", "https://www.youtube.com/playlist?list=PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG", PlatformYouTube, TypePlaylist, "PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG", "https://www.youtube.com/playlist?list=PLx0sYbCqOb8TBPRdmBHs5Iftvv9TPboYG"},
+		{"YouTube chaîne", "https://www.youtube.com/@daftpunk/videos", PlatformYouTube, TypeArtist, "@daftpunk", "https://www.youtube.com/@daftpunk"},
+		{"YouTube Music morceau", "https://music.youtube.com/watch?v=dQw4w9WgXcQ&list=RDAMVM", PlatformYouTubeMusic, TypeTrack, "dQw4w9WgXcQ", "https://music.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{"YouTube Music album", "https://music.youtube.com/playlist?list=OLAK5uy_abcdef", PlatformYouTubeMusic, TypeAlbum, "OLAK5uy_abcdef", "https://music.youtube.com/playlist?list=OLAK5uy_abcdef"},
+		{"Spotify morceau", "https://open.spotify.com/intl-fr/track/4uLU6hMCjMI75M1A2tKUQC?si=abc123&utm_source=copy", PlatformSpotify, TypeTrack, "4uLU6hMCjMI75M1A2tKUQC", "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC"},
+		{"Spotify URI", "spotify:album:2noRn2Aes5aoNVsU6iWThc", PlatformSpotify, TypeAlbum, "2noRn2Aes5aoNVsU6iWThc", "https://open.spotify.com/album/2noRn2Aes5aoNVsU6iWThc"},
+		{"Deezer album", "https://www.deezer.com/fr/album/302127?utm_campaign=share", PlatformDeezer, TypeAlbum, "302127", "https://www.deezer.com/album/302127"},
+		{"Apple Music album", "https://music.apple.com/fr/album/discovery/697194953", PlatformAppleMusic, TypeAlbum, "697194953", "https://music.apple.com/fr/album/697194953"},
+		{"Apple Music morceau d'album", "https://music.apple.com/fr/album/discovery/697194953?i=697195462&ls", PlatformAppleMusic, TypeTrack, "697195462", "https://music.apple.com/fr/song/697195462"},
+		{"Apple Music playlist", "https://music.apple.com/us/playlist/chill/pl.u-abc123", PlatformAppleMusic, TypePlaylist, "pl.u-abc123", "https://music.apple.com/us/playlist/pl.u-abc123"},
+		{"SoundCloud morceau", "https://soundcloud.com/Artiste/Son-Titre?in=playlist&utm_medium=text", PlatformSoundCloud, TypeTrack, "artiste/son-titre", "https://soundcloud.com/artiste/son-titre"},
+		{"SoundCloud set", "https://soundcloud.com/artiste/sets/mixtape", PlatformSoundCloud, TypePlaylist, "artiste/sets/mixtape", "https://soundcloud.com/artiste/sets/mixtape"},
+		{"Bandcamp album", "https://artiste.bandcamp.com/album/premier-ep?from=embed", PlatformBandcamp, TypeAlbum, "artiste/premier-ep", "https://artiste.bandcamp.com/album/premier-ep"},
+		{"Bandcamp artiste", "https://artiste.bandcamp.com/", PlatformBandcamp, TypeArtist, "artiste", "https://artiste.bandcamp.com"},
+		{"Tidal morceau", "https://listen.tidal.com/track/77646170/u", PlatformTidal, TypeTrack, "77646170", "https://tidal.com/browse/track/77646170"},
+		{"Ponctuation finale", "https://www.deezer.com/track/3135556.", PlatformDeezer, TypeTrack, "3135556", "https://www.deezer.com/track/3135556"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := Parse(tt.raw)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.raw, err)
+			}
+			if link.Platform != tt.platform || link.Type != tt.kind || link.ID != tt.id || link.URL != tt.url {
+				t.Errorf("Obtenu %+v, attendu %s %s %s %s", *link, tt.platform, tt.kind, tt.id, tt.url)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	rejected := []string{
+		"https://example.com/track/123",
+		"https://www.youtube.com/feed/trending",
+		"https://youtu.be/trop-court",
+		"https://open.spotify.com/episode/4uLU6hMCjMI75M1A2tKUQC",
+		"https://open.spotify.com/track/pas-un-id",
+		"https://www.deezer.com/track/abc",
+		"https://soundcloud.com/discover",
+		"https://music.apple.com/us/browse",
+		"ftp://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC",
+		"spotify:user:someone:playlist",
+	}
+
+	for _, raw := range rejected {
+		if link, err := Parse(raw); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Parse(%q) devrait échouer, obtenu %+v", raw, link)
+		}
+	}
+}
+
+func TestExtractDeduplicates(t *testing.T) {
+	links := Extract(`Écoutez https://youtu.be/dQw4w9WgXcQ et (https://www.youtube.com/watch?v=dQw4w9WgXcQ),
+puis https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC et https://example.com`)
+
+	if len(links) != 2 {
+		t.Fatalf("2 liens attendus, obtenu %d: %+v", len(links), links)
+	}
+	if links[0].Key() != "youtube:track:dQw4w9WgXcQ" || links[1].Platform != PlatformSpotify {
+		t.Errorf("Liens inattendus: %s, %s", links[0].Key(), links[1].Key())
+	}
+}
@@ -0,0 +1,50 @@
+package musicmeta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"rythmitbackend/pkg/musiclink"
+)
+
+// FixtureProvider fournisseur local : métadonnées indexées par clé de lien
+// (plateforme:type:id, voir musiclink.Link.Key)
+type FixtureProvider struct {
+	fixtures map[string]*Metadata
+}
+
+// NewFixtureProvider crée un fournisseur à partir de métadonnées en mémoire
+func NewFixtureProvider(fixtures map[string]*Metadata) *FixtureProvider {
+	return &FixtureProvider{fixtures: fixtures}
+}
+
+// LoadFixtureProvider charge les métadonnées d'un fichier JSON {"spotify:track:ID": {...}}
+func LoadFixtureProvider(path string) (*FixtureProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture des métadonnées locales: %w", err)
+	}
+
+	fixtures := make(map[string]*Metadata)
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("métadonnées locales invalides (%s): %w", path, err)
+	}
+
+	return NewFixtureProvider(fixtures), nil
+}
+
+// Name nom du fournisseur
+func (p *FixtureProvider) Name() string {
+	return ProviderFixture
+}
+
+// Fetch retourne les métadonnées du lien, ou ErrNotFound s'il n'est pas dans le fichier
+func (p *FixtureProvider) Fetch(ctx context.Context, link *musiclink.Link) (*Metadata, error) {
+	meta, ok := p.fixtures[link.Key()]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *meta
+	return &copied, nil
+}
@@ -0,0 +1,63 @@
+// Package musicmeta récupère les métadonnées des liens musicaux reconnus par
+// musiclink : titre, artiste, album, durée et pochette.
+//
+// Un Provider interroge une source pour un lien. Deux implémentations :
+//
+//	OEmbedProvider   endpoints oEmbed des plateformes (et API publique Deezer)
+//	FixtureProvider  fichier JSON local, pour le développement et les tests
+//
+// Les appels sont faits hors des requêtes HTTP, par un worker qui met les
+// résultats en cache (voir services.StartMusicMetadataFetcher).
+package musicmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"rythmitbackend/pkg/musiclink"
+	"strings"
+)
+
+// Fournisseurs disponibles (configuration MUSIC_METADATA_PROVIDER)
+const (
+	ProviderFixture = "fixture"
+	ProviderOEmbed  = "oembed"
+	ProviderNone    = "none"
+)
+
+// ErrNotFound la source ne connaît pas ce lien (ou ne gère pas sa plateforme)
+var ErrNotFound = errors.New("métadonnées introuvables")
+
+// Metadata métadonnées d'un lien musical
+type Metadata struct {
+	Title      string `json:"title"`
+	Artist     string `json:"artist,omitempty"`
+	Album      string `json:"album,omitempty"`
+	DurationMs int    `json:"duration_ms,omitempty"`
+	ArtworkURL string `json:"artwork_url,omitempty"`
+}
+
+// Provider source de métadonnées ; Fetch retourne ErrNotFound pour un lien inconnu
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context, link *musiclink.Link) (*Metadata, error)
+}
+
+// New crée le fournisseur configuré ; "none" (ou vide) désactive la récupération
+func New(kind, fixturesPath string, client *http.Client) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case ProviderFixture:
+		provider, err := LoadFixtureProvider(fixturesPath)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	case ProviderOEmbed:
+		return NewOEmbedProvider(client), nil
+	case ProviderNone, "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("fournisseur de métadonnées inconnu: %q", kind)
+	}
+}
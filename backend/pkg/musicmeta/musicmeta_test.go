@@ -0,0 +1,135 @@
+package musicmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"rythmitbackend/pkg/musiclink"
+	"strings"
+	"testing"
+)
+
+func mustParse(t *testing.T, raw string) *musiclink.Link {
+	t.Helper()
+	link, err := musiclink.Parse(raw)
+	if err != nil {
+		t.Fatalf("Lien %s non reconnu: %v", raw, err)
+	}
+	return link
+}
+
+func TestFixtureProvider(t *testing.T) {
+	provider := NewFixtureProvider(map[string]*Metadata{
+		"spotify:track:0DiWol3AO6WpXZgp0goxAV": {Title: "One More Time", Artist: "Daft Punk"},
+	})
+
+	meta, err := provider.Fetch(context.Background(), mustParse(t, "https://open.spotify.com/intl-fr/track/0DiWol3AO6WpXZgp0goxAV?si=abc"))
+	if err != nil {
+		t.Fatalf("Erreur inattendue: %v", err)
+	}
+	if meta.Title != "One More Time" || meta.Artist != "Daft Punk" {
+		t.Errorf("Métadonnées inattendues: %+v", meta)
+	}
+
+	meta.Title = "modifié"
+	again, _ := provider.Fetch(context.Background(), mustParse(t, "spotify:track:0DiWol3AO6WpXZgp0goxAV"))
+	if again.Title != "One More Time" {
+		t.Error("Le fournisseur doit retourner une copie des métadonnées")
+	}
+
+	if _, err := provider.Fetch(context.Background(), mustParse(t, "https://www.deezer.com/track/1")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ErrNotFound attendu, Obtenu: %v", err)
+	}
+}
+
+func TestLoadFixtureProvider(t *testing.T) {
+	// Le fichier utilisé en développement doit rester valide
+	if _, err := LoadFixtureProvider(filepath.Join("..", "..", "fixtures", "music_metadata.json")); err != nil {
+		t.Fatalf("Fichier de développement invalide: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(path, []byte("[1, 2]"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFixtureProvider(path); err == nil {
+		t.Error("Erreur attendue pour un fichier invalide")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if provider, err := New("none", "", nil); err != nil || provider != nil {
+		t.Errorf("Aucun fournisseur attendu, Obtenu: %v, %v", provider, err)
+	}
+	if provider, err := New("oembed", "", nil); err != nil || provider.Name() != ProviderOEmbed {
+		t.Errorf("Fournisseur oEmbed attendu, Obtenu: %v, %v", provider, err)
+	}
+	if _, err := New("lastfm", "", nil); err == nil {
+		t.Error("Erreur attendue pour un fournisseur inconnu")
+	}
+}
+
+func TestOEmbedProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/oembed" && strings.Contains(r.URL.Query().Get("url"), "FGBhQbmPwH8"):
+			w.Write([]byte(`{"title": "One More Time", "author_name": "Daft Punk - Topic", "thumbnail_url": "https://i.ytimg.com/vi/FGBhQbmPwH8/hqdefault.jpg"}`))
+		case r.URL.Path == "/deezer/track/3135556":
+			w.Write([]byte(`{"title": "Harder, Better, Faster, Stronger", "duration": 224, "artist": {"name": "Daft Punk"}, "album": {"title": "Discovery", "cover_medium": "https://cdn/cover.jpg"}}`))
+		case strings.HasPrefix(r.URL.Path, "/deezer/"):
+			w.Write([]byte(`{"error": {"message": "no data"}}`))
+		case r.URL.Path == "/broken":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewOEmbedProvider(server.Client())
+	provider.endpoints = map[string]string{
+		musiclink.PlatformYouTubeMusic: server.URL + "/oembed",
+		musiclink.PlatformSpotify:      server.URL + "/oembed",
+		musiclink.PlatformSoundCloud:   server.URL + "/broken",
+	}
+	provider.deezerAPI = server.URL + "/deezer"
+	ctx := context.Background()
+
+	meta, err := provider.Fetch(ctx, mustParse(t, "https://music.youtube.com/watch?v=FGBhQbmPwH8"))
+	if err != nil || meta.Title != "One More Time" || meta.Artist != "Daft Punk" || meta.ArtworkURL == "" {
+		t.Errorf("oEmbed YouTube Music inattendu: %+v, %v", meta, err)
+	}
+
+	meta, err = provider.Fetch(ctx, mustParse(t, "https://www.deezer.com/track/3135556"))
+	if err != nil || meta.Album != "Discovery" || meta.DurationMs != 224000 || meta.ArtworkURL != "https://cdn/cover.jpg" {
+		t.Errorf("Morceau Deezer inattendu: %+v, %v", meta, err)
+	}
+
+	cases := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{"oEmbed inconnu", "https://open.spotify.com/track/0DiWol3AO6WpXZgp0goxAV", ErrNotFound},
+		{"erreur Deezer", "https://www.deezer.com/track/1", ErrNotFound},
+		{"plateforme sans oEmbed", "https://music.apple.com/fr/album/discovery/697194953", ErrNotFound},
+		{"erreur serveur", "https://soundcloud.com/flume/never-be-like-you-feat-kai", nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := provider.Fetch(ctx, mustParse(t, c.url))
+			if err == nil {
+				t.Fatal("Erreur attendue")
+			}
+			if c.wantErr != nil && !errors.Is(err, c.wantErr) {
+				t.Errorf("Attendu: %v, Obtenu: %v", c.wantErr, err)
+			}
+			if c.wantErr == nil && errors.Is(err, ErrNotFound) {
+				t.Errorf("Une erreur serveur ne doit pas être traitée comme introuvable: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package musicmeta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"rythmitbackend/pkg/musiclink"
+	"strings"
+	"time"
+)
+
+// maxResponseSize taille maximale lue d'une réponse (1 Mo)
+const maxResponseSize = 1 << 20
+
+// defaultOEmbedEndpoints endpoints oEmbed des plateformes qui en proposent un
+// (Apple Music et Bandcamp n'en ont pas : leurs liens restent sans métadonnées)
+var defaultOEmbedEndpoints = map[string]string{
+	musiclink.PlatformYouTube:      "https://www.youtube.com/oembed",
+	musiclink.PlatformYouTubeMusic: "https://www.youtube.com/oembed",
+	musiclink.PlatformSpotify:      "https://open.spotify.com/oembed",
+	musiclink.PlatformSoundCloud:   "https://soundcloud.com/oembed",
+	musiclink.PlatformTidal:        "https://oembed.tidal.com/",
+}
+
+// OEmbedProvider fournisseur interrogeant les endpoints oEmbed des plateformes,
+// et l'API publique de Deezer (sans oEmbed, mais avec durée et album)
+type OEmbedProvider struct {
+	client    *http.Client
+	endpoints map[string]string
+	deezerAPI string
+}
+
+// oembedResponse champs utiles d'une réponse oEmbed
+type oembedResponse struct {
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// deezerResponse champs utiles d'un morceau, album ou artiste Deezer
+type deezerResponse struct {
+	Title         string `json:"title"`
+	Name          string `json:"name"` // artiste
+	Duration      int    `json:"duration"`
+	CoverMedium   string `json:"cover_medium"`
+	PictureMedium string `json:"picture_medium"`
+	Artist        *struct {
+		Name string `json:"name"`
+	} `json:"artist"`
+	Album *struct {
+		Title       string `json:"title"`
+		CoverMedium string `json:"cover_medium"`
+	} `json:"album"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewOEmbedProvider crée un fournisseur oEmbed (client par défaut : délai de 10 s)
+func NewOEmbedProvider(client *http.Client) *OEmbedProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OEmbedProvider{
+		client:    client,
+		endpoints: defaultOEmbedEndpoints,
+		deezerAPI: "https://api.deezer.com",
+	}
+}
+
+// Name nom du fournisseur
+func (p *OEmbedProvider) Name() string {
+	return ProviderOEmbed
+}
+
+// Fetch récupère les métadonnées d'un lien auprès de sa plateforme
+func (p *OEmbedProvider) Fetch(ctx context.Context, link *musiclink.Link) (*Metadata, error) {
+	if link.Platform == musiclink.PlatformDeezer {
+		return p.fetchDeezer(ctx, link)
+	}
+
+	endpoint, ok := p.endpoints[link.Platform]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	target := link.URL
+	if link.Platform == musiclink.PlatformYouTubeMusic && link.Type == musiclink.TypeTrack {
+		// L'endpoint YouTube ne reconnaît que les URLs youtube.com
+		target = "https://www.youtube.com/watch?v=" + link.ID
+	}
+
+	var resp oembedResponse
+	if err := p.getJSON(ctx, endpoint+"?format=json&url="+url.QueryEscape(target), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Title == "" {
+		return nil, ErrNotFound
+	}
+
+	return &Metadata{
+		Title:      resp.Title,
+		Artist:     strings.TrimSuffix(resp.AuthorName, " - Topic"), // chaînes générées par YouTube
+		ArtworkURL: resp.ThumbnailURL,
+	}, nil
+}
+
+// fetchDeezer récupère un morceau, un album, un artiste ou une playlist via l'API Deezer
+func (p *OEmbedProvider) fetchDeezer(ctx context.Context, link *musiclink.Link) (*Metadata, error) {
+	var resp deezerResponse
+	if err := p.getJSON(ctx, fmt.Sprintf("%s/%s/%s", p.deezerAPI, link.Type, url.PathEscape(link.ID)), &resp); err != nil {
+		return nil, err
+	}
+	// L'API répond 200 avec un objet error pour un identifiant inconnu
+	if resp.Error != nil {
+		return nil, ErrNotFound
+	}
+
+	meta := &Metadata{
+		Title:      resp.Title,
+		DurationMs: resp.Duration * 1000,
+		ArtworkURL: resp.CoverMedium,
+	}
+	if link.Type == musiclink.TypeArtist {
+		meta.Title, meta.ArtworkURL = resp.Name, resp.PictureMedium
+	}
+	if resp.Artist != nil {
+		meta.Artist = resp.Artist.Name
+	}
+	if resp.Album != nil {
+		meta.Album = resp.Album.Title
+		if meta.ArtworkURL == "" {
+			meta.ArtworkURL = resp.Album.CoverMedium
+		}
+	}
+	if meta.Title == "" {
+		return nil, ErrNotFound
+	}
+
+	return meta, nil
+}
+
+// getJSON décode une réponse JSON ; 404 devient ErrNotFound, les autres statuts sont des erreurs
+func (p *OEmbedProvider) getJSON(ctx context.Context, rawURL string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("erreur création requête de métadonnées: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erreur requête de métadonnées: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("réponse inattendue de %s: %s", req.URL.Host, resp.Status)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(dest); err != nil {
+		return fmt.Errorf("réponse de métadonnées invalide: %w", err)
+	}
+	return nil
+}
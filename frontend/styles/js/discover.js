@@ -208,6 +208,11 @@ document.addEventListener('DOMContentLoaded', function() {
                 const threads = data.data.threads;
                 displayThreadSearchResults(threads, `Tags: ${tags.join(' + ')}`);
                 showNotification(`🏷️ ${threads.length} thread(s) trouvé(s) avec TOUS ces tags`, 'success');
+
+                // Note moyenne et critiques d'un artiste, album ou morceau
+                if (tags.length === 1) {
+                    loadTagReviews(tags[0]);
+                }
             } else {
                 displayNoResults(`Tags: ${tags.join(' + ')}`);
                 showNotification('🏷️ Aucun thread trouvé avec TOUS ces tags', 'warning');
@@ -226,6 +231,78 @@ document.addEventListener('DOMContentLoaded', function() {
         }
     }
     
+    // Charger la note moyenne et les critiques d'un tag (triées et filtrées par note)
+    async function loadTagReviews(tag, sort = 'score_desc', minScore = '') {
+        const params = new URLSearchParams({ sort: sort });
+        if (minScore !== '') {
+            params.set('min_score', minScore);
+        }
+
+        try {
+            const response = await fetch(`/api/v1/tags/${encodeURIComponent(tag)}/reviews?${params.toString()}`, {
+                credentials: 'include'
+            });
+            const data = await response.json();
+            if (!data.success || data.data.rating.review_count === 0) return;
+
+            displayTagReviews(tag, data.data, sort, minScore);
+        } catch (error) {
+            console.error('❌ Erreur chargement des critiques:', error);
+        }
+    }
+
+    // Afficher la note moyenne d'un tag et ses critiques au-dessus des résultats
+    function displayTagReviews(tag, data, sort, minScore) {
+        const resultsList = document.getElementById('results-list');
+        if (!resultsList) return;
+
+        let section = resultsList.querySelector('.tag-reviews');
+        if (!section) {
+            section = document.createElement('div');
+            section.className = 'results-section tag-reviews';
+            resultsList.prepend(section);
+        }
+
+        const reviewsHTML = data.reviews.map(review => `
+            <a class="tag-review-item" href="/thread/${review.thread_id}">
+                <span class="review-score">${review.score.toFixed(1)}</span>
+                <span class="tag-review-title">${escapeHTML(review.thread_title)}</span>
+                <span class="tag-review-author">par ${escapeHTML(review.author ? review.author.username : '')}</span>
+            </a>
+        `).join('');
+
+        section.innerHTML = `
+            <h3>⭐ ${data.rating.average.toFixed(1)}/10 <small>· ${data.rating.review_count} critique(s) de #${escapeHTML(data.tag.name)}</small></h3>
+            <div class="tag-review-filters">
+                <select class="tag-review-sort">
+                    <option value="score_desc" ${sort === 'score_desc' ? 'selected' : ''}>Mieux notées</option>
+                    <option value="score_asc" ${sort === 'score_asc' ? 'selected' : ''}>Moins bien notées</option>
+                    <option value="recent" ${sort === 'recent' ? 'selected' : ''}>Plus récentes</option>
+                </select>
+                <select class="tag-review-min">
+                    <option value="" ${minScore === '' ? 'selected' : ''}>Toutes les notes</option>
+                    <option value="5" ${minScore === '5' ? 'selected' : ''}>5 et plus</option>
+                    <option value="7" ${minScore === '7' ? 'selected' : ''}>7 et plus</option>
+                    <option value="9" ${minScore === '9' ? 'selected' : ''}>9 et plus</option>
+                </select>
+            </div>
+            <div class="tag-review-list">${reviewsHTML || '<p>Aucune critique avec cette note</p>'}</div>
+        `;
+
+        const sortSelect = section.querySelector('.tag-review-sort');
+        const minSelect = section.querySelector('.tag-review-min');
+        const reload = () => loadTagReviews(tag, sortSelect.value, minSelect.value);
+        sortSelect.addEventListener('change', reload);
+        minSelect.addEventListener('change', reload);
+    }
+
+    // Échapper un texte avant insertion dans le HTML
+    function escapeHTML(str) {
+        const div = document.createElement('div');
+        div.textContent = str || '';
+        return div.innerHTML;
+    }
+
     // NOUVELLE FONCTION: Réinitialiser la recherche
     function resetSearch() {
         if (searchResults) {
@@ -270,6 +347,13 @@ document.addEventListener('DOMContentLoaded', function() {
     function handleURLParams() {
         const urlParams = new URLSearchParams(window.location.search);
         const genreParam = urlParams.get('genre');
+        const tagParam = urlParams.get('tag');
+
+        if (tagParam) {
+            setTimeout(() => {
+                window.addTag(tagParam);
+            }, 1000);
+        }
         
         if (genreParam) {
             setTimeout(() => {
@@ -13,6 +13,41 @@ document.addEventListener('DOMContentLoaded', function() {
     window.removeTag = removeTag;
 });
 
+// Basculer entre l'éditeur et l'aperçu Markdown rendu par le serveur
+async function toggleMarkdownPreview(btn) {
+    const container = btn.closest('.composer-input-area, .edit-field');
+    const textarea = container.querySelector('textarea');
+    const preview = container.querySelector('.markdown-preview');
+
+    if (preview.style.display !== 'none') {
+        preview.style.display = 'none';
+        textarea.style.display = '';
+        return;
+    }
+
+    try {
+        const response = await fetch('/api/v1/markdown/preview', {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            credentials: 'include',
+            body: JSON.stringify({ content: textarea.value })
+        });
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(data.message || `Erreur HTTP: ${response.status}`);
+        }
+
+        // HTML déjà assaini côté serveur
+        preview.innerHTML = data.data.html || '<p><em>Rien à prévisualiser</em></p>';
+        preview.style.display = 'block';
+        textarea.style.display = 'none';
+    } catch (error) {
+        console.error('Erreur aperçu Markdown:', error);
+    }
+}
+
 // ===== GESTION DES IMAGES =====
 function initImageUpload() {
     const fileInput = document.getElementById('image-upload-input');
@@ -165,7 +165,7 @@ document.addEventListener('DOMContentLoaded', function() {
                 <div class="friend-info">
                     <h3>${friend.username}</h3>
                     <p class="friend-username">@${friend.username}</p>
-                    ${activity ? `<p class="friend-activity">${activity}</p>` : ''}
+                    ${activity ? `<p class="friend-activity">${escapeHTML(activity)}</p>` : ''}
                 </div>
                 <div class="friend-stats">
                     <div class="stat">
@@ -1144,6 +1144,13 @@ document.addEventListener('DOMContentLoaded', function() {
     }
     
     // Fonctions utilitaires
+    // Échapper un texte avant insertion dans le HTML
+    function escapeHTML(str) {
+        const div = document.createElement('div');
+        div.textContent = str || '';
+        return div.innerHTML;
+    }
+
     function formatDate(dateString) {
         const date = new Date(dateString);
         const now = new Date();
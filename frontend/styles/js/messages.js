@@ -9,6 +9,7 @@ document.addEventListener('DOMContentLoaded', function() {
     let isTyping = false;
     let typingTimeout = null;
     let conversations = [];
+    let reactionSet = [];
     
     // Éléments DOM
     const conversationsList = document.querySelector('.conversations-list');
@@ -44,6 +45,9 @@ document.addEventListener('DOMContentLoaded', function() {
 
         // Charger les conversations
         loadConversations();
+
+        // Charger les réactions proposées
+        loadReactionSet();
         
         // Connecter au WebSocket
         connectWebSocket();
@@ -89,6 +93,13 @@ document.addEventListener('DOMContentLoaded', function() {
         if (searchInput) {
             searchInput.addEventListener('input', (e) => filterConversations(e.target.value));
         }
+        if (chatMessages) {
+            chatMessages.addEventListener('click', handleReactionClick);
+            chatMessages.addEventListener('mouseover', (e) => {
+                const chip = e.target.closest('.reaction-chip');
+                if (chip) loadMessageReactors(chip);
+            });
+        }
     }
     
     // Connexion WebSocket
@@ -183,6 +194,13 @@ document.addEventListener('DOMContentLoaded', function() {
                     updateUserOnlineStatus(wsMsg.data.user_id, false);
                 }
                 break;
+
+            case 'reaction':
+                // Réaction ajoutée ou retirée sur un message de la conversation
+                if (wsMsg.data && wsMsg.data.reaction && wsMsg.conversation_id === currentConversationId) {
+                    updateMessageReactions(wsMsg.data.reaction);
+                }
+                break;
         }
     }
     
@@ -389,7 +407,7 @@ document.addEventListener('DOMContentLoaded', function() {
         const timeAgo = formatTimeShort(message.created_at);
         
         return `
-            <div class="message ${messageClass}" data-sender-id="${message.sender_id}" data-is-read="${message.is_read}">
+            <div class="message ${messageClass}" data-message-id="${message.id}" data-sender-id="${message.sender_id}" data-is-read="${message.is_read}">
                 ${!isSent ? `
                     <div class="message-avatar">
                         <div class="user-pic tiny">U</div>
@@ -397,14 +415,160 @@ document.addEventListener('DOMContentLoaded', function() {
                 ` : ''}
                 <div class="message-content">
                     <div class="message-bubble">
-                        ${escapeHTML(message.content)}
+                        ${message.shared_thread ? createSharedThreadHTML(message) : renderMentions(message.content, message.mentions)}
                     </div>
+                    ${createReactionsHTML(message.id, message.reactions || [])}
                     <span class="message-timestamp">${timeAgo}</span>
                 </div>
             </div>
         `;
     }
     
+    // Créer la barre de réactions d'un message
+    function createReactionsHTML(messageId, reactions) {
+        const chips = reactions.map(reaction => `
+            <button class="reaction-chip${reaction.reacted ? ' reacted' : ''}" data-emoji="${reaction.emoji}">${reaction.emoji} <span class="reaction-count">${reaction.count}</span></button>
+        `).join('');
+        const picker = reactionSet.map(emoji => `<button data-emoji="${emoji}">${emoji}</button>`).join('');
+
+        return `
+            <div class="reaction-bar" data-message-id="${messageId}">
+                ${chips}
+                <button class="reaction-add" title="Réagir">😀+</button>
+                <div class="reaction-picker" style="display: none;">${picker}</div>
+            </div>
+        `;
+    }
+
+    // Charger l'ensemble des réactions proposées
+    async function loadReactionSet() {
+        try {
+            const response = await fetch('/api/v1/reactions', { credentials: 'include' });
+            const data = await response.json();
+            if (data.success) {
+                reactionSet = data.data.reactions;
+            }
+        } catch (error) {
+            console.error('❌ Erreur chargement des réactions:', error);
+        }
+    }
+
+    // Ouvrir le sélecteur ou basculer une réaction
+    function handleReactionClick(e) {
+        const add = e.target.closest('.reaction-add');
+        if (add) {
+            const picker = add.parentElement.querySelector('.reaction-picker');
+            const opened = picker.style.display !== 'none';
+            chatMessages.querySelectorAll('.reaction-picker').forEach(p => p.style.display = 'none');
+            picker.style.display = opened ? 'none' : 'flex';
+            return;
+        }
+
+        const btn = e.target.closest('.reaction-chip, .reaction-picker button');
+        if (btn) {
+            toggleMessageReaction(btn.closest('.reaction-bar'), btn.dataset.emoji);
+        }
+    }
+
+    // Ajouter ou retirer une réaction sur un message
+    async function toggleMessageReaction(bar, emoji) {
+        bar.querySelector('.reaction-picker').style.display = 'none';
+
+        try {
+            const response = await fetch(`/api/v1/direct-messages/${bar.dataset.messageId}/reactions`, {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                credentials: 'include',
+                body: JSON.stringify({ emoji: emoji })
+            });
+
+            const data = await response.json();
+            if (data.success) {
+                bar.outerHTML = createReactionsHTML(bar.dataset.messageId, data.data.reactions);
+            } else {
+                safeNotify('❌ ' + (data.message || data.error || 'Erreur inconnue'), 'error');
+            }
+        } catch (error) {
+            console.error('❌ Erreur réaction:', error);
+            safeNotify('❌ Erreur de connexion', 'error');
+        }
+    }
+
+    // Mettre à jour les réactions d'un message reçues par WebSocket
+    function updateMessageReactions(change) {
+        const bar = chatMessages && chatMessages.querySelector(`.reaction-bar[data-message-id="${change.target_id}"]`);
+        if (!bar) return;
+
+        // Conserver l'état "réagi" de l'utilisateur courant
+        const reacted = new Set(
+            Array.from(bar.querySelectorAll('.reaction-chip.reacted')).map(chip => chip.dataset.emoji)
+        );
+        if (change.user_id === currentUserId) {
+            if (change.added) {
+                reacted.add(change.emoji);
+            } else {
+                reacted.delete(change.emoji);
+            }
+        }
+
+        bar.outerHTML = createReactionsHTML(change.target_id, change.reactions.map(reaction => ({
+            ...reaction,
+            reacted: reacted.has(reaction.emoji),
+        })));
+    }
+
+    // Afficher au survol qui a réagi avec cet emoji
+    async function loadMessageReactors(chip) {
+        if (chip.dataset.loaded) return;
+        chip.dataset.loaded = 'true';
+
+        try {
+            const messageId = chip.closest('.reaction-bar').dataset.messageId;
+            const response = await fetch(`/api/v1/direct-messages/${messageId}/reactions`, { credentials: 'include' });
+            const data = await response.json();
+            if (!data.success) return;
+
+            const detail = data.data.reactions.find(reaction => reaction.emoji === chip.dataset.emoji);
+            if (detail) {
+                chip.title = detail.users.map(user => user.username).join(', ');
+            }
+        } catch (error) {
+            console.error('Erreur chargement des réactions:', error);
+        }
+    }
+
+    // Créer la carte d'un thread partagé (la première ligne du contenu est l'en-tête du partage)
+    function createSharedThreadHTML(message) {
+        const card = message.shared_thread;
+        const comment = message.content.split('\n').slice(1).join('\n');
+
+        return `
+            <a class="shared-thread-card" href="${card.url}" style="display: block; padding: 10px 12px; border-radius: 10px; background: rgba(255, 255, 255, 0.06); border: 1px solid rgba(255, 255, 255, 0.12); color: inherit; text-decoration: none;">
+                <span style="font-size: 12px; opacity: 0.7;">🧵 Thread partagé</span>
+                <strong style="display: block;">${escapeHTML(card.title)}</strong>
+            </a>
+            ${comment ? `<p style="margin: 8px 0 0;">${renderMentions(comment, message.mentions)}</p>` : ''}
+        `;
+    }
+
+    // Échapper un contenu et transformer les @mentions enregistrées en liens de profil
+    function renderMentions(content, mentions) {
+        const html = escapeHTML(content);
+        if (!mentions || mentions.length === 0) return html;
+
+        const userIds = {};
+        mentions.forEach(mention => {
+            userIds[mention.mentioned_username.toLowerCase()] = mention.mentioned_user_id;
+        });
+
+        return html.replace(/(^|[^a-zA-Z0-9_.@])@([a-zA-Z0-9_]{3,30})(?![a-zA-Z0-9_])/g, (match, before, username) => {
+            const userId = userIds[username.toLowerCase()];
+            return userId ? `${before}<a href="/profile?user=${userId}" class="mention">@${username}</a>` : match;
+        });
+    }
+
     // Ajouter un message au chat
     function addMessageToChat(message) {
         if (!chatMessages) return;
@@ -34,6 +34,46 @@ document.addEventListener('DOMContentLoaded', function() {
         
         // Surveiller les changements
         startChangeTracking();
+
+        // Plateforme préférée pour ouvrir les liens musicaux
+        initPreferredPlatform();
+    }
+
+    // Charger la plateforme préférée et l'enregistrer dès qu'elle change
+    async function initPreferredPlatform() {
+        const select = document.getElementById('preferred-platform');
+        if (!select) return;
+
+        try {
+            const response = await fetch('/api/v1/catalog/preferences', { credentials: 'include' });
+            const data = await response.json();
+            if (response.ok && data.success) {
+                select.value = data.data.platform || '';
+            }
+        } catch (error) {
+            console.error('❌ Erreur chargement de la plateforme préférée:', error);
+        }
+
+        select.addEventListener('change', async function() {
+            try {
+                const response = await fetch('/api/v1/catalog/preferences', {
+                    method: 'PUT',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    credentials: 'include',
+                    body: JSON.stringify({ platform: this.value })
+                });
+                const data = await response.json();
+                if (!response.ok || !data.success) {
+                    throw new Error(data.message || `Erreur HTTP: ${response.status}`);
+                }
+                showNotification('🎧 Plateforme préférée enregistrée', 'success');
+            } catch (error) {
+                console.error('❌ Erreur enregistrement de la plateforme préférée:', error);
+                showNotification('❌ Impossible d\'enregistrer la plateforme préférée', 'error');
+            }
+        });
     }
     
     // Gestion des événements
@@ -145,6 +145,17 @@ document.addEventListener('DOMContentLoaded', function() {
         
         // Démarrer les mises à jour en temps réel
         startRealTimeUpdates();
+
+        // Autocomplétion des @mentions dans le commentaire
+        if (commentInput) {
+            initMentionAutocomplete(commentInput);
+        }
+
+        // Réactions des autres utilisateurs en temps réel
+        connectReactionSocket();
+
+        // Annotations des passages de la description
+        initAnnotations();
     }
     
     // Gestion des événements
@@ -1391,6 +1402,243 @@ async function deleteThread(threadId) {
     }
 }
 
+// FONCTION GLOBALE: S'abonner / se désabonner d'un thread
+async function toggleSubscription(btn) {
+    const threadId = getThreadIdFromURL();
+    if (!threadId) {
+        console.error('ID du thread non trouvé');
+        return;
+    }
+
+    const subscribed = btn.classList.contains('subscribed');
+    btn.disabled = true;
+
+    try {
+        const response = await fetch(`/api/v1/threads/${threadId}/subscription`, {
+            method: subscribed ? 'DELETE' : 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            credentials: 'include' // Important pour envoyer les cookies d'auth
+        });
+
+        if (!response.ok) {
+            throw new Error(`Erreur HTTP: ${response.status}`);
+        }
+
+        const data = await response.json();
+        if (data.success) {
+            btn.classList.toggle('subscribed', !subscribed);
+            btn.textContent = subscribed ? '🔕' : '🔔';
+            btn.title = subscribed ? 'Suivre le thread' : 'Ne plus suivre le thread';
+            showGlobalNotification(subscribed ? '🔕 Vous ne suivez plus ce thread' : '🔔 Vous suivez ce thread', 'success');
+        } else {
+            showGlobalNotification('❌ ' + (data.message || 'Erreur inconnue'), 'error');
+        }
+    } catch (error) {
+        console.error('❌ Erreur abonnement:', error);
+        showGlobalNotification('❌ Erreur de connexion', 'error');
+    } finally {
+        btn.disabled = false;
+    }
+}
+
+// FONCTION GLOBALE: Republier le thread sur son profil avec un commentaire optionnel
+async function repostThread(btn) {
+    const threadId = getThreadIdFromURL();
+    if (!threadId) {
+        console.error('ID du thread non trouvé');
+        return;
+    }
+
+    const comment = prompt('Republier sur votre profil — ajouter un commentaire (optionnel) :');
+    if (comment === null) return; // Annulé
+
+    btn.disabled = true;
+
+    try {
+        const response = await fetch(`/api/v1/threads/${threadId}/repost`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            credentials: 'include', // Important pour envoyer les cookies d'auth
+            body: JSON.stringify({ comment: comment })
+        });
+
+        const data = await response.json();
+        if (data.success) {
+            const count = btn.querySelector('.btn-count');
+            if (count && data.data) {
+                count.textContent = data.data.share_count;
+            }
+            showGlobalNotification('🔄 Thread republié sur votre profil', 'success');
+        } else {
+            showGlobalNotification('❌ ' + (data.message || data.error || 'Erreur inconnue'), 'error');
+        }
+    } catch (error) {
+        console.error('❌ Erreur repost:', error);
+        showGlobalNotification('❌ Erreur de connexion', 'error');
+    } finally {
+        btn.disabled = false;
+    }
+}
+
+// Basculer entre l'éditeur et l'aperçu Markdown rendu par le serveur
+async function toggleMarkdownPreview(btn) {
+    const container = btn.closest('.composer-input-area, .edit-field');
+    const textarea = container.querySelector('textarea');
+    const preview = container.querySelector('.markdown-preview');
+
+    if (preview.style.display !== 'none') {
+        preview.style.display = 'none';
+        textarea.style.display = '';
+        return;
+    }
+
+    try {
+        const response = await fetch('/api/v1/markdown/preview', {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            credentials: 'include',
+            body: JSON.stringify({ content: textarea.value })
+        });
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(data.message || `Erreur HTTP: ${response.status}`);
+        }
+
+        // HTML déjà assaini côté serveur
+        preview.innerHTML = data.data.html || '<p><em>Rien à prévisualiser</em></p>';
+        preview.style.display = 'block';
+        textarea.style.display = 'none';
+    } catch (error) {
+        console.error('Erreur aperçu Markdown:', error);
+    }
+}
+
+// Autocomplétion des @mentions : suggère des utilisateurs pendant la saisie de "@pseudo"
+function initMentionAutocomplete(textarea) {
+    const list = document.createElement('div');
+    list.className = 'mention-suggestions';
+    list.style.cssText = 'display: none; position: absolute; z-index: 100; min-width: 200px; border-radius: 8px; background: #1e1e2e; border: 1px solid rgba(255, 255, 255, 0.12);';
+    textarea.parentElement.style.position = 'relative';
+    textarea.parentElement.appendChild(list);
+
+    let debounceTimer = null;
+
+    const currentMention = () => {
+        const beforeCaret = textarea.value.slice(0, textarea.selectionStart);
+        const match = beforeCaret.match(/(^|[^a-zA-Z0-9_.@])@([a-zA-Z0-9_]{1,30})$/);
+        return match ? match[2] : null;
+    };
+
+    const hide = () => {
+        list.style.display = 'none';
+        list.innerHTML = '';
+    };
+
+    const insert = (username) => {
+        const caret = textarea.selectionStart;
+        const prefix = currentMention() || '';
+        const start = caret - prefix.length;
+        textarea.value = textarea.value.slice(0, start) + username + ' ' + textarea.value.slice(caret);
+        textarea.selectionStart = textarea.selectionEnd = start + username.length + 1;
+        textarea.focus();
+        hide();
+    };
+
+    textarea.addEventListener('input', () => {
+        clearTimeout(debounceTimer);
+        const prefix = currentMention();
+        if (!prefix) {
+            hide();
+            return;
+        }
+
+        debounceTimer = setTimeout(async () => {
+            try {
+                const response = await fetch(`/api/v1/mentions/suggest?q=${encodeURIComponent(prefix)}`, {
+                    credentials: 'include'
+                });
+                if (!response.ok) {
+                    hide();
+                    return;
+                }
+
+                const data = await response.json();
+                const users = (data.success && data.data && data.data.users) || [];
+                if (users.length === 0) {
+                    hide();
+                    return;
+                }
+
+                list.innerHTML = '';
+                users.forEach(user => {
+                    const item = document.createElement('div');
+                    item.className = 'mention-suggestion';
+                    item.style.cssText = 'padding: 6px 12px; cursor: pointer;';
+                    item.textContent = '@' + user.username;
+                    item.addEventListener('mousedown', (e) => {
+                        e.preventDefault();
+                        insert(user.username);
+                    });
+                    list.appendChild(item);
+                });
+                list.style.display = 'block';
+            } catch (error) {
+                console.error('Erreur autocomplétion des mentions:', error);
+                hide();
+            }
+        }, 200);
+    });
+
+    textarea.addEventListener('blur', hide);
+}
+
+// FONCTION GLOBALE: Ajouter / retirer un thread des favoris
+async function toggleBookmark(btn) {
+    const threadId = getThreadIdFromURL();
+    if (!threadId) {
+        console.error('ID du thread non trouvé');
+        return;
+    }
+
+    const bookmarked = btn.classList.contains('bookmarked');
+    btn.disabled = true;
+
+    try {
+        const response = await fetch(`/api/v1/threads/${threadId}/bookmark`, {
+            method: bookmarked ? 'DELETE' : 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            credentials: 'include' // Important pour envoyer les cookies d'auth
+        });
+
+        if (!response.ok) {
+            throw new Error(`Erreur HTTP: ${response.status}`);
+        }
+
+        const data = await response.json();
+        if (data.success) {
+            btn.classList.toggle('bookmarked', !bookmarked);
+            btn.textContent = bookmarked ? '📑' : '🔖';
+            btn.title = bookmarked ? 'Ajouter aux favoris' : 'Retirer des favoris';
+            showGlobalNotification(bookmarked ? '📑 Thread retiré des favoris' : '🔖 Thread ajouté aux favoris', 'success');
+        } else {
+            showGlobalNotification('❌ ' + (data.message || 'Erreur inconnue'), 'error');
+        }
+    } catch (error) {
+        console.error('❌ Erreur favori:', error);
+        showGlobalNotification('❌ Erreur de connexion', 'error');
+    } finally {
+        btn.disabled = false;
+    }
+}
+
 // Styles CSS additionnels pour les animations
 const threadAdditionalStyles = `
 @keyframes heartFloat {
@@ -1500,4 +1748,398 @@ if (!document.getElementById('thread-additional-styles')) {
     styleSheet.id = 'thread-additional-styles';
     styleSheet.textContent = threadAdditionalStyles;
     document.head.appendChild(styleSheet);
-} 
\ No newline at end of file
+} 
+
+// Ouvrir / fermer le sélecteur de réactions d'un commentaire ou d'un message
+function toggleReactionPicker(btn) {
+    const picker = btn.parentElement.querySelector('.reaction-picker');
+    const opened = picker.style.display !== 'none';
+
+    document.querySelectorAll('.reaction-picker').forEach(p => p.style.display = 'none');
+    picker.style.display = opened ? 'none' : 'flex';
+}
+
+// FONCTION GLOBALE: Ajouter ou retirer une réaction sur un commentaire
+async function toggleCommentReaction(btn) {
+    const bar = btn.closest('.reaction-bar');
+    const commentId = bar.dataset.commentId;
+
+    btn.disabled = true;
+
+    try {
+        const response = await fetch(`/api/v1/comments/${commentId}/reactions`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            credentials: 'include', // Important pour envoyer les cookies d'auth
+            body: JSON.stringify({ emoji: btn.dataset.emoji })
+        });
+
+        const data = await response.json();
+        if (data.success) {
+            renderCommentReactions(bar, data.data.reactions);
+        } else {
+            showGlobalNotification('❌ ' + (data.message || data.error || 'Erreur inconnue'), 'error');
+        }
+    } catch (error) {
+        console.error('❌ Erreur réaction:', error);
+        showGlobalNotification('❌ Erreur de connexion', 'error');
+    } finally {
+        btn.disabled = false;
+        const picker = bar.querySelector('.reaction-picker');
+        if (picker) picker.style.display = 'none';
+    }
+}
+
+// Réafficher les compteurs de réactions d'un commentaire
+function renderCommentReactions(bar, reactions) {
+    bar.querySelectorAll('.reaction-chip').forEach(chip => chip.remove());
+
+    const anchor = bar.querySelector('.reaction-add');
+    reactions.forEach(reaction => {
+        const chip = document.createElement('button');
+        chip.className = 'reaction-chip' + (reaction.reacted ? ' reacted' : '');
+        chip.dataset.emoji = reaction.emoji;
+        chip.innerHTML = `${reaction.emoji} <span class="reaction-count">${reaction.count}</span>`;
+        chip.onclick = () => toggleCommentReaction(chip);
+        chip.onmouseenter = () => loadCommentReactors(chip);
+        bar.insertBefore(chip, anchor);
+    });
+}
+
+// Afficher au survol qui a réagi avec cet emoji
+async function loadCommentReactors(chip) {
+    if (chip.dataset.loaded) return;
+    chip.dataset.loaded = 'true';
+
+    try {
+        const commentId = chip.closest('.reaction-bar').dataset.commentId;
+        const response = await fetch(`/api/v1/comments/${commentId}/reactions`, { credentials: 'include' });
+        const data = await response.json();
+        if (!data.success) return;
+
+        const detail = data.data.reactions.find(reaction => reaction.emoji === chip.dataset.emoji);
+        if (detail) {
+            chip.title = detail.users.map(user => user.username).join(', ');
+        }
+    } catch (error) {
+        console.error('Erreur chargement des réactions:', error);
+    }
+}
+
+// Écouter les changements de réactions des commentaires de ce thread
+function connectReactionSocket() {
+    // Le WebSocket de notifications est réservé aux utilisateurs connectés
+    if (!document.querySelector('.reaction-add')) return;
+
+    // Le serveur n'envoie les réactions qu'aux pages affichant le thread
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const socket = new WebSocket(`${protocol}//${window.location.host}/ws?thread=${encodeURIComponent(getThreadIdFromURL())}`);
+
+    socket.onmessage = (event) => {
+        const notification = JSON.parse(event.data);
+        const change = notification.data;
+        if (notification.type !== 'reaction' || !change || change.target_type !== 'comment') return;
+        if (String(change.thread_id) !== getThreadIdFromURL()) return;
+
+        const bar = document.querySelector(`.reaction-bar[data-comment-id="${change.target_id}"]`);
+        if (!bar) return;
+
+        // Conserver l'état "réagi" de l'utilisateur courant
+        const reacted = new Set(
+            Array.from(bar.querySelectorAll('.reaction-chip.reacted')).map(chip => chip.dataset.emoji)
+        );
+        renderCommentReactions(bar, change.reactions.map(reaction => ({
+            ...reaction,
+            reacted: reacted.has(reaction.emoji),
+        })));
+    };
+
+    socket.onclose = () => {
+        setTimeout(connectReactionSocket, 5000);
+    };
+}
+
+// ===== ANNOTATIONS DES PASSAGES DU THREAD =====
+
+// Révision courante du texte et annotations du thread
+let threadAnnotations = { revision: 0, description: '', annotations: [] };
+let pendingAnnotation = null;
+
+// Charger les annotations et écouter les sélections dans le texte du thread
+function initAnnotations() {
+    const textElement = document.querySelector('.thread-text');
+    if (!textElement || !getThreadIdFromURL()) return;
+
+    loadAnnotations();
+
+    const annotateBtn = document.getElementById('annotate-btn');
+    if (!annotateBtn) return;
+
+    textElement.addEventListener('mouseup', () => {
+        const selection = window.getSelection();
+        if (!selection || selection.isCollapsed || !selection.toString().trim()) {
+            annotateBtn.style.display = 'none';
+            return;
+        }
+
+        const rect = selection.getRangeAt(0).getBoundingClientRect();
+        annotateBtn.style.display = 'inline-block';
+        annotateBtn.style.top = `${rect.bottom + window.scrollY + 6}px`;
+        annotateBtn.style.left = `${rect.left + window.scrollX}px`;
+    });
+}
+
+// Récupérer les annotations du thread
+async function loadAnnotations() {
+    try {
+        const response = await fetch(`/api/v1/threads/${getThreadIdFromURL()}/annotations`, { credentials: 'include' });
+        const data = await response.json();
+        if (!data.success) return;
+
+        threadAnnotations = data.data;
+        highlightAnnotations();
+    } catch (error) {
+        console.error('Erreur chargement des annotations:', error);
+    }
+}
+
+// Surligner les passages annotés dans le texte affiché
+function highlightAnnotations() {
+    const textElement = document.querySelector('.thread-text');
+
+    // Retirer les surlignages précédents
+    textElement.querySelectorAll('mark.annotation-mark').forEach(mark => {
+        mark.replaceWith(...mark.childNodes);
+    });
+    textElement.normalize();
+
+    threadAnnotations.annotations.filter(annotation => !annotation.stale).forEach(annotation => {
+        // Le passage peut apparaître plusieurs fois : retrouver la même occurrence que dans le texte brut
+        const before = Array.from(threadAnnotations.description).slice(0, annotation.start_offset).join('');
+        const occurrence = countOccurrences(before, annotation.quote);
+        const range = findTextRange(textElement, annotation.quote, occurrence);
+        if (range) {
+            wrapRange(range, annotation.id);
+        }
+    });
+}
+
+// Nombre d'occurrences d'un passage dans un texte
+function countOccurrences(text, quote) {
+    let count = 0;
+    for (let index = text.indexOf(quote); index !== -1; index = text.indexOf(quote, index + 1)) {
+        count++;
+    }
+    return count;
+}
+
+// Positions des nœuds texte d'un élément dans son texte affiché
+function collectTextNodes(element) {
+    const walker = document.createTreeWalker(element, NodeFilter.SHOW_TEXT);
+    const nodes = [];
+    let text = '';
+    while (walker.nextNode()) {
+        nodes.push({ node: walker.currentNode, start: text.length });
+        text += walker.currentNode.textContent;
+    }
+    return { nodes, text };
+}
+
+// Trouver la n-ième occurrence d'un passage dans le texte affiché (la première à défaut)
+function findTextRange(element, quote, occurrence) {
+    const { nodes, text } = collectTextNodes(element);
+
+    let index = text.indexOf(quote);
+    for (let i = 0; i < occurrence && index !== -1; i++) {
+        const next = text.indexOf(quote, index + 1);
+        if (next === -1) break;
+        index = next;
+    }
+    if (index === -1) return null;
+
+    return { nodes, start: index, end: index + quote.length };
+}
+
+// Entourer un passage (éventuellement réparti sur plusieurs nœuds) de marques cliquables
+function wrapRange(range, annotationId) {
+    range.nodes.forEach(({ node, start }) => {
+        const nodeEnd = start + node.textContent.length;
+        if (nodeEnd <= range.start || start >= range.end) return;
+
+        const domRange = document.createRange();
+        domRange.setStart(node, Math.max(range.start - start, 0));
+        domRange.setEnd(node, Math.min(range.end, nodeEnd) - start);
+
+        const mark = document.createElement('mark');
+        mark.className = 'annotation-mark';
+        mark.dataset.annotationId = annotationId;
+        mark.onclick = () => showAnnotations(annotationId);
+        domRange.surroundContents(mark);
+    });
+}
+
+// Afficher le formulaire d'annotation du passage sélectionné
+function openAnnotationForm() {
+    const selection = window.getSelection();
+    const textElement = document.querySelector('.thread-text');
+    const selected = selection.toString();
+    if (!selected.trim()) return;
+
+    // Occurrence sélectionnée dans le texte affiché, reportée sur le texte brut
+    const { nodes } = collectTextNodes(textElement);
+    const domRange = selection.getRangeAt(0);
+    const anchor = nodes.find(({ node }) => node === domRange.startContainer);
+    const displayedText = nodes.map(({ node }) => node.textContent).join('');
+    const displayedStart = anchor ? anchor.start + domRange.startOffset : displayedText.indexOf(selected);
+    const occurrence = countOccurrences(displayedText.slice(0, displayedStart), selected);
+
+    let index = -1;
+    for (let i = 0; i <= occurrence; i++) {
+        const next = threadAnnotations.description.indexOf(selected, index + 1);
+        if (next === -1) break;
+        index = next;
+    }
+    if (index === -1) {
+        showGlobalNotification('❌ Ce passage ne peut pas être annoté (mise en forme)', 'error');
+        return;
+    }
+
+    // Offsets en caractères (points de code), comme côté serveur
+    const start = Array.from(threadAnnotations.description.slice(0, index)).length;
+    pendingAnnotation = { start_offset: start, end_offset: start + Array.from(selected).length };
+
+    const panel = document.getElementById('annotation-panel');
+    panel.innerHTML = `
+        <blockquote class="annotation-quote">${escapeAnnotationHTML(selected)}</blockquote>
+        <textarea class="annotation-input" maxlength="2000" placeholder="Explique ce passage..."></textarea>
+        <div class="annotation-actions">
+            <button class="annotation-submit" onclick="submitAnnotation()">Publier</button>
+            <button class="annotation-cancel" onclick="closeAnnotationPanel()">Annuler</button>
+        </div>
+    `;
+    panel.style.display = 'block';
+    panel.querySelector('.annotation-input').focus();
+    document.getElementById('annotate-btn').style.display = 'none';
+}
+
+// Publier l'annotation du passage sélectionné
+async function submitAnnotation() {
+    const panel = document.getElementById('annotation-panel');
+    const body = panel.querySelector('.annotation-input').value.trim();
+    if (!pendingAnnotation || !body) return;
+
+    try {
+        const response = await fetch(`/api/v1/threads/${getThreadIdFromURL()}/annotations`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            credentials: 'include',
+            body: JSON.stringify({ ...pendingAnnotation, revision: threadAnnotations.revision, body })
+        });
+
+        const data = await response.json();
+        if (response.status === 409) {
+            // Le texte a changé entre-temps : recharger pour sélectionner à nouveau
+            showGlobalNotification('⚠️ ' + data.message, 'warning');
+            closeAnnotationPanel();
+            loadAnnotations();
+            return;
+        }
+        if (!data.success) {
+            showGlobalNotification('❌ ' + (data.message || data.error || 'Erreur inconnue'), 'error');
+            return;
+        }
+
+        threadAnnotations.annotations.push(data.data.annotation);
+        highlightAnnotations();
+        showAnnotations(data.data.annotation.id);
+        showGlobalNotification('📝 Annotation publiée', 'success');
+    } catch (error) {
+        console.error('❌ Erreur annotation:', error);
+        showGlobalNotification('❌ Erreur de connexion', 'error');
+    } finally {
+        pendingAnnotation = null;
+    }
+}
+
+// Afficher les annotations d'un passage (celles qui portent sur le même texte), les mieux votées en premier
+function showAnnotations(annotationId) {
+    const clicked = threadAnnotations.annotations.find(annotation => annotation.id === annotationId);
+    if (!clicked) return;
+
+    const annotations = threadAnnotations.annotations
+        .filter(annotation => !annotation.stale && annotation.start_offset === clicked.start_offset && annotation.end_offset === clicked.end_offset)
+        .sort((a, b) => b.score - a.score);
+
+    const panel = document.getElementById('annotation-panel');
+    panel.innerHTML = `
+        <blockquote class="annotation-quote">${escapeAnnotationHTML(clicked.quote)}</blockquote>
+        ${annotations.map(createAnnotationHTML).join('')}
+        <div class="annotation-actions">
+            <button class="annotation-cancel" onclick="closeAnnotationPanel()">Fermer</button>
+        </div>
+    `;
+    panel.style.display = 'block';
+}
+
+// HTML d'une annotation avec ses votes Fire/Skip
+function createAnnotationHTML(annotation) {
+    return `
+        <div class="annotation-item" data-annotation-id="${annotation.id}">
+            <div class="annotation-author">@${escapeAnnotationHTML(annotation.author.username)}</div>
+            <div class="annotation-body">${escapeAnnotationHTML(annotation.body)}</div>
+            <div class="annotation-votes">
+                <button class="annotation-vote ${annotation.user_vote === 'fire' ? 'voted' : ''}" onclick="voteAnnotation(${annotation.id}, 'fire')">🔥 ${annotation.fire_count}</button>
+                <button class="annotation-vote ${annotation.user_vote === 'skip' ? 'voted' : ''}" onclick="voteAnnotation(${annotation.id}, 'skip')">⏭️ ${annotation.skip_count}</button>
+            </div>
+        </div>
+    `;
+}
+
+// Voter sur une annotation (un second clic retire le vote)
+async function voteAnnotation(annotationId, vote) {
+    const annotation = threadAnnotations.annotations.find(a => a.id === annotationId);
+    if (!annotation) return;
+
+    try {
+        const response = await fetch(`/api/v1/annotations/${annotationId}/vote`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            credentials: 'include',
+            body: JSON.stringify({ vote: annotation.user_vote === vote ? 'neutral' : vote })
+        });
+
+        const data = await response.json();
+        if (!data.success) {
+            showGlobalNotification('❌ ' + (data.message || data.error || 'Erreur inconnue'), 'error');
+            return;
+        }
+
+        Object.assign(annotation, data.data.annotation);
+        if (!data.data.annotation.user_vote) delete annotation.user_vote;
+        showAnnotations(annotationId);
+    } catch (error) {
+        console.error('❌ Erreur vote annotation:', error);
+        showGlobalNotification('❌ Erreur de connexion', 'error');
+    }
+}
+
+// Fermer le panneau des annotations
+function closeAnnotationPanel() {
+    const panel = document.getElementById('annotation-panel');
+    panel.style.display = 'none';
+    panel.innerHTML = '';
+    pendingAnnotation = null;
+}
+
+// Échapper le texte saisi par les utilisateurs
+function escapeAnnotationHTML(text) {
+    const div = document.createElement('div');
+    div.textContent = text;
+    return div.innerHTML;
+}